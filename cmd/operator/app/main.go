@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	"github.com/minorhacks/clickhouse-operator/pkg/version"
 	"os"
 	"os/signal"
@@ -73,18 +74,50 @@ func Run() {
 
 	initClickHouse(ctx)
 	initClickHouseReconcilerMetricsExporter(ctx)
+	defer initTracing(ctx)()
 	keeperErr := initKeeper(ctx)
+	resourcesErr := initResources(ctx)
+
+	initLeaderElection(ctx)
 
 	var wg sync.WaitGroup
 	wg.Add(3)
 
 	go func() {
 		defer wg.Done()
-		runClickHouse(ctx)
+		runClickHouseReconcilerMetricsExporter(ctx)
 	}()
 	go func() {
 		defer wg.Done()
-		runClickHouseReconcilerMetricsExporter(ctx)
+		if chop.Config().Sharding.Enabled {
+			// CHIs are partitioned among all instances, thus CHI controller is run by every instance
+			runClickHouse(ctx)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		// Controllers are run by the leader only, while metrics exporter is run by every instance
+		runLeaderElection(ctx, func(ctx context.Context) {
+			runControllers(ctx, keeperErr, resourcesErr)
+		}, cancelFunc)
+	}()
+
+	// Wait for completion
+	<-ctx.Done()
+	wg.Wait()
+}
+
+// runControllers runs CHI controller, unless CHIs are partitioned among instances, keeper manager
+// and users/schema migrations manager till the context is done
+func runControllers(ctx context.Context, keeperErr, resourcesErr error) {
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		if !chop.Config().Sharding.Enabled {
+			runClickHouse(ctx)
+		}
 	}()
 	go func() {
 		defer wg.Done()
@@ -100,9 +133,21 @@ func Run() {
 			log.Warning("Starting keeper skipped due to failed initialization with err: %v", keeperErr)
 		}
 	}()
+	go func() {
+		defer wg.Done()
+		if resourcesErr == nil {
+			log.Info("Starting users and schema migrations controllers")
+			resourcesErr = runResources(ctx)
+			if resourcesErr == nil {
+				log.Info("Starting users and schema migrations controllers OK")
+			} else {
+				log.Warning("Starting users and schema migrations controllers FAILED with err: %v", resourcesErr)
+			}
+		} else {
+			log.Warning("Starting users and schema migrations controllers skipped due to failed initialization with err: %v", resourcesErr)
+		}
+	}()
 
-	// Wait for completion
-	<-ctx.Done()
 	wg.Wait()
 }
 
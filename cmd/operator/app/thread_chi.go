@@ -18,12 +18,15 @@ import (
 	"context"
 	"time"
 
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
 	kubeinformers "k8s.io/client-go/informers"
 
 	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
 	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	chopinformers "github.com/minorhacks/clickhouse-operator/pkg/client/informers/externalversions"
 	"github.com/minorhacks/clickhouse-operator/pkg/controller/chi"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
 )
 
 // Prometheus exporter defaults
@@ -69,6 +72,16 @@ func initClickHouse(ctx context.Context) {
 		kubeInformerFactoryResyncPeriod,
 		kubeinformers.WithNamespace(chop.Config().GetInformerNamespace()),
 	)
+	// Generated operator credentials are looked up by a separate informer filtered by labels,
+	// so their lister does not depend on an informer of all Secrets
+	secretInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient,
+		kubeInformerFactoryResyncPeriod,
+		kubeinformers.WithNamespace(chop.Config().GetInformerNamespace()),
+		kubeinformers.WithTweakListOptions(func(opts *meta.ListOptions) {
+			opts.LabelSelector = k8sLabels.SelectorFromSet(model.GetSelectorOperatorCredentials()).String()
+		}),
+	)
 	chopInformerFactory := chopinformers.NewSharedInformerFactoryWithOptions(
 		chopClient,
 		chopInformerFactoryResyncPeriod,
@@ -82,10 +95,12 @@ func initClickHouse(ctx context.Context) {
 		kubeClient,
 		chopInformerFactory,
 		kubeInformerFactory,
+		secretInformerFactory,
 	)
 
 	// Start Informers
 	kubeInformerFactory.Start(ctx.Done())
+	secretInformerFactory.Start(ctx.Done())
 	chopInformerFactory.Start(ctx.Done())
 }
 
@@ -16,6 +16,7 @@ import (
 	//	ctrl "sigs.k8s.io/controller-runtime/pkg/controller"
 
 	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
+	apiChi "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	controller "github.com/minorhacks/clickhouse-operator/pkg/controller/chk"
 )
@@ -42,6 +43,10 @@ func initKeeper(ctx context.Context) error {
 		logger.Error(err, "init keeper - unable to api.AddToScheme")
 		return err
 	}
+	if err = apiChi.AddToScheme(scheme); err != nil {
+		logger.Error(err, "init keeper - unable to apiChi.AddToScheme")
+		return err
+	}
 
 	manager, err = ctrlRuntime.NewManager(ctrlRuntime.GetConfigOrDie(), ctrlRuntime.Options{
 		Scheme: scheme,
@@ -0,0 +1,265 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/metrics"
+)
+
+// leaderElectionMetrics is a set of metrics of the leader election
+type leaderElectionMetrics struct {
+	// Transitions is a number (counter) of leadership acquisitions and losses of the instance
+	Transitions metric.Int64Counter
+	// Leader is a gauge, which is 1 in case the instance is the leader and 0 otherwise
+	Leader metric.Int64ObservableGauge
+}
+
+var (
+	// leaderElectionLock specifies Lease instances compete for. Nil in case leader election is disabled
+	leaderElectionLock resourcelock.Interface
+	// isLeader specifies whether the instance is the leader at the moment
+	isLeader       atomic.Bool
+	leMetrics      *leaderElectionMetrics
+	leMetricsMutex sync.Mutex
+)
+
+// initLeaderElection prepares Lease for the leader election in case it is enabled
+func initLeaderElection(ctx context.Context) {
+	config := &chop.Config().LeaderElection
+	if !config.Enabled {
+		return
+	}
+
+	log.S().P()
+	defer log.E().P()
+
+	if config.Namespace == "" {
+		log.F().Fatal("Leader election requires namespace of the lease to be specified")
+	}
+
+	// Lease is renewed by the dedicated client, thus renewal is not throttled by the controllers' requests
+	kubeClient, _, _ := chop.GetClientset(kubeConfigFile, masterURL)
+	leaderElectionLock = &resourcelock.LeaseLock{
+		LeaseMeta: meta.ObjectMeta{
+			Name:      config.LeaseName,
+			Namespace: config.Namespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: config.Identity,
+		},
+	}
+	log.V(1).F().Info("Leader election enabled. Lease: %s/%s Identity: %s", config.Namespace, config.LeaseName, config.Identity)
+}
+
+// runLeaderElection runs specified function as soon as the instance is elected as the leader.
+// In case leader election is disabled function is run straight away.
+// Function lost is called in case leadership is lost while the instance is running, the function being stopped already.
+// Returns after the function has completed
+func runLeaderElection(ctx context.Context, run func(ctx context.Context), lost func()) {
+	if leaderElectionLock == nil {
+		run(ctx)
+		return
+	}
+
+	log.S().P()
+	defer log.E().P()
+
+	// Elector has its own context, which is cancelled on shutdown only after the function has completed.
+	// Thus, the lease is released after the instance stops touching the objects and stand-by instance may take over safely
+	electorCtx, cancelElector := context.WithCancel(context.Background())
+	defer cancelElector()
+
+	config := chop.Config().LeaderElection
+	callbacks := newLeaderCallbacks(ctx, config.Identity, run, lost)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          leaderElectionLock,
+		LeaseDuration: config.LeaseDuration,
+		RenewDeadline: config.RenewDeadline,
+		RetryPeriod:   config.RetryPeriod,
+		// Lease is released on cancel of the elector, thus stand-by instance takes over without waiting for the lease to expire
+		ReleaseOnCancel: true,
+		Name:            "clickhouse-operator",
+		Callbacks:       callbacks.LeaderCallbacks(),
+	})
+	if err != nil {
+		log.F().Fatal("Unable to create leader elector. Err: %v", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		// Lease is renewed till the controllers complete
+		callbacks.stop()
+		cancelElector()
+	}()
+
+	log.V(1).F().Info("Waiting for leadership")
+	elector.Run(electorCtx)
+
+	// Wait for the controllers to complete
+	callbacks.stop()
+}
+
+// leaderCallbacks runs function while the instance is the leader
+type leaderCallbacks struct {
+	// ctx is a context of the instance, function is stopped on its cancel
+	ctx      context.Context
+	identity string
+	run      func(ctx context.Context)
+	lost     func()
+
+	mutex   sync.Mutex
+	stopped bool
+	running sync.WaitGroup
+}
+
+// newLeaderCallbacks creates callbacks of the leader elector
+func newLeaderCallbacks(ctx context.Context, identity string, run func(ctx context.Context), lost func()) *leaderCallbacks {
+	return &leaderCallbacks{
+		ctx:      ctx,
+		identity: identity,
+		run:      run,
+		lost:     lost,
+	}
+}
+
+// LeaderCallbacks gets callbacks of the leader elector
+func (c *leaderCallbacks) LeaderCallbacks() leaderelection.LeaderCallbacks {
+	return leaderelection.LeaderCallbacks{
+		OnStartedLeading: c.onStartedLeading,
+		OnStoppedLeading: c.onStoppedLeading,
+		OnNewLeader: func(identity string) {
+			log.V(1).F().Info("Leader is: %s", identity)
+		},
+	}
+}
+
+// onStartedLeading runs the function till either leadership is lost or the instance is shut down
+func (c *leaderCallbacks) onStartedLeading(leadingCtx context.Context) {
+	c.mutex.Lock()
+	if c.stopped {
+		c.mutex.Unlock()
+		return
+	}
+	c.running.Add(1)
+	c.mutex.Unlock()
+	defer c.running.Done()
+
+	// Function is stopped either on shutdown or on leadership loss
+	runCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-leadingCtx.Done():
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	log.V(1).F().Info("Leadership acquired by: %s", c.identity)
+	metricsLeaderElectionTransition(runCtx, true)
+	c.run(runCtx)
+}
+
+// onStoppedLeading waits for the function to complete. Lease is released by the elector afterwards
+func (c *leaderCallbacks) onStoppedLeading() {
+	if !isLeader.Load() {
+		// Has not been the leader
+		return
+	}
+	// Function is being stopped already, since its context is cancelled along with the leading context
+	c.stop()
+	metricsLeaderElectionTransition(context.Background(), false)
+	if c.ctx.Err() != nil {
+		log.V(1).F().Info("Leadership released by: %s", c.identity)
+		return
+	}
+	// Controllers can not be restarted within the same process, thus the instance is shut down gracefully
+	// and the pod is restarted as stand-by
+	log.F().Warning("Leadership lost by: %s", c.identity)
+	c.lost()
+}
+
+// stop prevents the function from being started and waits for the running one to complete
+func (c *leaderCallbacks) stop() {
+	c.mutex.Lock()
+	c.stopped = true
+	c.mutex.Unlock()
+	c.running.Wait()
+}
+
+func ensureLeaderElectionMetrics() *leaderElectionMetrics {
+	leMetricsMutex.Lock()
+	defer leMetricsMutex.Unlock()
+
+	if leMetrics != nil {
+		return leMetrics
+	}
+	if metrics.Meter() == nil {
+		// Metrics exporter has not been started yet
+		return nil
+	}
+
+	transitions, _ := metrics.Meter().Int64Counter(
+		"clickhouse_operator_leader_election_transitions",
+		metric.WithDescription("number of leadership acquisitions and losses of the operator instance"),
+		metric.WithUnit("items"),
+	)
+	leader, _ := metrics.Meter().Int64ObservableGauge(
+		"clickhouse_operator_leader_election_leader",
+		metric.WithDescription("whether the operator instance is the leader"),
+		metric.WithUnit("items"),
+		metric.WithInt64Callback(observeLeader),
+	)
+	leMetrics = &leaderElectionMetrics{
+		Transitions: transitions,
+		Leader:      leader,
+	}
+	return leMetrics
+}
+
+// metricsLeaderElectionTransition records leadership acquisition or loss
+func metricsLeaderElectionTransition(ctx context.Context, leader bool) {
+	isLeader.Store(leader)
+	transition := "lost"
+	if leader {
+		transition = "acquired"
+	}
+	if m := ensureLeaderElectionMetrics(); m != nil {
+		m.Transitions.Add(ctx, 1, metric.WithAttributes(attribute.String("transition", transition)))
+	}
+}
+
+// observeLeader reports whether the instance is the leader at the moment
+func observeLeader(_ context.Context, observer metric.Int64Observer) error {
+	var value int64
+	if isLeader.Load() {
+		value = 1
+	}
+	observer.Observe(value)
+	return nil
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLeaderRun is a function run by the leader, which records its state
+type testLeaderRun struct {
+	started   chan struct{}
+	release   chan struct{}
+	completed atomic.Bool
+}
+
+func newTestLeaderRun() *testLeaderRun {
+	return &testLeaderRun{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+// run is running till its context is cancelled, and completes as soon as it is released afterwards
+func (r *testLeaderRun) run(ctx context.Context) {
+	close(r.started)
+	<-ctx.Done()
+	<-r.release
+	r.completed.Store(true)
+}
+
+func TestLeaderCallbacksLost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := newTestLeaderRun()
+	var lost atomic.Int32
+	callbacks := newLeaderCallbacks(ctx, "test", r.run, func() {
+		lost.Add(1)
+	}).LeaderCallbacks()
+
+	// Callbacks of the instance, which has never been the leader, do nothing
+	callbacks.OnStoppedLeading()
+	require.False(t, isLeader.Load())
+	require.Zero(t, lost.Load())
+
+	leadingCtx, cancelLeading := context.WithCancel(ctx)
+	startedLeading := make(chan struct{})
+	go func() {
+		defer close(startedLeading)
+		callbacks.OnStartedLeading(leadingCtx)
+	}()
+	<-r.started
+	require.True(t, isLeader.Load())
+
+	// Leadership is lost. Controllers are stopped before the instance is reported as not the leader
+	cancelLeading()
+	stoppedLeading := make(chan struct{})
+	go func() {
+		defer close(stoppedLeading)
+		callbacks.OnStoppedLeading()
+	}()
+	select {
+	case <-stoppedLeading:
+		require.Fail(t, "leading stopped while controllers are running")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.True(t, isLeader.Load())
+	require.Zero(t, lost.Load())
+
+	close(r.release)
+	<-stoppedLeading
+	<-startedLeading
+	require.True(t, r.completed.Load())
+	require.False(t, isLeader.Load())
+	// Instance is to be shut down, since controllers can not be restarted
+	require.Equal(t, int32(1), lost.Load())
+}
+
+func TestLeaderCallbacksShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := newTestLeaderRun()
+	var lost atomic.Int32
+	c := newLeaderCallbacks(ctx, "test", r.run, func() {
+		lost.Add(1)
+	})
+	callbacks := c.LeaderCallbacks()
+
+	leadingCtx, cancelLeading := context.WithCancel(context.Background())
+	defer cancelLeading()
+	go callbacks.OnStartedLeading(leadingCtx)
+	<-r.started
+	require.True(t, isLeader.Load())
+
+	// Shutdown stops controllers, while lease is still held
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		c.stop()
+	}()
+	select {
+	case <-stopped:
+		require.Fail(t, "stopped while controllers are running")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(r.release)
+	<-stopped
+	require.True(t, r.completed.Load())
+
+	// Elector is cancelled afterwards and leadership is released, not lost
+	cancelLeading()
+	callbacks.OnStoppedLeading()
+	require.False(t, isLeader.Load())
+	require.Zero(t, lost.Load())
+
+	// Controllers are not started after being stopped
+	callbacks.OnStartedLeading(context.Background())
+	require.False(t, isLeader.Load())
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	apiMachineryRuntime "k8s.io/apimachinery/pkg/runtime"
+	clientGoScheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlRuntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	apiChi "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+)
+
+// Users and schema migrations controllers run within own manager, so they do not depend on keeper being initialized
+var (
+	resourcesScheme  *apiMachineryRuntime.Scheme
+	resourcesManager ctrlRuntime.Manager
+	resourcesLogger  logr.Logger
+)
+
+// initResources initializes manager of ClickHouseUser and ClickHouseSchemaMigration controllers and registers them within
+func initResources(ctx context.Context) error {
+	var err error
+
+	resourcesLogger = ctrl.Log.WithName("resources-runner")
+
+	resourcesScheme = apiMachineryRuntime.NewScheme()
+	if err = clientGoScheme.AddToScheme(resourcesScheme); err != nil {
+		resourcesLogger.Error(err, "init resources - unable to clientGoScheme.AddToScheme")
+		return err
+	}
+	if err = apiChi.AddToScheme(resourcesScheme); err != nil {
+		resourcesLogger.Error(err, "init resources - unable to apiChi.AddToScheme")
+		return err
+	}
+
+	resourcesManager, err = ctrlRuntime.NewManager(ctrlRuntime.GetConfigOrDie(), ctrlRuntime.Options{
+		Scheme: resourcesScheme,
+		Cache: cache.Options{
+			Namespaces: []string{chop.Config().GetInformerNamespace()},
+		},
+		// Metrics endpoint is served by keeper manager already
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		resourcesLogger.Error(err, "init resources - unable to ctrlRuntime.NewManager")
+		return err
+	}
+
+	if err = initUser(ctx, resourcesManager); err != nil {
+		return err
+	}
+	if err = initSchemaMigration(ctx, resourcesManager); err != nil {
+		return err
+	}
+
+	// Initialization successful
+	return nil
+}
+
+func runResources(ctx context.Context) error {
+	if err := resourcesManager.Start(ctx); err != nil {
+		resourcesLogger.Error(err, "run resources - unable to manager.Start")
+		return err
+	}
+	// Run successful
+	return nil
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+
+	core "k8s.io/api/core/v1"
+	ctrlRuntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	controller "github.com/minorhacks/clickhouse-operator/pkg/controller/chsm"
+)
+
+// initSchemaMigration registers ClickHouseSchemaMigration controller within the manager
+func initSchemaMigration(ctx context.Context, mgr ctrlRuntime.Manager) error {
+	reconciler := &controller.ChsmReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	err := ctrlRuntime.
+		NewControllerManagedBy(mgr).
+		For(&api.ClickHouseSchemaMigration{}).
+		// Edited ConfigMap with migrations has to be re-applied
+		Watches(&core.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(reconciler.FindMigrationsForConfigMap)).
+		Complete(reconciler)
+	if err != nil {
+		resourcesLogger.Error(err, "init schema migration - unable to ctrlRuntime.NewControllerManagedBy")
+		return err
+	}
+
+	// Initialization successful
+	return nil
+}
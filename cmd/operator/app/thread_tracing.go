@@ -0,0 +1,52 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/metrics"
+)
+
+// tracingShutdownTimeout specifies how long to wait for the remaining spans to be exported on exit
+const tracingShutdownTimeout = 5 * time.Second
+
+// initTracing starts export of reconcile spans in case tracing is enabled.
+// Returns function, which flushes spans remaining on exit
+func initTracing(ctx context.Context) func() {
+	config := chop.Config().Tracing
+	if !config.Enabled {
+		return func() {}
+	}
+
+	log.S().P()
+	defer log.E().P()
+
+	shutdown, err := metrics.StartTracing(ctx, config.Endpoint, config.Insecure, config.SampleRatio, config.Statements)
+	if err != nil {
+		log.V(1).F().Error("Unable to start tracing. Err: %v", err)
+		return func() {}
+	}
+	log.V(1).F().Info("Tracing started. Endpoint: %s Sample ratio: %v", config.Endpoint, config.SampleRatio)
+
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		shutdown(shutdownCtx)
+	}
+}
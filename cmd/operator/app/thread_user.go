@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+
+	core "k8s.io/api/core/v1"
+	ctrlRuntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	controller "github.com/minorhacks/clickhouse-operator/pkg/controller/chu"
+)
+
+// initUser registers ClickHouseUser controller within the manager
+func initUser(ctx context.Context, mgr ctrlRuntime.Manager) error {
+	reconciler := &controller.ChuReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	err := ctrlRuntime.
+		NewControllerManagedBy(mgr).
+		For(&api.ClickHouseUser{}).
+		// Changed password has to be applied to SQL-provisioned users
+		Watches(&core.Secret{}, handler.EnqueueRequestsFromMapFunc(reconciler.FindUsersForSecret)).
+		Complete(reconciler)
+	if err != nil {
+		resourcesLogger.Error(err, "init user - unable to ctrlRuntime.NewControllerManagedBy")
+		return err
+	}
+
+	// Initialization successful
+	return nil
+}
@@ -138,6 +138,22 @@ if [[ "${MANIFEST_PRINT_CRD}" == "yes" ]]; then
     cat "${TEMPLATES_DIR}/${SECTION_FILE_NAME}" | \
         OPERATOR_VERSION="${OPERATOR_VERSION}"    \
         envsubst
+
+    # Render CHU
+    SECTION_FILE_NAME="clickhouse-operator-install-yaml-template-01-section-crd-04-chu.yaml"
+    ensure_file "${TEMPLATES_DIR}" "${SECTION_FILE_NAME}" "${REPO_PATH_TEMPLATES_PATH}"
+    render_separator
+    cat "${TEMPLATES_DIR}/${SECTION_FILE_NAME}" | \
+        OPERATOR_VERSION="${OPERATOR_VERSION}"    \
+        envsubst
+
+    # Render CHSM
+    SECTION_FILE_NAME="clickhouse-operator-install-yaml-template-01-section-crd-05-chsm.yaml"
+    ensure_file "${TEMPLATES_DIR}" "${SECTION_FILE_NAME}" "${REPO_PATH_TEMPLATES_PATH}"
+    render_separator
+    cat "${TEMPLATES_DIR}/${SECTION_FILE_NAME}" | \
+        OPERATOR_VERSION="${OPERATOR_VERSION}"    \
+        envsubst
 fi
 
 # Render RBAC section for ClusterRole
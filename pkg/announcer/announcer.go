@@ -15,10 +15,13 @@
 package announcer
 
 import (
+	"log/slog"
+	"os"
 	"reflect"
 	"strconv"
 
 	log "github.com/golang/glog"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/minorhacks/clickhouse-operator/pkg/util/runtime"
@@ -42,6 +45,10 @@ type Announcer struct {
 	prefix string
 	// meta specifies meta-information of the object, if required
 	meta string
+	// fields specifies structured fields of the object, if required
+	fields fields
+	// correlation specifies IDs of the reconcile log line relates to
+	correlation correlation
 }
 
 // announcer which would be used in top-level functions, can be called as a 'default announcer'
@@ -184,17 +191,68 @@ func (a Announcer) M(m ...interface{}) Announcer {
 			if typed.Spec.HasTaskID() {
 				b.meta += "/" + typed.Spec.GetTaskID()
 			}
+			b.fields = fields{
+				namespace: typed.Namespace,
+				chi:       typed.Name,
+				taskID:    typed.Spec.GetTaskID(),
+			}
+		case *v1.Cluster:
+			if typed == nil {
+				return a
+			}
+			address := typed.Runtime.Address
+			b.fields = fields{
+				namespace: address.Namespace,
+				chi:       address.CHIName,
+				cluster:   address.ClusterName,
+			}
+		case *v1.ChiShard:
+			if typed == nil {
+				return a
+			}
+			address := typed.Runtime.Address
+			b.fields = fields{
+				namespace: address.Namespace,
+				chi:       address.CHIName,
+				cluster:   address.ClusterName,
+				shard:     address.ShardName,
+			}
+		case *v1.ChiHost:
+			if typed == nil {
+				return a
+			}
+			address := typed.Runtime.Address
+			b.fields = fields{
+				namespace: address.Namespace,
+				chi:       address.CHIName,
+				cluster:   address.ClusterName,
+				shard:     address.ShardName,
+				host:      address.HostName,
+			}
+			if chi := typed.GetCHI(); chi != nil {
+				b.fields.taskID = chi.Spec.GetTaskID()
+			}
 		default:
 			if meta, ok := a.findMeta(m[0]); ok {
 				b.meta = meta
 			} else {
 				return a
 			}
+			if object, ok := m[0].(metaV1.Object); ok {
+				b.fields = fields{
+					namespace: object.GetNamespace(),
+					object:    object.GetName(),
+				}
+			}
 		}
 	case 2:
 		namespace, _ := m[0].(string)
 		name, _ := m[1].(string)
 		b.meta = namespace + "/" + name
+		b.fields = fields{
+			namespace: namespace,
+			object:    name,
+		}
 	}
 	return b
 }
@@ -221,6 +279,14 @@ func (a Announcer) Info(format string, args ...interface{}) {
 		return
 	}
 
+	if isStructured() {
+		if (a.v > 0) && !log.V(a.v) {
+			return
+		}
+		a.writeStructured(slog.LevelInfo, format, args...)
+		return
+	}
+
 	format = a.prependFormat(format)
 	if a.v > 0 {
 		if len(args) > 0 {
@@ -249,6 +315,11 @@ func (a Announcer) Warning(format string, args ...interface{}) {
 		return
 	}
 
+	if isStructured() {
+		a.writeStructured(slog.LevelWarn, format, args...)
+		return
+	}
+
 	format = a.prependFormat(format)
 	if len(args) > 0 {
 		log.Warningf(format, args...)
@@ -269,6 +340,11 @@ func (a Announcer) Error(format string, args ...interface{}) {
 		return
 	}
 
+	if isStructured() {
+		a.writeStructured(slog.LevelError, format, args...)
+		return
+	}
+
 	format = a.prependFormat(format)
 	if len(args) > 0 {
 		log.Errorf(format, args...)
@@ -284,6 +360,13 @@ func Error(format string, args ...interface{}) {
 
 // Fatal is inspired by log.Fatalf()
 func (a Announcer) Fatal(format string, args ...interface{}) {
+	if isStructured() {
+		a.writeStructured(slog.LevelError, format, args...)
+		// Exit the same way glog does
+		log.Flush()
+		os.Exit(255)
+	}
+
 	format = a.prependFormat(format)
 	// Write and exit
 	if len(args) > 0 {
@@ -0,0 +1,183 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package announcer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Log formats
+const (
+	// FormatText specifies classic glog-based free-form text log lines
+	FormatText = "text"
+	// FormatJSON specifies structured log lines, one JSON object per line
+	FormatJSON = "json"
+)
+
+// Standard keys of the structured log lines
+const (
+	KeyNamespace   = "namespace"
+	KeyCHI         = "chi"
+	KeyCluster     = "cluster"
+	KeyShard       = "shard"
+	KeyHost        = "host"
+	KeyObject      = "object"
+	KeyTaskID      = "taskID"
+	KeyReconcileID = "reconcileID"
+	KeyV           = "v"
+	KeyFile        = "file"
+	KeyLine        = "line"
+	KeyFunction    = "function"
+	KeyTag         = "tag"
+)
+
+// structured specifies structured logger. Nil in case classic text log lines are produced
+var structured atomic.Pointer[slog.Logger]
+
+// SetFormat sets format of the log lines
+func SetFormat(format string) error {
+	switch format {
+	case "", FormatText:
+		structured.Store(nil)
+	case FormatJSON:
+		structured.Store(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+			// Verbosity is controlled by V()
+			Level: slog.LevelDebug,
+		})))
+	default:
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+	return nil
+}
+
+// fields specifies structured fields of the object log line relates to
+type fields struct {
+	namespace string
+	chi       string
+	cluster   string
+	shard     string
+	host      string
+	object    string
+	taskID    string
+}
+
+// correlation specifies IDs log lines of the same reconcile are correlated by
+type correlation struct {
+	taskID      string
+	reconcileID string
+}
+
+// correlationKey is a key of the correlation IDs within context
+type correlationKey struct{}
+
+// NewReconcileContext creates context of a new reconcile, which carries task ID and newly generated reconcile ID
+func NewReconcileContext(ctx context.Context, taskID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation{
+		taskID:      taskID,
+		reconcileID: uuid.New().String(),
+	})
+}
+
+// WithTaskID sets task ID of the reconcile context, in case task ID becomes known in the middle of the reconcile
+func WithTaskID(ctx context.Context, taskID string) context.Context {
+	c, ok := ctx.Value(correlationKey{}).(correlation)
+	if !ok || (c.taskID == taskID) {
+		return ctx
+	}
+	c.taskID = taskID
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// GetReconcileID gets ID of the reconcile context belongs to
+func GetReconcileID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	c, _ := ctx.Value(correlationKey{}).(correlation)
+	return c.reconcileID
+}
+
+// Ctx adds correlation IDs of the reconcile context belongs to
+func (a Announcer) Ctx(ctx context.Context) Announcer {
+	b := a
+	b.correlation = correlation{}
+	if ctx != nil {
+		b.correlation, _ = ctx.Value(correlationKey{}).(correlation)
+	}
+	return b
+}
+
+// Ctx adds correlation IDs of the reconcile context belongs to
+func Ctx(ctx context.Context) Announcer {
+	return announcer.Ctx(ctx)
+}
+
+// isStructured checks whether log lines are structured
+func isStructured() bool {
+	return structured.Load() != nil
+}
+
+// writeStructured writes structured log line
+func (a Announcer) writeStructured(level slog.Level, format string, args ...interface{}) {
+	logger := structured.Load()
+	if logger == nil {
+		return
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	logger.LogAttrs(context.Background(), level, msg, a.attrs()...)
+}
+
+// attrs builds attributes of the structured log line
+func (a Announcer) attrs() []slog.Attr {
+	var attrs []slog.Attr
+	str := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, slog.String(key, value))
+		}
+	}
+
+	if a.v > 0 {
+		attrs = append(attrs, slog.Int(KeyV, int(a.v)))
+	}
+	str(KeyNamespace, a.fields.namespace)
+	str(KeyCHI, a.fields.chi)
+	str(KeyCluster, a.fields.cluster)
+	str(KeyShard, a.fields.shard)
+	str(KeyHost, a.fields.host)
+	str(KeyObject, a.fields.object)
+	taskID := a.fields.taskID
+	if taskID == "" {
+		taskID = a.correlation.taskID
+	}
+	str(KeyTaskID, taskID)
+	str(KeyReconcileID, a.correlation.reconcileID)
+	str(KeyFile, a.file)
+	if a.line != 0 {
+		attrs = append(attrs, slog.Int(KeyLine, a.line))
+	}
+	str(KeyFunction, a.function)
+	str(KeyTag, a.prefix)
+	return attrs
+}
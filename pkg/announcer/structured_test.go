@@ -0,0 +1,204 @@
+package announcer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// captureStructured makes structured log lines to be written into the buffer for the duration of the test
+func captureStructured(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	structured.Store(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() {
+		structured.Store(nil)
+	})
+	return buf
+}
+
+// readStructured reads the only log line written into the buffer
+func readStructured(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	line := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	buf.Reset()
+	return line
+}
+
+func TestSetFormat(t *testing.T) {
+	t.Cleanup(func() {
+		structured.Store(nil)
+	})
+
+	require.NoError(t, SetFormat(FormatJSON))
+	require.True(t, isStructured())
+	require.NoError(t, SetFormat(FormatText))
+	require.False(t, isStructured())
+	require.NoError(t, SetFormat(FormatJSON))
+	require.NoError(t, SetFormat(""))
+	require.False(t, isStructured())
+	require.Error(t, SetFormat("xml"))
+	require.False(t, isStructured())
+}
+
+func TestReconcileContext(t *testing.T) {
+	require.Empty(t, GetReconcileID(nil))
+	require.Empty(t, GetReconcileID(context.Background()))
+
+	ctx := NewReconcileContext(context.Background(), "task-1")
+	reconcileID := GetReconcileID(ctx)
+	require.NotEmpty(t, reconcileID)
+	require.NotEqual(t, reconcileID, GetReconcileID(NewReconcileContext(context.Background(), "task-1")))
+	require.Equal(t, correlation{taskID: "task-1", reconcileID: reconcileID}, Ctx(ctx).correlation)
+
+	// Task ID is replaced, while reconcile ID is kept
+	withTaskID := WithTaskID(ctx, "task-2")
+	require.Equal(t, correlation{taskID: "task-2", reconcileID: reconcileID}, Ctx(withTaskID).correlation)
+	require.Equal(t, ctx, WithTaskID(ctx, "task-1"))
+
+	// Task ID is not attached to the context of no reconcile
+	require.Equal(t, context.Background(), WithTaskID(context.Background(), "task-1"))
+	require.Equal(t, correlation{}, Ctx(context.Background()).correlation)
+	require.Equal(t, correlation{}, Ctx(nil).correlation)
+
+	// Correlation IDs of the previous context are not inherited
+	require.Equal(t, correlation{}, Ctx(ctx).Ctx(context.Background()).correlation)
+}
+
+func TestStructuredFields(t *testing.T) {
+	taskID := "task-1"
+	chi := &v1.ClickHouseInstallation{}
+	chi.Namespace = "ns"
+	chi.Name = "chi"
+	chi.Spec.TaskID = &taskID
+
+	host := &v1.ChiHost{}
+	host.Runtime.Address = v1.ChiHostAddress{
+		Namespace:   "ns",
+		CHIName:     "chi",
+		ClusterName: "cluster",
+		ShardName:   "0",
+		HostName:    "0-1",
+	}
+	host.Runtime.CHI = chi
+
+	reconcileCtx := NewReconcileContext(context.Background(), "task-2")
+	reconcileID := GetReconcileID(reconcileCtx)
+
+	tests := []struct {
+		name     string
+		a        Announcer
+		expected map[string]interface{}
+	}{
+		{
+			name: "no fields",
+			a:    New(),
+			expected: map[string]interface{}{
+				"level": "INFO",
+				"msg":   "message 1",
+			},
+		},
+		{
+			name: "chi",
+			a:    New().M(chi),
+			expected: map[string]interface{}{
+				"level":      "INFO",
+				"msg":        "message 1",
+				KeyNamespace: "ns",
+				KeyCHI:       "chi",
+				KeyTaskID:    "task-1",
+			},
+		},
+		{
+			name: "host",
+			a:    New().M(host),
+			expected: map[string]interface{}{
+				"level":      "INFO",
+				"msg":        "message 1",
+				KeyNamespace: "ns",
+				KeyCHI:       "chi",
+				KeyCluster:   "cluster",
+				KeyShard:     "0",
+				KeyHost:      "0-1",
+				KeyTaskID:    "task-1",
+			},
+		},
+		{
+			name: "object",
+			a:    New().M("ns", "secret"),
+			expected: map[string]interface{}{
+				"level":      "INFO",
+				"msg":        "message 1",
+				KeyNamespace: "ns",
+				KeyObject:    "secret",
+			},
+		},
+		{
+			name: "task ID of the reconcile",
+			a:    New().Ctx(reconcileCtx).M("ns", "secret"),
+			expected: map[string]interface{}{
+				"level":        "INFO",
+				"msg":          "message 1",
+				KeyNamespace:   "ns",
+				KeyObject:      "secret",
+				KeyTaskID:      "task-2",
+				KeyReconcileID: reconcileID,
+			},
+		},
+		{
+			name: "task ID of the object has priority over task ID of the reconcile",
+			a:    Ctx(reconcileCtx).M(chi),
+			expected: map[string]interface{}{
+				"level":        "INFO",
+				"msg":          "message 1",
+				KeyNamespace:   "ns",
+				KeyCHI:         "chi",
+				KeyTaskID:      "task-1",
+				KeyReconcileID: reconcileID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureStructured(t)
+			tt.a.Info("message %d", 1)
+			line := readStructured(t, buf)
+			delete(line, "time")
+			require.Equal(t, tt.expected, line)
+		})
+	}
+}
+
+func TestStructuredLevels(t *testing.T) {
+	buf := captureStructured(t)
+
+	New().Warning("warning")
+	require.Equal(t, "WARN", readStructured(t, buf)["level"])
+	New().Error("error %s", "text")
+	line := readStructured(t, buf)
+	require.Equal(t, "ERROR", line["level"])
+	require.Equal(t, "error text", line["msg"])
+
+	// Format is not interpreted without args
+	New().Info("100%%s")
+	require.Equal(t, "100%%s", readStructured(t, buf)["msg"])
+
+	// Code address and tag
+	New().S().Info("")
+	line = readStructured(t, buf)
+	require.Equal(t, "start", line[KeyTag])
+	require.Equal(t, "TestStructuredLevels", line[KeyFunction])
+	require.Contains(t, line[KeyFile], "structured_test.go")
+	require.NotZero(t, line[KeyLine])
+
+	// Silenced and filtered out by verbosity announcers do not write
+	New().Silence().Info("silenced")
+	New().V(100).Info("verbose")
+	require.Zero(t, buf.Len())
+}
@@ -159,10 +159,16 @@ func (chk *ClickHouseKeeperInstallation) MergeFrom(from *ClickHouseKeeperInstall
 
 // ChkSpec defines spec section of ClickHouseKeeper resource
 type ChkSpec struct {
+	OperatorClass string            `json:"operatorClass,omitempty"          yaml:"operatorClass,omitempty"`
 	Configuration *ChkConfiguration `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
 	Templates     *apiChi.Templates `json:"templates,omitempty"              yaml:"templates,omitempty"`
 }
 
+// GetOperatorClass gets class of the operator in charge of the resource
+func (spec ChkSpec) GetOperatorClass() string {
+	return spec.OperatorClass
+}
+
 func (spec ChkSpec) GetConfiguration() *ChkConfiguration {
 	return spec.Configuration
 }
@@ -45,6 +45,10 @@ func init() {
 		&ClickHouseInstallationTemplateList{},
 		&ClickHouseOperatorConfiguration{},
 		&ClickHouseOperatorConfigurationList{},
+		&ClickHouseUser{},
+		&ClickHouseUserList{},
+		&ClickHouseSchemaMigration{},
+		&ClickHouseSchemaMigrationList{},
 	)
 }
 
@@ -23,4 +23,6 @@ const (
 	ClickHouseInstallationCRDResourceKind         = "ClickHouseInstallation"
 	ClickHouseInstallationTemplateCRDResourceKind = "ClickHouseInstallationTemplate"
 	ClickHouseOperatorCRDResourceKind             = "ClickHouseOperator"
+	ClickHouseUserCRDResourceKind                 = "ClickHouseUser"
+	ClickHouseSchemaMigrationCRDResourceKind      = "ClickHouseSchemaMigration"
 )
@@ -231,7 +231,8 @@ func (chi *ClickHouseInstallation) WalkShards(
 	return res
 }
 
-// WalkHostsFullPathAndScope walks hosts with full path
+// WalkHostsFullPathAndScope walks hosts with full path.
+// External hosts are walked as well, however they do not occupy scope addresses
 func (chi *ClickHouseInstallation) WalkHostsFullPathAndScope(
 	chiScopeCycleSize int,
 	clusterScopeCycleSize int,
@@ -252,6 +253,9 @@ func (chi *ClickHouseInstallation) WalkHostsFullPathAndScope(
 				address.ShardIndex = shardIndex
 				address.ReplicaIndex = replicaIndex
 				res = append(res, f(chi, cluster, shard, replica, host, address))
+				if host.IsExternal() {
+					continue
+				}
 				address.CHIScopeAddress.Inc()
 				address.ClusterScopeAddress.Inc()
 			}
@@ -265,7 +269,7 @@ func (chi *ClickHouseInstallation) WalkHostsFullPath(f WalkHostsAddressFn) []err
 	return chi.WalkHostsFullPathAndScope(0, 0, f)
 }
 
-// WalkHosts walks hosts with a function
+// WalkHosts walks hosts managed by the operator with a function
 func (chi *ClickHouseInstallation) WalkHosts(f func(host *ChiHost) error) []error {
 	if chi == nil {
 		return nil
@@ -278,6 +282,9 @@ func (chi *ClickHouseInstallation) WalkHosts(f func(host *ChiHost) error) []erro
 			shard := &cluster.Layout.Shards[shardIndex]
 			for replicaIndex := range shard.Hosts {
 				host := shard.Hosts[replicaIndex]
+				if host.IsExternal() {
+					continue
+				}
 				res = append(res, f(host))
 			}
 		}
@@ -286,6 +293,29 @@ func (chi *ClickHouseInstallation) WalkHosts(f func(host *ChiHost) error) []erro
 	return res
 }
 
+// WalkExternalHosts walks hosts, which are not managed by the operator, with a function
+func (chi *ClickHouseInstallation) WalkExternalHosts(f func(host *ChiHost) error) []error {
+	if chi == nil {
+		return nil
+	}
+	res := make([]error, 0)
+
+	for clusterIndex := range chi.Spec.Configuration.Clusters {
+		cluster := chi.Spec.Configuration.Clusters[clusterIndex]
+		for shardIndex := range cluster.Layout.Shards {
+			shard := &cluster.Layout.Shards[shardIndex]
+			for replicaIndex := range shard.Hosts {
+				host := shard.Hosts[replicaIndex]
+				if host.IsExternal() {
+					res = append(res, f(host))
+				}
+			}
+		}
+	}
+
+	return res
+}
+
 // WalkTillError walks hosts with a function until an error met
 func (chi *ClickHouseInstallation) WalkTillError(
 	ctx context.Context,
@@ -375,6 +405,14 @@ func (spec *ChiSpec) GetTaskID() string {
 	return ""
 }
 
+// GetOperatorClass gets class of the operator in charge of the resource
+func (spec *ChiSpec) GetOperatorClass() string {
+	if spec == nil {
+		return ""
+	}
+	return spec.OperatorClass
+}
+
 // MergeFrom merges from spec
 func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 	if from == nil {
@@ -386,12 +424,18 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if !spec.HasTaskID() {
 			spec.TaskID = from.TaskID
 		}
+		if spec.OperatorClass == "" {
+			spec.OperatorClass = from.OperatorClass
+		}
 		if !spec.Stop.HasValue() {
 			spec.Stop = spec.Stop.MergeFrom(from.Stop)
 		}
 		if spec.Restart == "" {
 			spec.Restart = from.Restart
 		}
+		if len(spec.RestartRequests) == 0 {
+			spec.RestartRequests = from.RestartRequests
+		}
 		if !spec.Troubleshoot.HasValue() {
 			spec.Troubleshoot = spec.Troubleshoot.MergeFrom(from.Troubleshoot)
 		}
@@ -402,6 +446,10 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		if from.HasTaskID() {
 			spec.TaskID = from.TaskID
 		}
+		if from.OperatorClass != "" {
+			// Override by non-empty values only
+			spec.OperatorClass = from.OperatorClass
+		}
 		if from.Stop.HasValue() {
 			// Override by non-empty values only
 			spec.Stop = from.Stop
@@ -410,6 +458,10 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 			// Override by non-empty values only
 			spec.Restart = from.Restart
 		}
+		if len(from.RestartRequests) > 0 {
+			// Override by non-empty values only
+			spec.RestartRequests = from.RestartRequests
+		}
 		if from.Troubleshoot.HasValue() {
 			// Override by non-empty values only
 			spec.Troubleshoot = from.Troubleshoot
@@ -419,6 +471,7 @@ func (spec *ChiSpec) MergeFrom(from *ChiSpec, _type MergeType) {
 		}
 	}
 
+	spec.Hibernation = spec.Hibernation.MergeFrom(from.Hibernation, _type)
 	spec.Templating = spec.Templating.MergeFrom(from.Templating, _type)
 	spec.Reconciling = spec.Reconciling.MergeFrom(from.Reconciling, _type)
 	spec.Defaults = spec.Defaults.MergeFrom(from.Defaults, _type)
@@ -612,6 +665,14 @@ func (chi *ClickHouseInstallation) IsStopped() bool {
 	return chi.Spec.Stop.Value()
 }
 
+// IsHibernated checks whether CHI is stopped by the hibernation schedule
+func (chi *ClickHouseInstallation) IsHibernated() bool {
+	if chi == nil {
+		return false
+	}
+	return chi.GetStatus().GetHibernation().IsHibernated()
+}
+
 // Restart constants present available values for .spec.restart
 // Controlling the operator's Clickhouse instances restart policy
 const (
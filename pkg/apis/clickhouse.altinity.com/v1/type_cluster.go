@@ -250,7 +250,7 @@ func (cluster *Cluster) WalkReplicas(f func(index int, replica *ChiReplica) erro
 	return res
 }
 
-// WalkHosts walks hosts
+// WalkHosts walks hosts managed by the operator
 func (cluster *Cluster) WalkHosts(f func(host *ChiHost) error) []error {
 
 	res := make([]error, 0)
@@ -259,6 +259,9 @@ func (cluster *Cluster) WalkHosts(f func(host *ChiHost) error) []error {
 		shard := &cluster.Layout.Shards[shardIndex]
 		for replicaIndex := range shard.Hosts {
 			host := shard.Hosts[replicaIndex]
+			if host.IsExternal() {
+				continue
+			}
 			res = append(res, f(host))
 		}
 	}
@@ -266,7 +269,7 @@ func (cluster *Cluster) WalkHosts(f func(host *ChiHost) error) []error {
 	return res
 }
 
-// WalkHostsByShards walks hosts by shards
+// WalkHostsByShards walks all hosts by shards, external hosts included
 func (cluster *Cluster) WalkHostsByShards(f func(shard, replica int, host *ChiHost) error) []error {
 
 	res := make([]error, 0)
@@ -282,7 +285,7 @@ func (cluster *Cluster) WalkHostsByShards(f func(shard, replica int, host *ChiHo
 	return res
 }
 
-// WalkHostsByReplicas walks hosts by replicas
+// WalkHostsByReplicas walks all hosts by replicas, external hosts included
 func (cluster *Cluster) WalkHostsByReplicas(f func(shard, replica int, host *ChiHost) error) []error {
 
 	res := make([]error, 0)
@@ -30,16 +30,27 @@ const (
 
 // Configuration defines configuration section of .spec
 type Configuration struct {
-	Zookeeper *ChiZookeeperConfig `json:"zookeeper,omitempty" yaml:"zookeeper,omitempty"`
-	Users     *Settings           `json:"users,omitempty"     yaml:"users,omitempty"`
-	Profiles  *Settings           `json:"profiles,omitempty"  yaml:"profiles,omitempty"`
-	Quotas    *Settings           `json:"quotas,omitempty"    yaml:"quotas,omitempty"`
-	Settings  *Settings           `json:"settings,omitempty"  yaml:"settings,omitempty"`
-	Files     *Settings           `json:"files,omitempty"     yaml:"files,omitempty"`
+	Zookeeper *ChiZookeeperConfig   `json:"zookeeper,omitempty" yaml:"zookeeper,omitempty"`
+	Users     *Settings             `json:"users,omitempty"     yaml:"users,omitempty"`
+	Profiles  *Settings             `json:"profiles,omitempty"  yaml:"profiles,omitempty"`
+	Quotas    *Settings             `json:"quotas,omitempty"    yaml:"quotas,omitempty"`
+	Settings  *Settings             `json:"settings,omitempty"  yaml:"settings,omitempty"`
+	Files     *Settings             `json:"files,omitempty"     yaml:"files,omitempty"`
+	Storage   *StorageConfiguration `json:"storage,omitempty"   yaml:"storage,omitempty"`
 	// TODO refactor into map[string]ChiCluster
 	Clusters []*Cluster `json:"clusters,omitempty"  yaml:"clusters,omitempty"`
+	// VirtualClusters specifies clusters, which consist of clusters of other CHIs
+	VirtualClusters []*ChiVirtualCluster `json:"virtualClusters,omitempty" yaml:"virtualClusters,omitempty"`
+	// Format specifies format of config files generated by the operator
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
 }
 
+// Possible formats of config files generated by the operator
+const (
+	ConfigFormatXML  = "XML"
+	ConfigFormatYAML = "YAML"
+)
+
 // NewConfiguration creates new Configuration objects
 func NewConfiguration() *Configuration {
 	return new(Configuration)
@@ -61,10 +72,35 @@ func (configuration *Configuration) MergeFrom(from *Configuration, _type MergeTy
 	configuration.Quotas = configuration.Quotas.MergeFrom(from.Quotas)
 	configuration.Settings = configuration.Settings.MergeFrom(from.Settings)
 	configuration.Files = configuration.Files.MergeFrom(from.Files)
+	configuration.Storage = configuration.Storage.MergeFrom(from.Storage, _type)
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if configuration.Format == "" {
+			configuration.Format = from.Format
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Format != "" {
+			// Override by non-empty values only
+			configuration.Format = from.Format
+		}
+	}
 
 	// TODO merge clusters
 	// Copy Clusters for now
 	configuration.Clusters = from.Clusters
+	configuration.VirtualClusters = from.VirtualClusters
 
 	return configuration
 }
+
+// GetFormat gets format of config files generated by the operator
+func (configuration *Configuration) GetFormat() string {
+	if configuration == nil {
+		return ConfigFormatXML
+	}
+	if configuration.Format == "" {
+		return ConfigFormatXML
+	}
+	return configuration.Format
+}
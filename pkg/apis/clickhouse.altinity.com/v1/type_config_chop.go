@@ -68,6 +68,11 @@ const (
 	defaultChPort     = 8123
 	defaultChRootCA   = ""
 
+	// defaultChGeneratedPasswordLength specifies default length of the generated operator user password
+	defaultChGeneratedPasswordLength = 32
+	// minChGeneratedPasswordLength specifies minimal length of the generated operator user password
+	minChGeneratedPasswordLength = 16
+
 	// Timeouts used to limit connection and queries from the operator to ClickHouse instances. In seconds
 	// defaultTimeoutConnect specifies default timeout to connect to the ClickHouse instance. In seconds
 	defaultTimeoutConnect = 2
@@ -76,6 +81,24 @@ const (
 	// defaultTimeoutCollect specifies default timeout to collect metrics from the ClickHouse instance. In seconds
 	defaultTimeoutCollect = 8
 
+	// defaultSchemaDriftPeriod specifies default period of schema drift check. In minutes
+	defaultSchemaDriftPeriod = 60
+
+	// defaultReplicationHealthPeriod specifies default period of replication health check. In seconds
+	defaultReplicationHealthPeriod = 60
+	// defaultReplicationHealthMinInterval specifies default minimal interval between replication health checks of a CHI. In seconds
+	defaultReplicationHealthMinInterval = 30
+	// defaultReplicationHealthQueueSize specifies default replication queue size replica is considered to be lagging with
+	defaultReplicationHealthQueueSize = 1000
+	// defaultReplicationHealthAbsoluteDelay specifies default replication delay replica is considered to be lagging with. In seconds
+	defaultReplicationHealthAbsoluteDelay = 300
+
+	// defaultPVCAutoscalingPeriod specifies default period of PVC autoscaling check. In seconds
+	defaultPVCAutoscalingPeriod = 300
+
+	// defaultSettingsValidationStrictness specifies default strictness of settings validation
+	defaultSettingsValidationStrictness = SettingsValidationStrictnessWarning
+
 	// defaultReconcileCHIsThreadsNumber specifies default number of controller threads running concurrently.
 	// Used in case no other specified in config
 	defaultReconcileCHIsThreadsNumber = 1
@@ -99,10 +122,32 @@ const (
 	// Used in case no other specified in config
 	DefaultReconcileSystemThreadsNumber = 1
 
+	// defaultReconcileHistoryRevisions specifies default number of completed revisions of CHI kept for rollback
+	defaultReconcileHistoryRevisions = 10
+
 	// defaultTerminationGracePeriod specifies default value for TerminationGracePeriod
 	defaultTerminationGracePeriod = 30
 	// defaultRevisionHistoryLimit specifies default value for RevisionHistoryLimit
 	defaultRevisionHistoryLimit = 10
+
+	// defaultLeaderElectionLeaseName specifies default name of the Lease object operator instances compete for
+	defaultLeaderElectionLeaseName = "clickhouse-operator-leader"
+	// defaultLeaderElectionLeaseDuration specifies default duration non-leader instances wait before they take over. In seconds
+	defaultLeaderElectionLeaseDuration = 15
+	// defaultLeaderElectionRenewDeadline specifies default duration leader retries to renew the lease within. In seconds
+	defaultLeaderElectionRenewDeadline = 10
+	// defaultLeaderElectionRetryPeriod specifies default duration between lease acquire/renew attempts. In seconds
+	defaultLeaderElectionRetryPeriod = 2
+
+	// defaultShardingGroup specifies default name of the group of operator instances CHIs are partitioned among
+	defaultShardingGroup = "clickhouse-operator"
+	// defaultShardingLeaseDuration specifies default duration instance is considered to be alive after the latest renewal. In seconds
+	defaultShardingLeaseDuration = 30
+	// defaultShardingRenewPeriod specifies default duration between renewals of the instance's lease. In seconds
+	defaultShardingRenewPeriod = 10
+
+	// defaultTracingSampleRatio specifies default ratio of reconciles traced
+	defaultTracingSampleRatio = 1.0
 )
 
 // Username/password replacers
@@ -163,6 +208,49 @@ type OperatorConfigRuntime struct {
 type OperatorConfigWatch struct {
 	// Namespaces where operator watches for events
 	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	// NamespaceSelector specifies label selector of namespaces where operator watches for events,
+	// in addition to the namespaces listed explicitly. Namespaces are (de)selected as soon as their labels change
+	NamespaceSelector string `json:"namespaceSelector" yaml:"namespaceSelector"`
+	// OperatorClass specifies class of the resources operator is in charge of. Resources of other classes are ignored
+	OperatorClass string `json:"operatorClass" yaml:"operatorClass"`
+	// OperatorClassDefault specifies whether resources with no class specified are handled as well,
+	// in case operator has own class specified
+	OperatorClassDefault bool `json:"operatorClassDefault" yaml:"operatorClassDefault"`
+
+	Runtime *OperatorConfigWatchRuntime `json:"-" yaml:"-"`
+}
+
+// OperatorConfigWatchRuntime specifies watch runtime section
+// +k8s:deepcopy-gen=false
+type OperatorConfigWatchRuntime struct {
+	// namespaces selected by the namespace selector at the moment
+	namespaces map[string]bool
+	mutex      sync.RWMutex
+}
+
+// DeepCopyInto copies the receiver, writing into out. in must be non-nil.
+// Written manually, since the mutex is not to be copied
+func (in *OperatorConfigWatchRuntime) DeepCopyInto(out *OperatorConfigWatchRuntime) {
+	in.mutex.RLock()
+	defer in.mutex.RUnlock()
+
+	out.namespaces = nil
+	if in.namespaces != nil {
+		out.namespaces = make(map[string]bool, len(in.namespaces))
+		for namespace, selected := range in.namespaces {
+			out.namespaces[namespace] = selected
+		}
+	}
+}
+
+// DeepCopy copies the receiver, creating a new OperatorConfigWatchRuntime.
+func (in *OperatorConfigWatchRuntime) DeepCopy() *OperatorConfigWatchRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigWatchRuntime)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // OperatorConfigConfig specifies Config section
@@ -270,6 +358,18 @@ type OperatorConfigClickHouse struct {
 			Connect time.Duration `json:"connect" yaml:"connect"`
 			Query   time.Duration `json:"query"   yaml:"query"`
 		} `json:"timeouts" yaml:"timeouts"`
+
+		// Generate specifies per-CHI password generation for the operator user.
+		// When enabled, each CHI gets its own strong password, kept in a k8s Secret owned by the CHI,
+		// instead of the password specified (above) explicitly or via k8s Secret
+		Generate struct {
+			Enabled bool `json:"enabled" yaml:"enabled"`
+			// Length of the generated password
+			Length int `json:"length" yaml:"length"`
+			// RotationPeriod specifies how often the generated password is rotated. In hours.
+			// Zero value means no scheduled rotation, password is rotated on request only
+			RotationPeriod time.Duration `json:"rotationPeriod" yaml:"rotationPeriod"`
+		} `json:"generate" yaml:"generate"`
 	} `json:"access" yaml:"access"`
 
 	// Metrics used to specify how the operator fetches metrics from ClickHouse instances
@@ -278,8 +378,73 @@ type OperatorConfigClickHouse struct {
 			Collect time.Duration `json:"collect" yaml:"collect"`
 		} `json:"timeouts" yaml:"timeouts"`
 	} `json:"metrics" yaml:"metrics"`
+
+	// SchemaDrift specifies periodic check of tables schema being the same on all hosts of each cluster
+	SchemaDrift struct {
+		Enabled bool `json:"enabled" yaml:"enabled"`
+		// Period specifies how often the check is performed. In minutes
+		Period time.Duration `json:"period" yaml:"period"`
+	} `json:"schemaDrift" yaml:"schemaDrift"`
+
+	// ReplicationHealth specifies periodic check of replicated tables health and remediations of unhealthy replicas
+	ReplicationHealth struct {
+		Enabled bool `json:"enabled" yaml:"enabled"`
+		// Period specifies how often the check is performed. In seconds
+		Period time.Duration `json:"period" yaml:"period"`
+		// MinInterval specifies minimal interval between two checks of a CHI, no matter how often the check is requested.
+		// In seconds
+		MinInterval time.Duration `json:"minInterval" yaml:"minInterval"`
+		// Thresholds specify replication delay replica is considered to be lagging with
+		Thresholds struct {
+			QueueSize int `json:"queueSize" yaml:"queueSize"`
+			// AbsoluteDelay is specified in seconds
+			AbsoluteDelay time.Duration `json:"absoluteDelay" yaml:"absoluteDelay"`
+		} `json:"thresholds" yaml:"thresholds"`
+		// Remediations specify what is done with each kind of unhealthy replica.
+		// One of: None, RestartReplica, RestoreReplica, ExcludeHost
+		Remediations struct {
+			ReadOnly     string `json:"readOnly"     yaml:"readOnly"`
+			LostMetadata string `json:"lostMetadata" yaml:"lostMetadata"`
+			Lagging      string `json:"lagging"      yaml:"lagging"`
+		} `json:"remediations" yaml:"remediations"`
+	} `json:"replicationHealth" yaml:"replicationHealth"`
+
+	// PVCAutoscaling specifies periodic check of disk usage of PVCs, which have autoscaling enabled in VolumeClaimTemplate
+	PVCAutoscaling struct {
+		// Period specifies how often the check is performed. In seconds
+		Period time.Duration `json:"period" yaml:"period"`
+	} `json:"pvcAutoscaling" yaml:"pvcAutoscaling"`
+
+	// SettingsValidation specifies validation of names and value types of settings and profiles
+	// against the catalog of ClickHouse settings
+	SettingsValidation struct {
+		// Strictness specifies how invalid settings are handled. One of: None, Warning, Error
+		Strictness string `json:"strictness" yaml:"strictness"`
+	} `json:"settingsValidation" yaml:"settingsValidation"`
 }
 
+// Possible remediations of unhealthy replica
+const (
+	// ReplicationRemediationNone means problem is reported only
+	ReplicationRemediationNone = "None"
+	// ReplicationRemediationRestartReplica means SYSTEM RESTART REPLICA is run for the table
+	ReplicationRemediationRestartReplica = "RestartReplica"
+	// ReplicationRemediationRestoreReplica means SYSTEM RESTORE REPLICA is run for the table
+	ReplicationRemediationRestoreReplica = "RestoreReplica"
+	// ReplicationRemediationExcludeHost means host is excluded from the cluster until it is healthy again
+	ReplicationRemediationExcludeHost = "ExcludeHost"
+)
+
+// Possible strictness of settings validation
+const (
+	// SettingsValidationStrictnessNone means settings are not validated
+	SettingsValidationStrictnessNone = "None"
+	// SettingsValidationStrictnessWarning means invalid settings are reported and reconciled as they are
+	SettingsValidationStrictnessWarning = "Warning"
+	// SettingsValidationStrictnessError means CHI with settings of mismatching types is not reconciled, unknown settings are reported only
+	SettingsValidationStrictnessError = "Error"
+)
+
 // OperatorConfigTemplate specifies template section
 type OperatorConfigTemplate struct {
 	CHI OperatorConfigCHI `json:"chi" yaml:"chi"`
@@ -356,6 +521,11 @@ type OperatorConfigReconcile struct {
 	} `json:"statefulSet" yaml:"statefulSet"`
 
 	Host OperatorConfigReconcileHost `json:"host" yaml:"host"`
+
+	History struct {
+		// Revisions specifies number of the latest completed revisions of CHI kept for rollback
+		Revisions int `json:"revisions" yaml:"revisions"`
+	} `json:"history" yaml:"history"`
 }
 
 // OperatorConfigReconcileHost defines reconcile host config
@@ -413,6 +583,46 @@ type OperatorConfig struct {
 		// Grace period for Pod termination.
 		TerminationGracePeriod int `json:"terminationGracePeriod" yaml:"terminationGracePeriod"`
 	} `json:"pod" yaml:"pod"`
+	LeaderElection struct {
+		// Enabled specifies whether operator instances elect the leader, which is the only one to run controllers
+		Enabled bool `json:"enabled" yaml:"enabled"`
+		// LeaseName and Namespace specify Lease object instances compete for.
+		// Namespace defaults to namespace the operator runs in
+		LeaseName string `json:"leaseName" yaml:"leaseName"`
+		Namespace string `json:"namespace" yaml:"namespace"`
+		// Identity specifies name of the instance in the Lease. Defaults to operator's pod name
+		Identity string `json:"identity" yaml:"identity"`
+		// LeaseDuration, RenewDeadline and RetryPeriod are specified in seconds
+		LeaseDuration time.Duration `json:"leaseDuration" yaml:"leaseDuration"`
+		RenewDeadline time.Duration `json:"renewDeadline" yaml:"renewDeadline"`
+		RetryPeriod   time.Duration `json:"retryPeriod"   yaml:"retryPeriod"`
+	} `json:"leaderElection" yaml:"leaderElection"`
+	Sharding struct {
+		// Enabled specifies whether CHIs are partitioned among all running operator instances
+		Enabled bool `json:"enabled" yaml:"enabled"`
+		// Group specifies name of the group of instances CHIs are partitioned among.
+		// Each instance of the group maintains own Lease named after the group and the instance's identity
+		Group string `json:"group" yaml:"group"`
+		// Namespace specifies namespace of the Leases. Defaults to namespace the operator runs in
+		Namespace string `json:"namespace" yaml:"namespace"`
+		// Identity specifies name of the instance. Defaults to operator's pod name
+		Identity string `json:"identity" yaml:"identity"`
+		// LeaseDuration and RenewPeriod are specified in seconds
+		LeaseDuration time.Duration `json:"leaseDuration" yaml:"leaseDuration"`
+		RenewPeriod   time.Duration `json:"renewPeriod"   yaml:"renewPeriod"`
+	} `json:"sharding" yaml:"sharding"`
+	Tracing struct {
+		// Enabled specifies whether spans of reconciles are exported via OTLP
+		Enabled bool `json:"enabled" yaml:"enabled"`
+		// Endpoint specifies host:port of the OTLP/HTTP collector. Standard OTEL_EXPORTER_OTLP_* env vars are used by default
+		Endpoint string `json:"endpoint" yaml:"endpoint"`
+		// Insecure specifies whether plain HTTP is used to connect to the collector
+		Insecure bool `json:"insecure" yaml:"insecure"`
+		// SampleRatio specifies ratio of reconciles traced, in (0, 1] range
+		SampleRatio float64 `json:"sampleRatio" yaml:"sampleRatio"`
+		// Statements specifies whether SQL statements are exported as span attributes. String literals are redacted
+		Statements bool `json:"statements" yaml:"statements"`
+	} `json:"tracing" yaml:"tracing"`
 	Logger struct {
 		// Logger section
 		LogToStderr     string `json:"logtostderr"      yaml:"logtostderr"`
@@ -421,6 +631,8 @@ type OperatorConfig struct {
 		StderrThreshold string `json:"stderrthreshold"  yaml:"stderrthreshold"`
 		VModule         string `json:"vmodule"          yaml:"vmodule"`
 		LogBacktraceAt  string `json:"log_backtrace_at" yaml:"log_backtrace_at"`
+		// Format specifies format of the log lines. Either "text" or "json"
+		Format string `json:"format" yaml:"format"`
 	} `json:"logger" yaml:"logger"`
 
 	//
@@ -702,6 +914,15 @@ func (c *OperatorConfig) Postprocess() {
 	c.applyDefaultWatchNamespace()
 }
 
+func (c *OperatorConfig) normalizeSectionWatch() {
+	c.Watch.NamespaceSelector = strings.TrimSpace(c.Watch.NamespaceSelector)
+	if c.Watch.Runtime == nil {
+		c.Watch.Runtime = &OperatorConfigWatchRuntime{
+			namespaces: make(map[string]bool),
+		}
+	}
+}
+
 func (c *OperatorConfig) normalizeSectionClickHouseConfigurationFile() {
 	// Process ClickHouse configuration files section
 	// Apply default paths in case nothing specified
@@ -825,6 +1046,16 @@ func (c *OperatorConfig) normalizeSectionClickHouseAccess() {
 	// Adjust seconds to time.Duration
 	c.ClickHouse.Access.Timeouts.Query = c.ClickHouse.Access.Timeouts.Query * time.Second
 
+	// Generated password
+
+	if c.ClickHouse.Access.Generate.Length == 0 {
+		c.ClickHouse.Access.Generate.Length = defaultChGeneratedPasswordLength
+	}
+	if c.ClickHouse.Access.Generate.Length < minChGeneratedPasswordLength {
+		c.ClickHouse.Access.Generate.Length = minChGeneratedPasswordLength
+	}
+	// Adjust hours to time.Duration
+	c.ClickHouse.Access.Generate.RotationPeriod = c.ClickHouse.Access.Generate.RotationPeriod * time.Hour
 }
 
 func (c *OperatorConfig) normalizeSectionClickHouseMetrics() {
@@ -835,6 +1066,82 @@ func (c *OperatorConfig) normalizeSectionClickHouseMetrics() {
 	c.ClickHouse.Metrics.Timeouts.Collect = c.ClickHouse.Metrics.Timeouts.Collect * time.Second
 }
 
+func (c *OperatorConfig) normalizeSectionClickHouseSchemaDrift() {
+	if c.ClickHouse.SchemaDrift.Period == 0 {
+		c.ClickHouse.SchemaDrift.Period = defaultSchemaDriftPeriod
+	}
+	// Adjust minutes to time.Duration
+	c.ClickHouse.SchemaDrift.Period = c.ClickHouse.SchemaDrift.Period * time.Minute
+}
+
+func (c *OperatorConfig) normalizeSectionClickHouseReplicationHealth() {
+	if c.ClickHouse.ReplicationHealth.Period == 0 {
+		c.ClickHouse.ReplicationHealth.Period = defaultReplicationHealthPeriod
+	}
+	// Adjust seconds to time.Duration
+	c.ClickHouse.ReplicationHealth.Period = c.ClickHouse.ReplicationHealth.Period * time.Second
+
+	if c.ClickHouse.ReplicationHealth.MinInterval == 0 {
+		c.ClickHouse.ReplicationHealth.MinInterval = defaultReplicationHealthMinInterval
+	}
+	// Adjust seconds to time.Duration
+	c.ClickHouse.ReplicationHealth.MinInterval = c.ClickHouse.ReplicationHealth.MinInterval * time.Second
+
+	if c.ClickHouse.ReplicationHealth.Thresholds.QueueSize == 0 {
+		c.ClickHouse.ReplicationHealth.Thresholds.QueueSize = defaultReplicationHealthQueueSize
+	}
+	if c.ClickHouse.ReplicationHealth.Thresholds.AbsoluteDelay == 0 {
+		c.ClickHouse.ReplicationHealth.Thresholds.AbsoluteDelay = defaultReplicationHealthAbsoluteDelay
+	}
+	// Adjust seconds to time.Duration
+	c.ClickHouse.ReplicationHealth.Thresholds.AbsoluteDelay = c.ClickHouse.ReplicationHealth.Thresholds.AbsoluteDelay * time.Second
+
+	remediations := &c.ClickHouse.ReplicationHealth.Remediations
+	remediations.ReadOnly = normalizeReplicationRemediation(remediations.ReadOnly, ReplicationRemediationRestartReplica)
+	remediations.LostMetadata = normalizeReplicationRemediation(remediations.LostMetadata, ReplicationRemediationRestoreReplica)
+	remediations.Lagging = normalizeReplicationRemediation(remediations.Lagging, ReplicationRemediationExcludeHost)
+}
+
+func (c *OperatorConfig) normalizeSectionClickHousePVCAutoscaling() {
+	if c.ClickHouse.PVCAutoscaling.Period == 0 {
+		c.ClickHouse.PVCAutoscaling.Period = defaultPVCAutoscalingPeriod
+	}
+	// Adjust seconds to time.Duration
+	c.ClickHouse.PVCAutoscaling.Period = c.ClickHouse.PVCAutoscaling.Period * time.Second
+}
+
+func (c *OperatorConfig) normalizeSectionClickHouseSettingsValidation() {
+	strictness := c.ClickHouse.SettingsValidation.Strictness
+	c.ClickHouse.SettingsValidation.Strictness = defaultSettingsValidationStrictness
+	for _, known := range []string{
+		SettingsValidationStrictnessNone,
+		SettingsValidationStrictnessWarning,
+		SettingsValidationStrictnessError,
+	} {
+		if strings.EqualFold(strictness, known) {
+			c.ClickHouse.SettingsValidation.Strictness = known
+		}
+	}
+}
+
+// normalizeReplicationRemediation normalizes remediation name. Unknown remediation falls back to None
+func normalizeReplicationRemediation(remediation, _default string) string {
+	if remediation == "" {
+		return _default
+	}
+	for _, known := range []string{
+		ReplicationRemediationNone,
+		ReplicationRemediationRestartReplica,
+		ReplicationRemediationRestoreReplica,
+		ReplicationRemediationExcludeHost,
+	} {
+		if strings.EqualFold(remediation, known) {
+			return known
+		}
+	}
+	return ReplicationRemediationNone
+}
+
 func (c *OperatorConfig) normalizeSectionLogger() {
 	// Logtostderr      string `json:"logtostderr"      yaml:"logtostderr"`
 	// Alsologtostderr  string `json:"alsologtostderr"  yaml:"alsologtostderr"`
@@ -862,6 +1169,12 @@ func (c *OperatorConfig) normalizeSectionReconcileRuntime() {
 	//reconcileWaitInclude: false
 }
 
+func (c *OperatorConfig) normalizeSectionReconcileHistory() {
+	if c.Reconcile.History.Revisions <= 0 {
+		c.Reconcile.History.Revisions = defaultReconcileHistoryRevisions
+	}
+}
+
 func (c *OperatorConfig) normalizeSectionLabel() {
 	//config.IncludeIntoPropagationAnnotations
 	//config.ExcludeFromPropagationAnnotations
@@ -883,22 +1196,95 @@ func (c *OperatorConfig) normalizeSectionPod() {
 	}
 }
 
+func (c *OperatorConfig) normalizeSectionLeaderElection() {
+	if c.LeaderElection.LeaseName == "" {
+		c.LeaderElection.LeaseName = defaultLeaderElectionLeaseName
+	}
+	if c.LeaderElection.Namespace == "" {
+		c.LeaderElection.Namespace = c.Runtime.Namespace
+	}
+	if c.LeaderElection.Identity == "" {
+		c.LeaderElection.Identity = getDefaultIdentity()
+	}
+
+	if c.LeaderElection.LeaseDuration == 0 {
+		c.LeaderElection.LeaseDuration = defaultLeaderElectionLeaseDuration
+	}
+	if c.LeaderElection.RenewDeadline == 0 {
+		c.LeaderElection.RenewDeadline = defaultLeaderElectionRenewDeadline
+	}
+	if c.LeaderElection.RetryPeriod == 0 {
+		c.LeaderElection.RetryPeriod = defaultLeaderElectionRetryPeriod
+	}
+	// Adjust seconds to time.Duration
+	c.LeaderElection.LeaseDuration = c.LeaderElection.LeaseDuration * time.Second
+	c.LeaderElection.RenewDeadline = c.LeaderElection.RenewDeadline * time.Second
+	c.LeaderElection.RetryPeriod = c.LeaderElection.RetryPeriod * time.Second
+}
+
+func (c *OperatorConfig) normalizeSectionSharding() {
+	if c.Sharding.Group == "" {
+		c.Sharding.Group = defaultShardingGroup
+	}
+	if c.Sharding.Namespace == "" {
+		c.Sharding.Namespace = c.Runtime.Namespace
+	}
+	if c.Sharding.Identity == "" {
+		c.Sharding.Identity = getDefaultIdentity()
+	}
+
+	if c.Sharding.LeaseDuration == 0 {
+		c.Sharding.LeaseDuration = defaultShardingLeaseDuration
+	}
+	if c.Sharding.RenewPeriod == 0 {
+		c.Sharding.RenewPeriod = defaultShardingRenewPeriod
+	}
+	// Adjust seconds to time.Duration
+	c.Sharding.LeaseDuration = c.Sharding.LeaseDuration * time.Second
+	c.Sharding.RenewPeriod = c.Sharding.RenewPeriod * time.Second
+}
+
+func (c *OperatorConfig) normalizeSectionTracing() {
+	if (c.Tracing.SampleRatio <= 0) || (c.Tracing.SampleRatio > 1) {
+		c.Tracing.SampleRatio = defaultTracingSampleRatio
+	}
+}
+
+// getDefaultIdentity gets default identity of the operator instance, which is operator's pod name
+func getDefaultIdentity() string {
+	if identity := os.Getenv(deployment.OPERATOR_POD_NAME); identity != "" {
+		return identity
+	}
+	// Operator may run out of the cluster
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
 // normalize() makes fully-and-correctly filled OperatorConfig
 func (c *OperatorConfig) normalize() {
 	c.move()
 	c.Runtime.Namespace = os.Getenv(deployment.OPERATOR_POD_NAMESPACE)
 
+	c.normalizeSectionWatch()
 	c.normalizeSectionClickHouseConfigurationFile()
 	c.normalizeSectionClickHouseConfigurationUserDefault()
 	c.normalizeSectionClickHouseAccess()
 	c.normalizeSectionClickHouseMetrics()
+	c.normalizeSectionClickHouseSchemaDrift()
+	c.normalizeSectionClickHouseReplicationHealth()
+	c.normalizeSectionClickHousePVCAutoscaling()
+	c.normalizeSectionClickHouseSettingsValidation()
 	c.normalizeSectionTemplate()
 	c.normalizeSectionReconcileStatefulSet()
 	c.normalizeSectionReconcileRuntime()
+	c.normalizeSectionReconcileHistory()
 	c.normalizeSectionLogger()
 	c.normalizeSectionLabel()
 	c.normalizeSectionStatefulSet()
 	c.normalizeSectionPod()
+	c.normalizeSectionLeaderElection()
+	c.normalizeSectionSharding()
+	c.normalizeSectionTracing()
 }
 
 // applyEnvVarParams applies ENV VARS over config
@@ -936,6 +1322,11 @@ func (c *OperatorConfig) applyDefaultWatchNamespace() {
 		return
 	}
 
+	if c.HasWatchNamespaceSelector() {
+		// Namespaces are selected by labels
+		return
+	}
+
 	// No namespaces specified
 
 	if c.Runtime.Namespace == "kube-system" {
@@ -1008,6 +1399,11 @@ func (c *OperatorConfig) String(hideCredentials bool) string {
 // IsWatchedNamespace returns whether specified namespace is in a list of watched
 // TODO unify with GetInformerNamespace
 func (c *OperatorConfig) IsWatchedNamespace(namespace string) bool {
+	if c.HasWatchNamespaceSelector() {
+		// Namespace is watched in case it is either listed explicitly or selected by labels
+		return util.InArrayWithRegexp(namespace, c.Watch.Namespaces) || c.isSelectedNamespace(namespace)
+	}
+
 	// In case no namespaces specified - watch all namespaces
 	if len(c.Watch.Namespaces) == 0 {
 		return true
@@ -1016,6 +1412,53 @@ func (c *OperatorConfig) IsWatchedNamespace(namespace string) bool {
 	return util.InArrayWithRegexp(namespace, c.Watch.Namespaces)
 }
 
+// HasWatchNamespaceSelector checks whether watched namespaces are selected by labels
+func (c *OperatorConfig) HasWatchNamespaceSelector() bool {
+	return c.Watch.NamespaceSelector != ""
+}
+
+// SetSelectedNamespace sets whether namespace is selected by the namespace selector.
+// Returns true in case namespace selection has changed
+func (c *OperatorConfig) SetSelectedNamespace(namespace string, selected bool) bool {
+	if c.Watch.Runtime == nil {
+		return false
+	}
+
+	c.Watch.Runtime.mutex.Lock()
+	defer c.Watch.Runtime.mutex.Unlock()
+
+	if c.Watch.Runtime.namespaces[namespace] == selected {
+		return false
+	}
+	if selected {
+		c.Watch.Runtime.namespaces[namespace] = true
+	} else {
+		delete(c.Watch.Runtime.namespaces, namespace)
+	}
+	return true
+}
+
+// isSelectedNamespace checks whether namespace is selected by the namespace selector at the moment
+func (c *OperatorConfig) isSelectedNamespace(namespace string) bool {
+	if c.Watch.Runtime == nil {
+		return false
+	}
+
+	c.Watch.Runtime.mutex.RLock()
+	defer c.Watch.Runtime.mutex.RUnlock()
+
+	return c.Watch.Runtime.namespaces[namespace]
+}
+
+// IsWatchedOperatorClass returns whether resource of the specified operator class is to be handled by the operator
+func (c *OperatorConfig) IsWatchedOperatorClass(class string) bool {
+	if class == c.Watch.OperatorClass {
+		return true
+	}
+	// Resource without class specified is handled by the default operator
+	return (class == "") && c.Watch.OperatorClassDefault
+}
+
 // GetInformerNamespace is a TODO stub
 // Namespace where informers would watch notifications from
 // The thing is that InformerFactory can accept only one parameter as watched namespace,
@@ -1027,6 +1470,14 @@ func (c *OperatorConfig) IsWatchedNamespace(namespace string) bool {
 func (c *OperatorConfig) GetInformerNamespace() string {
 	// Namespace where informers would watch notifications from
 	namespace := metav1.NamespaceAll
+	if c.HasWatchNamespaceSelector() {
+		// Namespaces are selected dynamically, thus all of them have to be watched.
+		// Namespace of the informers is fixed as soon as the informer factory is created and the factory is shared
+		// by all controllers, so it can not be narrowed to the namespaces selected at the moment.
+		// Events of not selected namespaces are filtered out by IsWatchedNamespace in event handlers instead,
+		// at the cost of caching objects of all namespaces, the same way as in case of several namespaces listed explicitly
+		return namespace
+	}
 	if len(c.Watch.Namespaces) == 1 {
 		// We have exactly one watch namespace specified
 		// This scenario is implemented in go-client
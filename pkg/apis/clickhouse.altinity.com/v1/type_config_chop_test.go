@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWatchedNamespace(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		selector   string
+		selected   []string
+		namespace  string
+		watched    bool
+	}{
+		{
+			name:      "all namespaces are watched by default",
+			namespace: "ns1",
+			watched:   true,
+		},
+		{
+			name:       "listed namespace",
+			namespaces: []string{"ns1", "ns2"},
+			namespace:  "ns2",
+			watched:    true,
+		},
+		{
+			name:       "not listed namespace",
+			namespaces: []string{"ns1", "ns2"},
+			namespace:  "ns3",
+		},
+		{
+			name:       "namespace listed by regexp",
+			namespaces: []string{"dev-.*"},
+			namespace:  "dev-1",
+			watched:    true,
+		},
+		{
+			name:      "namespace selected by labels",
+			selector:  "team=db",
+			selected:  []string{"ns1"},
+			namespace: "ns1",
+			watched:   true,
+		},
+		{
+			name:      "namespaces are not watched by default in case selector is specified",
+			selector:  "team=db",
+			selected:  []string{"ns1"},
+			namespace: "ns2",
+		},
+		{
+			name:       "listed namespace is watched along with selected ones",
+			namespaces: []string{"ns2"},
+			selector:   "team=db",
+			selected:   []string{"ns1"},
+			namespace:  "ns2",
+			watched:    true,
+		},
+		{
+			name:       "selected namespace is watched along with listed ones",
+			namespaces: []string{"ns2"},
+			selector:   "team=db",
+			selected:   []string{"ns1"},
+			namespace:  "ns1",
+			watched:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &OperatorConfig{
+				Watch: OperatorConfigWatch{
+					Namespaces:        tt.namespaces,
+					NamespaceSelector: tt.selector,
+				},
+			}
+			c.normalizeSectionWatch()
+			for _, namespace := range tt.selected {
+				require.True(t, c.SetSelectedNamespace(namespace, true))
+			}
+			require.Equal(t, tt.watched, c.IsWatchedNamespace(tt.namespace))
+		})
+	}
+}
+
+func TestSetSelectedNamespace(t *testing.T) {
+	c := &OperatorConfig{
+		Watch: OperatorConfigWatch{
+			NamespaceSelector: " team=db ",
+		},
+	}
+	// Selection is not tracked before normalization
+	require.False(t, c.SetSelectedNamespace("ns1", true))
+
+	c.normalizeSectionWatch()
+	require.True(t, c.HasWatchNamespaceSelector())
+	require.Equal(t, "team=db", c.Watch.NamespaceSelector)
+
+	// Only changes of the selection are reported
+	require.False(t, c.SetSelectedNamespace("ns1", false))
+	require.True(t, c.SetSelectedNamespace("ns1", true))
+	require.False(t, c.SetSelectedNamespace("ns1", true))
+	require.True(t, c.IsWatchedNamespace("ns1"))
+	require.True(t, c.SetSelectedNamespace("ns1", false))
+	require.False(t, c.IsWatchedNamespace("ns1"))
+
+	// Informers watch all namespaces, since namespaces may be selected at any moment
+	c.Watch.Namespaces = []string{"ns2"}
+	require.Equal(t, "", c.GetInformerNamespace())
+}
+
+func TestOperatorConfigWatchRuntimeDeepCopy(t *testing.T) {
+	c := &OperatorConfig{
+		Watch: OperatorConfigWatch{
+			NamespaceSelector: "team=db",
+		},
+	}
+	c.normalizeSectionWatch()
+	c.SetSelectedNamespace("ns1", true)
+
+	// Copy keeps selected namespaces, but does not share them with the original
+	watch := c.Watch.DeepCopy()
+	require.Equal(t, map[string]bool{"ns1": true}, watch.Runtime.namespaces)
+	c.SetSelectedNamespace("ns2", true)
+	require.Equal(t, map[string]bool{"ns1": true}, watch.Runtime.namespaces)
+
+	var runtime *OperatorConfigWatchRuntime
+	require.Nil(t, runtime.DeepCopy())
+	require.Nil(t, (&OperatorConfigWatchRuntime{}).DeepCopy().namespaces)
+}
@@ -22,4 +22,10 @@ import (
 type DataSource struct {
 	// SecretKeyRef points to a secret and mirrors k8s SecretSource type
 	SecretKeyRef *core.SecretKeySelector `json:"secretKeyRef,omitempty" yaml:"secretKeyRef,omitempty"`
+	// ConfigMapKeyRef points to a config map and mirrors k8s ConfigMapKeySelector type
+	ConfigMapKeyRef *core.ConfigMapKeySelector `json:"configMapKeyRef,omitempty" yaml:"configMapKeyRef,omitempty"`
+	// FieldRef points to a field of the pod and mirrors k8s ObjectFieldSelector type
+	FieldRef *core.ObjectFieldSelector `json:"fieldRef,omitempty" yaml:"fieldRef,omitempty"`
+	// ResourceFieldRef points to a resource of the container and mirrors k8s ResourceFieldSelector type
+	ResourceFieldRef *core.ResourceFieldSelector `json:"resourceFieldRef,omitempty" yaml:"resourceFieldRef,omitempty"`
 }
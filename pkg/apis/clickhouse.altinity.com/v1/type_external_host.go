@@ -0,0 +1,52 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiExternalHost defines host, which is not managed by the operator, but is a part of the cluster layout.
+// Ports and secure flag of the external host are specified by the regular host fields
+type ChiExternalHost struct {
+	// Hostname specifies address the host is reachable by
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+}
+
+// NewChiExternalHost creates new ChiExternalHost
+func NewChiExternalHost() *ChiExternalHost {
+	return new(ChiExternalHost)
+}
+
+// GetHostname gets hostname of the external host
+func (h *ChiExternalHost) GetHostname() string {
+	if h == nil {
+		return ""
+	}
+	return h.Hostname
+}
+
+// MergeFrom merges from specified object
+func (h *ChiExternalHost) MergeFrom(from *ChiExternalHost) *ChiExternalHost {
+	if from == nil {
+		return h
+	}
+
+	if h == nil {
+		h = NewChiExternalHost()
+	}
+
+	if h.Hostname == "" {
+		h.Hostname = from.Hostname
+	}
+
+	return h
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiHibernation defines schedule CHI is stopped and started by
+type ChiHibernation struct {
+	// Sleep specifies in cron format when CHI is stopped
+	Sleep string `json:"sleep,omitempty"    yaml:"sleep,omitempty"`
+	// Wake specifies in cron format when CHI is started
+	Wake     string `json:"wake,omitempty"     yaml:"wake,omitempty"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// NewChiHibernation creates new ChiHibernation
+func NewChiHibernation() *ChiHibernation {
+	return new(ChiHibernation)
+}
+
+// GetSleep gets sleep schedule
+func (h *ChiHibernation) GetSleep() string {
+	if h == nil {
+		return ""
+	}
+	return h.Sleep
+}
+
+// GetWake gets wake schedule
+func (h *ChiHibernation) GetWake() string {
+	if h == nil {
+		return ""
+	}
+	return h.Wake
+}
+
+// GetTimezone gets timezone of the schedules
+func (h *ChiHibernation) GetTimezone() string {
+	if h == nil {
+		return ""
+	}
+	return h.Timezone
+}
+
+// MergeFrom merges from specified object
+func (h *ChiHibernation) MergeFrom(from *ChiHibernation, _type MergeType) *ChiHibernation {
+	if from == nil {
+		return h
+	}
+
+	if h == nil {
+		h = NewChiHibernation()
+	}
+
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if h.Sleep == "" {
+			h.Sleep = from.Sleep
+		}
+		if h.Wake == "" {
+			h.Wake = from.Wake
+		}
+		if h.Timezone == "" {
+			h.Timezone = from.Timezone
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.Sleep != "" {
+			// Override by non-empty values only
+			h.Sleep = from.Sleep
+		}
+		if from.Wake != "" {
+			// Override by non-empty values only
+			h.Wake = from.Wake
+		}
+		if from.Timezone != "" {
+			// Override by non-empty values only
+			h.Timezone = from.Timezone
+		}
+	}
+
+	return h
+}
+
+// ChiHibernationStatus defines hibernation state of the CHI
+type ChiHibernationStatus struct {
+	Hibernated bool `json:"hibernated"          yaml:"hibernated"`
+	// NextSleep and NextWake specify when CHI is stopped and started next time
+	NextSleep string `json:"nextSleep,omitempty" yaml:"nextSleep,omitempty"`
+	NextWake  string `json:"nextWake,omitempty"  yaml:"nextWake,omitempty"`
+}
+
+// IsHibernated checks whether CHI is stopped by the hibernation schedule
+func (s *ChiHibernationStatus) IsHibernated() bool {
+	if s == nil {
+		return false
+	}
+	return s.Hibernated
+}
@@ -37,6 +37,8 @@ type ChiHost struct {
 	Settings            *Settings         `json:"settings,omitempty"            yaml:"settings,omitempty"`
 	Files               *Settings         `json:"files,omitempty"               yaml:"files,omitempty"`
 	Templates           *ChiTemplateNames `json:"templates,omitempty"           yaml:"templates,omitempty"`
+	// External specifies host, which is not managed by the operator, but is included into remote servers
+	External *ChiExternalHost `json:"external,omitempty" yaml:"external,omitempty"`
 
 	Runtime ChiHostRuntime `json:"-" yaml:"-"`
 }
@@ -133,6 +135,16 @@ func (host *ChiHost) MergeFrom(from *ChiHost) {
 	}
 	host.Templates = host.Templates.MergeFrom(from.Templates, MergeTypeFillEmptyValues)
 	host.Templates.HandleDeprecatedFields()
+	host.External = host.External.MergeFrom(from.External)
+}
+
+// IsExternal checks whether host is not managed by the operator.
+// External host has neither StatefulSet nor Service, it is only referenced by the cluster configuration
+func (host *ChiHost) IsExternal() bool {
+	if host == nil {
+		return false
+	}
+	return host.External != nil
 }
 
 // GetHostTemplate gets host template
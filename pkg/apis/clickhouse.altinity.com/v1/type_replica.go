@@ -48,12 +48,15 @@ func (replica *ChiReplica) HasShardsCount() bool {
 	return replica.ShardsCount > 0
 }
 
-// WalkHosts walks over hosts
+// WalkHosts walks over hosts managed by the operator
 func (replica *ChiReplica) WalkHosts(f func(host *ChiHost) error) []error {
 	res := make([]error, 0)
 
 	for shardIndex := range replica.Hosts {
 		host := replica.Hosts[shardIndex]
+		if host.IsExternal() {
+			continue
+		}
 		res = append(res, f(host))
 	}
 
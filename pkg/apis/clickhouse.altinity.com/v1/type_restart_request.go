@@ -0,0 +1,91 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "github.com/minorhacks/clickhouse-operator/pkg/util"
+
+// ChiRestartRequest defines request to restart hosts addressed by cluster, shard, replica and host names.
+// Name, which is not specified, matches any. Request is run once per ID
+type ChiRestartRequest struct {
+	// ID identifies the request. Request is acknowledged in status by its ID, thus it is not run again
+	ID      string `json:"id"                yaml:"id"`
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Shard   string `json:"shard,omitempty"   yaml:"shard,omitempty"`
+	Replica string `json:"replica,omitempty" yaml:"replica,omitempty"`
+	// Host specifies either name of the host within the cluster or name of its StatefulSet
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+}
+
+// IsHostAddressed checks whether host with specified address is addressed by the request
+func (r *ChiRestartRequest) IsHostAddressed(address *ChiHostAddress) bool {
+	if (r == nil) || (address == nil) {
+		return false
+	}
+	switch {
+	case (r.Cluster != "") && (r.Cluster != address.ClusterName):
+		return false
+	case (r.Shard != "") && (r.Shard != address.ShardName):
+		return false
+	case (r.Replica != "") && (r.Replica != address.ReplicaName):
+		return false
+	case (r.Host != "") && (r.Host != address.HostName) && (r.Host != address.StatefulSet):
+		return false
+	}
+	return true
+}
+
+// Possible phases of restart request
+const (
+	// RestartRequestPhaseInProgress means hosts addressed by the request are being restarted
+	RestartRequestPhaseInProgress = "InProgress"
+	// RestartRequestPhaseCompleted means all hosts addressed by the request are restarted
+	RestartRequestPhaseCompleted = "Completed"
+	// RestartRequestPhaseFailed means request addresses no hosts
+	RestartRequestPhaseFailed = "Failed"
+)
+
+// ChiRestartRequestStatus defines acknowledged restart request
+type ChiRestartRequestStatus struct {
+	ID    string `json:"id"    yaml:"id"`
+	Phase string `json:"phase" yaml:"phase"`
+	// Hosts lists hosts addressed by the request at the moment it was acknowledged
+	Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	// RestartedHosts lists hosts restarted already
+	RestartedHosts []string `json:"restartedHosts,omitempty" yaml:"restartedHosts,omitempty"`
+	StartedAt      string   `json:"startedAt,omitempty"      yaml:"startedAt,omitempty"`
+	FinishedAt     string   `json:"finishedAt,omitempty"     yaml:"finishedAt,omitempty"`
+	Error          string   `json:"error,omitempty"          yaml:"error,omitempty"`
+}
+
+// IsHostPending checks whether host is addressed by the request, which is in progress, and is not restarted yet
+func (r *ChiRestartRequestStatus) IsHostPending(host string) bool {
+	if r == nil {
+		return false
+	}
+	return (r.Phase == RestartRequestPhaseInProgress) && util.InArray(host, r.Hosts) && !util.InArray(host, r.RestartedHosts)
+}
+
+// IsCompleted checks whether all hosts addressed by the request are restarted
+func (r *ChiRestartRequestStatus) IsCompleted() bool {
+	if r == nil {
+		return false
+	}
+	for _, host := range r.Hosts {
+		if !util.InArray(host, r.RestartedHosts) {
+			return false
+		}
+	}
+	return true
+}
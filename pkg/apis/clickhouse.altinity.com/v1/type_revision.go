@@ -0,0 +1,47 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiRevision defines successfully completed normalized spec of the CHI, persisted in a ConfigMap.
+// CHI can be rolled back to any revision listed in status via .spec.rollbackTo
+type ChiRevision struct {
+	// Revision is a sequence number of the revision within the CHI
+	Revision int `json:"revision" yaml:"revision"`
+	// Generation specifies generation of the CHI, which has been completed
+	Generation int64 `json:"generation,omitempty" yaml:"generation,omitempty"`
+	// TaskID specifies task ID of the reconcile, which has completed the revision
+	TaskID      string `json:"taskID,omitempty"      yaml:"taskID,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty" yaml:"completedAt,omitempty"`
+	// ConfigMap specifies name of the ConfigMap the spec is persisted in
+	ConfigMap string `json:"configMap" yaml:"configMap"`
+	// Hash specifies hash of the spec, thus reconciles of the same spec do not produce new revisions
+	Hash string `json:"hash,omitempty" yaml:"hash,omitempty"`
+}
+
+// HasRollbackTo checks whether rollback to a revision is requested
+func (spec *ChiSpec) HasRollbackTo() bool {
+	if spec == nil {
+		return false
+	}
+	return spec.RollbackTo != nil
+}
+
+// GetRollbackTo gets revision rollback is requested to
+func (spec *ChiSpec) GetRollbackTo() int {
+	if !spec.HasRollbackTo() {
+		return 0
+	}
+	return *spec.RollbackTo
+}
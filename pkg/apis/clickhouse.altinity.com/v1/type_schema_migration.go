@@ -0,0 +1,129 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseSchemaMigration defines ordered set of SQL migrations to be applied to ClickHouseInstallation
+type ClickHouseSchemaMigration struct {
+	meta.TypeMeta   `json:",inline"            yaml:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   ClickHouseSchemaMigrationSpec    `json:"spec"             yaml:"spec"`
+	Status *ClickHouseSchemaMigrationStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseSchemaMigrationList defines a list of ClickHouseSchemaMigration resources
+type ClickHouseSchemaMigrationList struct {
+	meta.TypeMeta `json:",inline"  yaml:",inline"`
+	meta.ListMeta `json:"metadata" yaml:"metadata"`
+	Items         []ClickHouseSchemaMigration `json:"items" yaml:"items"`
+}
+
+// Possible values of ClickHouseSchemaMigration status
+const (
+	ClickHouseSchemaMigrationStatusCompleted = StatusCompleted
+	ClickHouseSchemaMigrationStatusPending   = "Pending"
+	ClickHouseSchemaMigrationStatusFailed    = "Failed"
+)
+
+// Possible values of per-migration status
+const (
+	// SchemaMigrationApplied means migration is applied and recorded in the history table
+	SchemaMigrationApplied = "Applied"
+	// SchemaMigrationPending means migration is not applied yet
+	SchemaMigrationPending = "Pending"
+	// SchemaMigrationFailed means migration failed to be applied
+	SchemaMigrationFailed = "Failed"
+	// SchemaMigrationModified means migration is applied, but its SQL has been edited afterwards
+	SchemaMigrationModified = "Modified"
+)
+
+// DefaultSchemaMigrationHistoryTable specifies table migrations history is kept in by default
+const DefaultSchemaMigrationHistoryTable = "default.schema_migrations"
+
+// ClickHouseSchemaMigrationSpec defines spec section of ClickHouseSchemaMigration resource
+type ClickHouseSchemaMigrationSpec struct {
+	// Target specifies ClickHouseInstallation migrations are applied to
+	Target ClickHouseSchemaMigrationTarget `json:"target" yaml:"target"`
+	// ConfigMapRef points to ConfigMap with migrations. Each key with '.sql' suffix is a migration,
+	// migrations are applied in the lexicographical order of the keys
+	ConfigMapRef core.LocalObjectReference `json:"configMapRef" yaml:"configMapRef"`
+	// HistoryTable specifies table, in the 'database.table' form, which keeps history of applied migrations
+	HistoryTable string `json:"historyTable,omitempty" yaml:"historyTable,omitempty"`
+}
+
+// ClickHouseSchemaMigrationTarget specifies ClickHouseInstallation migrations are applied to
+type ClickHouseSchemaMigrationTarget struct {
+	// CHI specifies name of the ClickHouseInstallation within the same namespace
+	CHI string `json:"chi" yaml:"chi"`
+	// Cluster specifies cluster, host of which is used to run migrations. The first cluster is used by default
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// ClickHouseSchemaMigrationStatus defines status section of ClickHouseSchemaMigration resource
+type ClickHouseSchemaMigrationStatus struct {
+	Status             string                       `json:"status,omitempty"             yaml:"status,omitempty"`
+	Error              string                       `json:"error,omitempty"              yaml:"error,omitempty"`
+	ObservedGeneration int64                        `json:"observedGeneration,omitempty" yaml:"observedGeneration,omitempty"`
+	Migrations         []SchemaMigrationEntryStatus `json:"migrations,omitempty"         yaml:"migrations,omitempty"`
+}
+
+// SchemaMigrationEntryStatus defines status of one migration
+type SchemaMigrationEntryStatus struct {
+	Version   string `json:"version"             yaml:"version"`
+	Checksum  string `json:"checksum,omitempty"  yaml:"checksum,omitempty"`
+	Status    string `json:"status,omitempty"    yaml:"status,omitempty"`
+	AppliedAt string `json:"appliedAt,omitempty" yaml:"appliedAt,omitempty"`
+	Error     string `json:"error,omitempty"     yaml:"error,omitempty"`
+	// Statements specifies number of statements in the migration
+	Statements int `json:"statements,omitempty"        yaml:"statements,omitempty"`
+	// AppliedStatements specifies number of statements applied, partially applied migration is resumed from the next one
+	AppliedStatements int `json:"appliedStatements,omitempty" yaml:"appliedStatements,omitempty"`
+}
+
+// GetHistoryTable gets table history of applied migrations is kept in
+func (m *ClickHouseSchemaMigration) GetHistoryTable() string {
+	if (m == nil) || (m.Spec.HistoryTable == "") {
+		return DefaultSchemaMigrationHistoryTable
+	}
+	return m.Spec.HistoryTable
+}
+
+// IsTargeting checks whether migrations are applied to specified ClickHouseInstallation
+func (m *ClickHouseSchemaMigration) IsTargeting(chi *ClickHouseInstallation) bool {
+	if (m == nil) || (chi == nil) {
+		return false
+	}
+	return (m.Namespace == chi.Namespace) && (m.Spec.Target.CHI == chi.Name)
+}
+
+// EnsureStatus ensures status is in place
+func (m *ClickHouseSchemaMigration) EnsureStatus() *ClickHouseSchemaMigrationStatus {
+	if m == nil {
+		return nil
+	}
+	if m.Status == nil {
+		m.Status = &ClickHouseSchemaMigrationStatus{}
+	}
+	return m.Status
+}
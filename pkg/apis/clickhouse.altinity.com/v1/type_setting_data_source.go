@@ -25,11 +25,14 @@ type SettingSource struct {
 	ValueFrom *DataSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
 }
 
-// GetNameKey gets name and key from the secret ref
+// GetNameKey gets name and key from the secret ref or config map ref
 func (s *SettingSource) GetNameKey() (string, string) {
 	if ref := s.GetSecretKeyRef(); ref != nil {
 		return ref.Name, ref.Key
 	}
+	if ref := s.GetConfigMapKeyRef(); ref != nil {
+		return ref.Name, ref.Key
+	}
 	return "", ""
 }
 
@@ -49,6 +52,57 @@ func (s *SettingSource) HasSecretKeyRef() bool {
 	return s.GetSecretKeyRef() != nil
 }
 
+// GetConfigMapKeyRef gets ConfigMapKeySelector (typically named as ConfigMapKeyRef) or nil
+func (s *SettingSource) GetConfigMapKeyRef() *core.ConfigMapKeySelector {
+	if s == nil {
+		return nil
+	}
+	if s.ValueFrom == nil {
+		return nil
+	}
+	return s.ValueFrom.ConfigMapKeyRef
+}
+
+// HasConfigMapKeyRef checks whether ConfigMapKeySelector (typically named as ConfigMapKeyRef) is available
+func (s *SettingSource) HasConfigMapKeyRef() bool {
+	return s.GetConfigMapKeyRef() != nil
+}
+
+// GetFieldRef gets ObjectFieldSelector (typically named as FieldRef) or nil
+func (s *SettingSource) GetFieldRef() *core.ObjectFieldSelector {
+	if s == nil {
+		return nil
+	}
+	if s.ValueFrom == nil {
+		return nil
+	}
+	return s.ValueFrom.FieldRef
+}
+
+// GetResourceFieldRef gets ResourceFieldSelector (typically named as ResourceFieldRef) or nil
+func (s *SettingSource) GetResourceFieldRef() *core.ResourceFieldSelector {
+	if s == nil {
+		return nil
+	}
+	if s.ValueFrom == nil {
+		return nil
+	}
+	return s.ValueFrom.ResourceFieldRef
+}
+
+// GetEnvVarSource gets k8s EnvVarSource, which provides the same data as the SettingSource, or nil
+func (s *SettingSource) GetEnvVarSource() *core.EnvVarSource {
+	if !s.HasValue() {
+		return nil
+	}
+	return &core.EnvVarSource{
+		SecretKeyRef:     s.GetSecretKeyRef(),
+		ConfigMapKeyRef:  s.GetConfigMapKeyRef(),
+		FieldRef:         s.GetFieldRef(),
+		ResourceFieldRef: s.GetResourceFieldRef(),
+	}
+}
+
 // HasValue checks whether SettingSource has no value
 func (s *SettingSource) HasValue() bool {
 	if s == nil {
@@ -57,7 +111,10 @@ func (s *SettingSource) HasValue() bool {
 	if s.ValueFrom == nil {
 		return false
 	}
-	return s.HasSecretKeyRef()
+	return s.HasSecretKeyRef() ||
+		s.HasConfigMapKeyRef() ||
+		(s.GetFieldRef() != nil) ||
+		(s.GetResourceFieldRef() != nil)
 }
 
 // NewSettingSource makes new source Setting
@@ -108,10 +165,10 @@ func (s *Setting) IsSource() bool {
 
 // GetNameKey gets name and key of source setting
 func (s *Setting) GetNameKey() (string, string) {
-	if ref := s.GetSecretKeyRef(); ref != nil {
-		return ref.Name, ref.Key
+	if !s.IsSource() {
+		return "", ""
 	}
-	return "", ""
+	return s.src.GetNameKey()
 }
 
 // GetSecretKeyRef gets SecretKeySelector (typically named as SecretKeyRef) or nil
@@ -137,3 +194,56 @@ func (s *Setting) HasSecretKeyRef() bool {
 
 	return s.GetSecretKeyRef() != nil
 }
+
+// GetConfigMapKeyRef gets ConfigMapKeySelector (typically named as ConfigMapKeyRef) or nil
+func (s *Setting) GetConfigMapKeyRef() *core.ConfigMapKeySelector {
+	if s == nil {
+		return nil
+	}
+	if !s.IsSource() {
+		return nil
+	}
+
+	return s.src.GetConfigMapKeyRef()
+}
+
+// HasConfigMapKeyRef checks whether ConfigMapKeySelector (typically named as ConfigMapKeyRef) is available
+func (s *Setting) HasConfigMapKeyRef() bool {
+	return s.GetConfigMapKeyRef() != nil
+}
+
+// GetFieldRef gets ObjectFieldSelector (typically named as FieldRef) or nil
+func (s *Setting) GetFieldRef() *core.ObjectFieldSelector {
+	if s == nil {
+		return nil
+	}
+	if !s.IsSource() {
+		return nil
+	}
+
+	return s.src.GetFieldRef()
+}
+
+// GetResourceFieldRef gets ResourceFieldSelector (typically named as ResourceFieldRef) or nil
+func (s *Setting) GetResourceFieldRef() *core.ResourceFieldSelector {
+	if s == nil {
+		return nil
+	}
+	if !s.IsSource() {
+		return nil
+	}
+
+	return s.src.GetResourceFieldRef()
+}
+
+// GetEnvVarSource gets k8s EnvVarSource of source setting or nil
+func (s *Setting) GetEnvVarSource() *core.EnvVarSource {
+	if s == nil {
+		return nil
+	}
+	if !s.IsSource() {
+		return nil
+	}
+
+	return s.src.GetEnvVarSource()
+}
@@ -48,7 +48,7 @@ func (shard *ChiShard) HasReplicasCount() bool {
 	return shard.ReplicasCount > 0
 }
 
-// WalkHosts runs specified function on each host
+// WalkHosts runs specified function on each host managed by the operator
 func (shard *ChiShard) WalkHosts(f func(host *ChiHost) error) []error {
 	if shard == nil {
 		return nil
@@ -56,6 +56,25 @@ func (shard *ChiShard) WalkHosts(f func(host *ChiHost) error) []error {
 
 	res := make([]error, 0)
 
+	for replicaIndex := range shard.Hosts {
+		host := shard.Hosts[replicaIndex]
+		if host.IsExternal() {
+			continue
+		}
+		res = append(res, f(host))
+	}
+
+	return res
+}
+
+// WalkAllHosts runs specified function on each host, external hosts included
+func (shard *ChiShard) WalkAllHosts(f func(host *ChiHost) error) []error {
+	if shard == nil {
+		return nil
+	}
+
+	res := make([]error, 0)
+
 	for replicaIndex := range shard.Hosts {
 		host := shard.Hosts[replicaIndex]
 		res = append(res, f(host))
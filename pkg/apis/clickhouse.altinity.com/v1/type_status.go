@@ -17,6 +17,7 @@ package v1
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 	"github.com/minorhacks/clickhouse-operator/pkg/version"
@@ -34,6 +35,7 @@ const (
 	StatusCompleted   = "Completed"
 	StatusAborted     = "Aborted"
 	StatusTerminating = "Terminating"
+	StatusPaused      = "Paused"
 )
 
 // ChiStatus defines status section of ClickHouseInstallation resource.
@@ -43,49 +45,274 @@ const (
 // that application logic sticks to the synchronized getter/setters by auditing whether all explicit Go field-level
 // accesses are strictly within _this_ source file OR the generated deep copy source file.
 type ChiStatus struct {
-	CHOpVersion            string                  `json:"chop-version,omitempty"           yaml:"chop-version,omitempty"`
-	CHOpCommit             string                  `json:"chop-commit,omitempty"            yaml:"chop-commit,omitempty"`
-	CHOpDate               string                  `json:"chop-date,omitempty"              yaml:"chop-date,omitempty"`
-	CHOpIP                 string                  `json:"chop-ip,omitempty"                yaml:"chop-ip,omitempty"`
-	ClustersCount          int                     `json:"clusters,omitempty"               yaml:"clusters,omitempty"`
-	ShardsCount            int                     `json:"shards,omitempty"                 yaml:"shards,omitempty"`
-	ReplicasCount          int                     `json:"replicas,omitempty"               yaml:"replicas,omitempty"`
-	HostsCount             int                     `json:"hosts,omitempty"                  yaml:"hosts,omitempty"`
-	Status                 string                  `json:"status,omitempty"                 yaml:"status,omitempty"`
-	TaskID                 string                  `json:"taskID,omitempty"                 yaml:"taskID,omitempty"`
-	TaskIDsStarted         []string                `json:"taskIDsStarted,omitempty"         yaml:"taskIDsStarted,omitempty"`
-	TaskIDsCompleted       []string                `json:"taskIDsCompleted,omitempty"       yaml:"taskIDsCompleted,omitempty"`
-	Action                 string                  `json:"action,omitempty"                 yaml:"action,omitempty"`
-	Actions                []string                `json:"actions,omitempty"                yaml:"actions,omitempty"`
-	Error                  string                  `json:"error,omitempty"                  yaml:"error,omitempty"`
-	Errors                 []string                `json:"errors,omitempty"                 yaml:"errors,omitempty"`
-	HostsUpdatedCount      int                     `json:"hostsUpdated,omitempty"           yaml:"hostsUpdated,omitempty"`
-	HostsAddedCount        int                     `json:"hostsAdded,omitempty"             yaml:"hostsAdded,omitempty"`
-	HostsUnchangedCount    int                     `json:"hostsUnchanged,omitempty"         yaml:"hostsUnchanged,omitempty"`
-	HostsFailedCount       int                     `json:"hostsFailed,omitempty"            yaml:"hostsFailed,omitempty"`
-	HostsCompletedCount    int                     `json:"hostsCompleted,omitempty"         yaml:"hostsCompleted,omitempty"`
-	HostsDeletedCount      int                     `json:"hostsDeleted,omitempty"           yaml:"hostsDeleted,omitempty"`
-	HostsDeleteCount       int                     `json:"hostsDelete,omitempty"            yaml:"hostsDelete,omitempty"`
-	Pods                   []string                `json:"pods,omitempty"                   yaml:"pods,omitempty"`
-	PodIPs                 []string                `json:"pod-ips,omitempty"                yaml:"pod-ips,omitempty"`
-	FQDNs                  []string                `json:"fqdns,omitempty"                  yaml:"fqdns,omitempty"`
-	Endpoint               string                  `json:"endpoint,omitempty"               yaml:"endpoint,omitempty"`
-	NormalizedCHI          *ClickHouseInstallation `json:"normalized,omitempty"             yaml:"normalized,omitempty"`
-	NormalizedCHICompleted *ClickHouseInstallation `json:"normalizedCompleted,omitempty"    yaml:"normalizedCompleted,omitempty"`
-	HostsWithTablesCreated []string                `json:"hostsWithTablesCreated,omitempty" yaml:"hostsWithTablesCreated,omitempty"`
-	UsedTemplates          []*TemplateRef          `json:"usedTemplates,omitempty"          yaml:"usedTemplates,omitempty"`
+	CHOpVersion            string                     `json:"chop-version,omitempty"           yaml:"chop-version,omitempty"`
+	CHOpCommit             string                     `json:"chop-commit,omitempty"            yaml:"chop-commit,omitempty"`
+	CHOpDate               string                     `json:"chop-date,omitempty"              yaml:"chop-date,omitempty"`
+	CHOpIP                 string                     `json:"chop-ip,omitempty"                yaml:"chop-ip,omitempty"`
+	ClustersCount          int                        `json:"clusters,omitempty"               yaml:"clusters,omitempty"`
+	ShardsCount            int                        `json:"shards,omitempty"                 yaml:"shards,omitempty"`
+	ReplicasCount          int                        `json:"replicas,omitempty"               yaml:"replicas,omitempty"`
+	HostsCount             int                        `json:"hosts,omitempty"                  yaml:"hosts,omitempty"`
+	Status                 string                     `json:"status,omitempty"                 yaml:"status,omitempty"`
+	TaskID                 string                     `json:"taskID,omitempty"                 yaml:"taskID,omitempty"`
+	TaskIDsStarted         []string                   `json:"taskIDsStarted,omitempty"         yaml:"taskIDsStarted,omitempty"`
+	TaskIDsCompleted       []string                   `json:"taskIDsCompleted,omitempty"       yaml:"taskIDsCompleted,omitempty"`
+	Action                 string                     `json:"action,omitempty"                 yaml:"action,omitempty"`
+	Actions                []string                   `json:"actions,omitempty"                yaml:"actions,omitempty"`
+	Error                  string                     `json:"error,omitempty"                  yaml:"error,omitempty"`
+	Errors                 []string                   `json:"errors,omitempty"                 yaml:"errors,omitempty"`
+	HostsUpdatedCount      int                        `json:"hostsUpdated,omitempty"           yaml:"hostsUpdated,omitempty"`
+	HostsAddedCount        int                        `json:"hostsAdded,omitempty"             yaml:"hostsAdded,omitempty"`
+	HostsUnchangedCount    int                        `json:"hostsUnchanged,omitempty"         yaml:"hostsUnchanged,omitempty"`
+	HostsFailedCount       int                        `json:"hostsFailed,omitempty"            yaml:"hostsFailed,omitempty"`
+	HostsCompletedCount    int                        `json:"hostsCompleted,omitempty"         yaml:"hostsCompleted,omitempty"`
+	HostsDeletedCount      int                        `json:"hostsDeleted,omitempty"           yaml:"hostsDeleted,omitempty"`
+	HostsDeleteCount       int                        `json:"hostsDelete,omitempty"            yaml:"hostsDelete,omitempty"`
+	Pods                   []string                   `json:"pods,omitempty"                   yaml:"pods,omitempty"`
+	PodIPs                 []string                   `json:"pod-ips,omitempty"                yaml:"pod-ips,omitempty"`
+	FQDNs                  []string                   `json:"fqdns,omitempty"                  yaml:"fqdns,omitempty"`
+	Endpoint               string                     `json:"endpoint,omitempty"               yaml:"endpoint,omitempty"`
+	NormalizedCHI          *ClickHouseInstallation    `json:"normalized,omitempty"             yaml:"normalized,omitempty"`
+	NormalizedCHICompleted *ClickHouseInstallation    `json:"normalizedCompleted,omitempty"    yaml:"normalizedCompleted,omitempty"`
+	HostsWithTablesCreated []string                   `json:"hostsWithTablesCreated,omitempty" yaml:"hostsWithTablesCreated,omitempty"`
+	UsedTemplates          []*TemplateRef             `json:"usedTemplates,omitempty"          yaml:"usedTemplates,omitempty"`
+	SchemaDrift            *ChiSchemaDrift            `json:"schemaDrift,omitempty"            yaml:"schemaDrift,omitempty"`
+	ReplicationHealth      *ChiReplicationHealth      `json:"replicationHealth,omitempty"      yaml:"replicationHealth,omitempty"`
+	PVCAutoscaling         *ChiPVCAutoscaling         `json:"pvcAutoscaling,omitempty"         yaml:"pvcAutoscaling,omitempty"`
+	StorageClassMigrations []ChiStorageClassMigration `json:"storageClassMigrations,omitempty" yaml:"storageClassMigrations,omitempty"`
+	Paused                 *ChiReconcilePaused        `json:"paused,omitempty"                 yaml:"paused,omitempty"`
+	DeferredHosts          []ChiDeferredHost          `json:"deferredHosts,omitempty"          yaml:"deferredHosts,omitempty"`
+	RestartRequests        []ChiRestartRequestStatus  `json:"restartRequests,omitempty"        yaml:"restartRequests,omitempty"`
+	Hibernation            *ChiHibernationStatus      `json:"hibernation,omitempty"            yaml:"hibernation,omitempty"`
+	VirtualClusters        []ChiVirtualClusterStatus  `json:"virtualClusters,omitempty"        yaml:"virtualClusters,omitempty"`
+	Revisions              []ChiRevision              `json:"revisions,omitempty"              yaml:"revisions,omitempty"`
 
 	mu sync.RWMutex `json:"-" yaml:"-"`
 }
 
 // CopyCHIStatusOptions specifies what to copy in CHI status options
 type CopyCHIStatusOptions struct {
-	Actions           bool
-	Errors            bool
-	Normalized        bool
-	MainFields        bool
-	WholeStatus       bool
-	InheritableFields bool
+	Actions                bool
+	Errors                 bool
+	Normalized             bool
+	MainFields             bool
+	WholeStatus            bool
+	InheritableFields      bool
+	SchemaDrift            bool
+	ReplicationHealth      bool
+	PVCAutoscaling         bool
+	StorageClassMigrations bool
+	Paused                 bool
+	DeferredHosts          bool
+	RestartRequests        bool
+	Hibernation            bool
+	VirtualClusters        bool
+	Revisions              bool
+}
+
+// Possible kinds of schema drift
+const (
+	// SchemaDriftMissing means table exists on other hosts of the cluster, but is missing on the host
+	SchemaDriftMissing = "Missing"
+	// SchemaDriftDivergent means table on the host has schema different from the schema on the majority of the hosts
+	SchemaDriftDivergent = "Divergent"
+)
+
+// ChiSchemaDrift defines result of the latest schema drift check
+type ChiSchemaDrift struct {
+	// CheckedAt specifies time of the check
+	CheckedAt string `json:"checkedAt,omitempty" yaml:"checkedAt,omitempty"`
+	// Error specifies why the check failed, if it did
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Tables lists tables, which schema differs between hosts of the same cluster
+	Tables []ChiSchemaDriftTable `json:"tables,omitempty" yaml:"tables,omitempty"`
+}
+
+// ChiSchemaDriftTable defines table, which is missing or divergent on the host
+type ChiSchemaDriftTable struct {
+	Cluster string `json:"cluster" yaml:"cluster"`
+	Host    string `json:"host"    yaml:"host"`
+	Table   string `json:"table"   yaml:"table"`
+	Kind    string `json:"kind"    yaml:"kind"`
+}
+
+// Possible problems of replica
+const (
+	// ReplicationProblemReadOnly means replicated table is read-only, however its metadata is present in ZooKeeper
+	ReplicationProblemReadOnly = "ReadOnly"
+	// ReplicationProblemLostMetadata means replicated table is read-only and its metadata is lost in ZooKeeper
+	ReplicationProblemLostMetadata = "LostMetadata"
+	// ReplicationProblemLagging means replication queue or delay of replicated table exceeds thresholds
+	ReplicationProblemLagging = "Lagging"
+)
+
+// ChiReplicationHealth defines result of the latest replication health check
+type ChiReplicationHealth struct {
+	// CheckedAt specifies time of the check
+	CheckedAt string `json:"checkedAt,omitempty" yaml:"checkedAt,omitempty"`
+	// Replicas lists unhealthy replicas along with remediations applied
+	Replicas []ChiReplicaHealth `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	// ExcludedHosts lists hosts, which are excluded from the cluster until they are healthy again
+	ExcludedHosts []string `json:"excludedHosts,omitempty" yaml:"excludedHosts,omitempty"`
+}
+
+// ChiReplicaHealth defines unhealthy replica of the replicated table
+type ChiReplicaHealth struct {
+	Host        string `json:"host"                  yaml:"host"`
+	Table       string `json:"table"                 yaml:"table"`
+	Problem     string `json:"problem"               yaml:"problem"`
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	Error       string `json:"error,omitempty"       yaml:"error,omitempty"`
+}
+
+// IsHostExcluded checks whether host is excluded from the cluster due to unhealthy replicas
+func (h *ChiReplicationHealth) IsHostExcluded(host string) bool {
+	if h == nil {
+		return false
+	}
+	return util.InArray(host, h.ExcludedHosts)
+}
+
+// GetReplicas gets unhealthy replicas
+func (h *ChiReplicationHealth) GetReplicas() []ChiReplicaHealth {
+	if h == nil {
+		return nil
+	}
+	return h.Replicas
+}
+
+// GetExcludedHosts gets hosts excluded from the cluster
+func (h *ChiReplicationHealth) GetExcludedHosts() []string {
+	if h == nil {
+		return nil
+	}
+	return h.ExcludedHosts
+}
+
+// MaxPVCExpansionsHistory specifies how many latest PVC expansions are kept in the status
+const MaxPVCExpansionsHistory = 20
+
+// ChiPVCAutoscaling defines result of the latest PVC autoscaling check
+type ChiPVCAutoscaling struct {
+	// CheckedAt specifies time of the check
+	CheckedAt string `json:"checkedAt,omitempty" yaml:"checkedAt,omitempty"`
+	// Expansions lists latest PVC expansions, including the failed ones
+	Expansions []ChiPVCExpansion `json:"expansions,omitempty" yaml:"expansions,omitempty"`
+}
+
+// ChiPVCExpansion defines expansion of the PVC
+type ChiPVCExpansion struct {
+	Time        string `json:"time"            yaml:"time"`
+	Host        string `json:"host"            yaml:"host"`
+	PVC         string `json:"pvc"             yaml:"pvc"`
+	Utilization int    `json:"utilization"     yaml:"utilization"`
+	From        string `json:"from"            yaml:"from"`
+	To          string `json:"to,omitempty"    yaml:"to,omitempty"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// GetExpansions gets latest PVC expansions
+func (a *ChiPVCAutoscaling) GetExpansions() []ChiPVCExpansion {
+	if a == nil {
+		return nil
+	}
+	return a.Expansions
+}
+
+// PushExpansion appends PVC expansion to the history, keeping MaxPVCExpansionsHistory latest entries only
+func (a *ChiPVCAutoscaling) PushExpansion(expansion ChiPVCExpansion) {
+	if a == nil {
+		return
+	}
+	a.Expansions = append(a.Expansions, expansion)
+	if len(a.Expansions) > MaxPVCExpansionsHistory {
+		a.Expansions = a.Expansions[len(a.Expansions)-MaxPVCExpansionsHistory:]
+	}
+}
+
+// ChiReconcilePaused defines state of the paused reconcile
+type ChiReconcilePaused struct {
+	// Since specifies time reconcile was paused at
+	Since string `json:"since,omitempty"             yaml:"since,omitempty"`
+	// Status specifies status the CHI had before reconcile was paused. It is restored on resume
+	Status string `json:"status,omitempty"            yaml:"status,omitempty"`
+	// PendingActionPlan summarizes changes to be reconciled on resume
+	PendingActionPlan string `json:"pendingActionPlan,omitempty" yaml:"pendingActionPlan,omitempty"`
+}
+
+// MaxStorageClassMigrationsHistory specifies how many latest storage class migrations are kept in the status
+const MaxStorageClassMigrationsHistory = 20
+
+// Possible phases of storage class migration
+const (
+	// StorageClassMigrationPhaseInProgress means old PVC is deleted and host is being re-populated by replication
+	StorageClassMigrationPhaseInProgress = "InProgress"
+	// StorageClassMigrationPhaseCompleted means host caught up with other replicas and old volume is released
+	StorageClassMigrationPhaseCompleted = "Completed"
+	// StorageClassMigrationPhaseFailed means migration is either not possible or host did not catch up in time
+	StorageClassMigrationPhaseFailed = "Failed"
+)
+
+// ChiStorageClassMigration defines migration of the PVC to another storage class
+type ChiStorageClassMigration struct {
+	Host string `json:"host"                 yaml:"host"`
+	PVC  string `json:"pvc"                  yaml:"pvc"`
+	From string `json:"from"                 yaml:"from"`
+	To   string `json:"to"                   yaml:"to"`
+	// PV specifies old volume, which is retained until migration is completed
+	PV string `json:"pv,omitempty"         yaml:"pv,omitempty"`
+	// PVReclaimPolicy specifies original reclaim policy of the old volume, which is restored on completion
+	PVReclaimPolicy string `json:"pvReclaimPolicy,omitempty" yaml:"pvReclaimPolicy,omitempty"`
+	// Retained specifies old volume is retained and is to be released as soon as the host catches up
+	Retained   bool   `json:"retained,omitempty"        yaml:"retained,omitempty"`
+	Phase      string `json:"phase"                     yaml:"phase"`
+	StartedAt  string `json:"startedAt,omitempty"       yaml:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"      yaml:"finishedAt,omitempty"`
+	Error      string `json:"error,omitempty"           yaml:"error,omitempty"`
+}
+
+// IsInProgress checks whether migration is in progress
+func (m *ChiStorageClassMigration) IsInProgress() bool {
+	if m == nil {
+		return false
+	}
+	return m.Phase == StorageClassMigrationPhaseInProgress
+}
+
+// IsRetained checks whether old volume of the migration is retained and is to be released
+func (m *ChiStorageClassMigration) IsRetained() bool {
+	if m == nil {
+		return false
+	}
+	return m.Retained
+}
+
+// Possible reasons of host changes being deferred till maintenance window
+const (
+	// DeferredReasonRestart means configuration change requires host restart
+	DeferredReasonRestart = "Restart"
+	// DeferredReasonStatefulSet means StatefulSet of the host has to be updated or re-created, which restarts the host
+	DeferredReasonStatefulSet = "StatefulSet"
+	// DeferredReasonStorageClassMigration means PVCs of the host have to be migrated to another storage class
+	DeferredReasonStorageClassMigration = "StorageClassMigration"
+)
+
+// ChiDeferredHost defines host, which disruptive changes are deferred till maintenance window
+type ChiDeferredHost struct {
+	Host    string   `json:"host"                 yaml:"host"`
+	Reasons []string `json:"reasons,omitempty"    yaml:"reasons,omitempty"`
+	// Since specifies time changes of the host were deferred first at
+	Since string `json:"since,omitempty"      yaml:"since,omitempty"`
+	// NextWindow specifies start of the next maintenance window changes are to be applied within
+	NextWindow string `json:"nextWindow,omitempty" yaml:"nextWindow,omitempty"`
+}
+
+// HasReason checks whether changes of the host are deferred due to specified reason
+func (h *ChiDeferredHost) HasReason(reason string) bool {
+	if h == nil {
+		return false
+	}
+	return util.InArray(reason, h.Reasons)
 }
 
 // FillStatusParams is a struct used to fill status params
@@ -181,6 +408,144 @@ func (s *ChiStatus) PushUsedTemplate(templateRef *TemplateRef) {
 	})
 }
 
+// SetSchemaDrift sets result of the schema drift check
+func (s *ChiStatus) SetSchemaDrift(drift *ChiSchemaDrift) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.SchemaDrift = drift
+	})
+}
+
+// SetReplicationHealth sets result of the replication health check
+func (s *ChiStatus) SetReplicationHealth(health *ChiReplicationHealth) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.ReplicationHealth = health
+	})
+}
+
+// SetPVCAutoscaling sets result of the PVC autoscaling check
+func (s *ChiStatus) SetPVCAutoscaling(autoscaling *ChiPVCAutoscaling) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.PVCAutoscaling = autoscaling
+	})
+}
+
+// PushStorageClassMigration records storage class migration of the PVC.
+// Migration replaces previous record of the same PVC, keeping MaxStorageClassMigrationsHistory latest entries only.
+// Migrations with retained volumes are never dropped, since they are the only record of volumes to be released
+func (s *ChiStatus) PushStorageClassMigration(migration ChiStorageClassMigration) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		for i := range s.StorageClassMigrations {
+			if s.StorageClassMigrations[i].PVC == migration.PVC {
+				s.StorageClassMigrations = append(s.StorageClassMigrations[:i], s.StorageClassMigrations[i+1:]...)
+				break
+			}
+		}
+		s.StorageClassMigrations = append(s.StorageClassMigrations, migration)
+		for i := 0; (len(s.StorageClassMigrations) > MaxStorageClassMigrationsHistory) && (i < len(s.StorageClassMigrations)); {
+			if s.StorageClassMigrations[i].Retained {
+				i++
+				continue
+			}
+			s.StorageClassMigrations = append(s.StorageClassMigrations[:i], s.StorageClassMigrations[i+1:]...)
+		}
+	})
+}
+
+// PushDeferredHost records host, which disruptive changes are deferred.
+// Reasons are merged into previous record of the same host, which keeps the time changes were deferred first at
+func (s *ChiStatus) PushDeferredHost(deferred ChiDeferredHost) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		// List may be shared with the object it is inherited from, thus it is re-built instead of being modified in place
+		var deferredHosts []ChiDeferredHost
+		for i := range s.DeferredHosts {
+			if s.DeferredHosts[i].Host == deferred.Host {
+				deferred.Since = s.DeferredHosts[i].Since
+				deferred.Reasons = util.MergeStringArrays(append([]string{}, s.DeferredHosts[i].Reasons...), deferred.Reasons)
+				continue
+			}
+			deferredHosts = append(deferredHosts, s.DeferredHosts[i])
+		}
+		s.DeferredHosts = append(deferredHosts, deferred)
+	})
+}
+
+// RemoveDeferredHost removes record of the host, which deferred changes are applied
+func (s *ChiStatus) RemoveDeferredHost(host string) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		var deferredHosts []ChiDeferredHost
+		for i := range s.DeferredHosts {
+			if s.DeferredHosts[i].Host != host {
+				deferredHosts = append(deferredHosts, s.DeferredHosts[i])
+			}
+		}
+		s.DeferredHosts = deferredHosts
+	})
+}
+
+// SyncDeferredHosts removes records of the hosts, which are not present in the list of hosts anymore
+func (s *ChiStatus) SyncDeferredHosts(hosts []string) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		var deferredHosts []ChiDeferredHost
+		for i := range s.DeferredHosts {
+			if util.InArray(s.DeferredHosts[i].Host, hosts) {
+				deferredHosts = append(deferredHosts, s.DeferredHosts[i])
+			}
+		}
+		s.DeferredHosts = deferredHosts
+	})
+}
+
+// PushRestartRequest records acknowledged restart request, replacing previous record of the same request
+func (s *ChiStatus) PushRestartRequest(request ChiRestartRequestStatus) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		// List may be shared with the object it is inherited from, thus it is re-built instead of being modified in place
+		var requests []ChiRestartRequestStatus
+		for i := range s.RestartRequests {
+			if s.RestartRequests[i].ID != request.ID {
+				requests = append(requests, s.RestartRequests[i])
+			}
+		}
+		s.RestartRequests = append(requests, request)
+	})
+}
+
+// MarkRestartRequestHost marks host as restarted by the restart request, completing the request once all its hosts are restarted.
+// Returns updated record of the request, nil in case the host is not pending to be restarted by the request
+func (s *ChiStatus) MarkRestartRequestHost(id, host string) (updated *ChiRestartRequestStatus) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		// List may be shared with the object it is inherited from, thus it is re-built instead of being modified in place
+		var requests []ChiRestartRequestStatus
+		for i := range s.RestartRequests {
+			request := s.RestartRequests[i]
+			if (request.ID == id) && request.IsHostPending(host) {
+				request.RestartedHosts = append(append([]string{}, request.RestartedHosts...), host)
+				if request.IsCompleted() {
+					request.Phase = RestartRequestPhaseCompleted
+					request.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+				}
+				r := request
+				updated = &r
+			}
+			requests = append(requests, request)
+		}
+		s.RestartRequests = requests
+	})
+	return updated
+}
+
+// SyncRestartRequests removes records of the restart requests, which are not present in the list of requests anymore
+func (s *ChiStatus) SyncRestartRequests(ids []string) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		var requests []ChiRestartRequestStatus
+		for i := range s.RestartRequests {
+			if util.InArray(s.RestartRequests[i].ID, ids) {
+				requests = append(requests, s.RestartRequests[i])
+			}
+		}
+		s.RestartRequests = requests
+	})
+}
+
 // GetUsedTemplatesCount gets used templates count
 func (s *ChiStatus) GetUsedTemplatesCount() int {
 	return getIntWithReadLock(s, func(s *ChiStatus) int {
@@ -313,6 +678,62 @@ func (s *ChiStatus) ReconcileAbort() {
 	})
 }
 
+// ReconcilePause marks reconcile paused
+func (s *ChiStatus) ReconcilePause(paused *ChiReconcilePaused) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		if s == nil {
+			return
+		}
+		if s.Paused != nil {
+			paused.Status = s.Paused.Status
+		} else {
+			paused.Status = s.Status
+		}
+		s.Status = StatusPaused
+		s.Paused = paused
+	})
+}
+
+// ReconcileResume marks reconcile resumed, status the CHI had before reconcile was paused is restored
+func (s *ChiStatus) ReconcileResume() {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		if (s == nil) || (s.Paused == nil) {
+			return
+		}
+		s.Status = s.Paused.Status
+		s.Paused = nil
+	})
+}
+
+// SetHibernation sets hibernation state
+func (s *ChiStatus) SetHibernation(hibernation *ChiHibernationStatus) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.Hibernation = hibernation
+	})
+}
+
+// SetVirtualClusters sets resolved layouts of the virtual clusters
+func (s *ChiStatus) SetVirtualClusters(virtualClusters []ChiVirtualClusterStatus) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		s.VirtualClusters = virtualClusters
+	})
+}
+
+// PushRevision appends revision to the list of revisions, keeping no more than limit latest revisions.
+// Returns revisions pushed out of the list
+func (s *ChiStatus) PushRevision(revision ChiRevision, limit int) (pushedOut []ChiRevision) {
+	doWithWriteLock(s, func(s *ChiStatus) {
+		// List may be shared with the object it is inherited from, thus it is re-built instead of being modified in place
+		revisions := append(append([]ChiRevision{}, s.Revisions...), revision)
+		if (limit > 0) && (len(revisions) > limit) {
+			pushedOut = revisions[:len(revisions)-limit]
+			revisions = revisions[len(revisions)-limit:]
+		}
+		s.Revisions = revisions
+	})
+	return pushedOut
+}
+
 // DeleteStart marks deletion start
 func (s *ChiStatus) DeleteStart() {
 	doWithWriteLock(s, func(s *ChiStatus) {
@@ -344,6 +765,16 @@ func (s *ChiStatus) CopyFrom(f *ChiStatus, opts CopyCHIStatusOptions) {
 				s.Actions = from.Actions
 				s.Errors = from.Errors
 				s.HostsWithTablesCreated = from.HostsWithTablesCreated
+				s.SchemaDrift = from.SchemaDrift
+				s.ReplicationHealth = from.ReplicationHealth
+				s.PVCAutoscaling = from.PVCAutoscaling
+				s.StorageClassMigrations = from.StorageClassMigrations
+				s.Paused = from.Paused
+				s.DeferredHosts = from.DeferredHosts
+				s.RestartRequests = from.RestartRequests
+				s.Hibernation = from.Hibernation
+				s.VirtualClusters = from.VirtualClusters
+				s.Revisions = from.Revisions
 			}
 
 			if opts.Actions {
@@ -428,6 +859,57 @@ func (s *ChiStatus) CopyFrom(f *ChiStatus, opts CopyCHIStatusOptions) {
 				s.Endpoint = from.Endpoint
 				s.NormalizedCHI = from.NormalizedCHI
 				s.NormalizedCHICompleted = from.NormalizedCHICompleted
+				s.SchemaDrift = from.SchemaDrift
+				s.ReplicationHealth = from.ReplicationHealth
+				s.PVCAutoscaling = from.PVCAutoscaling
+				s.StorageClassMigrations = from.StorageClassMigrations
+				s.Paused = from.Paused
+				s.DeferredHosts = from.DeferredHosts
+				s.RestartRequests = from.RestartRequests
+				s.Hibernation = from.Hibernation
+				s.VirtualClusters = from.VirtualClusters
+				s.Revisions = from.Revisions
+			}
+
+			if opts.SchemaDrift {
+				s.SchemaDrift = from.SchemaDrift
+			}
+
+			if opts.ReplicationHealth {
+				s.ReplicationHealth = from.ReplicationHealth
+			}
+
+			if opts.PVCAutoscaling {
+				s.PVCAutoscaling = from.PVCAutoscaling
+			}
+
+			if opts.StorageClassMigrations {
+				s.StorageClassMigrations = from.StorageClassMigrations
+			}
+
+			if opts.Paused {
+				s.Status = from.Status
+				s.Paused = from.Paused
+			}
+
+			if opts.DeferredHosts {
+				s.DeferredHosts = from.DeferredHosts
+			}
+
+			if opts.RestartRequests {
+				s.RestartRequests = from.RestartRequests
+			}
+
+			if opts.Hibernation {
+				s.Hibernation = from.Hibernation
+			}
+
+			if opts.VirtualClusters {
+				s.VirtualClusters = from.VirtualClusters
+			}
+
+			if opts.Revisions {
+				s.Revisions = from.Revisions
 			}
 		})
 	})
@@ -657,6 +1139,154 @@ func (s *ChiStatus) GetHostsWithTablesCreated() []string {
 	})
 }
 
+// GetSchemaDrift gets result of the latest schema drift check
+func (s *ChiStatus) GetSchemaDrift() (drift *ChiSchemaDrift) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		drift = s.SchemaDrift
+	})
+	return drift
+}
+
+// GetReplicationHealth gets result of the latest replication health check
+func (s *ChiStatus) GetReplicationHealth() (health *ChiReplicationHealth) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		health = s.ReplicationHealth
+	})
+	return health
+}
+
+// GetPVCAutoscaling gets result of the latest PVC autoscaling check
+func (s *ChiStatus) GetPVCAutoscaling() (autoscaling *ChiPVCAutoscaling) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		autoscaling = s.PVCAutoscaling
+	})
+	return autoscaling
+}
+
+// GetPaused gets state of the paused reconcile
+func (s *ChiStatus) GetPaused() (paused *ChiReconcilePaused) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		paused = s.Paused
+	})
+	return paused
+}
+
+// GetHibernation gets hibernation state
+func (s *ChiStatus) GetHibernation() (hibernation *ChiHibernationStatus) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		hibernation = s.Hibernation
+	})
+	return hibernation
+}
+
+// GetVirtualClusters gets resolved layouts of the virtual clusters
+func (s *ChiStatus) GetVirtualClusters() (virtualClusters []ChiVirtualClusterStatus) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		virtualClusters = s.VirtualClusters
+	})
+	return virtualClusters
+}
+
+// GetRevisions gets persisted revisions, the latest one is the last
+func (s *ChiStatus) GetRevisions() (revisions []ChiRevision) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		revisions = s.Revisions
+	})
+	return revisions
+}
+
+// GetRevision gets persisted revision by its sequence number
+func (s *ChiStatus) GetRevision(revision int) (r *ChiRevision) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		for i := range s.Revisions {
+			if s.Revisions[i].Revision == revision {
+				rev := s.Revisions[i]
+				r = &rev
+			}
+		}
+	})
+	return r
+}
+
+// GetLatestRevision gets the latest persisted revision, if any
+func (s *ChiStatus) GetLatestRevision() (r *ChiRevision) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		if len(s.Revisions) > 0 {
+			rev := s.Revisions[len(s.Revisions)-1]
+			r = &rev
+		}
+	})
+	return r
+}
+
+// GetStorageClassMigration gets the latest storage class migration of the PVC
+func (s *ChiStatus) GetStorageClassMigration(pvc string) (migration *ChiStorageClassMigration) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		for i := range s.StorageClassMigrations {
+			if s.StorageClassMigrations[i].PVC == pvc {
+				m := s.StorageClassMigrations[i]
+				migration = &m
+			}
+		}
+	})
+	return migration
+}
+
+// GetRetainedStorageClassMigrations gets storage class migrations, which old volumes are retained
+func (s *ChiStatus) GetRetainedStorageClassMigrations() (migrations []*ChiStorageClassMigration) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		for i := range s.StorageClassMigrations {
+			if s.StorageClassMigrations[i].Retained {
+				m := s.StorageClassMigrations[i]
+				migrations = append(migrations, &m)
+			}
+		}
+	})
+	return migrations
+}
+
+// GetDeferredHost gets record of the host, which disruptive changes are deferred
+func (s *ChiStatus) GetDeferredHost(host string) (deferred *ChiDeferredHost) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		for i := range s.DeferredHosts {
+			if s.DeferredHosts[i].Host == host {
+				h := s.DeferredHosts[i]
+				deferred = &h
+			}
+		}
+	})
+	return deferred
+}
+
+// GetDeferredHosts gets hosts, which disruptive changes are deferred
+func (s *ChiStatus) GetDeferredHosts() (deferred []ChiDeferredHost) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		deferred = s.DeferredHosts
+	})
+	return deferred
+}
+
+// GetRestartRequest gets record of the acknowledged restart request
+func (s *ChiStatus) GetRestartRequest(id string) (request *ChiRestartRequestStatus) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		for i := range s.RestartRequests {
+			if s.RestartRequests[i].ID == id {
+				r := s.RestartRequests[i]
+				request = &r
+			}
+		}
+	})
+	return request
+}
+
+// GetRestartRequests gets acknowledged restart requests
+func (s *ChiStatus) GetRestartRequests() (requests []ChiRestartRequestStatus) {
+	doWithReadLock(s, func(s *ChiStatus) {
+		requests = s.RestartRequests
+	})
+	return requests
+}
+
 // Begin helpers
 
 func doWithWriteLock(s *ChiStatus, f func(s *ChiStatus)) {
@@ -246,3 +246,34 @@ func Test_ChiStatus_BasicOperations_SingleStatus_ConcurrencyTest(t *testing.T) {
 		})
 	}
 }
+
+func Test_ChiStatus_MarkRestartRequestHost_ConcurrencyTest(t *testing.T) {
+	status := &ChiStatus{}
+	status.PushRestartRequest(ChiRestartRequestStatus{
+		ID:    "request-a",
+		Phase: RestartRequestPhaseInProgress,
+		Hosts: []string{"host-a", "host-b"},
+	})
+	startWg := sync.WaitGroup{}
+	doneWg := sync.WaitGroup{}
+	startWg.Add(2)
+	doneWg.Add(2)
+	for _, host := range []string{"host-a", "host-b"} {
+		go func(host string) {
+			startWg.Done()
+			startWg.Wait() // Block until the other goroutine has begun execution
+			require.NotNil(t, status.MarkRestartRequestHost("request-a", host))
+			doneWg.Done()
+		}(host)
+	}
+	doneWg.Wait()
+
+	request := status.GetRestartRequest("request-a")
+	require.ElementsMatch(t, []string{"host-a", "host-b"}, request.RestartedHosts)
+	require.Equal(t, RestartRequestPhaseCompleted, request.Phase)
+	require.NotEmpty(t, request.FinishedAt)
+
+	// Host is not pending anymore
+	require.Nil(t, status.MarkRestartRequestHost("request-a", "host-a"))
+	require.Nil(t, status.MarkRestartRequestHost("request-b", "host-a"))
+}
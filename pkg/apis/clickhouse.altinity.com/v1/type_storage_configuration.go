@@ -0,0 +1,176 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "gopkg.in/d4l3k/messagediff.v1"
+
+// Set of storage disk types
+const (
+	StorageDiskTypeLocal            = "local"
+	StorageDiskTypeS3               = "s3"
+	StorageDiskTypeAzureBlobStorage = "azure_blob_storage"
+	StorageDiskTypeCache            = "cache"
+)
+
+// StorageConfiguration defines storage section of .spec.configuration
+// Refers to
+// https://clickhouse.com/docs/en/engines/table-engines/mergetree-family/mergetree#table_engine-mergetree-multiple-volumes
+type StorageConfiguration struct {
+	Disks    []*StorageDisk   `json:"disks,omitempty"    yaml:"disks,omitempty"`
+	Policies []*StoragePolicy `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// StorageDisk defines disk of the storage configuration
+type StorageDisk struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Type is one of: local, s3, azure_blob_storage, cache
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Path is a local path of the disk. Used by local and cache disks
+	Path               string `json:"path,omitempty"               yaml:"path,omitempty"`
+	KeepFreeSpaceBytes string `json:"keepFreeSpaceBytes,omitempty" yaml:"keepFreeSpaceBytes,omitempty"`
+
+	// S3 disk
+	Endpoint                  string         `json:"endpoint,omitempty"                  yaml:"endpoint,omitempty"`
+	Region                    string         `json:"region,omitempty"                    yaml:"region,omitempty"`
+	AccessKeyID               *SettingSource `json:"accessKeyID,omitempty"               yaml:"accessKeyID,omitempty"`
+	SecretAccessKey           *SettingSource `json:"secretAccessKey,omitempty"           yaml:"secretAccessKey,omitempty"`
+	UseEnvironmentCredentials *StringBool    `json:"useEnvironmentCredentials,omitempty" yaml:"useEnvironmentCredentials,omitempty"`
+
+	// Azure blob storage disk
+	StorageAccountURL string         `json:"storageAccountURL,omitempty" yaml:"storageAccountURL,omitempty"`
+	ContainerName     string         `json:"containerName,omitempty"     yaml:"containerName,omitempty"`
+	AccountName       *SettingSource `json:"accountName,omitempty"       yaml:"accountName,omitempty"`
+	AccountKey        *SettingSource `json:"accountKey,omitempty"        yaml:"accountKey,omitempty"`
+
+	// MetadataPath is a local path where metadata of object storage disk is kept
+	MetadataPath string `json:"metadataPath,omitempty" yaml:"metadataPath,omitempty"`
+
+	// Cache disk
+	Disk    string `json:"disk,omitempty"    yaml:"disk,omitempty"`
+	MaxSize string `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+}
+
+// StoragePolicy defines storage policy of the storage configuration
+type StoragePolicy struct {
+	Name       string           `json:"name,omitempty"       yaml:"name,omitempty"`
+	Volumes    []*StorageVolume `json:"volumes,omitempty"    yaml:"volumes,omitempty"`
+	MoveFactor string           `json:"moveFactor,omitempty" yaml:"moveFactor,omitempty"`
+}
+
+// StorageVolume defines volume of the storage policy
+type StorageVolume struct {
+	Name                 string      `json:"name,omitempty"                 yaml:"name,omitempty"`
+	Disks                []string    `json:"disks,omitempty"                yaml:"disks,omitempty"`
+	MaxDataPartSizeBytes string      `json:"maxDataPartSizeBytes,omitempty" yaml:"maxDataPartSizeBytes,omitempty"`
+	PreferNotToMerge     *StringBool `json:"preferNotToMerge,omitempty"     yaml:"preferNotToMerge,omitempty"`
+}
+
+// NewStorageConfiguration creates new StorageConfiguration object
+func NewStorageConfiguration() *StorageConfiguration {
+	return new(StorageConfiguration)
+}
+
+// IsEmpty checks whether storage configuration is empty
+func (s *StorageConfiguration) IsEmpty() bool {
+	if s == nil {
+		return true
+	}
+
+	return (len(s.Disks) == 0) && (len(s.Policies) == 0)
+}
+
+// GetDisk gets disk by name
+func (s *StorageConfiguration) GetDisk(name string) *StorageDisk {
+	if s == nil {
+		return nil
+	}
+	for _, disk := range s.Disks {
+		if (disk != nil) && (disk.Name == name) {
+			return disk
+		}
+	}
+	return nil
+}
+
+// GetPolicy gets policy by name
+func (s *StorageConfiguration) GetPolicy(name string) *StoragePolicy {
+	if s == nil {
+		return nil
+	}
+	for _, policy := range s.Policies {
+		if (policy != nil) && (policy.Name == name) {
+			return policy
+		}
+	}
+	return nil
+}
+
+// MergeFrom merges from provided object.
+// Disks and policies are matched by name, entries with the same name are not merged field-by-field,
+// but are either kept or replaced as a whole, depending on merge type
+func (s *StorageConfiguration) MergeFrom(from *StorageConfiguration, _type MergeType) *StorageConfiguration {
+	if from == nil {
+		return s
+	}
+
+	if s == nil {
+		s = NewStorageConfiguration()
+	}
+
+	for _, fromDisk := range from.Disks {
+		if fromDisk == nil {
+			continue
+		}
+		switch disk := s.GetDisk(fromDisk.Name); {
+		case disk == nil:
+			s.Disks = append(s.Disks, fromDisk.DeepCopy())
+		case _type == MergeTypeOverrideByNonEmptyValues:
+			*disk = *fromDisk.DeepCopy()
+		}
+	}
+
+	for _, fromPolicy := range from.Policies {
+		if fromPolicy == nil {
+			continue
+		}
+		switch policy := s.GetPolicy(fromPolicy.Name); {
+		case policy == nil:
+			s.Policies = append(s.Policies, fromPolicy.DeepCopy())
+		case _type == MergeTypeOverrideByNonEmptyValues:
+			*policy = *fromPolicy.DeepCopy()
+		}
+	}
+
+	return s
+}
+
+// Equals checks whether storage configuration is equal to another one
+func (s *StorageConfiguration) Equals(b *StorageConfiguration) bool {
+	_, equals := messagediff.DeepDiff(s, b)
+	return equals
+}
+
+// IsObjectStorage checks whether disk keeps data in an object storage
+func (d *StorageDisk) IsObjectStorage() bool {
+	if d == nil {
+		return false
+	}
+	switch d.Type {
+	case StorageDiskTypeS3, StorageDiskTypeAzureBlobStorage:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseUser defines ClickHouse user managed separately from the ClickHouseInstallation it belongs to
+type ClickHouseUser struct {
+	meta.TypeMeta   `json:",inline"            yaml:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   ClickHouseUserSpec    `json:"spec"             yaml:"spec"`
+	Status *ClickHouseUserStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClickHouseUserList defines a list of ClickHouseUser resources
+type ClickHouseUserList struct {
+	meta.TypeMeta `json:",inline"  yaml:",inline"`
+	meta.ListMeta `json:"metadata" yaml:"metadata"`
+	Items         []ClickHouseUser `json:"items" yaml:"items"`
+}
+
+// ClickHouseUserMode specifies how user is provisioned into ClickHouse
+type ClickHouseUserMode string
+
+// Possible user provisioning modes
+const (
+	// ClickHouseUserModeConfig provisions user as a part of users.xml config
+	ClickHouseUserModeConfig ClickHouseUserMode = "Config"
+	// ClickHouseUserModeSQL provisions user with SQL statements, such as CREATE USER and GRANT
+	ClickHouseUserModeSQL ClickHouseUserMode = "SQL"
+)
+
+// Possible values of ClickHouseUser status
+const (
+	ClickHouseUserStatusCompleted = StatusCompleted
+	ClickHouseUserStatusPending   = "Pending"
+	ClickHouseUserStatusFailed    = "Failed"
+)
+
+// ClickHouseUserSpec defines spec section of ClickHouseUser resource
+type ClickHouseUserSpec struct {
+	// Target specifies ClickHouseInstallation the user belongs to
+	Target ClickHouseUserTarget `json:"target"              yaml:"target"`
+	// Mode specifies how user is provisioned. Config is used by default
+	Mode ClickHouseUserMode `json:"mode,omitempty"     yaml:"mode,omitempty"`
+	// Username specifies name of the user in ClickHouse. Name of the resource is used by default
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	// PasswordSecretRef points to a key of k8s Secret with plaintext password
+	PasswordSecretRef *core.SecretKeySelector `json:"passwordSecretRef,omitempty" yaml:"passwordSecretRef,omitempty"`
+	// Profile specifies settings profile of the user
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	// Quota specifies quota of the user. Applicable in Config mode only, user with quota is rejected in SQL mode
+	Quota string `json:"quota,omitempty" yaml:"quota,omitempty"`
+	// Networks specifies where the user is allowed to connect from
+	Networks *ClickHouseUserNetworks `json:"networks,omitempty" yaml:"networks,omitempty"`
+	// Grants specifies privileges granted to the user, ex.: "SELECT ON db.*"
+	Grants []string `json:"grants,omitempty" yaml:"grants,omitempty"`
+	// Roles specifies roles granted to the user
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// ClickHouseUserTarget specifies ClickHouseInstallation the user belongs to
+type ClickHouseUserTarget struct {
+	// CHI specifies name of the ClickHouseInstallation within the same namespace
+	CHI string `json:"chi" yaml:"chi"`
+	// Cluster specifies cluster to be used in ON CLUSTER clause in SQL mode.
+	// Auto-generated cluster with all hosts of the ClickHouseInstallation is used by default
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// ClickHouseUserNetworks specifies where the user is allowed to connect from
+type ClickHouseUserNetworks struct {
+	IP         []string `json:"ip,omitempty"         yaml:"ip,omitempty"`
+	HostRegexp []string `json:"hostRegexp,omitempty" yaml:"hostRegexp,omitempty"`
+}
+
+// ClickHouseUserStatus defines status section of ClickHouseUser resource
+type ClickHouseUserStatus struct {
+	Status             string             `json:"status,omitempty"             yaml:"status,omitempty"`
+	Error              string             `json:"error,omitempty"              yaml:"error,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty" yaml:"observedGeneration,omitempty"`
+	Mode               ClickHouseUserMode `json:"mode,omitempty"               yaml:"mode,omitempty"`
+	// Target the user is provisioned into with SQL, used to drop the user from it in case target changes
+	Target *ClickHouseUserTarget `json:"target,omitempty" yaml:"target,omitempty"`
+	// Grants and Roles applied with SQL, used to revoke the ones removed from spec
+	Grants []string `json:"grants,omitempty" yaml:"grants,omitempty"`
+	Roles  []string `json:"roles,omitempty"  yaml:"roles,omitempty"`
+}
+
+// GetUsername gets name of the user in ClickHouse
+func (u *ClickHouseUser) GetUsername() string {
+	if u == nil {
+		return ""
+	}
+	if u.Spec.Username != "" {
+		return u.Spec.Username
+	}
+	return u.Name
+}
+
+// GetMode gets provisioning mode of the user
+func (u *ClickHouseUser) GetMode() ClickHouseUserMode {
+	if u == nil {
+		return ClickHouseUserModeConfig
+	}
+	switch u.Spec.Mode {
+	case ClickHouseUserModeSQL:
+		return ClickHouseUserModeSQL
+	default:
+		return ClickHouseUserModeConfig
+	}
+}
+
+// IsTargeting checks whether the user belongs to specified ClickHouseInstallation
+func (u *ClickHouseUser) IsTargeting(chi *ClickHouseInstallation) bool {
+	if (u == nil) || (chi == nil) {
+		return false
+	}
+	return (u.Namespace == chi.Namespace) && (u.Spec.Target.CHI == chi.Name)
+}
+
+// IsPasswordSourcedFrom checks whether password of the user is sourced from the specified Secret
+func (u *ClickHouseUser) IsPasswordSourcedFrom(secret string) bool {
+	if (u == nil) || (u.Spec.PasswordSecretRef == nil) {
+		return false
+	}
+	return u.Spec.PasswordSecretRef.Name == secret
+}
+
+// IsDeleting checks whether the user is being deleted
+func (u *ClickHouseUser) IsDeleting() bool {
+	if u == nil {
+		return false
+	}
+	return !u.DeletionTimestamp.IsZero()
+}
+
+// GetProvisionedTarget gets target the user is provisioned into with SQL.
+// Target from spec is used in case status has no target recorded
+func (u *ClickHouseUser) GetProvisionedTarget() ClickHouseUserTarget {
+	if u == nil {
+		return ClickHouseUserTarget{}
+	}
+	if (u.Status != nil) && (u.Status.Target != nil) {
+		return *u.Status.Target
+	}
+	return u.Spec.Target
+}
+
+// EnsureStatus ensures status is in place
+func (u *ClickHouseUser) EnsureStatus() *ClickHouseUserStatus {
+	if u == nil {
+		return nil
+	}
+	if u.Status == nil {
+		u.Status = &ClickHouseUserStatus{}
+	}
+	return u.Status
+}
+
+// GetIP gets list of IP networks
+func (n *ClickHouseUserNetworks) GetIP() []string {
+	if n == nil {
+		return nil
+	}
+	return n.IP
+}
+
+// GetHostRegexp gets list of host regexps
+func (n *ClickHouseUserNetworks) GetHostRegexp() []string {
+	if n == nil {
+		return nil
+	}
+	return n.HostRegexp
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "fmt"
+
+// ChiVirtualCluster defines cluster, which consists of clusters of other CHIs.
+// Virtual cluster is rendered into remote_servers, thus Distributed tables are able to span several CHIs
+type ChiVirtualCluster struct {
+	Name     string                       `json:"name,omitempty"     yaml:"name,omitempty"`
+	Clusters []ChiVirtualClusterReference `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	// Secret specifies shared secret for interserver authentication. It has to be the same in all referenced CHIs
+	Secret *ClusterSecret `json:"secret,omitempty" yaml:"secret,omitempty"`
+
+	Runtime ChiVirtualClusterRuntime `json:"-" yaml:"-" testdiff:"ignore"`
+}
+
+// ChiVirtualClusterReference references clusters of other CHIs either by CHI name or by CHI labels
+type ChiVirtualClusterReference struct {
+	// CHI specifies name of the referenced CHI
+	CHI string `json:"chi,omitempty" yaml:"chi,omitempty"`
+	// Namespace specifies namespace of the referenced CHIs. Namespace of the CHI itself is used by default
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Selector specifies labels of the referenced CHIs, in case CHI name is not specified
+	Selector map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Cluster specifies name of the referenced cluster. All clusters of the referenced CHIs are used by default
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// ChiVirtualClusterRuntime is a layout of the virtual cluster, resolved from the referenced CHIs
+type ChiVirtualClusterRuntime struct {
+	Shards []ChiVirtualClusterShard `json:"-" yaml:"-"`
+}
+
+// ChiVirtualClusterShard defines shard of the virtual cluster
+type ChiVirtualClusterShard struct {
+	InternalReplication *StringBool
+	Weight              *int
+	Replicas            []ChiVirtualClusterReplica
+}
+
+// ChiVirtualClusterReplica defines replica of the virtual cluster shard
+type ChiVirtualClusterReplica struct {
+	Host   string
+	Port   int32
+	Secure bool
+}
+
+// GetHosts lists hosts of the virtual cluster as host:port
+func (r *ChiVirtualClusterRuntime) GetHosts() (hosts []string) {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Shards {
+		for _, replica := range r.Shards[i].Replicas {
+			hosts = append(hosts, fmt.Sprintf("%s:%d", replica.Host, replica.Port))
+		}
+	}
+	return hosts
+}
+
+// ChiVirtualClusterStatus defines resolved layout of the virtual cluster
+type ChiVirtualClusterStatus struct {
+	Name  string   `json:"name"            yaml:"name"`
+	Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+}
+
+// Equals checks whether virtual cluster status is the same as specified one
+func (s *ChiVirtualClusterStatus) Equals(b *ChiVirtualClusterStatus) bool {
+	if (s == nil) || (b == nil) {
+		return s == b
+	}
+	if (s.Name != b.Name) || (len(s.Hosts) != len(b.Hosts)) {
+		return false
+	}
+	for i := range s.Hosts {
+		if s.Hosts[i] != b.Hosts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualVirtualClustersStatus checks whether lists of virtual clusters statuses are the same
+func EqualVirtualClustersStatus(a, b []ChiVirtualClusterStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
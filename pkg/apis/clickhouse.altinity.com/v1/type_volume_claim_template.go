@@ -25,6 +25,46 @@ type VolumeClaimTemplate struct {
 	StorageManagement
 	ObjectMeta meta.ObjectMeta                `json:"metadata,omitempty"      yaml:"metadata,omitempty"`
 	Spec       core.PersistentVolumeClaimSpec `json:"spec,omitempty"          yaml:"spec,omitempty"`
+	// Autoscaling specifies policy of PVC expansion driven by disk usage
+	Autoscaling *VolumeClaimTemplateAutoscaling `json:"autoscaling,omitempty" yaml:"autoscaling,omitempty"`
+	// StorageClassMigration specifies how existing PVCs are migrated in case storage class is changed
+	StorageClassMigration *VolumeClaimTemplateStorageClassMigration `json:"storageClassMigration,omitempty" yaml:"storageClassMigration,omitempty"`
+}
+
+// VolumeClaimTemplateAutoscaling defines policy of PVC expansion driven by disk usage
+type VolumeClaimTemplateAutoscaling struct {
+	Enabled *StringBool `json:"enabled,omitempty"   yaml:"enabled,omitempty"`
+	// Threshold specifies disk utilization PVC is expanded at. In percents
+	Threshold int `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	// Step specifies how much PVC is expanded by.
+	// Either a quantity, ex.: 10Gi, or a percentage of the current size, ex.: 20%
+	Step string `json:"step,omitempty"      yaml:"step,omitempty"`
+	// MaxSize specifies size PVC is not expanded beyond
+	MaxSize string `json:"maxSize,omitempty"   yaml:"maxSize,omitempty"`
+}
+
+// IsEnabled checks whether PVC autoscaling is enabled
+func (a *VolumeClaimTemplateAutoscaling) IsEnabled() bool {
+	if a == nil {
+		return false
+	}
+	return a.Enabled.IsTrue()
+}
+
+// VolumeClaimTemplateStorageClassMigration defines migration of existing PVCs to the storage class specified in the template.
+// Host is re-created with an empty PVC of the new storage class and its data is replicated from other replicas of the shard
+type VolumeClaimTemplateStorageClassMigration struct {
+	Enabled *StringBool `json:"enabled,omitempty"       yaml:"enabled,omitempty"`
+	// VerifyTimeout specifies how long host is waited to catch up with other replicas. In seconds
+	VerifyTimeout int `json:"verifyTimeout,omitempty" yaml:"verifyTimeout,omitempty"`
+}
+
+// IsEnabled checks whether storage class migration is enabled
+func (m *VolumeClaimTemplateStorageClassMigration) IsEnabled() bool {
+	if m == nil {
+		return false
+	}
+	return m.Enabled.IsTrue()
 }
 
 // PVCProvisioner defines PVC provisioner
@@ -77,6 +77,8 @@ type ComparableAttributes struct {
 	AdditionalVolumes      []core.Volume      `json:"-" yaml:"-"`
 	AdditionalVolumeMounts []core.VolumeMount `json:"-" yaml:"-"`
 	SkipOwnerRef           bool               `json:"-" yaml:"-"`
+	// DataSourcesVersion specifies version of the data of config maps, which settings and files are sourced from
+	DataSourcesVersion string `json:"-" yaml:"-"`
 }
 
 // +genclient
@@ -100,17 +102,21 @@ type ClickHouseOperatorConfiguration struct {
 
 // ChiSpec defines spec section of ClickHouseInstallation resource
 type ChiSpec struct {
-	TaskID                 *string         `json:"taskID,omitempty"                 yaml:"taskID,omitempty"`
-	Stop                   *StringBool     `json:"stop,omitempty"                   yaml:"stop,omitempty"`
-	Restart                string          `json:"restart,omitempty"                yaml:"restart,omitempty"`
-	Troubleshoot           *StringBool     `json:"troubleshoot,omitempty"           yaml:"troubleshoot,omitempty"`
-	NamespaceDomainPattern string          `json:"namespaceDomainPattern,omitempty" yaml:"namespaceDomainPattern,omitempty"`
-	Templating             *ChiTemplating  `json:"templating,omitempty"             yaml:"templating,omitempty"`
-	Reconciling            *ChiReconciling `json:"reconciling,omitempty"            yaml:"reconciling,omitempty"`
-	Defaults               *ChiDefaults    `json:"defaults,omitempty"               yaml:"defaults,omitempty"`
-	Configuration          *Configuration  `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
-	Templates              *Templates      `json:"templates,omitempty"              yaml:"templates,omitempty"`
-	UseTemplates           []*TemplateRef  `json:"useTemplates,omitempty"           yaml:"useTemplates,omitempty"`
+	TaskID                 *string             `json:"taskID,omitempty"                 yaml:"taskID,omitempty"`
+	OperatorClass          string              `json:"operatorClass,omitempty"          yaml:"operatorClass,omitempty"`
+	Stop                   *StringBool         `json:"stop,omitempty"                   yaml:"stop,omitempty"`
+	Hibernation            *ChiHibernation     `json:"hibernation,omitempty"            yaml:"hibernation,omitempty"`
+	Restart                string              `json:"restart,omitempty"                yaml:"restart,omitempty"`
+	RestartRequests        []ChiRestartRequest `json:"restartRequests,omitempty"        yaml:"restartRequests,omitempty"`
+	RollbackTo             *int                `json:"rollbackTo,omitempty"             yaml:"rollbackTo,omitempty"`
+	Troubleshoot           *StringBool         `json:"troubleshoot,omitempty"           yaml:"troubleshoot,omitempty"`
+	NamespaceDomainPattern string              `json:"namespaceDomainPattern,omitempty" yaml:"namespaceDomainPattern,omitempty"`
+	Templating             *ChiTemplating      `json:"templating,omitempty"             yaml:"templating,omitempty"`
+	Reconciling            *ChiReconciling     `json:"reconciling,omitempty"            yaml:"reconciling,omitempty"`
+	Defaults               *ChiDefaults        `json:"defaults,omitempty"               yaml:"defaults,omitempty"`
+	Configuration          *Configuration      `json:"configuration,omitempty"          yaml:"configuration,omitempty"`
+	Templates              *Templates          `json:"templates,omitempty"              yaml:"templates,omitempty"`
+	UseTemplates           []*TemplateRef      `json:"useTemplates,omitempty"           yaml:"useTemplates,omitempty"`
 }
 
 // TemplateRef defines UseTemplate section of ClickHouseInstallation resource
@@ -465,6 +471,19 @@ type ChiReconciling struct {
 	ConfigMapPropagationTimeout int `json:"configMapPropagationTimeout,omitempty" yaml:"configMapPropagationTimeout,omitempty"`
 	// Cleanup specifies cleanup behavior
 	Cleanup *ChiCleanup `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`
+	// MaintenanceWindows specifies time windows disruptive changes, such as hosts restarts, are applied within.
+	// Disruptive changes are applied immediately in case no windows specified
+	MaintenanceWindows []ChiMaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+}
+
+// ChiMaintenanceWindow defines recurring time window, disruptive changes are allowed within
+type ChiMaintenanceWindow struct {
+	// Schedule specifies start of the window in standard 5-fields cron format
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Duration specifies how long the window lasts, such as "2h"
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Timezone specifies IANA timezone the schedule is in, such as "Europe/Berlin"
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
 }
 
 // NewChiReconciling creates new reconciling
@@ -490,6 +509,9 @@ func (t *ChiReconciling) MergeFrom(from *ChiReconciling, _type MergeType) *ChiRe
 		if t.ConfigMapPropagationTimeout == 0 {
 			t.ConfigMapPropagationTimeout = from.ConfigMapPropagationTimeout
 		}
+		if len(t.MaintenanceWindows) == 0 {
+			t.MaintenanceWindows = from.MaintenanceWindows
+		}
 	case MergeTypeOverrideByNonEmptyValues:
 		if from.Policy != "" {
 			// Override by non-empty values only
@@ -499,6 +521,10 @@ func (t *ChiReconciling) MergeFrom(from *ChiReconciling, _type MergeType) *ChiRe
 			// Override by non-empty values only
 			t.ConfigMapPropagationTimeout = from.ConfigMapPropagationTimeout
 		}
+		if len(from.MaintenanceWindows) > 0 {
+			// Override by non-empty values only
+			t.MaintenanceWindows = from.MaintenanceWindows
+		}
 	}
 
 	t.Cleanup = t.Cleanup.MergeFrom(from.Cleanup, _type)
@@ -582,6 +608,14 @@ func (t *ChiReconciling) GetCleanup() *ChiCleanup {
 	return t.Cleanup
 }
 
+// GetMaintenanceWindows gets maintenance windows
+func (t *ChiReconciling) GetMaintenanceWindows() []ChiMaintenanceWindow {
+	if t == nil {
+		return nil
+	}
+	return t.MaintenanceWindows
+}
+
 // ChiTemplateNames defines references to .spec.templates to be used on current level of cluster
 type ChiTemplateNames struct {
 	HostTemplate            string `json:"hostTemplate,omitempty"            yaml:"hostTemplate,omitempty"`
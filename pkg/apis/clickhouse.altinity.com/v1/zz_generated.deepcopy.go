@@ -163,6 +163,27 @@ func (in *ChiDefaults) DeepCopy() *ChiDefaults {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiDeferredHost) DeepCopyInto(out *ChiDeferredHost) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiDeferredHost.
+func (in *ChiDeferredHost) DeepCopy() *ChiDeferredHost {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiDeferredHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiDistributedDDL) DeepCopyInto(out *ChiDistributedDDL) {
 	*out = *in
@@ -179,6 +200,54 @@ func (in *ChiDistributedDDL) DeepCopy() *ChiDistributedDDL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiExternalHost) DeepCopyInto(out *ChiExternalHost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiExternalHost.
+func (in *ChiExternalHost) DeepCopy() *ChiExternalHost {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiExternalHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiHibernation) DeepCopyInto(out *ChiHibernation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHibernation.
+func (in *ChiHibernation) DeepCopy() *ChiHibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiHibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiHibernationStatus) DeepCopyInto(out *ChiHibernationStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiHibernationStatus.
+func (in *ChiHibernationStatus) DeepCopy() *ChiHibernationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiHibernationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiHost) DeepCopyInto(out *ChiHost) {
 	*out = *in
@@ -207,6 +276,11 @@ func (in *ChiHost) DeepCopyInto(out *ChiHost) {
 		*out = new(ChiTemplateNames)
 		**out = **in
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ChiExternalHost)
+		**out = **in
+	}
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	return
 }
@@ -319,6 +393,22 @@ func (in *ChiHostRuntime) DeepCopy() *ChiHostRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiMaintenanceWindow) DeepCopyInto(out *ChiMaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiMaintenanceWindow.
+func (in *ChiMaintenanceWindow) DeepCopy() *ChiMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiObjectsCleanup) DeepCopyInto(out *ChiObjectsCleanup) {
 	*out = *in
@@ -335,6 +425,59 @@ func (in *ChiObjectsCleanup) DeepCopy() *ChiObjectsCleanup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiPVCAutoscaling) DeepCopyInto(out *ChiPVCAutoscaling) {
+	*out = *in
+	if in.Expansions != nil {
+		in, out := &in.Expansions, &out.Expansions
+		*out = make([]ChiPVCExpansion, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPVCAutoscaling.
+func (in *ChiPVCAutoscaling) DeepCopy() *ChiPVCAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiPVCAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiPVCExpansion) DeepCopyInto(out *ChiPVCExpansion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPVCExpansion.
+func (in *ChiPVCExpansion) DeepCopy() *ChiPVCExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiPVCExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiReconcilePaused) DeepCopyInto(out *ChiReconcilePaused) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReconcilePaused.
+func (in *ChiReconcilePaused) DeepCopy() *ChiReconcilePaused {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiReconcilePaused)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiReconciling) DeepCopyInto(out *ChiReconciling) {
 	*out = *in
@@ -343,6 +486,11 @@ func (in *ChiReconciling) DeepCopyInto(out *ChiReconciling) {
 		*out = new(ChiCleanup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]ChiMaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -415,6 +563,22 @@ func (in *ChiReplicaAddress) DeepCopy() *ChiReplicaAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiReplicaHealth) DeepCopyInto(out *ChiReplicaHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicaHealth.
+func (in *ChiReplicaHealth) DeepCopy() *ChiReplicaHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiReplicaHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiReplicaRuntime) DeepCopyInto(out *ChiReplicaRuntime) {
 	*out = *in
@@ -437,6 +601,127 @@ func (in *ChiReplicaRuntime) DeepCopy() *ChiReplicaRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiReplicationHealth) DeepCopyInto(out *ChiReplicationHealth) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ChiReplicaHealth, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedHosts != nil {
+		in, out := &in.ExcludedHosts, &out.ExcludedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiReplicationHealth.
+func (in *ChiReplicationHealth) DeepCopy() *ChiReplicationHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiReplicationHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiRestartRequest) DeepCopyInto(out *ChiRestartRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRestartRequest.
+func (in *ChiRestartRequest) DeepCopy() *ChiRestartRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiRestartRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiRestartRequestStatus) DeepCopyInto(out *ChiRestartRequestStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartedHosts != nil {
+		in, out := &in.RestartedHosts, &out.RestartedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRestartRequestStatus.
+func (in *ChiRestartRequestStatus) DeepCopy() *ChiRestartRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiRestartRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiRevision) DeepCopyInto(out *ChiRevision) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiRevision.
+func (in *ChiRevision) DeepCopy() *ChiRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiSchemaDrift) DeepCopyInto(out *ChiSchemaDrift) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]ChiSchemaDriftTable, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSchemaDrift.
+func (in *ChiSchemaDrift) DeepCopy() *ChiSchemaDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiSchemaDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiSchemaDriftTable) DeepCopyInto(out *ChiSchemaDriftTable) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiSchemaDriftTable.
+func (in *ChiSchemaDriftTable) DeepCopy() *ChiSchemaDriftTable {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiSchemaDriftTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiShard) DeepCopyInto(out *ChiShard) {
 	*out = *in
@@ -541,6 +826,21 @@ func (in *ChiSpec) DeepCopyInto(out *ChiSpec) {
 		*out = new(StringBool)
 		**out = **in
 	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(ChiHibernation)
+		**out = **in
+	}
+	if in.RestartRequests != nil {
+		in, out := &in.RestartRequests, &out.RestartRequests
+		*out = make([]ChiRestartRequest, len(*in))
+		copy(*out, *in)
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int)
+		**out = **in
+	}
 	if in.Troubleshoot != nil {
 		in, out := &in.Troubleshoot, &out.Troubleshoot
 		*out = new(StringBool)
@@ -659,6 +959,62 @@ func (in *ChiStatus) DeepCopyInto(out *ChiStatus) {
 			}
 		}
 	}
+	if in.SchemaDrift != nil {
+		in, out := &in.SchemaDrift, &out.SchemaDrift
+		*out = new(ChiSchemaDrift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicationHealth != nil {
+		in, out := &in.ReplicationHealth, &out.ReplicationHealth
+		*out = new(ChiReplicationHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVCAutoscaling != nil {
+		in, out := &in.PVCAutoscaling, &out.PVCAutoscaling
+		*out = new(ChiPVCAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClassMigrations != nil {
+		in, out := &in.StorageClassMigrations, &out.StorageClassMigrations
+		*out = make([]ChiStorageClassMigration, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(ChiReconcilePaused)
+		**out = **in
+	}
+	if in.DeferredHosts != nil {
+		in, out := &in.DeferredHosts, &out.DeferredHosts
+		*out = make([]ChiDeferredHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RestartRequests != nil {
+		in, out := &in.RestartRequests, &out.RestartRequests
+		*out = make([]ChiRestartRequestStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(ChiHibernationStatus)
+		**out = **in
+	}
+	if in.VirtualClusters != nil {
+		in, out := &in.VirtualClusters, &out.VirtualClusters
+		*out = make([]ChiVirtualClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]ChiRevision, len(*in))
+		copy(*out, *in)
+	}
 	out.mu = in.mu
 	return
 }
@@ -673,6 +1029,22 @@ func (in *ChiStatus) DeepCopy() *ChiStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiStorageClassMigration) DeepCopyInto(out *ChiStorageClassMigration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiStorageClassMigration.
+func (in *ChiStorageClassMigration) DeepCopy() *ChiStorageClassMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiStorageClassMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiTemplateNames) DeepCopyInto(out *ChiTemplateNames) {
 	*out = *in
@@ -713,12 +1085,155 @@ func (in *ChiTemplating) DeepCopy() *ChiTemplating {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiZookeeperConfig) DeepCopyInto(out *ChiZookeeperConfig) {
+func (in *ChiVirtualCluster) DeepCopyInto(out *ChiVirtualCluster) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = make([]ChiZookeeperNode, len(*in))
-		for i := range *in {
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ChiVirtualClusterReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ClusterSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Runtime.DeepCopyInto(&out.Runtime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualCluster.
+func (in *ChiVirtualCluster) DeepCopy() *ChiVirtualCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVirtualClusterReference) DeepCopyInto(out *ChiVirtualClusterReference) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualClusterReference.
+func (in *ChiVirtualClusterReference) DeepCopy() *ChiVirtualClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVirtualClusterReplica) DeepCopyInto(out *ChiVirtualClusterReplica) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualClusterReplica.
+func (in *ChiVirtualClusterReplica) DeepCopy() *ChiVirtualClusterReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualClusterReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVirtualClusterRuntime) DeepCopyInto(out *ChiVirtualClusterRuntime) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]ChiVirtualClusterShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualClusterRuntime.
+func (in *ChiVirtualClusterRuntime) DeepCopy() *ChiVirtualClusterRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualClusterRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVirtualClusterShard) DeepCopyInto(out *ChiVirtualClusterShard) {
+	*out = *in
+	if in.InternalReplication != nil {
+		in, out := &in.InternalReplication, &out.InternalReplication
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ChiVirtualClusterReplica, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualClusterShard.
+func (in *ChiVirtualClusterShard) DeepCopy() *ChiVirtualClusterShard {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualClusterShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiVirtualClusterStatus) DeepCopyInto(out *ChiVirtualClusterStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiVirtualClusterStatus.
+func (in *ChiVirtualClusterStatus) DeepCopy() *ChiVirtualClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiVirtualClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiZookeeperConfig) DeepCopyInto(out *ChiZookeeperConfig) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ChiZookeeperNode, len(*in))
+		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
@@ -825,64 +1340,309 @@ func (in *ClickHouseInstallationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationRuntime) DeepCopyInto(out *ClickHouseInstallationRuntime) {
+	*out = *in
+	if in.attributes != nil {
+		in, out := &in.attributes, &out.attributes
+		*out = new(ComparableAttributes)
+		(*in).DeepCopyInto(*out)
+	}
+	out.commonConfigMutex = in.commonConfigMutex
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationRuntime.
+func (in *ClickHouseInstallationRuntime) DeepCopy() *ClickHouseInstallationRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationTemplate) DeepCopyInto(out *ClickHouseInstallationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ChiStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.runtime != nil {
+		in, out := &in.runtime, &out.runtime
+		*out = new(ClickHouseInstallationRuntime)
+		(*in).DeepCopyInto(*out)
+	}
+	out.statusCreatorMutex = in.statusCreatorMutex
+	out.runtimeCreatorMutex = in.runtimeCreatorMutex
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplate.
+func (in *ClickHouseInstallationTemplate) DeepCopy() *ClickHouseInstallationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseInstallationTemplateList) DeepCopyInto(out *ClickHouseInstallationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseInstallationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplateList.
+func (in *ClickHouseInstallationTemplateList) DeepCopy() *ClickHouseInstallationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseInstallationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseInstallationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseOperatorConfiguration) DeepCopyInto(out *ClickHouseOperatorConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfiguration.
+func (in *ClickHouseOperatorConfiguration) DeepCopy() *ClickHouseOperatorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseOperatorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseOperatorConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseOperatorConfigurationList) DeepCopyInto(out *ClickHouseOperatorConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseOperatorConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfigurationList.
+func (in *ClickHouseOperatorConfigurationList) DeepCopy() *ClickHouseOperatorConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseOperatorConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseOperatorConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseSchemaMigration) DeepCopyInto(out *ClickHouseSchemaMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ClickHouseSchemaMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseSchemaMigration.
+func (in *ClickHouseSchemaMigration) DeepCopy() *ClickHouseSchemaMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseSchemaMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseSchemaMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseSchemaMigrationList) DeepCopyInto(out *ClickHouseSchemaMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseSchemaMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseSchemaMigrationList.
+func (in *ClickHouseSchemaMigrationList) DeepCopy() *ClickHouseSchemaMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseSchemaMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseSchemaMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseSchemaMigrationSpec) DeepCopyInto(out *ClickHouseSchemaMigrationSpec) {
+	*out = *in
+	out.Target = in.Target
+	out.ConfigMapRef = in.ConfigMapRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseSchemaMigrationSpec.
+func (in *ClickHouseSchemaMigrationSpec) DeepCopy() *ClickHouseSchemaMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseSchemaMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseSchemaMigrationStatus) DeepCopyInto(out *ClickHouseSchemaMigrationStatus) {
+	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]SchemaMigrationEntryStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseSchemaMigrationStatus.
+func (in *ClickHouseSchemaMigrationStatus) DeepCopy() *ClickHouseSchemaMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseSchemaMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationRuntime) DeepCopyInto(out *ClickHouseInstallationRuntime) {
+func (in *ClickHouseSchemaMigrationTarget) DeepCopyInto(out *ClickHouseSchemaMigrationTarget) {
 	*out = *in
-	if in.attributes != nil {
-		in, out := &in.attributes, &out.attributes
-		*out = new(ComparableAttributes)
-		(*in).DeepCopyInto(*out)
-	}
-	out.commonConfigMutex = in.commonConfigMutex
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationRuntime.
-func (in *ClickHouseInstallationRuntime) DeepCopy() *ClickHouseInstallationRuntime {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseSchemaMigrationTarget.
+func (in *ClickHouseSchemaMigrationTarget) DeepCopy() *ClickHouseSchemaMigrationTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationRuntime)
+	out := new(ClickHouseSchemaMigrationTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationTemplate) DeepCopyInto(out *ClickHouseInstallationTemplate) {
+func (in *ClickHouseUser) DeepCopyInto(out *ClickHouseUser) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(ChiStatus)
+		*out = new(ClickHouseUserStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.runtime != nil {
-		in, out := &in.runtime, &out.runtime
-		*out = new(ClickHouseInstallationRuntime)
-		(*in).DeepCopyInto(*out)
-	}
-	out.statusCreatorMutex = in.statusCreatorMutex
-	out.runtimeCreatorMutex = in.runtimeCreatorMutex
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplate.
-func (in *ClickHouseInstallationTemplate) DeepCopy() *ClickHouseInstallationTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUser.
+func (in *ClickHouseUser) DeepCopy() *ClickHouseUser {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationTemplate)
+	out := new(ClickHouseUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallationTemplate) DeepCopyObject() runtime.Object {
+func (in *ClickHouseUser) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -890,13 +1650,13 @@ func (in *ClickHouseInstallationTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseInstallationTemplateList) DeepCopyInto(out *ClickHouseInstallationTemplateList) {
+func (in *ClickHouseUserList) DeepCopyInto(out *ClickHouseUserList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseInstallationTemplate, len(*in))
+		*out = make([]ClickHouseUser, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -904,18 +1664,18 @@ func (in *ClickHouseInstallationTemplateList) DeepCopyInto(out *ClickHouseInstal
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInstallationTemplateList.
-func (in *ClickHouseInstallationTemplateList) DeepCopy() *ClickHouseInstallationTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUserList.
+func (in *ClickHouseUserList) DeepCopy() *ClickHouseUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseInstallationTemplateList)
+	out := new(ClickHouseUserList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseInstallationTemplateList) DeepCopyObject() runtime.Object {
+func (in *ClickHouseUserList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -923,63 +1683,113 @@ func (in *ClickHouseInstallationTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseOperatorConfiguration) DeepCopyInto(out *ClickHouseOperatorConfiguration) {
+func (in *ClickHouseUserNetworks) DeepCopyInto(out *ClickHouseUserNetworks) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostRegexp != nil {
+		in, out := &in.HostRegexp, &out.HostRegexp
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfiguration.
-func (in *ClickHouseOperatorConfiguration) DeepCopy() *ClickHouseOperatorConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUserNetworks.
+func (in *ClickHouseUserNetworks) DeepCopy() *ClickHouseUserNetworks {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseOperatorConfiguration)
+	out := new(ClickHouseUserNetworks)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseOperatorConfiguration) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseUserSpec) DeepCopyInto(out *ClickHouseUserSpec) {
+	*out = *in
+	out.Target = in.Target
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = new(ClickHouseUserNetworks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUserSpec.
+func (in *ClickHouseUserSpec) DeepCopy() *ClickHouseUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseUserSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseOperatorConfigurationList) DeepCopyInto(out *ClickHouseOperatorConfigurationList) {
+func (in *ClickHouseUserStatus) DeepCopyInto(out *ClickHouseUserStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseOperatorConfiguration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ClickHouseUserTarget)
+		**out = **in
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseOperatorConfigurationList.
-func (in *ClickHouseOperatorConfigurationList) DeepCopy() *ClickHouseOperatorConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUserStatus.
+func (in *ClickHouseUserStatus) DeepCopy() *ClickHouseUserStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseOperatorConfigurationList)
+	out := new(ClickHouseUserStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseOperatorConfigurationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseUserTarget) DeepCopyInto(out *ClickHouseUserTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseUserTarget.
+func (in *ClickHouseUserTarget) DeepCopy() *ClickHouseUserTarget {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ClickHouseUserTarget)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1178,6 +1988,11 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]*Cluster, len(*in))
@@ -1189,6 +2004,17 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 			}
 		}
 	}
+	if in.VirtualClusters != nil {
+		in, out := &in.VirtualClusters, &out.VirtualClusters
+		*out = make([]*ChiVirtualCluster, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ChiVirtualCluster)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -1274,6 +2100,21 @@ func (in *DataSource) DeepCopyInto(out *DataSource) {
 		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FieldRef != nil {
+		in, out := &in.FieldRef, &out.FieldRef
+		*out = new(corev1.ObjectFieldSelector)
+		**out = **in
+	}
+	if in.ResourceFieldRef != nil {
+		in, out := &in.ResourceFieldRef, &out.ResourceFieldRef
+		*out = new(corev1.ResourceFieldSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1474,6 +2315,9 @@ func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
 	in.Label.DeepCopyInto(&out.Label)
 	out.StatefulSet = in.StatefulSet
 	out.Pod = in.Pod
+	out.LeaderElection = in.LeaderElection
+	out.Sharding = in.Sharding
+	out.Tracing = in.Tracing
 	out.Logger = in.Logger
 	if in.WatchNamespaces != nil {
 		in, out := &in.WatchNamespaces, &out.WatchNamespaces
@@ -1608,6 +2452,10 @@ func (in *OperatorConfigClickHouse) DeepCopyInto(out *OperatorConfigClickHouse)
 	in.ConfigRestartPolicy.DeepCopyInto(&out.ConfigRestartPolicy)
 	out.Access = in.Access
 	out.Metrics = in.Metrics
+	out.SchemaDrift = in.SchemaDrift
+	out.ReplicationHealth = in.ReplicationHealth
+	out.PVCAutoscaling = in.PVCAutoscaling
+	out.SettingsValidation = in.SettingsValidation
 	return
 }
 
@@ -1749,6 +2597,7 @@ func (in *OperatorConfigReconcile) DeepCopyInto(out *OperatorConfigReconcile) {
 	out.Runtime = in.Runtime
 	out.StatefulSet = in.StatefulSet
 	in.Host.DeepCopyInto(&out.Host)
+	out.History = in.History
 	return
 }
 
@@ -1947,6 +2796,10 @@ func (in *OperatorConfigWatch) DeepCopyInto(out *OperatorConfigWatch) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -2068,6 +2921,22 @@ func (in *PortDistribution) DeepCopy() *PortDistribution {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaMigrationEntryStatus) DeepCopyInto(out *SchemaMigrationEntryStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaMigrationEntryStatus.
+func (in *SchemaMigrationEntryStatus) DeepCopy() *SchemaMigrationEntryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaMigrationEntryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchemaPolicy) DeepCopyInto(out *SchemaPolicy) {
 	*out = *in
@@ -2296,6 +3165,85 @@ func (in *SettingsUser) DeepCopy() *SettingsUser {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]*StorageDisk, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(StorageDisk)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]*StoragePolicy, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(StoragePolicy)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
+func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageDisk) DeepCopyInto(out *StorageDisk) {
+	*out = *in
+	if in.AccessKeyID != nil {
+		in, out := &in.AccessKeyID, &out.AccessKeyID
+		*out = new(SettingSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(SettingSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UseEnvironmentCredentials != nil {
+		in, out := &in.UseEnvironmentCredentials, &out.UseEnvironmentCredentials
+		*out = new(StringBool)
+		**out = **in
+	}
+	if in.AccountName != nil {
+		in, out := &in.AccountName, &out.AccountName
+		*out = new(SettingSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccountKey != nil {
+		in, out := &in.AccountKey, &out.AccountKey
+		*out = new(SettingSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageDisk.
+func (in *StorageDisk) DeepCopy() *StorageDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageManagement) DeepCopyInto(out *StorageManagement) {
 	*out = *in
@@ -2312,6 +3260,59 @@ func (in *StorageManagement) DeepCopy() *StorageManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePolicy) DeepCopyInto(out *StoragePolicy) {
+	*out = *in
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]*StorageVolume, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(StorageVolume)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePolicy.
+func (in *StoragePolicy) DeepCopy() *StoragePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageVolume) DeepCopyInto(out *StorageVolume) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferNotToMerge != nil {
+		in, out := &in.PreferNotToMerge, &out.PreferNotToMerge
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageVolume.
+func (in *StorageVolume) DeepCopy() *StorageVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateRef) DeepCopyInto(out *TemplateRef) {
 	*out = *in
@@ -2398,6 +3399,16 @@ func (in *VolumeClaimTemplate) DeepCopyInto(out *VolumeClaimTemplate) {
 	out.StorageManagement = in.StorageManagement
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(VolumeClaimTemplateAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClassMigration != nil {
+		in, out := &in.StorageClassMigration, &out.StorageClassMigration
+		*out = new(VolumeClaimTemplateStorageClassMigration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2411,6 +3422,48 @@ func (in *VolumeClaimTemplate) DeepCopy() *VolumeClaimTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimTemplateAutoscaling) DeepCopyInto(out *VolumeClaimTemplateAutoscaling) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplateAutoscaling.
+func (in *VolumeClaimTemplateAutoscaling) DeepCopy() *VolumeClaimTemplateAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimTemplateAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimTemplateStorageClassMigration) DeepCopyInto(out *VolumeClaimTemplateStorageClassMigration) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(StringBool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplateStorageClassMigration.
+func (in *VolumeClaimTemplateStorageClassMigration) DeepCopy() *VolumeClaimTemplateStorageClassMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimTemplateStorageClassMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeClaimTemplatesIndex) DeepCopyInto(out *VolumeClaimTemplatesIndex) {
 	*out = *in
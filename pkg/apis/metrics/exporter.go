@@ -32,6 +32,7 @@ import (
 	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	chopAPI "github.com/minorhacks/clickhouse-operator/pkg/client/clientset/versioned"
 	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	chiModel "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
 	chiNormalizer "github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
 	"github.com/minorhacks/clickhouse-operator/pkg/model/clickhouse"
 )
@@ -144,9 +145,10 @@ func (e *Exporter) updateWatched(chi *WatchedCHI) {
 }
 
 // newFetcher returns new Metrics Fetcher for specified host
-func (e *Exporter) newHostFetcher(host *WatchedHost) *ClickHouseMetricsFetcher {
+func (e *Exporter) newHostFetcher(chi *WatchedCHI, host *WatchedHost) *ClickHouseMetricsFetcher {
 	// Make base cluster connection params
-	clusterConnectionParams := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config())
+	// Generated operator credentials (if any) have priority over credentials from CHOp config
+	clusterConnectionParams := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config()).SetCredentials(chi.GetCredentials())
 	// Adjust base cluster connection params with per-host props
 	switch clusterConnectionParams.Scheme {
 	case api.ChSchemeAuto:
@@ -169,7 +171,7 @@ func (e *Exporter) newHostFetcher(host *WatchedHost) *ClickHouseMetricsFetcher {
 
 // collectHostMetrics collects metrics from one host and writes them into chan
 func (e *Exporter) collectHostMetrics(ctx context.Context, chi *WatchedCHI, host *WatchedHost, c chan<- prometheus.Metric) {
-	fetcher := e.newHostFetcher(host)
+	fetcher := e.newHostFetcher(chi, host)
 	writer := NewCHIPrometheusWriter(c, chi, host)
 
 	wg := sync.WaitGroup{}
@@ -386,7 +388,7 @@ func (e *Exporter) DiscoveryWatchedCHIs(kubeClient kube.Interface, chopClient *c
 		// Convenience wrapper
 		chi := &list.Items[i]
 
-		if chi.IsStopped() {
+		if chi.IsStopped() || chi.IsHibernated() {
 			log.V(1).Infof("CHI %s/%s is stopped, skip it", chi.Namespace, chi.Name)
 			continue
 		}
@@ -403,6 +405,12 @@ func (e *Exporter) DiscoveryWatchedCHIs(kubeClient kube.Interface, chopClient *c
 		normalized, _ := normalizer.CreateTemplatedCHI(chi, chiNormalizer.NewOptions())
 
 		watchedCHI := NewWatchedCHI(normalized)
+		if chop.Config().ClickHouse.Access.Generate.Enabled {
+			secret, err := kubeClient.CoreV1().Secrets(chi.Namespace).Get(context.TODO(), chiModel.CreateOperatorCredentialsSecretName(chi), controller.NewGetOptions())
+			if err == nil {
+				watchedCHI.SetCredentials(chiModel.NewOperatorCredentialsFromSecret(secret).GetConnectionCredentials())
+			}
+		}
 		e.updateWatched(watchedCHI)
 	}
 }
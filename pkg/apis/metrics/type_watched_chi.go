@@ -27,6 +27,14 @@ type WatchedCHI struct {
 	Labels      map[string]string `json:"labels"`
 	Annotations map[string]string `json:"annotations"`
 	Clusters    []*WatchedCluster `json:"clusters"`
+	// Credentials specifies generated operator credentials, if any
+	Credentials *WatchedCredentials `json:"credentials,omitempty"`
+}
+
+// WatchedCredentials specifies credentials to connect to hosts of watched ClickHouseInstallation
+type WatchedCredentials struct {
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
 }
 
 // WatchedCluster specifies watched cluster
@@ -69,6 +77,29 @@ func (chi *WatchedCHI) readFrom(c *api.ClickHouseInstallation) {
 	})
 }
 
+// SetCredentials sets credentials to connect to hosts. Empty username means no specific credentials
+func (chi *WatchedCHI) SetCredentials(username, password string) {
+	if chi == nil {
+		return
+	}
+	if username == "" {
+		chi.Credentials = nil
+		return
+	}
+	chi.Credentials = &WatchedCredentials{
+		Username: username,
+		Password: password,
+	}
+}
+
+// GetCredentials gets credentials to connect to hosts
+func (chi *WatchedCHI) GetCredentials() (username, password string) {
+	if (chi == nil) || (chi.Credentials == nil) {
+		return "", ""
+	}
+	return chi.Credentials.Username, chi.Credentials.Password
+}
+
 func (chi *WatchedCHI) isValid() bool {
 	return !chi.empty()
 }
@@ -125,7 +156,15 @@ func (chi *WatchedCHI) String() string {
 	if chi == nil {
 		return "nil"
 	}
-	bytes, _ := json.Marshal(chi)
+	// Do not expose password
+	_chi := *chi
+	if _chi.Credentials != nil {
+		_chi.Credentials = &WatchedCredentials{
+			Username: _chi.Credentials.Username,
+			Password: api.PasswordReplacer,
+		}
+	}
+	bytes, _ := json.Marshal(&_chi)
 	return string(bytes)
 }
 
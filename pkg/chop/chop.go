@@ -94,6 +94,14 @@ func (c *CHOp) SetupLog() {
 		_ = flag.Set("v", c.Config().Logger.V)
 	}
 
+	if c.Config().Logger.Format != "" {
+		log.V(1).Info("Log option 'format' change value to '%s'", c.Config().Logger.Format)
+		updated = true
+		if err := log.SetFormat(c.Config().Logger.Format); err != nil {
+			log.Warning("Unable to apply log format. Err: %v", err)
+		}
+	}
+
 	if updated {
 		log.V(1).Info("Additional log options applied")
 	}
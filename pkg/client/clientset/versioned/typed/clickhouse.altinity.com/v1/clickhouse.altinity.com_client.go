@@ -31,6 +31,7 @@ type ClickhouseV1Interface interface {
 	ClickHouseInstallationsGetter
 	ClickHouseInstallationTemplatesGetter
 	ClickHouseOperatorConfigurationsGetter
+	ClickHouseUsersGetter
 }
 
 // ClickhouseV1Client is used to interact with features provided by the clickhouse.altinity.com group.
@@ -50,6 +51,10 @@ func (c *ClickhouseV1Client) ClickHouseOperatorConfigurations(namespace string)
 	return newClickHouseOperatorConfigurations(c, namespace)
 }
 
+func (c *ClickhouseV1Client) ClickHouseUsers(namespace string) ClickHouseUserInterface {
+	return newClickHouseUsers(c, namespace)
+}
+
 // NewForConfig creates a new ClickhouseV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	scheme "github.com/minorhacks/clickhouse-operator/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClickHouseUsersGetter has a method to return a ClickHouseUserInterface.
+// A group's client should implement this interface.
+type ClickHouseUsersGetter interface {
+	ClickHouseUsers(namespace string) ClickHouseUserInterface
+}
+
+// ClickHouseUserInterface has methods to work with ClickHouseUser resources.
+type ClickHouseUserInterface interface {
+	Create(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.CreateOptions) (*v1.ClickHouseUser, error)
+	Update(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (*v1.ClickHouseUser, error)
+	UpdateStatus(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (*v1.ClickHouseUser, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ClickHouseUser, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.ClickHouseUserList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClickHouseUser, err error)
+	ClickHouseUserExpansion
+}
+
+// clickHouseUsers implements ClickHouseUserInterface
+type clickHouseUsers struct {
+	client rest.Interface
+	ns     string
+}
+
+// newClickHouseUsers returns a ClickHouseUsers
+func newClickHouseUsers(c *ClickhouseV1Client, namespace string) *clickHouseUsers {
+	return &clickHouseUsers{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the clickHouseUser, and returns the corresponding clickHouseUser object, and an error if there is any.
+func (c *clickHouseUsers) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ClickHouseUser, err error) {
+	result = &v1.ClickHouseUser{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClickHouseUsers that match those selectors.
+func (c *clickHouseUsers) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ClickHouseUserList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ClickHouseUserList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clickHouseUsers.
+func (c *clickHouseUsers) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clickHouseUser and creates it.  Returns the server's representation of the clickHouseUser, and an error, if there is any.
+func (c *clickHouseUsers) Create(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.CreateOptions) (result *v1.ClickHouseUser, err error) {
+	result = &v1.ClickHouseUser{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clickHouseUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clickHouseUser and updates it. Returns the server's representation of the clickHouseUser, and an error, if there is any.
+func (c *clickHouseUsers) Update(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (result *v1.ClickHouseUser, err error) {
+	result = &v1.ClickHouseUser{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		Name(clickHouseUser.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clickHouseUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *clickHouseUsers) UpdateStatus(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (result *v1.ClickHouseUser, err error) {
+	result = &v1.ClickHouseUser{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		Name(clickHouseUser.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clickHouseUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clickHouseUser and deletes it. Returns an error if one occurs.
+func (c *clickHouseUsers) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clickHouseUsers) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clickHouseUser.
+func (c *clickHouseUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClickHouseUser, err error) {
+	result = &v1.ClickHouseUser{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("clickhouseusers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
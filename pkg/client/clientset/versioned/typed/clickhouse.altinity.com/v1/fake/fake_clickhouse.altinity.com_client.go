@@ -40,6 +40,10 @@ func (c *FakeClickhouseV1) ClickHouseOperatorConfigurations(namespace string) v1
 	return &FakeClickHouseOperatorConfigurations{c, namespace}
 }
 
+func (c *FakeClickhouseV1) ClickHouseUsers(namespace string) v1.ClickHouseUserInterface {
+	return &FakeClickHouseUsers{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeClickhouseV1) RESTClient() rest.Interface {
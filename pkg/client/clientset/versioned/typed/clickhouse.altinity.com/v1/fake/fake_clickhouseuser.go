@@ -0,0 +1,141 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClickHouseUsers implements ClickHouseUserInterface
+type FakeClickHouseUsers struct {
+	Fake *FakeClickhouseV1
+	ns   string
+}
+
+var clickhouseusersResource = v1.SchemeGroupVersion.WithResource("clickhouseusers")
+
+var clickhouseusersKind = v1.SchemeGroupVersion.WithKind("ClickHouseUser")
+
+// Get takes name of the clickHouseUser, and returns the corresponding clickHouseUser object, and an error if there is any.
+func (c *FakeClickHouseUsers) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.ClickHouseUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(clickhouseusersResource, c.ns, name), &v1.ClickHouseUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.ClickHouseUser), err
+}
+
+// List takes label and field selectors, and returns the list of ClickHouseUsers that match those selectors.
+func (c *FakeClickHouseUsers) List(ctx context.Context, opts metav1.ListOptions) (result *v1.ClickHouseUserList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(clickhouseusersResource, clickhouseusersKind, c.ns, opts), &v1.ClickHouseUserList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1.ClickHouseUserList{ListMeta: obj.(*v1.ClickHouseUserList).ListMeta}
+	for _, item := range obj.(*v1.ClickHouseUserList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clickHouseUsers.
+func (c *FakeClickHouseUsers) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(clickhouseusersResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a clickHouseUser and creates it.  Returns the server's representation of the clickHouseUser, and an error, if there is any.
+func (c *FakeClickHouseUsers) Create(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.CreateOptions) (result *v1.ClickHouseUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(clickhouseusersResource, c.ns, clickHouseUser), &v1.ClickHouseUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.ClickHouseUser), err
+}
+
+// Update takes the representation of a clickHouseUser and updates it. Returns the server's representation of the clickHouseUser, and an error, if there is any.
+func (c *FakeClickHouseUsers) Update(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (result *v1.ClickHouseUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(clickhouseusersResource, c.ns, clickHouseUser), &v1.ClickHouseUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.ClickHouseUser), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeClickHouseUsers) UpdateStatus(ctx context.Context, clickHouseUser *v1.ClickHouseUser, opts metav1.UpdateOptions) (*v1.ClickHouseUser, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(clickhouseusersResource, "status", c.ns, clickHouseUser), &v1.ClickHouseUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.ClickHouseUser), err
+}
+
+// Delete takes name of the clickHouseUser and deletes it. Returns an error if one occurs.
+func (c *FakeClickHouseUsers) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(clickhouseusersResource, c.ns, name, opts), &v1.ClickHouseUser{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClickHouseUsers) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(clickhouseusersResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1.ClickHouseUserList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clickHouseUser.
+func (c *FakeClickHouseUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.ClickHouseUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(clickhouseusersResource, c.ns, name, pt, data, subresources...), &v1.ClickHouseUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.ClickHouseUser), err
+}
@@ -23,3 +23,5 @@ type ClickHouseInstallationExpansion interface{}
 type ClickHouseInstallationTemplateExpansion interface{}
 
 type ClickHouseOperatorConfigurationExpansion interface{}
+
+type ClickHouseUserExpansion interface{}
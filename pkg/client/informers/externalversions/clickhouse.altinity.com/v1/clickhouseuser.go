@@ -0,0 +1,90 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	clickhousealtinitycomv1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	versioned "github.com/minorhacks/clickhouse-operator/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/minorhacks/clickhouse-operator/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/minorhacks/clickhouse-operator/pkg/client/listers/clickhouse.altinity.com/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ClickHouseUserInformer provides access to a shared informer and lister for
+// ClickHouseUsers.
+type ClickHouseUserInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.ClickHouseUserLister
+}
+
+type clickHouseUserInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewClickHouseUserInformer constructs a new informer for ClickHouseUser type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewClickHouseUserInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredClickHouseUserInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredClickHouseUserInformer constructs a new informer for ClickHouseUser type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredClickHouseUserInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ClickhouseV1().ClickHouseUsers(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ClickhouseV1().ClickHouseUsers(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&clickhousealtinitycomv1.ClickHouseUser{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *clickHouseUserInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredClickHouseUserInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *clickHouseUserInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&clickhousealtinitycomv1.ClickHouseUser{}, f.defaultInformer)
+}
+
+func (f *clickHouseUserInformer) Lister() v1.ClickHouseUserLister {
+	return v1.NewClickHouseUserLister(f.Informer().GetIndexer())
+}
@@ -30,6 +30,8 @@ type Interface interface {
 	ClickHouseInstallationTemplates() ClickHouseInstallationTemplateInformer
 	// ClickHouseOperatorConfigurations returns a ClickHouseOperatorConfigurationInformer.
 	ClickHouseOperatorConfigurations() ClickHouseOperatorConfigurationInformer
+	// ClickHouseUsers returns a ClickHouseUserInformer.
+	ClickHouseUsers() ClickHouseUserInformer
 }
 
 type version struct {
@@ -57,3 +59,8 @@ func (v *version) ClickHouseInstallationTemplates() ClickHouseInstallationTempla
 func (v *version) ClickHouseOperatorConfigurations() ClickHouseOperatorConfigurationInformer {
 	return &clickHouseOperatorConfigurationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ClickHouseUsers returns a ClickHouseUserInformer.
+func (v *version) ClickHouseUsers() ClickHouseUserInformer {
+	return &clickHouseUserInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClickHouseUserLister helps list ClickHouseUsers.
+// All objects returned here must be treated as read-only.
+type ClickHouseUserLister interface {
+	// List lists all ClickHouseUsers in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ClickHouseUser, err error)
+	// ClickHouseUsers returns an object that can list and get ClickHouseUsers.
+	ClickHouseUsers(namespace string) ClickHouseUserNamespaceLister
+	ClickHouseUserListerExpansion
+}
+
+// clickHouseUserLister implements the ClickHouseUserLister interface.
+type clickHouseUserLister struct {
+	indexer cache.Indexer
+}
+
+// NewClickHouseUserLister returns a new ClickHouseUserLister.
+func NewClickHouseUserLister(indexer cache.Indexer) ClickHouseUserLister {
+	return &clickHouseUserLister{indexer: indexer}
+}
+
+// List lists all ClickHouseUsers in the indexer.
+func (s *clickHouseUserLister) List(selector labels.Selector) (ret []*v1.ClickHouseUser, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ClickHouseUser))
+	})
+	return ret, err
+}
+
+// ClickHouseUsers returns an object that can list and get ClickHouseUsers.
+func (s *clickHouseUserLister) ClickHouseUsers(namespace string) ClickHouseUserNamespaceLister {
+	return clickHouseUserNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ClickHouseUserNamespaceLister helps list and get ClickHouseUsers.
+// All objects returned here must be treated as read-only.
+type ClickHouseUserNamespaceLister interface {
+	// List lists all ClickHouseUsers in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.ClickHouseUser, err error)
+	// Get retrieves the ClickHouseUser from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.ClickHouseUser, error)
+	ClickHouseUserNamespaceListerExpansion
+}
+
+// clickHouseUserNamespaceLister implements the ClickHouseUserNamespaceLister
+// interface.
+type clickHouseUserNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ClickHouseUsers in the indexer for a given namespace.
+func (s clickHouseUserNamespaceLister) List(selector labels.Selector) (ret []*v1.ClickHouseUser, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ClickHouseUser))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClickHouseUser from the indexer for a given namespace and name.
+func (s clickHouseUserNamespaceLister) Get(name string) (*v1.ClickHouseUser, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("clickhouseuser"), name)
+	}
+	return obj.(*v1.ClickHouseUser), nil
+}
@@ -41,3 +41,11 @@ type ClickHouseOperatorConfigurationListerExpansion interface{}
 // ClickHouseOperatorConfigurationNamespaceListerExpansion allows custom methods to be added to
 // ClickHouseOperatorConfigurationNamespaceLister.
 type ClickHouseOperatorConfigurationNamespaceListerExpansion interface{}
+
+// ClickHouseUserListerExpansion allows custom methods to be added to
+// ClickHouseUserLister.
+type ClickHouseUserListerExpansion interface{}
+
+// ClickHouseUserNamespaceListerExpansion allows custom methods to be added to
+// ClickHouseUserNamespaceLister.
+type ClickHouseUserNamespaceListerExpansion interface{}
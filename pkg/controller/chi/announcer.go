@@ -122,6 +122,13 @@ func (a Announcer) M(m ...interface{}) Announcer {
 	return b
 }
 
+// Ctx adds correlation IDs of the reconcile context belongs to
+func (a Announcer) Ctx(ctx context.Context) Announcer {
+	b := a
+	b.Announcer = b.Announcer.Ctx(ctx)
+	return b
+}
+
 // P triggers log to print line
 func (a Announcer) P() {
 	a.Info("")
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/sanity-io/litter"
@@ -27,6 +28,7 @@ import (
 	core "k8s.io/api/core/v1"
 	apiExtensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -58,6 +60,7 @@ func NewController(
 	kubeClient kube.Interface,
 	chopInformerFactory chopInformers.SharedInformerFactory,
 	kubeInformerFactory kubeInformers.SharedInformerFactory,
+	secretInformerFactory kubeInformers.SharedInformerFactory,
 ) *Controller {
 
 	// Initializations
@@ -87,6 +90,8 @@ func NewController(
 		chiListerSynced:         chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Informer().HasSynced,
 		chitLister:              chopInformerFactory.Clickhouse().V1().ClickHouseInstallationTemplates().Lister(),
 		chitListerSynced:        chopInformerFactory.Clickhouse().V1().ClickHouseInstallationTemplates().Informer().HasSynced,
+		chuLister:               chopInformerFactory.Clickhouse().V1().ClickHouseUsers().Lister(),
+		chuListerSynced:         chopInformerFactory.Clickhouse().V1().ClickHouseUsers().Informer().HasSynced,
 		serviceLister:           kubeInformerFactory.Core().V1().Services().Lister(),
 		serviceListerSynced:     kubeInformerFactory.Core().V1().Services().Informer().HasSynced,
 		endpointsLister:         kubeInformerFactory.Core().V1().Endpoints().Lister(),
@@ -97,9 +102,25 @@ func NewController(
 		statefulSetListerSynced: kubeInformerFactory.Apps().V1().StatefulSets().Informer().HasSynced,
 		podLister:               kubeInformerFactory.Core().V1().Pods().Lister(),
 		podListerSynced:         kubeInformerFactory.Core().V1().Pods().Informer().HasSynced,
+		secretLister:            secretInformerFactory.Core().V1().Secrets().Lister(),
+		secretListerSynced:      secretInformerFactory.Core().V1().Secrets().Informer().HasSynced,
 		recorder:                recorder,
 	}
 	controller.initQueues()
+	if chop.Config().HasWatchNamespaceSelector() {
+		selector, err := k8sLabels.Parse(chop.Config().Watch.NamespaceSelector)
+		if err != nil {
+			log.F().Fatal("Unable to parse namespace selector: %s Err: %v", chop.Config().Watch.NamespaceSelector, err)
+		}
+		controller.namespaceSelector = selector
+		controller.namespaceListerSynced = kubeInformerFactory.Core().V1().Namespaces().Informer().HasSynced
+	}
+	if chop.Config().Sharding.Enabled {
+		if chop.Config().Sharding.Namespace == "" {
+			log.F().Fatal("Sharding requires namespace of the leases to be specified")
+		}
+		controller.sharding = newShardMembership(kubeClient, controller.rebalance, controller.hasCHI)
+	}
 	controller.addEventHandlers(chopInformerFactory, kubeInformerFactory)
 
 	return controller
@@ -129,8 +150,11 @@ func (c *Controller) addEventHandlersCHI(
 			if !chop.Config().IsWatchedNamespace(chi.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) {
+				return
+			}
 			log.V(3).M(chi).Info("chiInformer.AddFunc")
-			c.enqueueObject(NewReconcileCHI(reconcileAdd, nil, chi))
+			c.enqueueCHIAdd(chi)
 		},
 		UpdateFunc: func(old, new interface{}) {
 			oldChi := old.(*api.ClickHouseInstallation)
@@ -138,14 +162,60 @@ func (c *Controller) addEventHandlersCHI(
 			if !chop.Config().IsWatchedNamespace(newChi.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(newChi.Spec.GetOperatorClass()) {
+				if chop.Config().IsWatchedOperatorClass(oldChi.Spec.GetOperatorClass()) {
+					// CHI is handed over to another operator, its resources are kept intact
+					log.V(1).M(newChi).F().Info("CHI is handed over to operator class: %s", newChi.Spec.GetOperatorClass())
+				}
+				return
+			}
 			log.V(3).M(newChi).Info("chiInformer.UpdateFunc")
-			c.enqueueObject(NewReconcileCHI(reconcileUpdate, oldChi, newChi))
+			switch {
+			case model.IsReconcilePaused(newChi):
+				// Nothing is done with the paused CHI, including periodic checks, except of pending changes being reported
+				if (oldChi.ResourceVersion != newChi.ResourceVersion) || (newChi.EnsureStatus().GetStatus() != api.StatusPaused) {
+					c.enqueueObject(NewReconcileCHI(reconcilePause, oldChi, newChi))
+				}
+				return
+			case model.IsReconcilePaused(oldChi):
+				c.enqueueObject(NewReconcileCHI(reconcileResume, oldChi, newChi))
+			default:
+				c.enqueueObject(NewReconcileCHI(reconcileUpdate, oldChi, newChi))
+			}
+			// Informer resyncs periodically, so it is a good place to check generated credentials as well
+			if c.isOperatorCredentialsRotationRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandRotateOperatorCredentials, &newChi.ObjectMeta))
+			}
+			if c.isSchemaDriftCheckRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandCheckSchemaDrift, &newChi.ObjectMeta))
+			}
+			if c.isReplicationHealthCheckRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandCheckReplicationHealth, &newChi.ObjectMeta))
+			}
+			if c.isPVCAutoscalingCheckRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandCheckPVCAutoscaling, &newChi.ObjectMeta))
+			}
+			if c.isDeferredChangesApplyRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandApplyDeferredChanges, &newChi.ObjectMeta))
+			}
+			if c.isHibernationTransitionRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandHibernationTransition, &newChi.ObjectMeta))
+			}
+			if c.isVirtualClustersUpdateRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandUpdateVirtualClusters, &newChi.ObjectMeta))
+			}
+			if c.isStorageClassMigrationsCheckRequired(newChi) {
+				c.enqueueObject(NewPerCHICommand(commandCheckStorageClassMigrations, &newChi.ObjectMeta))
+			}
 		},
 		DeleteFunc: func(obj interface{}) {
 			chi := obj.(*api.ClickHouseInstallation)
 			if !chop.Config().IsWatchedNamespace(chi.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) {
+				return
+			}
 			log.V(3).M(chi).Info("chiInformer.DeleteFunc")
 			c.enqueueObject(NewReconcileCHI(reconcileDelete, chi, nil))
 		},
@@ -161,6 +231,9 @@ func (c *Controller) addEventHandlersCHIT(
 			if !chop.Config().IsWatchedNamespace(chit.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(chit.Spec.GetOperatorClass()) {
+				return
+			}
 			log.V(3).M(chit).Info("chitInformer.AddFunc")
 			c.enqueueObject(NewReconcileCHIT(reconcileAdd, nil, chit))
 		},
@@ -170,6 +243,13 @@ func (c *Controller) addEventHandlersCHIT(
 			if !chop.Config().IsWatchedNamespace(newChit.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(newChit.Spec.GetOperatorClass()) {
+				if chop.Config().IsWatchedOperatorClass(oldChit.Spec.GetOperatorClass()) {
+					// Template is handed over to another operator, thus it is not applicable anymore
+					c.enqueueObject(NewReconcileCHIT(reconcileDelete, oldChit, nil))
+				}
+				return
+			}
 			log.V(3).M(newChit).Info("chitInformer.UpdateFunc")
 			c.enqueueObject(NewReconcileCHIT(reconcileUpdate, oldChit, newChit))
 		},
@@ -178,6 +258,9 @@ func (c *Controller) addEventHandlersCHIT(
 			if !chop.Config().IsWatchedNamespace(chit.Namespace) {
 				return
 			}
+			if !chop.Config().IsWatchedOperatorClass(chit.Spec.GetOperatorClass()) {
+				return
+			}
 			log.V(3).M(chit).Info("chitInformer.DeleteFunc")
 			c.enqueueObject(NewReconcileCHIT(reconcileDelete, chit, nil))
 		},
@@ -216,6 +299,40 @@ func (c *Controller) addEventHandlersChopConfig(
 	})
 }
 
+func (c *Controller) addEventHandlersUser(
+	chopInformerFactory chopInformers.SharedInformerFactory,
+) {
+	chopInformerFactory.Clickhouse().V1().ClickHouseUsers().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			user := obj.(*api.ClickHouseUser)
+			if !chop.Config().IsWatchedNamespace(user.Namespace) {
+				return
+			}
+			log.V(3).M(user).Info("userInformer.AddFunc")
+			c.enqueueConfigUserConsumer(user)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldUser := old.(*api.ClickHouseUser)
+			newUser := new.(*api.ClickHouseUser)
+			if !chop.Config().IsWatchedNamespace(newUser.Namespace) || !isConfigUserChanged(oldUser, newUser) {
+				return
+			}
+			log.V(3).M(newUser).Info("userInformer.UpdateFunc")
+			// User may be moved to another CHI or to another mode, both CHIs are to be updated
+			c.enqueueConfigUserConsumer(oldUser)
+			c.enqueueConfigUserConsumer(newUser)
+		},
+		DeleteFunc: func(obj interface{}) {
+			user, ok := obj.(*api.ClickHouseUser)
+			if !ok || !chop.Config().IsWatchedNamespace(user.Namespace) {
+				return
+			}
+			log.V(3).M(user).Info("userInformer.DeleteFunc")
+			c.enqueueConfigUserConsumer(user)
+		},
+	})
+}
+
 func (c *Controller) addEventHandlersService(
 	kubeInformerFactory kubeInformers.SharedInformerFactory,
 ) {
@@ -361,6 +478,9 @@ func (c *Controller) addEventHandlersConfigMap(
 		},
 		UpdateFunc: func(old, new interface{}) {
 			configMap := old.(*core.ConfigMap)
+			if c.isDataSourceConfigMapChanged(configMap, new.(*core.ConfigMap)) {
+				c.enqueueDataSourceConfigMapConsumers(new.(*core.ConfigMap))
+			}
 			if !c.isTrackedObject(&configMap.ObjectMeta) {
 				return
 			}
@@ -438,6 +558,70 @@ func (c *Controller) addEventHandlersPod(
 	})
 }
 
+func (c *Controller) addEventHandlersSecret(
+	kubeInformerFactory kubeInformers.SharedInformerFactory,
+) {
+	kubeInformerFactory.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			secret := obj.(*core.Secret)
+			if !chop.Config().IsWatchedNamespace(secret.Namespace) {
+				return
+			}
+			log.V(3).M(secret).Info("secretInformer.AddFunc")
+			c.enqueueConfigUsersOfSecret(secret)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret := old.(*core.Secret)
+			newSecret := new.(*core.Secret)
+			if !chop.Config().IsWatchedNamespace(newSecret.Namespace) || (oldSecret.ResourceVersion == newSecret.ResourceVersion) {
+				return
+			}
+			if reflect.DeepEqual(oldSecret.Data, newSecret.Data) && reflect.DeepEqual(oldSecret.StringData, newSecret.StringData) {
+				return
+			}
+			log.V(3).M(newSecret).Info("secretInformer.UpdateFunc")
+			c.enqueueConfigUsersOfSecret(newSecret)
+		},
+		DeleteFunc: func(obj interface{}) {
+			secret, ok := obj.(*core.Secret)
+			if !ok || !chop.Config().IsWatchedNamespace(secret.Namespace) {
+				return
+			}
+			log.V(3).M(secret).Info("secretInformer.DeleteFunc")
+			c.enqueueConfigUsersOfSecret(secret)
+		},
+	})
+}
+
+func (c *Controller) addEventHandlersNamespace(
+	kubeInformerFactory kubeInformers.SharedInformerFactory,
+) {
+	if c.namespaceSelector == nil {
+		// Namespaces are not selected by labels, no need to watch them
+		return
+	}
+	// Namespaces are cluster-wide, thus permissions to list and watch them are required in this case only.
+	// Informers of other resources watch all namespaces as well, since namespaces may be selected at any moment,
+	// see GetInformerNamespace
+	kubeInformerFactory.Core().V1().Namespaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			namespace := obj.(*core.Namespace)
+			log.V(3).M(namespace).Info("namespaceInformer.AddFunc")
+			c.selectNamespace(namespace)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			newNamespace := new.(*core.Namespace)
+			log.V(3).M(newNamespace).Info("namespaceInformer.UpdateFunc")
+			c.selectNamespace(newNamespace)
+		},
+		DeleteFunc: func(obj interface{}) {
+			namespace := obj.(*core.Namespace)
+			log.V(3).M(namespace).Info("namespaceInformer.DeleteFunc")
+			chop.Config().SetSelectedNamespace(namespace.Name, false)
+		},
+	})
+}
+
 // addEventHandlers
 func (c *Controller) addEventHandlers(
 	chopInformerFactory chopInformers.SharedInformerFactory,
@@ -446,11 +630,299 @@ func (c *Controller) addEventHandlers(
 	c.addEventHandlersCHI(chopInformerFactory)
 	c.addEventHandlersCHIT(chopInformerFactory)
 	c.addEventHandlersChopConfig(chopInformerFactory)
+	c.addEventHandlersUser(chopInformerFactory)
 	c.addEventHandlersService(kubeInformerFactory)
 	c.addEventHandlersEndpoint(kubeInformerFactory)
 	c.addEventHandlersConfigMap(kubeInformerFactory)
 	c.addEventHandlersStatefulSet(kubeInformerFactory)
 	c.addEventHandlersPod(kubeInformerFactory)
+	c.addEventHandlersSecret(kubeInformerFactory)
+	c.addEventHandlersNamespace(kubeInformerFactory)
+}
+
+// selectNamespace (de)selects namespace as watched one according to its labels.
+// CHIs and CHITs of the newly selected namespace are enqueued, since their events have been ignored so far.
+// Resources of the deselected namespace are kept intact, the namespace is not watched anymore only
+func (c *Controller) selectNamespace(namespace *core.Namespace) {
+	selected := c.namespaceSelector.Matches(k8sLabels.Set(namespace.Labels))
+	if !chop.Config().SetSelectedNamespace(namespace.Name, selected) {
+		// Nothing changed
+		return
+	}
+	if !selected {
+		log.V(1).M(namespace).F().Info("Namespace is not watched anymore: %s", namespace.Name)
+		return
+	}
+
+	log.V(1).M(namespace).F().Info("Namespace is watched: %s", namespace.Name)
+	chits, err := c.chitLister.ClickHouseInstallationTemplates(namespace.Name).List(k8sLabels.Everything())
+	if err != nil {
+		log.V(1).M(namespace).F().Error("Unable to list CHITs of the namespace: %s Err: %v", namespace.Name, err)
+	}
+	for _, chit := range chits {
+		if chop.Config().IsWatchedOperatorClass(chit.Spec.GetOperatorClass()) {
+			c.enqueueObject(NewReconcileCHIT(reconcileAdd, nil, chit))
+		}
+	}
+	chis, err := c.chiLister.ClickHouseInstallations(namespace.Name).List(k8sLabels.Everything())
+	if err != nil {
+		log.V(1).M(namespace).F().Error("Unable to list CHIs of the namespace: %s Err: %v", namespace.Name, err)
+	}
+	for _, chi := range chis {
+		if chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) {
+			c.enqueueCHIAdd(chi)
+		}
+	}
+}
+
+// enqueueCHIAdd enqueues CHI, which has appeared in the watched scope
+func (c *Controller) enqueueCHIAdd(chi *api.ClickHouseInstallation) {
+	switch {
+	case model.IsReconcilePaused(chi):
+		c.enqueueObject(NewReconcileCHI(reconcilePause, nil, chi))
+	case chi.EnsureStatus().GetStatus() == api.StatusPaused:
+		// Reconcile was resumed while the operator was not running
+		c.enqueueObject(NewReconcileCHI(reconcileResume, nil, chi))
+	default:
+		c.enqueueObject(NewReconcileCHI(reconcileAdd, nil, chi))
+	}
+}
+
+// isOperatorCredentialsRotationRequired checks whether generated operator credentials of the CHI are to be rotated
+func (c *Controller) isOperatorCredentialsRotationRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() {
+		// Stopped CHI has no hosts to roll credentials out to
+		return false
+	}
+	creds, err := c.getOperatorCredentials(chi)
+	if err != nil {
+		// Checked again on the next resync
+		log.V(1).M(chi).F().Warning("unable to get operator credentials err: %v", err)
+		return false
+	}
+	if creds == nil {
+		// Running CHI has to get generated credentials as soon as password generation is enabled
+		return chop.Config().ClickHouse.Access.Generate.Enabled && chi.HasAncestor()
+	}
+	if creds.IsRotationInProgress() {
+		// Unfinished rotation has to be completed
+		return true
+	}
+	return creds.IsRotationRequested(
+		chop.Config().ClickHouse.Access.Generate.RotationPeriod,
+		chi.GetAnnotations()[model.AnnotationRotateOperatorPassword],
+	)
+}
+
+// isSchemaDriftCheckRequired checks whether schema drift check of the CHI is due
+func (c *Controller) isSchemaDriftCheckRequired(chi *api.ClickHouseInstallation) bool {
+	if !chop.Config().ClickHouse.SchemaDrift.Enabled || chi.IsStopped() || chi.IsHibernated() || !chi.HasAncestor() {
+		// Nothing to check in CHI, which has not been reconciled yet
+		return false
+	}
+	drift := chi.Status.GetSchemaDrift()
+	if drift == nil {
+		return true
+	}
+	checkedAt, err := time.Parse(time.RFC3339, drift.CheckedAt)
+	if err != nil {
+		return true
+	}
+	return time.Since(checkedAt) >= chop.Config().ClickHouse.SchemaDrift.Period
+}
+
+// isReplicationHealthCheckRequired checks whether replication health check of the CHI is due
+func (c *Controller) isReplicationHealthCheckRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() || !chi.HasAncestor() {
+		// Nothing to check in CHI, which has not been reconciled yet
+		return false
+	}
+	health := chi.Status.GetReplicationHealth()
+	if !chop.Config().ClickHouse.ReplicationHealth.Enabled {
+		// Hosts excluded before the check was disabled have to be included back
+		return len(health.GetExcludedHosts()) > 0
+	}
+	if health == nil {
+		return true
+	}
+	checkedAt, err := time.Parse(time.RFC3339, health.CheckedAt)
+	if err != nil {
+		return true
+	}
+	return time.Since(checkedAt) >= chop.Config().ClickHouse.ReplicationHealth.Period
+}
+
+// isStorageClassMigrationsCheckRequired checks whether CHI has old volumes retained by storage class migrations,
+// so hosts with migrated storage have to be checked to catch up with other replicas
+func (c *Controller) isStorageClassMigrationsCheckRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() {
+		// Stopped hosts do not replicate
+		return false
+	}
+	return len(chi.Status.GetRetainedStorageClassMigrations()) > 0
+}
+
+// isPVCAutoscalingCheckRequired checks whether PVC autoscaling check of the CHI is due
+func (c *Controller) isPVCAutoscalingCheckRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() || !chi.HasAncestor() {
+		// Nothing to check in CHI, which has not been reconciled yet
+		return false
+	}
+	if !model.HasPVCAutoscaling(chi.Status.GetNormalizedCHICompleted()) {
+		return false
+	}
+	autoscaling := chi.Status.GetPVCAutoscaling()
+	if autoscaling == nil {
+		return true
+	}
+	checkedAt, err := time.Parse(time.RFC3339, autoscaling.CheckedAt)
+	if err != nil {
+		return true
+	}
+	return time.Since(checkedAt) >= chop.Config().ClickHouse.PVCAutoscaling.Period
+}
+
+// isDeferredChangesApplyRequired checks whether maintenance window is open for changes deferred till it
+func (c *Controller) isDeferredChangesApplyRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() || !chi.HasAncestor() {
+		return false
+	}
+	if len(chi.Status.GetDeferredHosts()) == 0 {
+		return false
+	}
+	if chi.Status.GetStatus() == api.StatusInProgress {
+		// Running reconcile applies deferred changes as well
+		return false
+	}
+	return model.IsInMaintenanceWindow(chi.Status.GetNormalizedCHICompleted(), time.Now())
+}
+
+// isHibernationTransitionRequired checks whether CHI is due to be put to sleep or woken up by the hibernation schedule
+func (c *Controller) isHibernationTransitionRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || !chi.HasAncestor() {
+		// Explicit stop takes precedence over the schedule
+		return false
+	}
+	if chi.Status.GetStatus() == api.StatusInProgress {
+		// Running reconcile applies the schedule as well
+		return false
+	}
+	ancestor := chi.Status.GetNormalizedCHICompleted()
+	if !model.HasHibernation(ancestor) {
+		return false
+	}
+	// Ancestor is stopped in case CHI has been hibernated by the latest reconcile
+	return model.IsHibernationTime(ancestor, time.Now()) != ancestor.IsStopped()
+}
+
+// isVirtualClustersUpdateRequired checks whether layouts of the referenced CHIs differ from virtual clusters rendered into CHI config
+func (c *Controller) isVirtualClustersUpdateRequired(chi *api.ClickHouseInstallation) bool {
+	if chi.IsStopped() || chi.IsHibernated() || !chi.HasAncestor() {
+		return false
+	}
+	if chi.Status.GetStatus() == api.StatusInProgress {
+		// Running reconcile resolves virtual clusters as well
+		return false
+	}
+	virtualClusters := chi.Status.GetNormalizedCHICompleted().Spec.Configuration.VirtualClusters
+	if (len(virtualClusters) == 0) && (len(chi.Status.GetVirtualClusters()) == 0) {
+		return false
+	}
+	return !api.EqualVirtualClustersStatus(c.getVirtualClustersStatus(virtualClusters), chi.Status.GetVirtualClusters())
+}
+
+// isDataSourceConfigMapChanged checks whether data of the config map, which may be a data source of settings or files, is changed.
+// Config maps generated by the operator are not data sources
+func (c *Controller) isDataSourceConfigMapChanged(old, new *core.ConfigMap) bool {
+	if !chop.Config().IsWatchedNamespace(new.Namespace) || model.IsCHOPGeneratedObject(&new.ObjectMeta) {
+		return false
+	}
+	if old.ResourceVersion == new.ResourceVersion {
+		// Periodic resync
+		return false
+	}
+	return !reflect.DeepEqual(old.Data, new.Data) || !reflect.DeepEqual(old.BinaryData, new.BinaryData)
+}
+
+// enqueueDataSourceConfigMapConsumers enqueues reconcile of CHIs, which settings or files are sourced from the config map
+func (c *Controller) enqueueDataSourceConfigMapConsumers(configMap *core.ConfigMap) {
+	statefulSets, err := c.statefulSetLister.StatefulSets(configMap.Namespace).List(k8sLabels.Everything())
+	if err != nil {
+		log.V(1).M(configMap).F().Error("unable to list StatefulSets err: %v", err)
+		return
+	}
+
+	var enqueued []string
+	for _, statefulSet := range statefulSets {
+		if !model.IsDataSourceConfigMap(statefulSet, configMap) {
+			continue
+		}
+		chiName, err := model.GetCHINameFromObjectMeta(&statefulSet.ObjectMeta)
+		if (err != nil) || util.InArray(chiName, enqueued) {
+			continue
+		}
+		chi, err := c.chiLister.ClickHouseInstallations(configMap.Namespace).Get(chiName)
+		if err != nil {
+			continue
+		}
+		if !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) || model.IsReconcilePaused(chi) {
+			continue
+		}
+		log.V(1).M(chi).F().Info("Config map %s/%s is changed, reconcile CHI", configMap.Namespace, configMap.Name)
+		c.enqueueObject(NewPerCHICommand(commandReconcileDataSources, &chi.ObjectMeta))
+		enqueued = append(enqueued, chiName)
+	}
+}
+
+// isConfigUserChanged checks whether the user is changed in a way, which affects users config of the CHI
+func isConfigUserChanged(old, new *api.ClickHouseUser) bool {
+	if old.ResourceVersion == new.ResourceVersion {
+		// Periodic resync
+		return false
+	}
+	return (old.Generation != new.Generation) ||
+		(old.IsDeleting() != new.IsDeleting()) ||
+		(getUserProvisionedMode(old) != getUserProvisionedMode(new))
+}
+
+// getUserProvisionedMode gets mode the user has been provisioned in, as recorded in its status
+func getUserProvisionedMode(user *api.ClickHouseUser) api.ClickHouseUserMode {
+	if user.Status == nil {
+		return ""
+	}
+	return user.Status.Mode
+}
+
+// enqueueConfigUserConsumer enqueues regeneration of users config of the CHI, which the user is provisioned to in config mode.
+// Users config is owned by the CHI, thus it is regenerated by the CHI controller only
+func (c *Controller) enqueueConfigUserConsumer(user *api.ClickHouseUser) {
+	if (user.GetMode() != api.ClickHouseUserModeConfig) && (getUserProvisionedMode(user) != api.ClickHouseUserModeConfig) {
+		// SQL-provisioned users are not present in users config
+		return
+	}
+	chi, err := c.chiLister.ClickHouseInstallations(user.Namespace).Get(user.Spec.Target.CHI)
+	if err != nil {
+		return
+	}
+	if !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) || model.IsReconcilePaused(chi) {
+		// Users config is regenerated by reconcile on resume
+		return
+	}
+	log.V(1).M(chi).F().Info("ClickHouseUser %s/%s is changed, reconcile users of CHI", user.Namespace, user.Name)
+	c.enqueueObject(NewPerCHICommand(commandReconcileUsers, &chi.ObjectMeta))
+}
+
+// enqueueConfigUsersOfSecret enqueues regeneration of users config of CHIs, which have users with passwords sourced from the Secret
+func (c *Controller) enqueueConfigUsersOfSecret(secret *core.Secret) {
+	users, err := c.chuLister.ClickHouseUsers(secret.Namespace).List(k8sLabels.Everything())
+	if err != nil {
+		return
+	}
+	for _, user := range users {
+		if user.IsPasswordSourcedFrom(secret.Name) {
+			c.enqueueConfigUserConsumer(user)
+		}
+	}
 }
 
 // isTrackedObject checks whether operator is interested in changes of this object
@@ -468,14 +940,23 @@ func (c *Controller) Run(ctx context.Context) {
 		}
 	}()
 
-	log.V(1).Info("Starting ClickHouseInstallation controller")
-	if !waitForCacheSync(
-		ctx,
-		"ClickHouseInstallation",
+	log.Ctx(ctx).V(1).Info("Starting ClickHouseInstallation controller")
+	cacheSyncs := []cache.InformerSynced{
 		c.chiListerSynced,
 		c.statefulSetListerSynced,
 		c.configMapListerSynced,
 		c.serviceListerSynced,
+		c.secretListerSynced,
+		c.chuListerSynced,
+	}
+	if c.namespaceListerSynced != nil {
+		// Watched namespaces have to be known before any CHI is processed
+		cacheSyncs = append(cacheSyncs, c.namespaceListerSynced)
+	}
+	if !waitForCacheSync(
+		ctx,
+		"ClickHouseInstallation",
+		cacheSyncs...,
 	) {
 		// Unable to sync
 		return
@@ -488,21 +969,27 @@ func (c *Controller) Run(ctx context.Context) {
 		case nil:
 			cnt = max
 		case ErrOperatorPodNotSpecified:
-			log.V(1).F().Error("Since operator pod is not specified, will not perform labeling")
+			log.Ctx(ctx).V(1).F().Error("Since operator pod is not specified, will not perform labeling")
 			cnt = max
 		default:
-			log.V(1).F().Error("ERROR label objects, will retry. Err: %v", err)
+			log.Ctx(ctx).V(1).F().Error("ERROR label objects, will retry. Err: %v", err)
 			util.WaitContextDoneOrTimeout(ctx, 5*time.Second)
 		}
 	}
 
+	// Partition CHIs among operator instances before any CHI is processed
+	if c.sharding != nil {
+		c.sharding.start(ctx)
+		go c.sharding.run(ctx)
+	}
+
 	//
 	// Start threads
 	//
 	workersNum := len(c.queues)
-	log.V(1).F().Info("ClickHouseInstallation controller: starting workers number: %d", workersNum)
+	log.Ctx(ctx).V(1).F().Info("ClickHouseInstallation controller: starting workers number: %d", workersNum)
 	for i := 0; i < workersNum; i++ {
-		log.V(1).F().Info("ClickHouseInstallation controller: starting worker %d out of %d", i+1, workersNum)
+		log.Ctx(ctx).V(1).F().Info("ClickHouseInstallation controller: starting worker %d out of %d", i+1, workersNum)
 		sys := false
 		if i < api.DefaultReconcileSystemThreadsNumber {
 			sys = true
@@ -510,9 +997,9 @@ func (c *Controller) Run(ctx context.Context) {
 		worker := c.newWorker(c.queues[i], sys)
 		go wait.Until(worker.run, runWorkerPeriod, ctx.Done())
 	}
-	defer log.V(1).F().Info("ClickHouseInstallation controller: shutting down workers")
+	defer log.Ctx(ctx).V(1).F().Info("ClickHouseInstallation controller: shutting down workers")
 
-	log.V(1).F().Info("ClickHouseInstallation controller: workers started")
+	log.Ctx(ctx).V(1).F().Info("ClickHouseInstallation controller: workers started")
 	<-ctx.Done()
 }
 
@@ -574,6 +1061,14 @@ func logCommand(command *ReconcileCHI) {
 
 // enqueueObject adds ClickHouseInstallation object to the work queue
 func (c *Controller) enqueueObject(obj queue.PriorityQueueItem) {
+	if !c.isOwnedItem(obj) {
+		// CHI is processed by another operator instance
+		return
+	}
+	if !c.isOperatorClassItem(obj) {
+		// CHI is in charge of another operator
+		return
+	}
 	handle := []byte(obj.Handle().(string))
 	index := 0
 	enqueue := false
@@ -582,11 +1077,14 @@ func (c *Controller) enqueueObject(obj queue.PriorityQueueItem) {
 		variants := len(c.queues) - api.DefaultReconcileSystemThreadsNumber
 		index = api.DefaultReconcileSystemThreadsNumber + util.HashIntoIntTopped(handle, variants)
 		switch command.cmd {
-		case reconcileAdd:
+		case reconcileAdd, reconcilePause, reconcileResume:
 			enqueue = prepareCHIAdd(command)
 		case reconcileUpdate:
 			enqueue = prepareCHIUpdate(command)
 		}
+	case *PerCHICommand:
+		index = c.getCHIQueueIndex(command.chi.Namespace, command.chi.Name)
+		enqueue = true
 	case
 		*ReconcileCHIT,
 		*ReconcileChopConfig,
@@ -603,9 +1101,45 @@ func (c *Controller) enqueueObject(obj queue.PriorityQueueItem) {
 	}
 }
 
+// getCHIQueueIndex gets index of the queue reconcile of the CHI goes into.
+// Commands run against the CHI go into the same queue, thus they are serialized with reconcile of the CHI
+func (c *Controller) getCHIQueueIndex(namespace, name string) int {
+	// The same handle as the one of the reconcile queue item of the CHI
+	handle := "ReconcileCHI" + ":" + namespace + "/" + name
+	variants := len(c.queues) - api.DefaultReconcileSystemThreadsNumber
+	return api.DefaultReconcileSystemThreadsNumber + util.HashIntoIntTopped([]byte(handle), variants)
+}
+
+// isOperatorClassItem checks whether queue item relates to CHI of the operator's class.
+// CHI events are filtered by the informer handlers, while events of the objects of the CHI are filtered here
+func (c *Controller) isOperatorClassItem(item queue.PriorityQueueItem) bool {
+	switch item.(type) {
+	case *ReconcileEndpoints, *ReconcilePod, *DropDns:
+	default:
+		return true
+	}
+	namespace, name, ok := getItemCHI(item)
+	if !ok {
+		return true
+	}
+	chi, err := c.chiLister.ClickHouseInstallations(namespace).Get(name)
+	if err != nil {
+		// Unknown CHI is to be handled by the processing of the item
+		return true
+	}
+	return chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass())
+}
+
 // updateWatch
 func (c *Controller) updateWatch(chi *api.ClickHouseInstallation) {
+	creds, err := c.fetchOperatorCredentials(chi)
+	if err != nil {
+		// Metrics exporter is informed on the next reconcile
+		log.V(1).M(chi).F().Warning("unable to get operator credentials err: %v", err)
+		return
+	}
 	watched := metrics.NewWatchedCHI(chi)
+	watched.SetCredentials(creds.GetConnectionCredentials())
 	go c.updateWatchAsync(watched)
 }
 
@@ -682,7 +1216,7 @@ type patchFinalizers struct {
 // patchCHIFinalizers patch ClickHouseInstallation finalizers
 func (c *Controller) patchCHIFinalizers(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -704,13 +1238,13 @@ func (c *Controller) patchCHIFinalizers(ctx context.Context, chi *api.ClickHouse
 	_new, err := c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Patch(ctx, chi.Name, types.JSONPatchType, payload, controller.NewPatchOptions())
 	if err != nil {
 		// Error update
-		log.V(1).M(chi).F().Error("%q", err)
+		log.Ctx(ctx).V(1).M(chi).F().Error("%q", err)
 		return err
 	}
 
 	if chi.ObjectMeta.ResourceVersion != _new.ObjectMeta.ResourceVersion {
 		// Updated
-		log.V(2).M(chi).F().Info("ResourceVersion change: %s to %s", chi.ObjectMeta.ResourceVersion, _new.ObjectMeta.ResourceVersion)
+		log.Ctx(ctx).V(2).M(chi).F().Info("ResourceVersion change: %s to %s", chi.ObjectMeta.ResourceVersion, _new.ObjectMeta.ResourceVersion)
 		chi.ObjectMeta.ResourceVersion = _new.ObjectMeta.ResourceVersion
 		return nil
 	}
@@ -729,7 +1263,7 @@ type UpdateCHIStatusOptions struct {
 // updateCHIObjectStatus updates ClickHouseInstallation object's Status
 func (c *Controller) updateCHIObjectStatus(ctx context.Context, chi *api.ClickHouseInstallation, opts UpdateCHIStatusOptions) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -744,10 +1278,10 @@ func (c *Controller) updateCHIObjectStatus(ctx context.Context, chi *api.ClickHo
 		}
 
 		if retry {
-			log.V(2).M(chi).F().Warning("got error, will retry. err: %q", err)
+			log.Ctx(ctx).V(2).M(chi).F().Warning("got error, will retry. err: %q", err)
 			time.Sleep(1 * time.Second)
 		} else {
-			log.V(1).M(chi).F().Error("got error, all retries are exhausted. err: %q", err)
+			log.Ctx(ctx).V(1).M(chi).F().Error("got error, all retries are exhausted. err: %q", err)
 		}
 	}
 	return
@@ -756,12 +1290,12 @@ func (c *Controller) updateCHIObjectStatus(ctx context.Context, chi *api.ClickHo
 // doUpdateCHIObjectStatus updates ClickHouseInstallation object's Status
 func (c *Controller) doUpdateCHIObjectStatus(ctx context.Context, chi *api.ClickHouseInstallation, opts UpdateCHIStatusOptions) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	namespace, name := util.NamespaceName(chi.ObjectMeta)
-	log.V(3).M(chi).F().Info("Update CHI status")
+	log.Ctx(ctx).V(3).M(chi).F().Info("Update CHI status")
 
 	podIPs := c.getPodsIPs(chi)
 
@@ -770,14 +1304,14 @@ func (c *Controller) doUpdateCHIObjectStatus(ctx context.Context, chi *api.Click
 		if opts.TolerateAbsence {
 			return nil
 		}
-		log.V(1).M(chi).F().Error("%q", err)
+		log.Ctx(ctx).V(1).M(chi).F().Error("%q", err)
 		return err
 	}
 	if cur == nil {
 		if opts.TolerateAbsence {
 			return nil
 		}
-		log.V(1).M(chi).F().Error("NULL returned")
+		log.Ctx(ctx).V(1).M(chi).F().Error("NULL returned")
 		return fmt.Errorf("ERROR GetCHI (%s/%s): NULL returned", namespace, name)
 	}
 
@@ -788,13 +1322,13 @@ func (c *Controller) doUpdateCHIObjectStatus(ctx context.Context, chi *api.Click
 	_new, err := c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).UpdateStatus(ctx, cur, controller.NewUpdateOptions())
 	if err != nil {
 		// Error update
-		log.V(2).M(chi).F().Info("Got error upon update, may retry. err: %q", err)
+		log.Ctx(ctx).V(2).M(chi).F().Info("Got error upon update, may retry. err: %q", err)
 		return err
 	}
 
 	// Propagate updated ResourceVersion into chi
 	if chi.ObjectMeta.ResourceVersion != _new.ObjectMeta.ResourceVersion {
-		log.V(3).M(chi).F().Info("ResourceVersion change: %s to %s", chi.ObjectMeta.ResourceVersion, _new.ObjectMeta.ResourceVersion)
+		log.Ctx(ctx).V(3).M(chi).F().Info("ResourceVersion change: %s to %s", chi.ObjectMeta.ResourceVersion, _new.ObjectMeta.ResourceVersion)
 		chi.ObjectMeta.ResourceVersion = _new.ObjectMeta.ResourceVersion
 		return nil
 	}
@@ -806,7 +1340,7 @@ func (c *Controller) doUpdateCHIObjectStatus(ctx context.Context, chi *api.Click
 
 func (c *Controller) poll(ctx context.Context, chi *api.ClickHouseInstallation, f func(c *api.ClickHouseInstallation, e error) bool) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -817,7 +1351,7 @@ func (c *Controller) poll(ctx context.Context, chi *api.ClickHouseInstallation,
 		if f(cur, err) {
 			// Continue polling
 			if util.IsContextDone(ctx) {
-				log.V(2).Info("task is done")
+				log.Ctx(ctx).V(2).Info("task is done")
 				return
 			}
 			time.Sleep(15 * time.Second)
@@ -831,12 +1365,12 @@ func (c *Controller) poll(ctx context.Context, chi *api.ClickHouseInstallation,
 // installFinalizer
 func (c *Controller) installFinalizer(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(2).M(chi).S().P()
-	defer log.V(2).M(chi).E().P()
+	log.Ctx(ctx).V(2).M(chi).S().P()
+	defer log.Ctx(ctx).V(2).M(chi).E().P()
 
 	cur, err := c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Get(ctx, chi.Name, controller.NewGetOptions())
 	if err != nil {
@@ -850,7 +1384,7 @@ func (c *Controller) installFinalizer(ctx context.Context, chi *api.ClickHouseIn
 		// Already installed
 		return nil
 	}
-	log.V(3).M(chi).F().Info("no finalizer found, need to install one")
+	log.Ctx(ctx).V(3).M(chi).F().Info("no finalizer found, need to install one")
 
 	cur.ObjectMeta.Finalizers = append(cur.ObjectMeta.Finalizers, FinalizerName)
 	return c.patchCHIFinalizers(ctx, cur)
@@ -859,12 +1393,12 @@ func (c *Controller) installFinalizer(ctx context.Context, chi *api.ClickHouseIn
 // uninstallFinalizer
 func (c *Controller) uninstallFinalizer(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(2).M(chi).S().P()
-	defer log.V(2).M(chi).E().P()
+	log.Ctx(ctx).V(2).M(chi).S().P()
+	defer log.Ctx(ctx).V(2).M(chi).E().P()
 
 	cur, err := c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Get(ctx, chi.Name, controller.NewGetOptions())
 	if err != nil {
@@ -927,11 +1461,11 @@ func (c *Controller) handleObject(obj interface{}) {
 
 // waitForCacheSync is a logger-wrapper over cache.WaitForCacheSync() and it waits for caches to populate
 func waitForCacheSync(ctx context.Context, name string, cacheSyncs ...cache.InformerSynced) bool {
-	log.V(1).F().Info("Syncing caches for %s controller", name)
+	log.Ctx(ctx).V(1).F().Info("Syncing caches for %s controller", name)
 	if !cache.WaitForCacheSync(ctx.Done(), cacheSyncs...) {
 		utilRuntime.HandleError(fmt.Errorf(messageUnableToSync, name))
 		return false
 	}
-	log.V(1).F().Info("Caches are synced for %s controller", name)
+	log.Ctx(ctx).V(1).F().Info("Caches are synced for %s controller", name)
 	return true
 }
@@ -0,0 +1,70 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	chopFake "github.com/minorhacks/clickhouse-operator/pkg/client/clientset/versioned/fake"
+	chopInformers "github.com/minorhacks/clickhouse-operator/pkg/client/informers/externalversions"
+)
+
+func TestSelectNamespace(t *testing.T) {
+	initTestCHOp()
+	namespaces, selector := chop.Config().Watch.Namespaces, chop.Config().Watch.NamespaceSelector
+	chop.Config().Watch.Namespaces, chop.Config().Watch.NamespaceSelector = nil, "team=db"
+	t.Cleanup(func() {
+		chop.Config().Watch.Namespaces, chop.Config().Watch.NamespaceSelector = namespaces, selector
+		chop.Config().SetSelectedNamespace("ns1", false)
+	})
+
+	chopInformerFactory := chopInformers.NewSharedInformerFactory(chopFake.NewSimpleClientset(), 0)
+	chiInformer := chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Informer()
+	chi := &api.ClickHouseInstallation{}
+	chi.Namespace = "ns1"
+	chi.Name = "chi"
+	require.NoError(t, chiInformer.GetIndexer().Add(chi))
+
+	c := &Controller{
+		chiLister:         chopInformerFactory.Clickhouse().V1().ClickHouseInstallations().Lister(),
+		chitLister:        chopInformerFactory.Clickhouse().V1().ClickHouseInstallationTemplates().Lister(),
+		namespaceSelector: k8sLabels.SelectorFromSet(k8sLabels.Set{"team": "db"}),
+	}
+	c.initQueues()
+	queued := func() (items int) {
+		for _, q := range c.queues {
+			items += q.Len()
+		}
+		return items
+	}
+	namespace := func(labels map[string]string) *core.Namespace {
+		ns := &core.Namespace{}
+		ns.Name = "ns1"
+		ns.Labels = labels
+		return ns
+	}
+
+	// Namespace without matching labels is not watched
+	c.selectNamespace(namespace(map[string]string{"team": "web"}))
+	require.False(t, chop.Config().IsWatchedNamespace("ns1"))
+	require.Zero(t, queued())
+
+	// Namespace is selected as soon as labels match, CHIs of the namespace are enqueued
+	c.selectNamespace(namespace(map[string]string{"team": "db", "env": "prod"}))
+	require.True(t, chop.Config().IsWatchedNamespace("ns1"))
+	require.Equal(t, 1, queued())
+
+	// Nothing is enqueued in case selection has not changed
+	c.selectNamespace(namespace(map[string]string{"team": "db"}))
+	require.True(t, chop.Config().IsWatchedNamespace("ns1"))
+	require.Equal(t, 1, queued())
+
+	// Namespace is deselected as soon as labels do not match anymore
+	c.selectNamespace(namespace(nil))
+	require.False(t, chop.Config().IsWatchedNamespace("ns1"))
+	require.Equal(t, 1, queued())
+}
@@ -32,28 +32,28 @@ import (
 
 // createStatefulSet is an internal function, used in reconcileStatefulSet only
 func (c *Controller) createStatefulSet(ctx context.Context, host *api.ChiHost) ErrorCRUD {
-	log.V(1).M(host).F().P()
+	log.Ctx(ctx).V(1).M(host).F().P()
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	statefulSet := host.Runtime.DesiredStatefulSet
 
-	log.V(1).Info("Create StatefulSet %s/%s", statefulSet.Namespace, statefulSet.Name)
+	log.Ctx(ctx).V(1).Info("Create StatefulSet %s/%s", statefulSet.Namespace, statefulSet.Name)
 	if _, err := c.kubeClient.AppsV1().StatefulSets(statefulSet.Namespace).Create(ctx, statefulSet, controller.NewCreateOptions()); err != nil {
-		log.V(1).M(host).F().Error("StatefulSet create failed. err: %v", err)
+		log.Ctx(ctx).V(1).M(host).F().Error("StatefulSet create failed. err: %v", err)
 		return errCRUDRecreate
 	}
 
 	// StatefulSet created, wait until host is ready
 	if err := c.waitHostReady(ctx, host); err != nil {
-		log.V(1).M(host).F().Error("StatefulSet create wait failed. err: %v", err)
+		log.Ctx(ctx).V(1).M(host).F().Error("StatefulSet create wait failed. err: %v", err)
 		return c.onStatefulSetCreateFailed(ctx, host)
 	}
 
-	log.V(2).M(host).F().Info("Target generation reached, StatefulSet created successfully")
+	log.Ctx(ctx).V(2).M(host).F().Info("Target generation reached, StatefulSet created successfully")
 	return nil
 }
 
@@ -64,17 +64,17 @@ func (c *Controller) updateStatefulSet(
 	newStatefulSet *apps.StatefulSet,
 	host *api.ChiHost,
 ) ErrorCRUD {
-	log.V(2).M(host).F().P()
+	log.Ctx(ctx).V(2).M(host).F().P()
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	// Apply newStatefulSet and wait for Generation to change
 	updatedStatefulSet, err := c.kubeClient.AppsV1().StatefulSets(newStatefulSet.Namespace).Update(ctx, newStatefulSet, controller.NewUpdateOptions())
 	if err != nil {
-		log.V(1).M(host).F().Error("StatefulSet update failed. err: %v", err)
+		log.Ctx(ctx).V(1).M(host).F().Error("StatefulSet update failed. err: %v", err)
 		diff, equal := messagediff.DeepDiff(oldStatefulSet.Spec, newStatefulSet.Spec)
 
 		str := ""
@@ -98,7 +98,7 @@ func (c *Controller) updateStatefulSet(
 			// Something modified
 			str += util.MessageDiffItemString("modified spec items", "none", "", diff.Modified)
 		}
-		log.V(1).M(host).F().Error("%s", str)
+		log.Ctx(ctx).V(1).M(host).F().Error("%s", str)
 
 		return errCRUDRecreate
 	}
@@ -109,18 +109,18 @@ func (c *Controller) updateStatefulSet(
 
 	if updatedStatefulSet.Generation == oldStatefulSet.Generation {
 		// Generation is not updated - no changes in .spec section were made
-		log.V(2).M(host).F().Info("no generation change")
+		log.Ctx(ctx).V(2).M(host).F().Info("no generation change")
 		return nil
 	}
 
-	log.V(1).M(host).F().Info("generation change %d=>%d", oldStatefulSet.Generation, updatedStatefulSet.Generation)
+	log.Ctx(ctx).V(1).M(host).F().Info("generation change %d=>%d", oldStatefulSet.Generation, updatedStatefulSet.Generation)
 
 	if err := c.waitHostReady(ctx, host); err != nil {
-		log.V(1).M(host).F().Error("StatefulSet update wait failed. err: %v", err)
+		log.Ctx(ctx).V(1).M(host).F().Error("StatefulSet update wait failed. err: %v", err)
 		return c.onStatefulSetUpdateFailed(ctx, oldStatefulSet, host)
 	}
 
-	log.V(2).M(host).F().Info("Target generation reached, StatefulSet updated successfully")
+	log.Ctx(ctx).V(2).M(host).F().Info("Target generation reached, StatefulSet updated successfully")
 	return nil
 }
 
@@ -146,9 +146,9 @@ func (c *Controller) updateStatefulSet(
 
 // updatePersistentVolumeClaim
 func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.PersistentVolumeClaim) (*core.PersistentVolumeClaim, error) {
-	log.V(2).M(pvc).F().P()
+	log.Ctx(ctx).V(2).M(pvc).F().P()
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil, fmt.Errorf("task is done")
 	}
 
@@ -158,12 +158,12 @@ func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.
 			// This is not an error per se, means PVC is not created (yet)?
 			_, err = c.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, controller.NewCreateOptions())
 			if err != nil {
-				log.V(1).M(pvc).F().Error("unable to Create PVC err: %v", err)
+				log.Ctx(ctx).V(1).M(pvc).F().Error("unable to Create PVC err: %v", err)
 			}
 			return pvc, err
 		}
 		// In case of any non-NotFound API error - unable to proceed
-		log.V(1).M(pvc).F().Error("ERROR unable to get PVC(%s/%s) err: %v", pvc.Namespace, pvc.Name, err)
+		log.Ctx(ctx).V(1).M(pvc).F().Error("ERROR unable to get PVC(%s/%s) err: %v", pvc.Namespace, pvc.Name, err)
 		return nil, err
 	}
 
@@ -177,7 +177,7 @@ func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.
 	//if strings.Contains(err.Error(), "field can not be less than previous value") {
 	//	return pvc, nil
 	//}
-	log.V(1).M(pvc).F().Error("unable to Update PVC err: %v", err)
+	log.Ctx(ctx).V(1).M(pvc).F().Error("unable to Update PVC err: %v", err)
 	return nil, err
 }
 
@@ -185,7 +185,7 @@ func (c *Controller) updatePersistentVolumeClaim(ctx context.Context, pvc *core.
 // It can just delete failed StatefulSet or do nothing
 func (c *Controller) onStatefulSetCreateFailed(ctx context.Context, host *api.ChiHost) ErrorCRUD {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return errCRUDIgnore
 	}
 
@@ -193,12 +193,12 @@ func (c *Controller) onStatefulSetCreateFailed(ctx context.Context, host *api.Ch
 	switch chop.Config().Reconcile.StatefulSet.Create.OnFailure {
 	case api.OnStatefulSetCreateFailureActionAbort:
 		// Report appropriate error, it will break reconcile loop
-		log.V(1).M(host).F().Info("abort")
+		log.Ctx(ctx).V(1).M(host).F().Info("abort")
 		return errCRUDAbort
 
 	case api.OnStatefulSetCreateFailureActionDelete:
 		// Delete gracefully failed StatefulSet
-		log.V(1).M(host).F().Info(
+		log.Ctx(ctx).V(1).M(host).F().Info(
 			"going to DELETE FAILED StatefulSet %s",
 			util.NamespaceNameString(host.Runtime.DesiredStatefulSet.ObjectMeta))
 		_ = c.deleteHost(ctx, host)
@@ -206,13 +206,13 @@ func (c *Controller) onStatefulSetCreateFailed(ctx context.Context, host *api.Ch
 
 	case api.OnStatefulSetCreateFailureActionIgnore:
 		// Ignore error, continue reconcile loop
-		log.V(1).M(host).F().Info(
+		log.Ctx(ctx).V(1).M(host).F().Info(
 			"going to ignore error %s",
 			util.NamespaceNameString(host.Runtime.DesiredStatefulSet.ObjectMeta))
 		return errCRUDIgnore
 
 	default:
-		log.V(1).M(host).F().Error(
+		log.Ctx(ctx).V(1).M(host).F().Error(
 			"Unknown c.chop.Config().OnStatefulSetCreateFailureAction=%s",
 			chop.Config().Reconcile.StatefulSet.Create.OnFailure)
 		return errCRUDIgnore
@@ -225,7 +225,7 @@ func (c *Controller) onStatefulSetCreateFailed(ctx context.Context, host *api.Ch
 // It can try to revert StatefulSet to its previous version, specified in rollbackStatefulSet
 func (c *Controller) onStatefulSetUpdateFailed(ctx context.Context, rollbackStatefulSet *apps.StatefulSet, host *api.ChiHost) ErrorCRUD {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return errCRUDIgnore
 	}
 
@@ -236,15 +236,15 @@ func (c *Controller) onStatefulSetUpdateFailed(ctx context.Context, rollbackStat
 	switch chop.Config().Reconcile.StatefulSet.Update.OnFailure {
 	case api.OnStatefulSetUpdateFailureActionAbort:
 		// Report appropriate error, it will break reconcile loop
-		log.V(1).M(host).F().Info("abort StatefulSet %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
+		log.Ctx(ctx).V(1).M(host).F().Info("abort StatefulSet %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
 		return errCRUDAbort
 
 	case api.OnStatefulSetUpdateFailureActionRollback:
 		// Need to revert current StatefulSet to oldStatefulSet
-		log.V(1).M(host).F().Info("going to ROLLBACK FAILED StatefulSet %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
+		log.Ctx(ctx).V(1).M(host).F().Info("going to ROLLBACK FAILED StatefulSet %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
 		statefulSet, err := c.getStatefulSet(host)
 		if err != nil {
-			log.V(1).M(host).F().Warning("Unable to fetch current StatefulSet %s. err: %q", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta), err)
+			log.Ctx(ctx).V(1).M(host).F().Warning("Unable to fetch current StatefulSet %s. err: %q", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta), err)
 			return c.shouldContinueOnUpdateFailed()
 		}
 
@@ -261,11 +261,11 @@ func (c *Controller) onStatefulSetUpdateFailed(ctx context.Context, rollbackStat
 
 	case api.OnStatefulSetUpdateFailureActionIgnore:
 		// Ignore error, continue reconcile loop
-		log.V(1).M(host).F().Info("going to ignore error %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
+		log.Ctx(ctx).V(1).M(host).F().Info("going to ignore error %s", util.NamespaceNameString(rollbackStatefulSet.ObjectMeta))
 		return errCRUDIgnore
 
 	default:
-		log.V(1).M(host).F().Error("Unknown c.chop.Config().OnStatefulSetUpdateFailureAction=%s", chop.Config().Reconcile.StatefulSet.Update.OnFailure)
+		log.Ctx(ctx).V(1).M(host).F().Error("Unknown c.chop.Config().OnStatefulSetUpdateFailureAction=%s", chop.Config().Reconcile.StatefulSet.Update.OnFailure)
 		return errCRUDIgnore
 	}
 
@@ -301,17 +301,17 @@ func (c *Controller) shouldContinueOnUpdateFailed() ErrorCRUD {
 }
 
 func (c *Controller) createSecret(ctx context.Context, secret *core.Secret) error {
-	log.V(1).M(secret).F().P()
+	log.Ctx(ctx).V(1).M(secret).F().P()
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(1).Info("Create Secret %s/%s", secret.Namespace, secret.Name)
+	log.Ctx(ctx).V(1).Info("Create Secret %s/%s", secret.Namespace, secret.Name)
 	if _, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, controller.NewCreateOptions()); err != nil {
 		// Unable to create StatefulSet at all
-		log.V(1).Error("Create Secret %s/%s failed err:%v", secret.Namespace, secret.Name, err)
+		log.Ctx(ctx).V(1).Error("Create Secret %s/%s failed err:%v", secret.Namespace, secret.Name, err)
 		return err
 	}
 
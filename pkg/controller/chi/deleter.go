@@ -31,7 +31,7 @@ import (
 
 // deleteHost deletes all kubernetes resources related to replica *chop.ChiHost
 func (c *Controller) deleteHost(ctx context.Context, host *api.ChiHost) error {
-	log.V(1).M(host).S().Info(host.Runtime.Address.ClusterNameString())
+	log.Ctx(ctx).V(1).M(host).S().Info(host.Runtime.Address.ClusterNameString())
 
 	// Each host consists of:
 	_ = c.deleteStatefulSet(ctx, host)
@@ -39,7 +39,7 @@ func (c *Controller) deleteHost(ctx context.Context, host *api.ChiHost) error {
 	_ = c.deleteConfigMap(ctx, host)
 	_ = c.deleteServiceHost(ctx, host)
 
-	log.V(1).M(host).E().Info(host.Runtime.Address.ClusterNameString())
+	log.Ctx(ctx).V(1).M(host).E().Info(host.Runtime.Address.ClusterNameString())
 
 	return nil
 }
@@ -47,7 +47,7 @@ func (c *Controller) deleteHost(ctx context.Context, host *api.ChiHost) error {
 // deleteConfigMapsCHI
 func (c *Controller) deleteConfigMapsCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -66,22 +66,41 @@ func (c *Controller) deleteConfigMapsCHI(ctx context.Context, chi *api.ClickHous
 	err = c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommon, controller.NewDeleteOptions())
 	switch {
 	case err == nil:
-		log.V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommon)
+		log.Ctx(ctx).V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommon)
 	case apiErrors.IsNotFound(err):
-		log.V(1).M(chi).Info("NEUTRAL not found ConfigMap %s/%s", chi.Namespace, configMapCommon)
+		log.Ctx(ctx).V(1).M(chi).Info("NEUTRAL not found ConfigMap %s/%s", chi.Namespace, configMapCommon)
 	default:
-		log.V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, configMapCommon, err)
+		log.Ctx(ctx).V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, configMapCommon, err)
 	}
 
 	err = c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, configMapCommonUsersName, controller.NewDeleteOptions())
 	switch {
 	case err == nil:
-		log.V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommonUsersName)
+		log.Ctx(ctx).V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, configMapCommonUsersName)
 	case apiErrors.IsNotFound(err):
-		log.V(1).M(chi).Info("NEUTRAL not found ConfigMap %s/%s", chi.Namespace, configMapCommonUsersName)
+		log.Ctx(ctx).V(1).M(chi).Info("NEUTRAL not found ConfigMap %s/%s", chi.Namespace, configMapCommonUsersName)
 		err = nil
 	default:
-		log.V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, configMapCommonUsersName, err)
+		log.Ctx(ctx).V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, configMapCommonUsersName, err)
+	}
+
+	// Delete revision ConfigMaps
+	opts := controller.NewListOptions(model.NewLabeler(chi).GetSelectorConfigMapCHIRevision())
+	if list, e := c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).List(ctx, opts); e == nil {
+		for i := range list.Items {
+			name := list.Items[i].Name
+			e := c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).Delete(ctx, name, controller.NewDeleteOptions())
+			switch {
+			case e == nil:
+				log.Ctx(ctx).V(1).M(chi).Info("OK delete ConfigMap %s/%s", chi.Namespace, name)
+			case apiErrors.IsNotFound(e):
+				log.Ctx(ctx).V(1).M(chi).Info("NEUTRAL not found ConfigMap %s/%s", chi.Namespace, name)
+			default:
+				log.Ctx(ctx).V(1).M(chi).F().Error("FAIL delete ConfigMap %s/%s err:%v", chi.Namespace, name, e)
+			}
+		}
+	} else {
+		log.Ctx(ctx).V(1).M(chi).F().Error("FAIL list revision ConfigMaps of CHI %s/%s err:%v", chi.Namespace, chi.Name, e)
 	}
 
 	return err
@@ -90,20 +109,20 @@ func (c *Controller) deleteConfigMapsCHI(ctx context.Context, chi *api.ClickHous
 // statefulSetDeletePod delete a pod of a StatefulSet. This requests StatefulSet to relaunch deleted pod
 func (c *Controller) statefulSetDeletePod(ctx context.Context, statefulSet *apps.StatefulSet, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	name := model.CreatePodName(statefulSet)
-	log.V(1).M(host).Info("Delete Pod %s/%s", statefulSet.Namespace, name)
+	log.Ctx(ctx).V(1).M(host).Info("Delete Pod %s/%s", statefulSet.Namespace, name)
 	err := c.kubeClient.CoreV1().Pods(statefulSet.Namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
-		log.V(1).M(host).Info("OK delete Pod %s/%s", statefulSet.Namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("OK delete Pod %s/%s", statefulSet.Namespace, name)
 	} else if apiErrors.IsNotFound(err) {
-		log.V(1).M(host).Info("NEUTRAL not found Pod %s/%s", statefulSet.Namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("NEUTRAL not found Pod %s/%s", statefulSet.Namespace, name)
 		err = nil
 	} else {
-		log.V(1).M(host).F().Error("FAIL delete Pod %s/%s err:%v", statefulSet.Namespace, name, err)
+		log.Ctx(ctx).V(1).M(host).F().Error("FAIL delete Pod %s/%s err:%v", statefulSet.Namespace, name, err)
 	}
 
 	return err
@@ -112,7 +131,7 @@ func (c *Controller) statefulSetDeletePod(ctx context.Context, statefulSet *apps
 // deleteStatefulSet gracefully deletes StatefulSet through zeroing Pod's count
 func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -124,16 +143,16 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 	// Namespaced name
 	name := model.CreateStatefulSetName(host)
 	namespace := host.Runtime.Address.Namespace
-	log.V(1).M(host).F().Info("%s/%s", namespace, name)
+	log.Ctx(ctx).V(1).M(host).F().Info("%s/%s", namespace, name)
 
 	var err error
 	host.Runtime.CurStatefulSet, err = c.getStatefulSet(host)
 	if err != nil {
 		// Unable to fetch cur StatefulSet, but this is not necessarily an error yet
 		if apiErrors.IsNotFound(err) {
-			log.V(1).M(host).Info("NEUTRAL not found StatefulSet %s/%s", namespace, name)
+			log.Ctx(ctx).V(1).M(host).Info("NEUTRAL not found StatefulSet %s/%s", namespace, name)
 		} else {
-			log.V(1).M(host).F().Error("FAIL get StatefulSet %s/%s err:%v", namespace, name, err)
+			log.Ctx(ctx).V(1).M(host).F().Error("FAIL get StatefulSet %s/%s err:%v", namespace, name, err)
 		}
 		return err
 	}
@@ -143,7 +162,7 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 	var zero int32 = 0
 	host.Runtime.CurStatefulSet.Spec.Replicas = &zero
 	if _, err := c.kubeClient.AppsV1().StatefulSets(namespace).Update(ctx, host.Runtime.CurStatefulSet, controller.NewUpdateOptions()); err != nil {
-		log.V(1).M(host).Error("UNABLE to update StatefulSet %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(host).Error("UNABLE to update StatefulSet %s/%s", namespace, name)
 		return err
 	}
 
@@ -152,12 +171,12 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 
 	// And now delete empty StatefulSet
 	if err := c.kubeClient.AppsV1().StatefulSets(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
-		log.V(1).M(host).Info("OK delete StatefulSet %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("OK delete StatefulSet %s/%s", namespace, name)
 		c.waitHostDeleted(host)
 	} else if apiErrors.IsNotFound(err) {
-		log.V(1).M(host).Info("NEUTRAL not found StatefulSet %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("NEUTRAL not found StatefulSet %s/%s", namespace, name)
 	} else {
-		log.V(1).M(host).F().Error("FAIL delete StatefulSet %s/%s err: %v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(host).F().Error("FAIL delete StatefulSet %s/%s err: %v", namespace, name, err)
 	}
 
 	return nil
@@ -167,16 +186,16 @@ func (c *Controller) deleteStatefulSet(ctx context.Context, host *api.ChiHost) e
 func (c *Controller) syncStatefulSet(ctx context.Context, host *api.ChiHost) {
 	for {
 		if util.IsContextDone(ctx) {
-			log.V(2).Info("task is done")
+			log.Ctx(ctx).V(2).Info("task is done")
 			return
 		}
 		// TODO
 		// There should be better way to sync cache
 		if sts, err := c.getStatefulSetByHost(host); err == nil {
-			log.V(2).Info("cache NOT yet synced sts %s/%s is scheduled for deletion on %s", sts.Namespace, sts.Name, sts.DeletionTimestamp)
+			log.Ctx(ctx).V(2).Info("cache NOT yet synced sts %s/%s is scheduled for deletion on %s", sts.Namespace, sts.Name, sts.DeletionTimestamp)
 			util.WaitContextDoneOrTimeout(ctx, 15*time.Second)
 		} else {
-			log.V(1).Info("cache synced")
+			log.Ctx(ctx).V(1).Info("cache synced")
 			return
 		}
 	}
@@ -185,36 +204,36 @@ func (c *Controller) syncStatefulSet(ctx context.Context, host *api.ChiHost) {
 // deletePVC deletes PersistentVolumeClaim
 func (c *Controller) deletePVC(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(2).M(host).S().P()
-	defer log.V(2).M(host).E().P()
+	log.Ctx(ctx).V(2).M(host).S().P()
+	defer log.Ctx(ctx).V(2).M(host).E().P()
 
 	namespace := host.Runtime.Address.Namespace
 	c.walkDiscoveredPVCs(host, func(pvc *core.PersistentVolumeClaim) {
 		if util.IsContextDone(ctx) {
-			log.V(2).Info("task is done")
+			log.Ctx(ctx).V(2).Info("task is done")
 			return
 		}
 
 		// Check whether PVC can be deleted
 		if model.HostCanDeletePVC(host, pvc.Name) {
-			log.V(1).M(host).Info("PVC %s/%s would be deleted", namespace, pvc.Name)
+			log.Ctx(ctx).V(1).M(host).Info("PVC %s/%s would be deleted", namespace, pvc.Name)
 		} else {
-			log.V(1).M(host).Info("PVC %s/%s should not be deleted, leave it intact", namespace, pvc.Name)
+			log.Ctx(ctx).V(1).M(host).Info("PVC %s/%s should not be deleted, leave it intact", namespace, pvc.Name)
 			// Move to the next PVC
 			return
 		}
 
 		// Delete PVC
 		if err := c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvc.Name, controller.NewDeleteOptions()); err == nil {
-			log.V(1).M(host).Info("OK delete PVC %s/%s", namespace, pvc.Name)
+			log.Ctx(ctx).V(1).M(host).Info("OK delete PVC %s/%s", namespace, pvc.Name)
 		} else if apiErrors.IsNotFound(err) {
-			log.V(1).M(host).Info("NEUTRAL not found PVC %s/%s", namespace, pvc.Name)
+			log.Ctx(ctx).V(1).M(host).Info("NEUTRAL not found PVC %s/%s", namespace, pvc.Name)
 		} else {
-			log.M(host).F().Error("FAIL to delete PVC %s/%s err:%v", namespace, pvc.Name, err)
+			log.Ctx(ctx).M(host).F().Error("FAIL to delete PVC %s/%s err:%v", namespace, pvc.Name, err)
 		}
 	})
 
@@ -224,20 +243,20 @@ func (c *Controller) deletePVC(ctx context.Context, host *api.ChiHost) error {
 // deleteConfigMap deletes ConfigMap
 func (c *Controller) deleteConfigMap(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	name := model.CreateConfigMapHostName(host)
 	namespace := host.Runtime.Address.Namespace
-	log.V(1).M(host).F().Info("%s/%s", namespace, name)
+	log.Ctx(ctx).V(1).M(host).F().Info("%s/%s", namespace, name)
 
 	if err := c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, controller.NewDeleteOptions()); err == nil {
-		log.V(1).M(host).Info("OK delete ConfigMap %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("OK delete ConfigMap %s/%s", namespace, name)
 	} else if apiErrors.IsNotFound(err) {
-		log.V(1).M(host).Info("NEUTRAL not found ConfigMap %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(host).Info("NEUTRAL not found ConfigMap %s/%s", namespace, name)
 	} else {
-		log.V(1).M(host).F().Error("FAIL delete ConfigMap %s/%s err:%v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(host).F().Error("FAIL delete ConfigMap %s/%s err:%v", namespace, name, err)
 	}
 
 	//name = chopmodel.CreateConfigMapHostMigrationName(host)
@@ -258,59 +277,59 @@ func (c *Controller) deleteConfigMap(ctx context.Context, host *api.ChiHost) err
 // deleteServiceHost deletes Service
 func (c *Controller) deleteServiceHost(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	serviceName := model.CreateStatefulSetServiceName(host)
 	namespace := host.Runtime.Address.Namespace
-	log.V(1).M(host).F().Info("%s/%s", namespace, serviceName)
+	log.Ctx(ctx).V(1).M(host).F().Info("%s/%s", namespace, serviceName)
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
 // deleteServiceShard
 func (c *Controller) deleteServiceShard(ctx context.Context, shard *api.ChiShard) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	serviceName := model.CreateShardServiceName(shard)
 	namespace := shard.Runtime.Address.Namespace
-	log.V(1).M(shard).F().Info("%s/%s", namespace, serviceName)
+	log.Ctx(ctx).V(1).M(shard).F().Info("%s/%s", namespace, serviceName)
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
 // deleteServiceCluster
 func (c *Controller) deleteServiceCluster(ctx context.Context, cluster *api.Cluster) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	serviceName := model.CreateClusterServiceName(cluster)
 	namespace := cluster.Runtime.Address.Namespace
-	log.V(1).M(cluster).F().Info("%s/%s", namespace, serviceName)
+	log.Ctx(ctx).V(1).M(cluster).F().Info("%s/%s", namespace, serviceName)
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
 // deleteServiceCHI
 func (c *Controller) deleteServiceCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	serviceName := model.CreateCHIServiceName(chi)
 	namespace := chi.Namespace
-	log.V(1).M(chi).F().Info("%s/%s", namespace, serviceName)
+	log.Ctx(ctx).V(1).M(chi).F().Info("%s/%s", namespace, serviceName)
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
 // deleteServiceIfExists deletes Service in case it does not exist
 func (c *Controller) deleteServiceIfExists(ctx context.Context, namespace, name string) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -319,16 +338,16 @@ func (c *Controller) deleteServiceIfExists(ctx context.Context, namespace, name
 
 	if err != nil {
 		// No such a service, nothing to delete
-		log.V(1).M(namespace, name).F().Info("Not Found Service: %s/%s err: %v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Info("Not Found Service: %s/%s err: %v", namespace, name, err)
 		return nil
 	}
 
 	// Delete service
 	err = c.kubeClient.CoreV1().Services(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
-		log.V(1).M(namespace, name).F().Info("OK delete Service: %s/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Info("OK delete Service: %s/%s", namespace, name)
 	} else {
-		log.V(1).M(namespace, name).F().Error("FAIL delete Service: %s/%s err:%v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error("FAIL delete Service: %s/%s err:%v", namespace, name, err)
 	}
 
 	return err
@@ -337,20 +356,20 @@ func (c *Controller) deleteServiceIfExists(ctx context.Context, namespace, name
 // deleteSecretCluster
 func (c *Controller) deleteSecretCluster(ctx context.Context, cluster *api.Cluster) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	secretName := model.CreateClusterAutoSecretName(cluster)
 	namespace := cluster.Runtime.Address.Namespace
-	log.V(1).M(cluster).F().Info("%s/%s", namespace, secretName)
+	log.Ctx(ctx).V(1).M(cluster).F().Info("%s/%s", namespace, secretName)
 	return c.deleteSecretIfExists(ctx, namespace, secretName)
 }
 
 // deleteSecretIfExists deletes Secret in case it does not exist
 func (c *Controller) deleteSecretIfExists(ctx context.Context, namespace, name string) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -365,9 +384,9 @@ func (c *Controller) deleteSecretIfExists(ctx context.Context, namespace, name s
 	// Delete
 	err = c.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, name, controller.NewDeleteOptions())
 	if err == nil {
-		log.V(1).M(namespace, name).Info("OK delete Secret/%s", namespace, name)
+		log.Ctx(ctx).V(1).M(namespace, name).Info("OK delete Secret %s/%s", namespace, name)
 	} else {
-		log.V(1).M(namespace, name).F().Error("FAIL delete Secret %s/%s err:%v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error("FAIL delete Secret %s/%s err:%v", namespace, name, err)
 	}
 
 	return err
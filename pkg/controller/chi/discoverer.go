@@ -28,7 +28,7 @@ import (
 
 func (c *Controller) discovery(ctx context.Context, chi *api.ClickHouseInstallation) *model.Registry {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -48,11 +48,11 @@ func (c *Controller) discovery(ctx context.Context, chi *api.ClickHouseInstallat
 func (c *Controller) discoveryStatefulSets(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.AppsV1().StatefulSets(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list StatefulSet err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list StatefulSet err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list StatefulSet list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list StatefulSet list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -63,11 +63,11 @@ func (c *Controller) discoveryStatefulSets(ctx context.Context, r *model.Registr
 func (c *Controller) discoveryConfigMaps(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.CoreV1().ConfigMaps(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list ConfigMap err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list ConfigMap err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list ConfigMap list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list ConfigMap list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -78,11 +78,11 @@ func (c *Controller) discoveryConfigMaps(ctx context.Context, r *model.Registry,
 func (c *Controller) discoveryServices(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.CoreV1().Services(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list Service err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list Service err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list Service list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list Service list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -93,11 +93,11 @@ func (c *Controller) discoveryServices(ctx context.Context, r *model.Registry, c
 func (c *Controller) discoverySecrets(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.CoreV1().Secrets(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list Secret err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list Secret err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list Secret list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list Secret list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -108,11 +108,11 @@ func (c *Controller) discoverySecrets(ctx context.Context, r *model.Registry, ch
 func (c *Controller) discoveryPVCs(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.CoreV1().PersistentVolumeClaims(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list PVC err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list PVC err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list PVC list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list PVC list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -139,11 +139,11 @@ func (c *Controller) discoveryPVCs(ctx context.Context, r *model.Registry, chi *
 func (c *Controller) discoveryPDBs(ctx context.Context, r *model.Registry, chi *api.ClickHouseInstallation, opts meta.ListOptions) {
 	list, err := c.kubeClient.PolicyV1().PodDisruptionBudgets(chi.Namespace).List(ctx, opts)
 	if err != nil {
-		log.M(chi).F().Error("FAIL list PDB err: %v", err)
+		log.Ctx(ctx).M(chi).F().Error("FAIL list PDB err: %v", err)
 		return
 	}
 	if list == nil {
-		log.M(chi).F().Error("FAIL list PDB list is nil")
+		log.Ctx(ctx).M(chi).F().Error("FAIL list PDB list is nil")
 		return
 	}
 	for _, obj := range list.Items {
@@ -43,23 +43,48 @@ const (
 
 const (
 	// Short, machine understandable string that gives the reason for the transition into the object's current status
-	eventReasonReconcileStarted       = "ReconcileStarted"
-	eventReasonReconcileInProgress    = "ReconcileInProgress"
-	eventReasonReconcileCompleted     = "ReconcileCompleted"
-	eventReasonReconcileFailed        = "ReconcileFailed"
-	eventReasonCreateStarted          = "CreateStarted"
-	eventReasonCreateInProgress       = "CreateInProgress"
-	eventReasonCreateCompleted        = "CreateCompleted"
-	eventReasonCreateFailed           = "CreateFailed"
-	eventReasonUpdateStarted          = "UpdateStarted"
-	eventReasonUpdateInProgress       = "UpdateInProgress"
-	eventReasonUpdateCompleted        = "UpdateCompleted"
-	eventReasonUpdateFailed           = "UpdateFailed"
-	eventReasonDeleteStarted          = "DeleteStarted"
-	eventReasonDeleteInProgress       = "DeleteInProgress"
-	eventReasonDeleteCompleted        = "DeleteCompleted"
-	eventReasonDeleteFailed           = "DeleteFailed"
-	eventReasonProgressHostsCompleted = "ProgressHostsCompleted"
+	eventReasonReconcileStarted               = "ReconcileStarted"
+	eventReasonReconcileInProgress            = "ReconcileInProgress"
+	eventReasonReconcileCompleted             = "ReconcileCompleted"
+	eventReasonReconcileFailed                = "ReconcileFailed"
+	eventReasonReconcilePaused                = "ReconcilePaused"
+	eventReasonReconcileResumed               = "ReconcileResumed"
+	eventReasonCreateStarted                  = "CreateStarted"
+	eventReasonCreateInProgress               = "CreateInProgress"
+	eventReasonCreateCompleted                = "CreateCompleted"
+	eventReasonCreateFailed                   = "CreateFailed"
+	eventReasonUpdateStarted                  = "UpdateStarted"
+	eventReasonUpdateInProgress               = "UpdateInProgress"
+	eventReasonUpdateCompleted                = "UpdateCompleted"
+	eventReasonUpdateFailed                   = "UpdateFailed"
+	eventReasonDeleteStarted                  = "DeleteStarted"
+	eventReasonDeleteInProgress               = "DeleteInProgress"
+	eventReasonDeleteCompleted                = "DeleteCompleted"
+	eventReasonDeleteFailed                   = "DeleteFailed"
+	eventReasonProgressHostsCompleted         = "ProgressHostsCompleted"
+	eventReasonSchemaDriftDetected            = "SchemaDriftDetected"
+	eventReasonSchemaDriftResolved            = "SchemaDriftResolved"
+	eventReasonReplicaUnhealthy               = "ReplicaUnhealthy"
+	eventReasonPVCExpanded                    = "PVCExpanded"
+	eventReasonPVCExpansionFailed             = "PVCExpansionFailed"
+	eventReasonStorageClassMigrationStarted   = "StorageClassMigrationStarted"
+	eventReasonStorageClassMigrationCompleted = "StorageClassMigrationCompleted"
+	eventReasonStorageClassMigrationFailed    = "StorageClassMigrationFailed"
+	eventReasonHostChangesDeferred            = "HostChangesDeferred"
+	eventReasonHostDeferredChangesApplied     = "HostDeferredChangesApplied"
+	eventReasonRestartRequestStarted          = "RestartRequestStarted"
+	eventReasonRestartRequestCompleted        = "RestartRequestCompleted"
+	eventReasonRestartRequestFailed           = "RestartRequestFailed"
+	eventReasonHibernated                     = "Hibernated"
+	eventReasonWokenUp                        = "WokenUp"
+	eventReasonVirtualClustersUpdated         = "VirtualClustersUpdated"
+	eventReasonRollbackStarted                = "RollbackStarted"
+	eventReasonRollbackFailed                 = "RollbackFailed"
+	eventReasonHostRestartRequired            = "HostRestartRequired"
+	eventReasonHostConfigReloaded             = "HostConfigReloaded"
+	eventReasonHostConfigReloadFailed         = "HostConfigReloadFailed"
+	eventReasonSettingsValidationFailed       = "SettingsValidationFailed"
+	eventReasonSpecValidationFailed           = "SpecValidationFailed"
 )
 
 // EventInfo emits event Info
@@ -16,6 +16,7 @@ package chi
 
 import (
 	"fmt"
+	"sort"
 
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
@@ -175,6 +176,41 @@ func (c *Controller) getSecret(secret *core.Secret) (*core.Secret, error) {
 	return c.kubeClient.CoreV1().Secrets(secret.Namespace).Get(controller.NewContext(), secret.Name, controller.NewGetOptions())
 }
 
+// getOperatorCredentials gets generated operator credentials of the CHI from the Secret lister.
+// Lister may lag behind credentials just written, thus it is used for connections only,
+// which are served with previous credentials till rotation is completed
+func (c *Controller) getOperatorCredentials(chi *api.ClickHouseInstallation) (*model.OperatorCredentials, error) {
+	return model.GetOperatorCredentials(chi, func(namespace, name string) (*core.Secret, error) {
+		return c.secretLister.Secrets(namespace).Get(name)
+	})
+}
+
+// fetchOperatorCredentials gets generated operator credentials of the CHI from the API server.
+// Used in case credentials are rendered or updated, so they have to be up-to-date
+func (c *Controller) fetchOperatorCredentials(chi *api.ClickHouseInstallation) (*model.OperatorCredentials, error) {
+	return model.GetOperatorCredentials(chi, func(namespace, name string) (*core.Secret, error) {
+		return c.kubeClient.CoreV1().Secrets(namespace).Get(controller.NewContext(), name, controller.NewGetOptions())
+	})
+}
+
+// getUsers gets ClickHouseUser resources provisioned into users config of the CHI
+func (c *Controller) getUsers(chi *api.ClickHouseInstallation) (users []*api.ClickHouseUser) {
+	if chi == nil {
+		return nil
+	}
+	list, err := c.chuLister.ClickHouseUsers(chi.Namespace).List(k8sLabels.Everything())
+	if err != nil {
+		log.V(1).M(chi).F().Warning("unable to list ClickHouseUsers err: %v", err)
+		return nil
+	}
+	for _, user := range list {
+		if user.IsTargeting(chi) && !user.IsDeleting() && (user.GetMode() == api.ClickHouseUserModeConfig) {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
 // getPod gets pod. Accepted types:
 //  1. *apps.StatefulSet
 //  2. *chop.ChiHost
@@ -276,3 +312,98 @@ func (c *Controller) GetCHIByObjectMeta(objectMeta *meta.ObjectMeta, isCHI bool)
 
 	return c.chopClient.ClickhouseV1().ClickHouseInstallations(objectMeta.Namespace).Get(controller.NewContext(), chiName, controller.NewGetOptions())
 }
+
+// getVirtualClusterCHIs gets CHIs referenced by the virtual cluster either by name or by labels
+func (c *Controller) getVirtualClusterCHIs(ref *api.ChiVirtualClusterReference) (chis []*api.ClickHouseInstallation) {
+	if ref.CHI != "" {
+		chi, err := c.chiLister.ClickHouseInstallations(ref.Namespace).Get(ref.CHI)
+		if err != nil {
+			log.V(1).M(ref.Namespace, ref.CHI).F().Warning("unable to get CHI referenced by virtual cluster. err: %v", err)
+			return nil
+		}
+		return []*api.ClickHouseInstallation{chi}
+	}
+
+	if len(ref.Selector) == 0 {
+		// Neither name nor selector specified, nothing is referenced
+		return nil
+	}
+	chis, err := c.chiLister.ClickHouseInstallations(ref.Namespace).List(k8sLabels.SelectorFromSet(ref.Selector))
+	if err != nil {
+		log.V(1).M(ref.Namespace, "").F().Warning("unable to list CHIs referenced by virtual cluster. err: %v", err)
+		return nil
+	}
+	// Keep order of the CHIs stable, thus layout of the virtual cluster does not flap
+	sort.Slice(chis, func(i, j int) bool {
+		return chis[i].Name < chis[j].Name
+	})
+	return chis
+}
+
+// getVirtualClusterLayout gets layout of the virtual cluster out of the referenced CHIs.
+// Referenced CHIs contribute hosts, which have been reconciled already
+func (c *Controller) getVirtualClusterLayout(virtualCluster *api.ChiVirtualCluster) (layout api.ChiVirtualClusterRuntime) {
+	for i := range virtualCluster.Clusters {
+		ref := &virtualCluster.Clusters[i]
+		for _, chi := range c.getVirtualClusterCHIs(ref) {
+			completed := chi.Status.GetNormalizedCHICompleted()
+			if completed == nil {
+				// CHI has not been reconciled yet
+				continue
+			}
+			// CHI comes from the informer cache, thus it has to be copied before runtime fields are filled
+			completed = completed.DeepCopy()
+			completed.FillSelfCalculatedAddressInfo()
+			completed.FillCHIPointer()
+			completed.WalkClusters(func(cluster *api.Cluster) error {
+				if (ref.Cluster != "") && (ref.Cluster != cluster.Name) {
+					return nil
+				}
+				cluster.WalkShards(func(index int, shard *api.ChiShard) error {
+					virtualShard := api.ChiVirtualClusterShard{
+						InternalReplication: shard.InternalReplication,
+					}
+					if shard.HasWeight() {
+						weight := shard.GetWeight()
+						virtualShard.Weight = &weight
+					}
+					shard.WalkAllHosts(func(host *api.ChiHost) error {
+						replica := api.ChiVirtualClusterReplica{
+							Host:   model.CreateFQDN(host),
+							Port:   host.TCPPort,
+							Secure: host.IsSecure(),
+						}
+						if host.IsExternal() {
+							replica.Host = host.External.GetHostname()
+						}
+						if replica.Secure {
+							replica.Port = host.TLSPort
+						}
+						if replica.Host != "" {
+							virtualShard.Replicas = append(virtualShard.Replicas, replica)
+						}
+						return nil
+					})
+					if len(virtualShard.Replicas) > 0 {
+						layout.Shards = append(layout.Shards, virtualShard)
+					}
+					return nil
+				})
+				return nil
+			})
+		}
+	}
+	return layout
+}
+
+// getVirtualClustersStatus gets resolved layouts of the virtual clusters to be recorded in CHI status
+func (c *Controller) getVirtualClustersStatus(virtualClusters []*api.ChiVirtualCluster) (res []api.ChiVirtualClusterStatus) {
+	for _, virtualCluster := range virtualClusters {
+		layout := c.getVirtualClusterLayout(virtualCluster)
+		res = append(res, api.ChiVirtualClusterStatus{
+			Name:  virtualCluster.Name,
+			Hosts: layout.GetHosts(),
+		})
+	}
+	return res
+}
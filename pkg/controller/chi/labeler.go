@@ -66,7 +66,7 @@ func (c *Controller) labelMyObjectsTree(ctx context.Context) error {
 	//    uid: a275a8a0-83ae-11e9-b92d-0208b778ea1a
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -76,11 +76,11 @@ func (c *Controller) labelMyObjectsTree(ctx context.Context) error {
 
 	if !ok1 || !ok2 {
 		str := fmt.Sprintf("ERROR read env vars: %s/%s ", deployment.OPERATOR_POD_NAME, deployment.OPERATOR_POD_NAMESPACE)
-		log.V(1).M(namespace, name).F().Error(str)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error(str)
 		return errors.New(str)
 	}
 
-	log.V(1).Info("OPERATOR_POD_NAMESPACE=%s OPERATOR_POD_NAME=%s", namespace, name)
+	log.Ctx(ctx).V(1).Info("OPERATOR_POD_NAMESPACE=%s OPERATOR_POD_NAME=%s", namespace, name)
 	if len(namespace) == 0 || len(name) == 0 {
 		return ErrOperatorPodNotSpecified
 	}
@@ -115,12 +115,12 @@ func (c *Controller) labelMyObjectsTree(ctx context.Context) error {
 func (c *Controller) labelPod(ctx context.Context, namespace, name string) (*core.Pod, error) {
 	pod, err := c.kubeClient.CoreV1().Pods(namespace).Get(ctx, name, controller.NewGetOptions())
 	if err != nil {
-		log.V(1).M(namespace, name).F().Error("ERROR get Pod %s/%s %v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error("ERROR get Pod %s/%s %v", namespace, name, err)
 		return nil, err
 	}
 	if pod == nil {
 		str := fmt.Sprintf("ERROR get Pod is nil %s/%s ", namespace, name)
-		log.V(1).M(namespace, name).F().Error(str)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error(str)
 		return nil, errors.New(str)
 	}
 
@@ -128,12 +128,12 @@ func (c *Controller) labelPod(ctx context.Context, namespace, name string) (*cor
 	pod.Labels = c.addLabels(pod.Labels)
 	pod, err = c.kubeClient.CoreV1().Pods(namespace).Update(ctx, pod, controller.NewUpdateOptions())
 	if err != nil {
-		log.V(1).M(namespace, name).F().Error("ERROR put label on Pod %s/%s %v", namespace, name, err)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error("ERROR put label on Pod %s/%s %v", namespace, name, err)
 		return nil, err
 	}
 	if pod == nil {
 		str := fmt.Sprintf("ERROR update Pod is nil %s/%s ", namespace, name)
-		log.V(1).M(namespace, name).F().Error(str)
+		log.Ctx(ctx).V(1).M(namespace, name).F().Error(str)
 		return nil, errors.New(str)
 	}
 
@@ -155,19 +155,19 @@ func (c *Controller) labelReplicaSet(ctx context.Context, pod *core.Pod) (*apps.
 	if replicaSetName == "" {
 		// ReplicaSet not found
 		str := fmt.Sprintf("ERROR ReplicaSet for Pod %s/%s not found", pod.Namespace, pod.Name)
-		log.V(1).M(pod.Namespace, pod.Name).F().Error(str)
+		log.Ctx(ctx).V(1).M(pod.Namespace, pod.Name).F().Error(str)
 		return nil, errors.New(str)
 	}
 
 	// ReplicaSet namespaced name found, fetch the ReplicaSet
 	replicaSet, err := c.kubeClient.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, replicaSetName, controller.NewGetOptions())
 	if err != nil {
-		log.V(1).M(pod.Namespace, replicaSetName).F().Error("ERROR get ReplicaSet %s/%s %v", pod.Namespace, replicaSetName, err)
+		log.Ctx(ctx).V(1).M(pod.Namespace, replicaSetName).F().Error("ERROR get ReplicaSet %s/%s %v", pod.Namespace, replicaSetName, err)
 		return nil, err
 	}
 	if replicaSet == nil {
 		str := fmt.Sprintf("ERROR get ReplicaSet is nil %s/%s ", pod.Namespace, replicaSetName)
-		log.V(1).M(pod.Namespace, replicaSetName).F().Error(str)
+		log.Ctx(ctx).V(1).M(pod.Namespace, replicaSetName).F().Error(str)
 		return nil, errors.New(str)
 	}
 
@@ -175,12 +175,12 @@ func (c *Controller) labelReplicaSet(ctx context.Context, pod *core.Pod) (*apps.
 	replicaSet.Labels = c.addLabels(replicaSet.Labels)
 	replicaSet, err = c.kubeClient.AppsV1().ReplicaSets(pod.Namespace).Update(ctx, replicaSet, controller.NewUpdateOptions())
 	if err != nil {
-		log.V(1).M(pod.Namespace, replicaSetName).F().Error("ERROR put label on ReplicaSet %s/%s %v", pod.Namespace, replicaSetName, err)
+		log.Ctx(ctx).V(1).M(pod.Namespace, replicaSetName).F().Error("ERROR put label on ReplicaSet %s/%s %v", pod.Namespace, replicaSetName, err)
 		return nil, err
 	}
 	if replicaSet == nil {
 		str := fmt.Sprintf("ERROR update ReplicaSet is nil %s/%s ", pod.Namespace, replicaSetName)
-		log.V(1).M(pod.Namespace, replicaSetName).F().Error(str)
+		log.Ctx(ctx).V(1).M(pod.Namespace, replicaSetName).F().Error(str)
 		return nil, errors.New(str)
 	}
 
@@ -202,19 +202,19 @@ func (c *Controller) labelDeployment(ctx context.Context, rs *apps.ReplicaSet) e
 	if deploymentName == "" {
 		// Deployment not found
 		str := fmt.Sprintf("ERROR find Deployment for ReplicaSet %s/%s not found", rs.Namespace, rs.Name)
-		log.V(1).M(rs.Namespace, rs.Name).F().Error(str)
+		log.Ctx(ctx).V(1).M(rs.Namespace, rs.Name).F().Error(str)
 		return errors.New(str)
 	}
 
 	// Deployment namespaced name found, fetch the Deployment
 	deployment, err := c.kubeClient.AppsV1().Deployments(rs.Namespace).Get(ctx, deploymentName, controller.NewGetOptions())
 	if err != nil {
-		log.V(1).M(rs.Namespace, deploymentName).F().Error("ERROR get Deployment %s/%s", rs.Namespace, deploymentName)
+		log.Ctx(ctx).V(1).M(rs.Namespace, deploymentName).F().Error("ERROR get Deployment %s/%s", rs.Namespace, deploymentName)
 		return err
 	}
 	if deployment == nil {
 		str := fmt.Sprintf("ERROR get Deployment is nil %s/%s ", rs.Namespace, deploymentName)
-		log.V(1).M(rs.Namespace, deploymentName).F().Error(str)
+		log.Ctx(ctx).V(1).M(rs.Namespace, deploymentName).F().Error(str)
 		return errors.New(str)
 	}
 
@@ -222,12 +222,12 @@ func (c *Controller) labelDeployment(ctx context.Context, rs *apps.ReplicaSet) e
 	deployment.Labels = c.addLabels(deployment.Labels)
 	deployment, err = c.kubeClient.AppsV1().Deployments(rs.Namespace).Update(ctx, deployment, controller.NewUpdateOptions())
 	if err != nil {
-		log.V(1).M(rs.Namespace, deploymentName).F().Error("ERROR put label on Deployment %s/%s %v", rs.Namespace, deploymentName, err)
+		log.Ctx(ctx).V(1).M(rs.Namespace, deploymentName).F().Error("ERROR put label on Deployment %s/%s %v", rs.Namespace, deploymentName, err)
 		return err
 	}
 	if deployment == nil {
 		str := fmt.Sprintf("ERROR update Deployment is nil %s/%s ", rs.Namespace, deploymentName)
-		log.V(1).M(rs.Namespace, deploymentName).F().Error(str)
+		log.Ctx(ctx).V(1).M(rs.Namespace, deploymentName).F().Error(str)
 		return errors.New(str)
 	}
 
@@ -251,13 +251,13 @@ func (c *Controller) addLabels(labels map[string]string) map[string]string {
 // appendLabelReadyOnPod appends Label "Ready" to the pod of the specified host
 func (c *Controller) appendLabelReadyOnPod(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	pod, err := c.getPod(host)
 	if err != nil {
-		log.M(host).F().Error("FAIL get pod for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
+		log.Ctx(ctx).M(host).F().Error("FAIL get pod for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 		return err
 	}
 
@@ -265,7 +265,7 @@ func (c *Controller) appendLabelReadyOnPod(ctx context.Context, host *api.ChiHos
 		// Modified, need to update
 		_, err = c.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, pod, controller.NewUpdateOptions())
 		if err != nil {
-			log.M(host).F().Error("FAIL setting 'ready' label for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
+			log.Ctx(ctx).M(host).F().Error("FAIL setting 'ready' label for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 			return err
 		}
 	}
@@ -276,7 +276,7 @@ func (c *Controller) appendLabelReadyOnPod(ctx context.Context, host *api.ChiHos
 // deleteLabelReadyPod deletes Label "Ready" from the pod of the specified host
 func (c *Controller) deleteLabelReadyPod(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -291,7 +291,7 @@ func (c *Controller) deleteLabelReadyPod(ctx context.Context, host *api.ChiHost)
 	}
 
 	if err != nil {
-		log.V(1).M(host).F().Info("FAIL get pod for host '%s' err: %v", host.Runtime.Address.NamespaceNameString(), err)
+		log.Ctx(ctx).V(1).M(host).F().Info("FAIL get pod for host '%s' err: %v", host.Runtime.Address.NamespaceNameString(), err)
 		return err
 	}
 
@@ -307,13 +307,13 @@ func (c *Controller) deleteLabelReadyPod(ctx context.Context, host *api.ChiHost)
 // appendAnnotationReadyOnService appends Annotation "Ready" to the service of the specified host
 func (c *Controller) appendAnnotationReadyOnService(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	svc, err := c.getService(host)
 	if err != nil {
-		log.M(host).F().Error("FAIL get service for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
+		log.Ctx(ctx).M(host).F().Error("FAIL get service for host %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 		return err
 	}
 
@@ -321,7 +321,7 @@ func (c *Controller) appendAnnotationReadyOnService(ctx context.Context, host *a
 		// Modified, need to update
 		_, err = c.kubeClient.CoreV1().Services(svc.Namespace).Update(ctx, svc, controller.NewUpdateOptions())
 		if err != nil {
-			log.M(host).F().Error("FAIL setting 'ready' annotation for host service %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
+			log.Ctx(ctx).M(host).F().Error("FAIL setting 'ready' annotation for host service %s err:%v", host.Runtime.Address.NamespaceNameString(), err)
 			return err
 		}
 	}
@@ -332,7 +332,7 @@ func (c *Controller) appendAnnotationReadyOnService(ctx context.Context, host *a
 // deleteAnnotationReadyService deletes Annotation "Ready" from the service of the specified host
 func (c *Controller) deleteAnnotationReadyService(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -347,7 +347,7 @@ func (c *Controller) deleteAnnotationReadyService(ctx context.Context, host *api
 		return nil
 	}
 	if err != nil {
-		log.V(1).M(host).F().Info("FAIL get service for host '%s' err: %v", host.Runtime.Address.NamespaceNameString(), err)
+		log.Ctx(ctx).V(1).M(host).F().Info("FAIL get service for host '%s' err: %v", host.Runtime.Address.NamespaceNameString(), err)
 		return err
 	}
 
@@ -16,6 +16,7 @@ package chi
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -52,10 +53,27 @@ type Metrics struct {
 	PodAddEvents    metric.Int64Counter
 	PodUpdateEvents metric.Int64Counter
 	PodDeleteEvents metric.Int64Counter
+
+	// SchemaDriftTables is a number (gauge) of missing and divergent tables per cluster, found by the latest schema drift check
+	SchemaDriftTables metric.Int64ObservableGauge
 }
 
 var m *Metrics
 
+// schemaDriftObservation specifies value of the schema drift gauge
+type schemaDriftObservation struct {
+	attributes []attribute.KeyValue
+	value      int64
+}
+
+// schemaDriftObservations keeps the latest schema drift check results, mapped by CHI namespace/name
+var schemaDriftObservations = struct {
+	sync.Mutex
+	chis map[string][]schemaDriftObservation
+}{
+	chis: make(map[string][]schemaDriftObservation),
+}
+
 func createMetrics() *Metrics {
 	// The unit u should be defined using the appropriate [UCUM](https://ucum.org) case-sensitive code.
 	CHIReconcilesStarted, _ := metrics.Meter().Int64Counter(
@@ -121,6 +139,13 @@ func createMetrics() *Metrics {
 		metric.WithUnit("items"),
 	)
 
+	SchemaDriftTables, _ := metrics.Meter().Int64ObservableGauge(
+		"clickhouse_operator_chi_schema_drift_tables",
+		metric.WithDescription("number of missing and divergent tables found by the latest schema drift check"),
+		metric.WithUnit("items"),
+		metric.WithInt64Callback(observeSchemaDrift),
+	)
+
 	return &Metrics{
 		CHIReconcilesStarted:   CHIReconcilesStarted,
 		CHIReconcilesCompleted: CHIReconcilesCompleted,
@@ -136,6 +161,8 @@ func createMetrics() *Metrics {
 		PodAddEvents:    PodAddEvents,
 		PodUpdateEvents: PodUpdateEvents,
 		PodDeleteEvents: PodDeleteEvents,
+
+		SchemaDriftTables: SchemaDriftTables,
 	}
 }
 
@@ -195,3 +222,37 @@ func metricsPodUpdate(ctx context.Context) {
 func metricsPodDelete(ctx context.Context) {
 	ensureMetrics().PodDeleteEvents.Add(ctx, 1)
 }
+
+// metricsSchemaDrift records number of drifted tables per cluster of the CHI
+func metricsSchemaDrift(chi *api.ClickHouseInstallation, tables map[string]int) {
+	ensureMetrics()
+	var observations []schemaDriftObservation
+	for cluster, count := range tables {
+		observations = append(observations, schemaDriftObservation{
+			attributes: append(prepareLabels(chi), attribute.String("cluster", cluster)),
+			value:      int64(count),
+		})
+	}
+	schemaDriftObservations.Lock()
+	defer schemaDriftObservations.Unlock()
+	schemaDriftObservations.chis[chi.Namespace+"/"+chi.Name] = observations
+}
+
+// metricsSchemaDriftForget drops schema drift records of the CHI
+func metricsSchemaDriftForget(chi *api.ClickHouseInstallation) {
+	schemaDriftObservations.Lock()
+	defer schemaDriftObservations.Unlock()
+	delete(schemaDriftObservations.chis, chi.Namespace+"/"+chi.Name)
+}
+
+// observeSchemaDrift reports the latest schema drift check results
+func observeSchemaDrift(_ context.Context, observer metric.Int64Observer) error {
+	schemaDriftObservations.Lock()
+	defer schemaDriftObservations.Unlock()
+	for _, observations := range schemaDriftObservations.chis {
+		for _, observation := range observations {
+			observer.Observe(observation.value, metric.WithAttributes(observation.attributes...))
+		}
+	}
+	return nil
+}
@@ -119,7 +119,7 @@ func (c *Controller) pollHost(
 	isDoneFn func(ctx context.Context, host *api.ChiHost) bool,
 ) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -147,12 +147,17 @@ func (c *Controller) pollHostStatefulSet(
 	opts *controller.PollerOptions,
 	isDoneFn func(context.Context, *apps.StatefulSet) bool,
 	backFn func(context.Context),
-) error {
+) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	ctx, span := startSpanHost(ctx, spanPollHostStatefulSet, host)
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	if opts == nil {
 		opts = controller.NewPollerOptions().FromConfig(chop.Config())
 	}
@@ -160,7 +165,7 @@ func (c *Controller) pollHostStatefulSet(
 	namespace := host.Runtime.Address.Namespace
 	name := host.Runtime.Address.StatefulSet
 
-	return controller.Poll(
+	err = controller.Poll(
 		ctx,
 		namespace, name,
 		opts,
@@ -179,4 +184,5 @@ func (c *Controller) pollHostStatefulSet(
 			F: backFn,
 		},
 	)
+	return err
 }
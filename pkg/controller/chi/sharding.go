@@ -0,0 +1,623 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	coordination "k8s.io/api/coordination/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+	kube "k8s.io/client-go/kubernetes"
+
+	"github.com/altinity/queue"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// labelShardingGroup specifies label Leases of the operator instances of the same group are marked with
+const labelShardingGroup = clickhouse_altinity_com.APIGroupName + "/" + "operator-sharding-group"
+
+// annotationShardingCHI specifies annotation per-CHI Leases are marked with, in order to tell CHI the Lease relates to
+const annotationShardingCHI = clickhouse_altinity_com.APIGroupName + "/" + "operator-sharding-chi"
+
+// shardMembership tracks operator instances CHIs are partitioned among.
+// Each instance maintains own Lease, instances with Leases renewed recently are alive members.
+// CHI is owned by the member, selected by rendezvous (highest random weight) hashing of CHI namespace/name,
+// thus only CHIs of the joined or left member are moved between members on rebalance.
+// Members may see different lists of members for a while and the previous owner may be in the middle of reconcile,
+// thus items of the CHI are processed under per-CHI Lease, which is held as long as the instance owns the CHI
+// and is renewed in background. Lease of the CHI, which is not owned anymore, is released as soon as items
+// of the CHI being processed are completed. New owner waits for the previous one to release the Lease or for the Lease to expire
+type shardMembership struct {
+	kubeClient kube.Interface
+
+	group         string
+	namespace     string
+	identity      string
+	leaseDuration time.Duration
+	renewPeriod   time.Duration
+
+	// onChange is called as soon as list of alive members has changed
+	onChange func(prev, cur []string)
+	// exists checks whether CHI exists, thus Leases of deleted CHIs are released
+	exists func(namespace, name string) bool
+
+	mutex sync.RWMutex
+	// members specifies sorted list of alive members. Nil till the first sync
+	members []string
+
+	// chiMutex guards chiLeases map only, Leases themselves are guarded by per-CHI locks
+	chiMutex sync.Mutex
+	// chiLeases specifies per-CHI Leases of the CHIs, which items have been processed by the instance
+	chiLeases map[string]*chiLease
+}
+
+// chiLease specifies per-CHI Lease. API calls on the Lease are made under the lock of the Lease only,
+// thus items of different CHIs do not wait for each other
+type chiLease struct {
+	sync.Mutex
+	// lease specifies the Lease as it has been written by the instance the last time. Nil in case the Lease is not held
+	lease *coordination.Lease
+	// processing specifies number of items of the CHI being processed
+	processing int
+	// removed specifies whether the entry has been removed from the map, thus has to be looked up again
+	removed bool
+}
+
+// chiLeasesSyncConcurrency specifies number of per-CHI Leases renewed concurrently
+const chiLeasesSyncConcurrency = 16
+
+// newShardMembership creates new shardMembership
+func newShardMembership(
+	kubeClient kube.Interface,
+	onChange func(prev, cur []string),
+	exists func(namespace, name string) bool,
+) *shardMembership {
+	config := chop.Config().Sharding
+	return &shardMembership{
+		kubeClient:    kubeClient,
+		group:         config.Group,
+		namespace:     config.Namespace,
+		identity:      config.Identity,
+		leaseDuration: config.LeaseDuration,
+		renewPeriod:   config.RenewPeriod,
+		onChange:      onChange,
+		exists:        exists,
+		chiLeases:     make(map[string]*chiLease),
+	}
+}
+
+// start joins the group. Returns as soon as list of members is known or context is done
+func (m *shardMembership) start(ctx context.Context) {
+	log.Ctx(ctx).V(1).F().Info("Joining operator sharding group: %s/%s as: %s", m.namespace, m.group, m.identity)
+	for {
+		err := m.sync(ctx)
+		if err == nil {
+			return
+		}
+		log.Ctx(ctx).V(1).F().Error("Unable to join operator sharding group, will retry. Err: %v", err)
+		if util.WaitContextDoneOrTimeout(ctx, m.renewPeriod) {
+			return
+		}
+	}
+}
+
+// run keeps own Lease renewed and list of members up to date till context is done.
+// Own Lease is deleted on exit, thus the rest of members take over CHIs without waiting for the Lease to expire
+func (m *shardMembership) run(ctx context.Context) {
+	for !util.WaitContextDoneOrTimeout(ctx, m.renewPeriod) {
+		if err := m.sync(ctx); err != nil {
+			log.Ctx(ctx).V(1).F().Error("Unable to sync operator sharding group. Err: %v", err)
+		}
+		m.syncCHILeases(ctx)
+	}
+	m.leave()
+}
+
+// sync renews own Lease and refreshes list of alive members
+func (m *shardMembership) sync(ctx context.Context) error {
+	if err := m.renew(ctx); err != nil {
+		return err
+	}
+
+	leases, err := m.kubeClient.CoordinationV1().Leases(m.namespace).List(ctx, controller.NewListOptions(map[string]string{
+		labelShardingGroup: m.group,
+	}))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	members := []string{m.identity}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if (lease.Spec.HolderIdentity == nil) || (*lease.Spec.HolderIdentity == m.identity) {
+			continue
+		}
+		if isLeaseExpired(lease, now) {
+			// Member has gone without leaving the group
+			continue
+		}
+		members = append(members, *lease.Spec.HolderIdentity)
+	}
+	sort.Strings(members)
+
+	m.mutex.Lock()
+	prev := m.members
+	m.members = members
+	m.mutex.Unlock()
+
+	if slices.Equal(prev, members) {
+		return nil
+	}
+	log.Ctx(ctx).V(1).F().Info("Operator sharding group members: %v", members)
+	if (prev != nil) && (m.onChange != nil) {
+		m.onChange(prev, members)
+	}
+	return nil
+}
+
+// renew creates or renews own Lease
+func (m *shardMembership) renew(ctx context.Context) error {
+	leases := m.kubeClient.CoordinationV1().Leases(m.namespace)
+	now := meta.NewMicroTime(time.Now())
+	duration := int32(m.leaseDuration.Seconds())
+
+	lease, err := leases.Get(ctx, m.getLeaseName(), controller.NewGetOptions())
+	if apiErrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordination.Lease{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      m.getLeaseName(),
+				Namespace: m.namespace,
+				Labels: map[string]string{
+					labelShardingGroup: m.group,
+				},
+			},
+			Spec: coordination.LeaseSpec{
+				HolderIdentity:       &m.identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, controller.NewCreateOptions())
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = &m.identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, controller.NewUpdateOptions())
+	return err
+}
+
+// leave deletes own Lease along with per-CHI Leases of CHIs, which items are not being processed
+func (m *shardMembership) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.renewPeriod)
+	defer cancel()
+	m.walkCHILeases(func(key string, entry *chiLease) {
+		if entry.processing == 0 {
+			m.releaseCHILease(ctx, key, entry)
+		}
+	})
+	err := m.kubeClient.CoordinationV1().Leases(m.namespace).Delete(ctx, m.getLeaseName(), controller.NewDeleteOptions())
+	if err != nil && !apiErrors.IsNotFound(err) {
+		log.V(1).F().Error("Unable to leave operator sharding group. Err: %v", err)
+		return
+	}
+	log.V(1).F().Info("Left operator sharding group: %s/%s", m.namespace, m.group)
+}
+
+// getLeaseName gets name of own Lease
+func (m *shardMembership) getLeaseName() string {
+	return m.group + "-" + m.identity
+}
+
+// isLeaseExpired checks whether Lease has not been renewed in time
+func isLeaseExpired(lease *coordination.Lease, now time.Time) bool {
+	if (lease.Spec.RenewTime == nil) || (lease.Spec.LeaseDurationSeconds == nil) {
+		return true
+	}
+	expires := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return expires.Before(now)
+}
+
+// getCHILeaseName gets name of the per-CHI Lease
+func (m *shardMembership) getCHILeaseName(key string) string {
+	return m.group + "-chi-" + util.CreateStringID(key, 16)
+}
+
+// lockCHILease gets locked per-CHI Lease entry, creating the entry in case there is none
+func (m *shardMembership) lockCHILease(key string) *chiLease {
+	for {
+		m.chiMutex.Lock()
+		entry, ok := m.chiLeases[key]
+		if !ok {
+			entry = &chiLease{}
+			m.chiLeases[key] = entry
+		}
+		m.chiMutex.Unlock()
+
+		entry.Lock()
+		if !entry.removed {
+			return entry
+		}
+		// Entry has been removed meanwhile
+		entry.Unlock()
+	}
+}
+
+// removeCHILease removes locked per-CHI Lease entry from the map
+func (m *shardMembership) removeCHILease(key string, entry *chiLease) {
+	m.chiMutex.Lock()
+	delete(m.chiLeases, key)
+	m.chiMutex.Unlock()
+	entry.removed = true
+}
+
+// walkCHILeases calls function on each locked per-CHI Lease entry concurrently
+func (m *shardMembership) walkCHILeases(f func(key string, entry *chiLease)) {
+	m.chiMutex.Lock()
+	keys := make([]string, 0, len(m.chiLeases))
+	for key := range m.chiLeases {
+		keys = append(keys, key)
+	}
+	m.chiMutex.Unlock()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, chiLeasesSyncConcurrency)
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			entry := m.lockCHILease(key)
+			defer entry.Unlock()
+			f(key, entry)
+		}(key)
+	}
+	wg.Wait()
+}
+
+// acquireCHI acquires per-CHI Lease before the item of the CHI is processed. Lease, which is held already, is reused as-is.
+// Returns false in case the Lease is held by another member, which has not completed items of the CHI yet
+func (m *shardMembership) acquireCHI(ctx context.Context, namespace, name string) bool {
+	key := namespace + "/" + name
+	entry := m.lockCHILease(key)
+	defer entry.Unlock()
+
+	if (entry.lease == nil) || isLeaseExpired(entry.lease, time.Now()) {
+		lease, err := m.acquireCHILease(ctx, key)
+		if err != nil {
+			log.Ctx(ctx).V(1).F().Info("Unable to acquire lease of CHI: %s Err: %v", key, err)
+			return false
+		}
+		entry.lease = lease
+	}
+	entry.processing++
+	return true
+}
+
+// releaseCHI is called as soon as the item of the CHI is processed.
+// Per-CHI Lease is kept as long as the CHI is owned, otherwise it is released as soon as no items of the CHI are being processed
+func (m *shardMembership) releaseCHI(namespace, name string) {
+	key := namespace + "/" + name
+	entry := m.lockCHILease(key)
+	defer entry.Unlock()
+
+	if entry.processing > 0 {
+		entry.processing--
+	}
+	if (entry.processing == 0) && !m.owns(namespace, name) {
+		ctx, cancel := context.WithTimeout(context.Background(), m.renewPeriod)
+		defer cancel()
+		m.releaseCHILease(ctx, key, entry)
+	}
+}
+
+// syncCHILeases renews per-CHI Leases of owned CHIs and releases Leases of CHIs, which are not owned anymore
+// and which items are not being processed. Leases are not renewed on exit, thus in case processing is interrupted,
+// Leases expire on their own
+func (m *shardMembership) syncCHILeases(ctx context.Context) {
+	m.walkCHILeases(func(key string, entry *chiLease) {
+		namespace, name, _ := strings.Cut(key, "/")
+		switch {
+		case entry.processing > 0:
+			// Lease is renewed regardless of ownership, since it protects items being processed
+		case !m.owns(namespace, name) || ((m.exists != nil) && !m.exists(namespace, name)):
+			m.releaseCHILease(ctx, key, entry)
+			return
+		case entry.lease == nil:
+			// Nothing to keep, Lease is acquired as soon as an item of the CHI is processed
+			m.removeCHILease(key, entry)
+			return
+		}
+		if entry.lease == nil {
+			return
+		}
+		lease, err := m.renewCHILease(ctx, entry.lease)
+		if err != nil {
+			// Lease may have been updated meanwhile, thus it is acquired from scratch
+			lease, err = m.acquireCHILease(ctx, key)
+		}
+		if err != nil {
+			log.Ctx(ctx).V(1).F().Error("Unable to renew lease of CHI: %s Err: %v", key, err)
+			entry.lease = nil
+			return
+		}
+		entry.lease = lease
+	})
+}
+
+// acquireCHILease creates, takes over or renews per-CHI Lease.
+// Lease held by another member is taken over only in case it has expired
+func (m *shardMembership) acquireCHILease(ctx context.Context, key string) (*coordination.Lease, error) {
+	leases := m.kubeClient.CoordinationV1().Leases(m.namespace)
+	now := meta.NewMicroTime(time.Now())
+	duration := int32(m.leaseDuration.Seconds())
+
+	lease, err := leases.Get(ctx, m.getCHILeaseName(key), controller.NewGetOptions())
+	if apiErrors.IsNotFound(err) {
+		return leases.Create(ctx, &coordination.Lease{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      m.getCHILeaseName(key),
+				Namespace: m.namespace,
+				Annotations: map[string]string{
+					annotationShardingCHI: key,
+				},
+			},
+			Spec: coordination.LeaseSpec{
+				HolderIdentity:       &m.identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, controller.NewCreateOptions())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holder := lease.Spec.HolderIdentity
+	if (holder != nil) && (*holder != m.identity) {
+		if !isLeaseExpired(lease, now.Time) {
+			return nil, fmt.Errorf("held by: %s", *holder)
+		}
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.HolderIdentity = &m.identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+	// Update fails on conflict, in case another member has updated the Lease meanwhile
+	return leases.Update(ctx, lease, controller.NewUpdateOptions())
+}
+
+// renewCHILease renews per-CHI Lease held by the instance. Fails on conflict, in case the Lease has been updated meanwhile
+func (m *shardMembership) renewCHILease(ctx context.Context, lease *coordination.Lease) (*coordination.Lease, error) {
+	now := meta.NewMicroTime(time.Now())
+	lease = lease.DeepCopy()
+	lease.Spec.RenewTime = &now
+	return m.kubeClient.CoordinationV1().Leases(m.namespace).Update(ctx, lease, controller.NewUpdateOptions())
+}
+
+// releaseCHILease deletes per-CHI Lease held by the instance, unless it has been taken over meanwhile,
+// and removes the entry of the Lease
+func (m *shardMembership) releaseCHILease(ctx context.Context, key string, entry *chiLease) {
+	defer m.removeCHILease(key, entry)
+	if entry.lease == nil {
+		return
+	}
+	entry.lease = nil
+
+	// Release is rare, since it happens on rebalance only, thus the Lease is checked to be still held prior to delete
+	leases := m.kubeClient.CoordinationV1().Leases(m.namespace)
+	lease, err := leases.Get(ctx, m.getCHILeaseName(key), controller.NewGetOptions())
+	if err != nil {
+		return
+	}
+	if (lease.Spec.HolderIdentity == nil) || (*lease.Spec.HolderIdentity != m.identity) {
+		// Has been taken over already
+		return
+	}
+	opts := controller.NewDeleteOptions()
+	opts.Preconditions = &meta.Preconditions{ResourceVersion: &lease.ResourceVersion}
+	err = leases.Delete(ctx, lease.Name, opts)
+	if (err != nil) && !apiErrors.IsNotFound(err) && !apiErrors.IsConflict(err) {
+		log.Ctx(ctx).V(1).F().Error("Unable to release lease of CHI: %s Err: %v", key, err)
+		return
+	}
+	log.Ctx(ctx).V(2).F().Info("Released lease of CHI: %s", key)
+}
+
+// owns checks whether CHI is owned by the instance.
+// Everything is owned till the list of members is known, since workers are not started before that
+func (m *shardMembership) owns(namespace, name string) bool {
+	if m == nil {
+		return true
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.members == nil {
+		return true
+	}
+	return getShardOwner(m.members, namespace+"/"+name) == m.identity
+}
+
+// getShardOwner selects member the key is owned by
+func getShardOwner(members []string, key string) (owner string) {
+	max := -1
+	for _, member := range members {
+		weight := util.HashIntoInt([]byte(member + "/" + key))
+		if (weight > max) || ((weight == max) && (member < owner)) {
+			max = weight
+			owner = member
+		}
+	}
+	return owner
+}
+
+// getItemCHI gets namespace and name of the CHI queue item relates to.
+// Items, which are not related to any CHI, such as templates and operator config, are processed by all instances
+func getItemCHI(item queue.PriorityQueueItem) (namespace, name string, ok bool) {
+	var objectMeta *meta.ObjectMeta
+	switch cmd := item.(type) {
+	case *ReconcileCHI:
+		if cmd.new != nil {
+			return cmd.new.Namespace, cmd.new.Name, true
+		}
+		if cmd.old != nil {
+			return cmd.old.Namespace, cmd.old.Name, true
+		}
+	case *ReconcileEndpoints:
+		if cmd.new != nil {
+			objectMeta = &cmd.new.ObjectMeta
+		}
+	case *ReconcilePod:
+		if cmd.new != nil {
+			objectMeta = &cmd.new.ObjectMeta
+		} else if cmd.old != nil {
+			objectMeta = &cmd.old.ObjectMeta
+		}
+	case *DropDns:
+		objectMeta = cmd.initiator
+	case *PerCHICommand:
+		objectMeta = cmd.chi
+	}
+	if objectMeta == nil {
+		return "", "", false
+	}
+
+	switch item.(type) {
+	case *ReconcileEndpoints, *ReconcilePod, *DropDns:
+		// Items are related to objects of the CHI
+		chiName, err := model.GetCHINameFromObjectMeta(objectMeta)
+		if err != nil {
+			return "", "", false
+		}
+		return objectMeta.Namespace, chiName, true
+	default:
+		return objectMeta.Namespace, objectMeta.Name, true
+	}
+}
+
+// isOwnedItem checks whether queue item is to be processed by the instance
+func (c *Controller) isOwnedItem(item queue.PriorityQueueItem) bool {
+	if c.sharding == nil {
+		return true
+	}
+	namespace, name, ok := getItemCHI(item)
+	if !ok {
+		return true
+	}
+	return c.sharding.owns(namespace, name)
+}
+
+// acquireItem acquires per-CHI Lease of the CHI queue item relates to, thus the item is not processed
+// concurrently with items of the same CHI being processed by the previous owner of the CHI.
+// Returns false in case the item has to be retried later, or function to call as soon as the item is processed
+func (c *Controller) acquireItem(ctx context.Context, item queue.PriorityQueueItem) (bool, func()) {
+	if c.sharding == nil {
+		return true, func() {}
+	}
+	namespace, name, ok := getItemCHI(item)
+	if !ok {
+		return true, func() {}
+	}
+	if !c.sharding.acquireCHI(ctx, namespace, name) {
+		return false, nil
+	}
+	return true, func() {
+		c.sharding.releaseCHI(namespace, name)
+	}
+}
+
+// retryItem puts item back into the queue after renew period, so the previous owner of the CHI has time to hand it off.
+// Reconcile is retried with the latest CHI, since newer reconcile of the CHI may have been enqueued meanwhile
+func (c *Controller) retryItem(item queue.PriorityQueueItem) {
+	time.AfterFunc(c.sharding.renewPeriod, func() {
+		cmd, ok := item.(*ReconcileCHI)
+		if !ok || (cmd.cmd == reconcileDelete) {
+			c.enqueueObject(item)
+			return
+		}
+		namespace, name, _ := getItemCHI(item)
+		chi, err := c.chiLister.ClickHouseInstallations(namespace).Get(name)
+		if err != nil {
+			// CHI has been deleted meanwhile
+			return
+		}
+		c.enqueueObject(NewReconcileCHI(reconcileAdd, nil, chi))
+	})
+}
+
+// hasCHI checks whether CHI exists
+func (c *Controller) hasCHI(namespace, name string) bool {
+	_, err := c.chiLister.ClickHouseInstallations(namespace).Get(name)
+	return !apiErrors.IsNotFound(err)
+}
+
+// rebalance reconciles CHIs taken over from other members, in case their latest generation has not been completed
+func (c *Controller) rebalance(prev, cur []string) {
+	chis, err := c.chiLister.List(k8sLabels.Everything())
+	if err != nil {
+		log.V(1).F().Error("Unable to list CHIs to rebalance. Err: %v", err)
+		return
+	}
+
+	identity := c.sharding.identity
+	owned := 0
+	for _, chi := range chis {
+		if !chop.Config().IsWatchedNamespace(chi.Namespace) {
+			continue
+		}
+		key := chi.Namespace + "/" + chi.Name
+		if getShardOwner(cur, key) != identity {
+			continue
+		}
+		owned++
+		if getShardOwner(prev, key) == identity {
+			// Has been owned already
+			continue
+		}
+		if model.IsReconcilePaused(chi) {
+			continue
+		}
+		if chi.HasAncestor() && (chi.Generation == chi.GetAncestor().Generation) {
+			// Latest generation has been completed by the previous owner
+			continue
+		}
+		log.V(1).M(chi).F().Info("CHI is taken over with generation not completed, reconcile")
+		c.enqueueObject(NewReconcileCHI(reconcileAdd, nil, chi))
+	}
+	log.V(1).F().Info("Operator sharding group rebalanced. CHIs owned: %d out of: %d", owned, len(chis))
+}
@@ -0,0 +1,161 @@
+package chi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetShardOwner(t *testing.T) {
+	require.Equal(t, "", getShardOwner(nil, "ns/chi"))
+	require.Equal(t, "a", getShardOwner([]string{"a"}, "ns/chi"))
+
+	// Owner does not depend on order of members
+	members := []string{"a", "b", "c", "d"}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("ns/chi-%d", i)
+		owner := getShardOwner(members, key)
+		require.Contains(t, members, owner)
+		require.Equal(t, owner, getShardOwner([]string{"d", "c", "b", "a"}, key))
+	}
+}
+
+func TestGetShardOwnerRebalance(t *testing.T) {
+	members := []string{"a", "b", "c"}
+	joined := append([]string{"d"}, members...)
+	left := []string{"a", "c"}
+
+	owned := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("ns/chi-%d", i)
+		owner := getShardOwner(members, key)
+		owned[owner]++
+
+		// Only keys moved to the joined member change their owner
+		if cur := getShardOwner(joined, key); cur != owner {
+			require.Equal(t, "d", cur, key)
+		}
+		// Only keys of the left member change their owner
+		if owner != "b" {
+			require.Equal(t, owner, getShardOwner(left, key), key)
+		}
+	}
+
+	// Keys are spread among all members
+	for _, member := range members {
+		require.Greater(t, owned[member], 200, member)
+	}
+}
+
+func newTestShardMembership(kubeClient *fake.Clientset, identity string) *shardMembership {
+	return &shardMembership{
+		kubeClient:    kubeClient,
+		group:         "group",
+		namespace:     "ns",
+		identity:      identity,
+		leaseDuration: time.Minute,
+		renewPeriod:   time.Second,
+		members:       []string{identity},
+		chiLeases:     make(map[string]*chiLease),
+	}
+}
+
+func TestShardMembershipCHIHandOff(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	a := newTestShardMembership(kubeClient, "a")
+	b := newTestShardMembership(kubeClient, "b")
+
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	a.releaseCHI("ns", "chi")
+	a.releaseCHI("ns", "chi")
+	// Lease is kept as long as CHI is owned, even with no items of the CHI being processed
+	require.False(t, b.acquireCHI(ctx, "ns", "chi"))
+
+	// CHI is not processed by the new owner till the previous owner has completed items of the CHI
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	a.members = []string{"b"}
+	a.syncCHILeases(ctx)
+	require.False(t, b.acquireCHI(ctx, "ns", "chi"))
+	a.releaseCHI("ns", "chi")
+	require.True(t, b.acquireCHI(ctx, "ns", "chi"))
+	b.releaseCHI("ns", "chi")
+
+	// Other CHIs are not affected
+	require.True(t, a.acquireCHI(ctx, "ns", "another"))
+	a.releaseCHI("ns", "another")
+}
+
+func TestShardMembershipCHIHandOffOnSync(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	a := newTestShardMembership(kubeClient, "a")
+	b := newTestShardMembership(kubeClient, "b")
+
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	a.releaseCHI("ns", "chi")
+
+	// Lease of the CHI, which is not owned anymore, is released in background
+	a.members = []string{"b"}
+	a.syncCHILeases(ctx)
+	require.Empty(t, a.chiLeases)
+	require.True(t, b.acquireCHI(ctx, "ns", "chi"))
+	b.releaseCHI("ns", "chi")
+
+	// Lease of the deleted CHI is released as well
+	b.exists = func(namespace, name string) bool { return false }
+	b.syncCHILeases(ctx)
+	require.Empty(t, b.chiLeases)
+	leases, err := kubeClient.CoordinationV1().Leases("ns").List(ctx, meta.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, leases.Items)
+}
+
+func TestShardMembershipCHILeaseRenew(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	a := newTestShardMembership(kubeClient, "a")
+
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	a.releaseCHI("ns", "chi")
+	acquired := a.chiLeases["ns/chi"].lease.Spec.RenewTime.Time
+
+	// Lease held already is reused with no API calls
+	kubeClient.ClearActions()
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	a.releaseCHI("ns", "chi")
+	require.Empty(t, kubeClient.Actions())
+
+	// Lease is renewed in background with a single update
+	time.Sleep(10 * time.Millisecond)
+	a.syncCHILeases(ctx)
+	require.Len(t, kubeClient.Actions(), 1)
+	require.Equal(t, "update", kubeClient.Actions()[0].GetVerb())
+	lease, err := kubeClient.CoordinationV1().Leases("ns").Get(ctx, a.getCHILeaseName("ns/chi"), meta.GetOptions{})
+	require.NoError(t, err)
+	require.True(t, lease.Spec.RenewTime.After(acquired))
+}
+
+func TestShardMembershipCHILeaseExpired(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	a := newTestShardMembership(kubeClient, "a")
+	a.leaseDuration = 0
+	b := newTestShardMembership(kubeClient, "b")
+
+	// Lease of the member, which has gone in the middle of processing, is taken over as soon as it expires
+	require.True(t, a.acquireCHI(ctx, "ns", "chi"))
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, b.acquireCHI(ctx, "ns", "chi"))
+
+	// Lease taken over is not released by the previous holder
+	a.members = []string{"b"}
+	a.releaseCHI("ns", "chi")
+	require.False(t, newTestShardMembership(kubeClient, "c").acquireCHI(ctx, "ns", "chi"))
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/metrics"
+)
+
+// Names of the spans
+const (
+	spanReconcileCHI        = "ReconcileCHI"
+	spanReconcileCluster    = "reconcileCluster"
+	spanReconcileShard      = "reconcileShard"
+	spanReconcileHost       = "reconcileHost"
+	spanPollHostStatefulSet = "pollHostStatefulSet"
+)
+
+// startSpanCHI starts span of the reconcile command of the CHI
+func startSpanCHI(ctx context.Context, command string, chi *api.ClickHouseInstallation) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String(metrics.AttributeCommand, command),
+		attribute.String(metrics.AttributeReconcileID, log.GetReconcileID(ctx)),
+	}
+	if chi != nil {
+		attrs = append(attrs,
+			attribute.String(metrics.AttributeNamespace, chi.Namespace),
+			attribute.String(metrics.AttributeCHI, chi.Name),
+			attribute.String(metrics.AttributeTaskID, chi.Spec.GetTaskID()),
+		)
+	}
+	return metrics.StartSpan(ctx, spanReconcileCHI, attrs...)
+}
+
+// startSpanCluster starts span of the operation over the cluster
+func startSpanCluster(ctx context.Context, name string, cluster *api.Cluster) (context.Context, trace.Span) {
+	address := cluster.Runtime.Address
+	return metrics.StartSpan(ctx, name,
+		attribute.String(metrics.AttributeNamespace, address.Namespace),
+		attribute.String(metrics.AttributeCHI, address.CHIName),
+		attribute.String(metrics.AttributeCluster, address.ClusterName),
+	)
+}
+
+// startSpanShard starts span of the operation over the shard
+func startSpanShard(ctx context.Context, name string, shard *api.ChiShard) (context.Context, trace.Span) {
+	address := shard.Runtime.Address
+	return metrics.StartSpan(ctx, name,
+		attribute.String(metrics.AttributeNamespace, address.Namespace),
+		attribute.String(metrics.AttributeCHI, address.CHIName),
+		attribute.String(metrics.AttributeCluster, address.ClusterName),
+		attribute.String(metrics.AttributeShard, address.ShardName),
+	)
+}
+
+// startSpanHost starts span of the operation over the host
+func startSpanHost(ctx context.Context, name string, host *api.ChiHost) (context.Context, trace.Span) {
+	address := host.Runtime.Address
+	return metrics.StartSpan(ctx, name,
+		attribute.String(metrics.AttributeNamespace, address.Namespace),
+		attribute.String(metrics.AttributeCHI, address.CHIName),
+		attribute.String(metrics.AttributeCluster, address.ClusterName),
+		attribute.String(metrics.AttributeShard, address.ShardName),
+		attribute.String(metrics.AttributeHost, address.HostName),
+		semconv.ServerAddress(address.FQDN),
+	)
+}
+
+// endSpan ends span with the outcome of the operation
+func endSpan(span trace.Span, err error) {
+	metrics.EndSpan(span, err)
+}
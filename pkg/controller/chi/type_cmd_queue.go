@@ -27,6 +27,8 @@ const (
 	reconcileAdd    = "add"
 	reconcileUpdate = "update"
 	reconcileDelete = "delete"
+	reconcilePause  = "pause"
+	reconcileResume = "resume"
 )
 
 // PriorityQueueItem specifies item of the priority queue
@@ -45,6 +47,16 @@ const (
 	priorityReconcileChopConfig int = 3
 	priorityReconcileEndpoints  int = 15
 	priorityDropDNS             int = 7
+	priorityRotateOperatorCreds int = 12
+	priorityCheckSchemaDrift    int = 20
+	priorityCheckReplication    int = 18
+	priorityCheckPVCAutoscaling int = 19
+	priorityApplyDeferred       int = 11
+	priorityHibernation         int = 11
+	priorityVirtualClusters     int = 12
+	priorityDataSources         int = 11
+	priorityReconcileUsers      int = 11
+	priorityCheckStorageClass   int = 17
 )
 
 // ReconcileCHI specifies reconcile request queue item
@@ -252,3 +264,72 @@ func NewReconcilePod(cmd string, old, new *core.Pod) *ReconcilePod {
 		new: new,
 	}
 }
+
+// PerCHICommandKind specifies kind of the command, which is run against one CHI
+type PerCHICommandKind string
+
+const (
+	// commandRotateOperatorCredentials rotates generated operator credentials
+	commandRotateOperatorCredentials PerCHICommandKind = "RotateOperatorCredentials"
+	// commandCheckSchemaDrift checks schema drift across replicas and shards
+	commandCheckSchemaDrift PerCHICommandKind = "CheckSchemaDrift"
+	// commandCheckReplicationHealth checks replication health and recovers unhealthy replicas
+	commandCheckReplicationHealth PerCHICommandKind = "CheckReplicationHealth"
+	// commandCheckPVCAutoscaling checks disk usage and expands PVCs
+	commandCheckPVCAutoscaling PerCHICommandKind = "CheckPVCAutoscaling"
+	// commandApplyDeferredChanges applies changes deferred till maintenance window
+	commandApplyDeferredChanges PerCHICommandKind = "ApplyDeferredChanges"
+	// commandReconcileDataSources rolls out changed data of config maps settings and files are sourced from
+	commandReconcileDataSources PerCHICommandKind = "ReconcileDataSources"
+	// commandHibernationTransition puts CHI to sleep or wakes it up by the hibernation schedule
+	commandHibernationTransition PerCHICommandKind = "HibernationTransition"
+	// commandUpdateVirtualClusters updates virtual clusters with layouts of the referenced CHIs
+	commandUpdateVirtualClusters PerCHICommandKind = "UpdateVirtualClusters"
+	// commandReconcileUsers regenerates users config with users specified by ClickHouseUser resources
+	commandReconcileUsers PerCHICommandKind = "ReconcileUsers"
+	// commandCheckStorageClassMigrations checks hosts with migrated storage and releases old volumes of hosts caught up
+	commandCheckStorageClassMigrations PerCHICommandKind = "CheckStorageClassMigrations"
+)
+
+// perCHICommandPriorities specifies priorities of the queue items of the commands
+var perCHICommandPriorities = map[PerCHICommandKind]int{
+	commandRotateOperatorCredentials:   priorityRotateOperatorCreds,
+	commandCheckSchemaDrift:            priorityCheckSchemaDrift,
+	commandCheckReplicationHealth:      priorityCheckReplication,
+	commandCheckPVCAutoscaling:         priorityCheckPVCAutoscaling,
+	commandApplyDeferredChanges:        priorityApplyDeferred,
+	commandReconcileDataSources:        priorityDataSources,
+	commandHibernationTransition:       priorityHibernation,
+	commandUpdateVirtualClusters:       priorityVirtualClusters,
+	commandReconcileUsers:              priorityReconcileUsers,
+	commandCheckStorageClassMigrations: priorityCheckStorageClass,
+}
+
+// PerCHICommand specifies queue item of the command, which is run against one CHI.
+// Commands change the CHI or its objects, thus they are serialized with reconcile of the same CHI
+type PerCHICommand struct {
+	PriorityQueueItem
+	kind PerCHICommandKind
+	chi  *meta.ObjectMeta
+}
+
+var _ queue.PriorityQueueItem = &PerCHICommand{}
+
+// Handle returns handle of the queue item
+func (r PerCHICommand) Handle() queue.T {
+	if r.chi != nil {
+		return string(r.kind) + ":" + r.chi.Namespace + "/" + r.chi.Name
+	}
+	return ""
+}
+
+// NewPerCHICommand creates new queue item of the command run against the CHI
+func NewPerCHICommand(kind PerCHICommandKind, chi *meta.ObjectMeta) *PerCHICommand {
+	return &PerCHICommand{
+		PriorityQueueItem: PriorityQueueItem{
+			priority: perCHICommandPriorities[kind],
+		},
+		kind: kind,
+		chi:  chi,
+	}
+}
@@ -17,6 +17,7 @@ package chi
 import (
 	"time"
 
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
 	kube "k8s.io/client-go/kubernetes"
 	appsListers "k8s.io/client-go/listers/apps/v1"
 	coreListers "k8s.io/client-go/listers/core/v1"
@@ -47,6 +48,11 @@ type Controller struct {
 	chitLister       chopListers.ClickHouseInstallationTemplateLister
 	chitListerSynced cache.InformerSynced
 
+	// chuLister used as chuLister.ClickHouseUsers(namespace).List(selector)
+	chuLister chopListers.ClickHouseUserLister
+	// chuListerSynced used in waitForCacheSync()
+	chuListerSynced cache.InformerSynced
+
 	// serviceLister used as serviceLister.Services(namespace).Get(name)
 	serviceLister coreListers.ServiceLister
 	// serviceListerSynced used in waitForCacheSync()
@@ -67,9 +73,21 @@ type Controller struct {
 	podLister coreListers.PodLister
 	// podListerSynced used in waitForCacheSync()
 	podListerSynced cache.InformerSynced
+	// secretLister used as secretLister.Secrets(namespace).Get(name). Lists Secrets with generated operator credentials only
+	secretLister coreListers.SecretLister
+	// secretListerSynced used in waitForCacheSync()
+	secretListerSynced cache.InformerSynced
+	// namespaceListerSynced used in waitForCacheSync(). Nil in case namespaces are not selected by labels
+	namespaceListerSynced cache.InformerSynced
+	// namespaceSelector specifies labels of the watched namespaces. Nil in case namespaces are not selected by labels
+	namespaceSelector k8sLabels.Selector
 
 	// queues used to organize events queue processed by operator
 	queues []queue.PriorityQueue
+	// sharding specifies operator instances CHIs are partitioned among. Nil in case sharding is disabled
+	sharding *shardMembership
+	// replicationHealthChecks specifies when replication health of CHIs has been checked
+	replicationHealthChecks replicationHealthChecks
 	// not used explicitly
 	recorder record.EventRecorder
 }
@@ -36,13 +36,14 @@ import (
 	"github.com/minorhacks/clickhouse-operator/pkg/controller"
 	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
 	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
 
 // reconcileCHI run reconcile cycle for a CHI
 func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -72,11 +73,60 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 		old = nil
 	}
 
+	if err := w.ensureOperatorCredentials(ctx, new); err != nil {
+		// Users config rendered without generated operator credentials would lock the operator out
+		w.a.WithEvent(new, eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusError(new).
+			M(new).F().
+			Error("FAILED to ensure operator credentials, reconcile aborted. CHI: %s/%s err: %v", new.Namespace, new.Name, err)
+		return err
+	}
+
 	w.a.M(new).F().Info("Normalized OLD CHI: %s/%s", new.Namespace, new.Name)
-	old = w.normalize(old)
+	old, err := w.normalize(old)
+	if err != nil {
+		w.a.WithEvent(new, eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusError(new).
+			M(new).F().
+			Error("FAILED to normalize OLD CHI, reconcile aborted. CHI: %s/%s err: %v", new.Namespace, new.Name, err)
+		return err
+	}
+
+	// Spec supplied by the user is recorded as a revision on completion, thus it is kept before normalization
+	userSpec := new.Spec.DeepCopy()
 
 	w.a.M(new).F().Info("Normalized NEW CHI: %s/%s", new.Namespace, new.Name)
-	new = w.normalize(new)
+	new, err = w.normalizeAndValidate(new)
+	switch {
+	case errors.Is(err, normalizer.ErrSpecValidation):
+		// CHI is not reconciled till spec is fixed
+		w.a.V(1).
+			WithEvent(new, eventActionReconcile, eventReasonSpecValidationFailed).
+			WithStatusError(new).
+			M(new).F().
+			Error("Reconcile of CHI: %s/%s rejected, fix spec to proceed. err: %v", new.Namespace, new.Name, err)
+		return nil
+	case errors.Is(err, normalizer.ErrSettingsValidation):
+		// CHI is not reconciled till settings are fixed
+		w.a.V(1).
+			WithEvent(new, eventActionReconcile, eventReasonSettingsValidationFailed).
+			WithStatusError(new).
+			M(new).F().
+			Error("Reconcile of CHI: %s/%s rejected, fix settings to proceed. err: %v", new.Namespace, new.Name, err)
+		return nil
+	case err != nil:
+		w.a.WithEvent(new, eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusError(new).
+			M(new).F().
+			Error("FAILED to normalize NEW CHI, reconcile aborted. CHI: %s/%s err: %v", new.Namespace, new.Name, err)
+		return err
+	}
+	w.fetchDataSourcesVersion(ctx, new)
+	w.applyHibernation(ctx, new)
+
+	// Task ID is generated by the normalizer in case it is not specified
+	ctx = log.WithTaskID(ctx, new.Spec.GetTaskID())
+	w.a = w.a.Ctx(ctx)
 
 	new.SetAncestor(old)
 	w.logOldAndNew("normalized", old, new)
@@ -89,13 +139,17 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 		w.a.M(new).F().Info("ActionPlan has actions - continue reconcile")
 	case w.isAfterFinalizerInstalled(old, new):
 		w.a.M(new).F().Info("isAfterFinalizerInstalled - continue reconcile-2")
+	case w.hasDeferredChangesDue(new):
+		w.a.M(new).F().Info("Maintenance window is open for deferred changes - continue reconcile")
+	case w.hasDataSourcesChanges(new):
+		w.a.M(new).F().Info("Data sources are changed - continue reconcile")
 	default:
 		w.a.M(new).F().Info("ActionPlan has no actions and not finalizer - nothing to do")
 		return nil
 	}
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -103,6 +157,9 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 	w.markReconcileStart(ctx, new, actionPlan)
 	w.excludeStoppedCHIFromMonitoring(new)
 	w.walkHosts(ctx, new, actionPlan)
+	w.startRestartRequests(ctx, new)
+	w.resolveVirtualClusters(ctx, new)
+	w.registerRevisions(new)
 
 	if err := w.reconcile(ctx, new); err != nil {
 		// Something went wrong
@@ -118,14 +175,15 @@ func (w *worker) reconcileCHI(ctx context.Context, old, new *api.ClickHouseInsta
 		// Reconcile successful
 		// Post-process added items
 		if util.IsContextDone(ctx) {
-			log.V(2).Info("task is done")
+			log.Ctx(ctx).V(2).Info("task is done")
 			return nil
 		}
 		w.clean(ctx, new)
 		w.dropReplicas(ctx, new, actionPlan)
+		w.syncDeferredHosts(ctx, new)
 		w.addCHIToMonitoring(new)
 		w.waitForIPAddresses(ctx, new)
-		w.finalizeReconcileAndMarkCompleted(ctx, new)
+		w.finalizeReconcileAndMarkCompleted(ctx, new, userSpec)
 
 		metricsCHIReconcilesCompleted(ctx, new)
 		metricsCHIReconcilesTimings(ctx, new, time.Now().Sub(startTime).Seconds())
@@ -145,7 +203,7 @@ const ReconcileShardsAndHostsOptionsCtxKey ReconcileShardsAndHostsOptionsCtxKeyT
 // reconcile reconciles ClickHouseInstallation
 func (w *worker) reconcile(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -179,7 +237,7 @@ func (w *worker) reconcile(ctx context.Context, chi *api.ClickHouseInstallation)
 // reconcileCHIAuxObjectsPreliminary reconciles CHI preliminary in order to ensure that ConfigMaps are in place
 func (w *worker) reconcileCHIAuxObjectsPreliminary(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -203,8 +261,9 @@ func (w *worker) reconcileCHIAuxObjectsPreliminary(ctx context.Context, chi *api
 
 // reconcileCHIServicePreliminary runs first stage of CHI reconcile process
 func (w *worker) reconcileCHIServicePreliminary(ctx context.Context, chi *api.ClickHouseInstallation) error {
-	if chi.IsStopped() {
-		// Stopped CHI must have no entry point
+	if chi.IsStopped() && !chi.IsHibernated() {
+		// Stopped CHI must have no entry point.
+		// Hibernated CHI keeps it, so clients fail fast instead of failing to resolve it
 		_ = w.c.deleteServiceCHI(ctx, chi)
 	}
 	return nil
@@ -212,7 +271,7 @@ func (w *worker) reconcileCHIServicePreliminary(ctx context.Context, chi *api.Cl
 
 // reconcileCHIServiceFinal runs second stage of CHI reconcile process
 func (w *worker) reconcileCHIServiceFinal(ctx context.Context, chi *api.ClickHouseInstallation) error {
-	if chi.IsStopped() {
+	if chi.IsStopped() && !chi.IsHibernated() {
 		// Stopped CHI must have no entry point
 		return nil
 	}
@@ -233,7 +292,7 @@ func (w *worker) reconcileCHIServiceFinal(ctx context.Context, chi *api.ClickHou
 // reconcileCHIAuxObjectsFinal reconciles CHI global objects
 func (w *worker) reconcileCHIAuxObjectsFinal(ctx context.Context, chi *api.ClickHouseInstallation) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -254,10 +313,15 @@ func (w *worker) reconcileCHIConfigMapCommon(
 	options *model.ClickHouseConfigFilesGeneratorOptions,
 ) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	// Hosts with unhealthy replicas are kept out of the cluster till they are healthy again
+	options = w.excludeUnhealthyHosts(chi, options)
+	// Hosts with migrated storage are kept out of the cluster till they catch up with other replicas
+	options = w.excludeMigratingHosts(chi, options)
+
 	// ConfigMap common for all resources in CHI
 	// contains several sections, mapped as separated chopConfig files,
 	// such as remote servers, zookeeper setup, etc
@@ -275,7 +339,7 @@ func (w *worker) reconcileCHIConfigMapCommon(
 // ConfigMap common for all users resources in CHI
 func (w *worker) reconcileCHIConfigMapUsers(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -293,7 +357,7 @@ func (w *worker) reconcileCHIConfigMapUsers(ctx context.Context, chi *api.ClickH
 // reconcileHostConfigMap reconciles host's personal ConfigMap
 func (w *worker) reconcileHostConfigMap(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -399,12 +463,12 @@ func (a reconcileHostStatefulSetOptionsArr) First() *reconcileHostStatefulSetOpt
 // reconcileHostStatefulSet reconciles host's StatefulSet
 func (w *worker) reconcileHostStatefulSet(ctx context.Context, host *api.ChiHost, opts ...*reconcileHostStatefulSetOptions) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(1).M(host).F().S().Info("reconcile StatefulSet start")
-	defer log.V(1).M(host).F().E().Info("reconcile StatefulSet end")
+	log.Ctx(ctx).V(1).M(host).F().S().Info("reconcile StatefulSet start")
+	defer log.Ctx(ctx).V(1).M(host).F().E().Info("reconcile StatefulSet end")
 
 	version, _ := w.getHostClickHouseVersion(ctx, host, versionOptions{skipNew: true, skipStoppedAncestor: true})
 	host.Runtime.CurStatefulSet, _ = w.c.getStatefulSet(host, false)
@@ -448,7 +512,7 @@ func (w *worker) reconcileHostStatefulSet(ctx context.Context, host *api.ChiHost
 // reconcileHostService reconciles host's Service
 func (w *worker) reconcileHostService(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 	service := w.task.creator.CreateServiceHost(host)
@@ -468,12 +532,17 @@ func (w *worker) reconcileHostService(ctx context.Context, host *api.ChiHost) er
 }
 
 // reconcileCluster reconciles ChkCluster, excluding nested shards
-func (w *worker) reconcileCluster(ctx context.Context, cluster *api.Cluster) error {
+func (w *worker) reconcileCluster(ctx context.Context, cluster *api.Cluster) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	ctx, span := startSpanCluster(ctx, spanReconcileCluster, cluster)
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	w.a.V(2).M(cluster).S().P()
 	defer w.a.V(2).M(cluster).E().P()
 
@@ -620,6 +689,10 @@ func (w *worker) reconcileShardWithHosts(ctx context.Context, shard *api.ChiShar
 	}
 	for replicaIndex := range shard.Hosts {
 		host := shard.Hosts[replicaIndex]
+		if host.IsExternal() {
+			// External host is not managed by the operator
+			continue
+		}
 		if err := w.reconcileHost(ctx, host); err != nil {
 			return err
 		}
@@ -628,12 +701,17 @@ func (w *worker) reconcileShardWithHosts(ctx context.Context, shard *api.ChiShar
 }
 
 // reconcileShard reconciles specified shard, excluding nested replicas
-func (w *worker) reconcileShard(ctx context.Context, shard *api.ChiShard) error {
+func (w *worker) reconcileShard(ctx context.Context, shard *api.ChiShard) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	ctx, span := startSpanShard(ctx, spanReconcileShard, shard)
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	w.a.V(2).M(shard).S().P()
 	defer w.a.V(2).M(shard).E().P()
 
@@ -643,7 +721,7 @@ func (w *worker) reconcileShard(ctx context.Context, shard *api.ChiShard) error
 		// This is not a problem, ServiceShard may be omitted
 		return nil
 	}
-	err := w.reconcileService(ctx, shard.Runtime.CHI, service)
+	err = w.reconcileService(ctx, shard.Runtime.CHI, service)
 	if err == nil {
 		w.task.registryReconciled.RegisterService(service.ObjectMeta)
 	} else {
@@ -653,17 +731,22 @@ func (w *worker) reconcileShard(ctx context.Context, shard *api.ChiShard) error
 }
 
 // reconcileHost reconciles specified ClickHouse host
-func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
+func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) (err error) {
 	var (
 		reconcileHostStatefulSetOpts *reconcileHostStatefulSetOptions
 		migrateTableOpts             *migrateTableOptions
 	)
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	ctx, span := startSpanHost(ctx, spanReconcileHost, host)
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	w.a.V(2).M(host).S().P()
 	defer w.a.V(2).M(host).E().P()
 
@@ -693,6 +776,11 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 	// Create artifacts
 	w.prepareHostStatefulSetWithStatus(ctx, host, false)
 
+	if w.deferHostChanges(ctx, host) {
+		// Host is neither restarted nor re-created till maintenance window
+		return w.reconcileDeferredHost(ctx, host)
+	}
+
 	if err := w.excludeHost(ctx, host); err != nil {
 		metricsHostReconcilesErrors(ctx, host.GetCHI())
 		w.a.V(1).
@@ -711,6 +799,17 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 		return err
 	}
 
+	if w.startHostStorageClassMigrations(ctx, host) {
+		// Host is re-created empty on the new storage class and its data is replicated from other replicas
+		reconcileHostStatefulSetOpts = &reconcileHostStatefulSetOptions{
+			forceRecreate: true,
+		}
+		migrateTableOpts = &migrateTableOptions{
+			forceMigrate: true,
+			dropReplica:  true,
+		}
+	}
+
 	w.a.V(1).
 		M(host).F().
 		Info("Reconcile PVCs and check possible data loss for host: %s", host.GetName())
@@ -756,7 +855,10 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 			M(host).F().
 			Warning("Check host for ClickHouse availability before migrating tables. Host: %s Failed to get ClickHouse version: %s", host.GetName(), version)
 	}
+	// Hot-reloadable config changes are applied without restart
+	w.reloadHostConfig(ctx, host)
 	_ = w.migrateTables(ctx, host, migrateTableOpts)
+	w.completeHostDeferredChanges(ctx, host)
 
 	if err := w.includeHost(ctx, host); err != nil {
 		metricsHostReconcilesErrors(ctx, host.GetCHI())
@@ -765,6 +867,7 @@ func (w *worker) reconcileHost(ctx context.Context, host *api.ChiHost) error {
 			Warning("Reconcile Host interrupted with an error 4. Host: %s Err: %v", host.GetName(), err)
 		return err
 	}
+	w.completeHostRestartRequests(ctx, host)
 
 	// Ensure host is running and accessible and what version is available.
 	// Sometimes service needs some time to start after creation|modification before being accessible for usage
@@ -816,21 +919,21 @@ func (w *worker) reconcilePDB(ctx context.Context, cluster *api.Cluster, pdb *po
 		pdb.ResourceVersion = cur.ResourceVersion
 		_, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Update(ctx, pdb, controller.NewUpdateOptions())
 		if err == nil {
-			log.V(1).Info("PDB updated: %s/%s", pdb.Namespace, pdb.Name)
+			log.Ctx(ctx).V(1).Info("PDB updated: %s/%s", pdb.Namespace, pdb.Name)
 		} else {
-			log.Error("FAILED to update PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
+			log.Ctx(ctx).Error("FAILED to update PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
 			return nil
 		}
 	case apiErrors.IsNotFound(err):
 		_, err := w.c.kubeClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, controller.NewCreateOptions())
 		if err == nil {
-			log.V(1).Info("PDB created: %s/%s", pdb.Namespace, pdb.Name)
+			log.Ctx(ctx).V(1).Info("PDB created: %s/%s", pdb.Namespace, pdb.Name)
 		} else {
-			log.Error("FAILED create PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
+			log.Ctx(ctx).Error("FAILED create PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
 			return err
 		}
 	default:
-		log.Error("FAILED get PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
+		log.Ctx(ctx).Error("FAILED get PDB: %s/%s err: %v", pdb.Namespace, pdb.Name, err)
 		return err
 	}
 
@@ -844,7 +947,7 @@ func (w *worker) reconcileConfigMap(
 	configMap *core.ConfigMap,
 ) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -885,7 +988,7 @@ func (w *worker) hasService(ctx context.Context, chi *api.ClickHouseInstallation
 // reconcileService reconciles core.Service
 func (w *worker) reconcileService(ctx context.Context, chi *api.ClickHouseInstallation, service *core.Service) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -934,7 +1037,7 @@ func (w *worker) reconcileService(ctx context.Context, chi *api.ClickHouseInstal
 // reconcileSecret reconciles core.Secret
 func (w *worker) reconcileSecret(ctx context.Context, chi *api.ClickHouseInstallation, secret *core.Secret) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1015,7 +1118,7 @@ func (w *worker) reconcileStatefulSet(
 	opts ...*reconcileHostStatefulSetOptions,
 ) (err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1270,7 +1373,7 @@ func (w *worker) reconcilePVC(
 	defer w.a.V(2).M(host).E().Info("reconcile PVC (%s/%s/%s)", pvc.Namespace, pvc.Name, host.GetName())
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil, fmt.Errorf("task is done")
 	}
 
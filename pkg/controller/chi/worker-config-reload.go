@@ -0,0 +1,160 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// hostConfigurationChanges caches configuration changes of the hosts of the task,
+// thus changes are computed and restart reasons are reported once per host
+type hostConfigurationChanges struct {
+	mutex sync.Mutex
+	hosts map[string]*hostConfigurationChangesEntry
+}
+
+// hostConfigurationChangesEntry specifies configuration changes of the host
+type hostConfigurationChangesEntry struct {
+	// version specifies ClickHouse version of the host changes are classified by restart policy rules for
+	version  string
+	changes  model.ConfigurationChanges
+	reported bool
+}
+
+// newHostConfigurationChanges creates new hostConfigurationChanges
+func newHostConfigurationChanges() *hostConfigurationChanges {
+	return &hostConfigurationChanges{
+		hosts: make(map[string]*hostConfigurationChangesEntry),
+	}
+}
+
+// getHostConfigurationChanges lists changes of the host configuration comparing to the ancestor.
+// Changes are computed once per host, unless ClickHouse version of the host, which restart policy rules depend on, has changed
+func (w *worker) getHostConfigurationChanges(host *api.ChiHost) model.ConfigurationChanges {
+	cache := w.task.configurationChanges
+	if cache == nil {
+		// No task is started
+		return model.GetConfigurationChanges(host)
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	version := host.Runtime.Version.String()
+	entry, ok := cache.hosts[host.GetName()]
+	if !ok {
+		entry = &hostConfigurationChangesEntry{}
+		cache.hosts[host.GetName()] = entry
+	}
+	if !ok || (entry.version != version) {
+		entry.version = version
+		entry.changes = model.GetConfigurationChanges(host)
+	}
+	return entry.changes
+}
+
+// reportHostRestartReasons reports config changes, which require the host to be restarted, once per host
+func (w *worker) reportHostRestartReasons(host *api.ChiHost, changes model.ConfigurationChanges) {
+	if w.isHostRestartReasonsReported(host, changes) {
+		return
+	}
+	w.a.V(1).
+		WithEvent(host.GetCHI(), eventActionReconcile, eventReasonHostRestartRequired).
+		WithStatusAction(host.GetCHI()).
+		M(host).F().
+		Info("Config change(s) require host restart. Host: %s Reasons: %s", host.GetName(), strings.Join(changes.RestartReasons(), ", "))
+}
+
+// isHostRestartReasonsReported checks whether restart reasons of the host have been reported by the task already
+// and marks them reported
+func (w *worker) isHostRestartReasonsReported(host *api.ChiHost, changes model.ConfigurationChanges) bool {
+	cache := w.task.configurationChanges
+	if cache == nil {
+		// No task is started
+		return false
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.hosts[host.GetName()]
+	if !ok {
+		entry = &hostConfigurationChangesEntry{
+			version: host.Runtime.Version.String(),
+			changes: changes,
+		}
+		cache.hosts[host.GetName()] = entry
+	}
+	reported := entry.reported
+	entry.reported = true
+	return reported
+}
+
+// reloadHostConfig applies config changes, which are hot-reloadable, to the running host via 'SYSTEM RELOAD CONFIG'.
+// Host, which has config changes requiring restart, is restarted instead and is not reloaded
+func (w *worker) reloadHostConfig(ctx context.Context, host *api.ChiHost) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	switch {
+	case host.GetReconcileAttributes().GetStatus() == api.ObjectStatusNew:
+		// New host starts with the desired config
+		return
+	case !host.HasAncestor():
+		// Nothing to compare with
+		return
+	case host.IsStopped():
+		// Config is picked up on start
+		return
+	case host.GetCHI().IsRollingUpdate():
+		// Host has been restarted
+		return
+	}
+
+	changes := w.getHostConfigurationChanges(host)
+	if !changes.IsReloadable() {
+		// Either there are no config changes or host has been restarted to apply them
+		return
+	}
+
+	// Host reads config from the ConfigMap mounted, thus reload makes sense as soon as the ConfigMap is propagated
+	if w.waitConfigMapPropagation(ctx, host) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	paths := strings.Join(changes.Paths(), ", ")
+	if err := w.ensureClusterSchemer(host).HostReloadConfig(ctx, host); err != nil {
+		w.a.V(1).
+			WithEvent(host.GetCHI(), eventActionReconcile, eventReasonHostConfigReloadFailed).
+			WithStatusError(host.GetCHI()).
+			M(host).F().
+			Warning("Config reload FAILED. Changes are applied by the periodical config reload of ClickHouse. Host: %s Changes: %s err: %v", host.GetName(), paths, err)
+		return
+	}
+
+	w.a.V(1).
+		WithEvent(host.GetCHI(), eventActionReconcile, eventReasonHostConfigReloaded).
+		WithStatusAction(host.GetCHI()).
+		M(host).F().
+		Info("Config reloaded without restart. Host: %s Changes: %s", host.GetName(), paths)
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processReconcileDataSources reconciles CHI in order to roll out changed data of config maps settings and files are sourced from
+func (w *worker) processReconcileDataSources(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.c.GetCHIByObjectMeta(cmd.chi, true)
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if model.IsReconcilePaused(chi) {
+		return nil
+	}
+
+	w.a.V(1).M(chi).F().Info("Data sources are changed, reconcile CHI: %s/%s", chi.Namespace, chi.Name)
+	return w.reconcileCHI(ctx, nil, chi)
+}
+
+// fetchDataSourcesVersion fetches data of config maps, which settings and files of the normalized CHI are sourced from,
+// and specifies version of the data in CHI runtime attributes
+func (w *worker) fetchDataSourcesVersion(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	keys := model.GetDataSourcesConfigMapKeys(chi)
+	data := make(map[api.ObjectAddress]string)
+	configMaps := make(map[string]map[string]string)
+	for _, key := range keys {
+		values, ok := configMaps[key.Name]
+		if !ok {
+			// Config maps are served by the informer's cache, which tracks changes of data sources anyway
+			configMap, err := w.c.configMapLister.ConfigMaps(key.Namespace).Get(key.Name)
+			switch {
+			case err == nil:
+				// Cached config map is shared, thus its data is copied
+				values = make(map[string]string, len(configMap.Data)+len(configMap.BinaryData))
+				for k, v := range configMap.Data {
+					values[k] = v
+				}
+				for k, v := range configMap.BinaryData {
+					values[k] = string(v)
+				}
+			case apiErrors.IsNotFound(err):
+				// Config map may be optional, missing config map has no data
+			default:
+				w.a.V(1).M(chi).F().Warning("unable to get config map %s/%s err: %v", key.Namespace, key.Name, err)
+			}
+			configMaps[key.Name] = values
+		}
+		data[key] = values[key.Key]
+	}
+
+	chi.EnsureRuntime().GetAttributes().DataSourcesVersion = model.BuildDataSourcesVersion(keys, data)
+}
+
+// hasDataSourcesChanges checks whether data of config maps, which settings and files are sourced from,
+// differs from the data StatefulSets of the hosts are rolled out with
+func (w *worker) hasDataSourcesChanges(chi *api.ClickHouseInstallation) bool {
+	version := chi.EnsureRuntime().GetAttributes().DataSourcesVersion
+	if version == "" {
+		// CHI has no data sources, dropping the last one is a change of the CHI itself
+		return false
+	}
+	changed := false
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		statefulSet, err := w.c.getStatefulSetByHost(host)
+		if err != nil {
+			// New host is reconciled anyway
+			return nil
+		}
+		if statefulSet.Spec.Template.Annotations[model.AnnotationDataSourcesVersion] != version {
+			changed = true
+		}
+		return nil
+	})
+	return changed
+}
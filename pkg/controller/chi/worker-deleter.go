@@ -32,7 +32,7 @@ import (
 
 func (w *worker) clean(ctx context.Context, chi *api.ClickHouseInstallation) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -61,7 +61,7 @@ func (w *worker) clean(ctx context.Context, chi *api.ClickHouseInstallation) {
 // dropReplicas cleans Zookeeper for replicas that are properly deleted - via AP
 func (w *worker) dropReplicas(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -215,7 +215,7 @@ func (w *worker) purge(
 	reconcileFailedObjs *model.Registry,
 ) (cnt int) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return cnt
 	}
 
@@ -241,7 +241,7 @@ func (w *worker) purge(
 // discoveryAndDeleteCHI deletes all kubernetes resources related to chi *chop.ClickHouseInstallation
 func (w *worker) discoveryAndDeleteCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -259,7 +259,7 @@ func (w *worker) discoveryAndDeleteCHI(ctx context.Context, chi *api.ClickHouseI
 // deleteCHIProtocol deletes all kubernetes resources related to chi *chop.ClickHouseInstallation
 func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -298,6 +298,8 @@ func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInsta
 
 	// Exclude this CHI from monitoring
 	w.c.deleteWatch(chi)
+	metricsSchemaDriftForget(chi)
+	w.c.replicationHealthChecks.forget(chi.Namespace + "/" + chi.Name)
 
 	// Delete Service
 	_ = w.c.deleteServiceCHI(ctx, chi)
@@ -313,7 +315,7 @@ func (w *worker) deleteCHIProtocol(ctx context.Context, chi *api.ClickHouseInsta
 	})
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -342,7 +344,7 @@ func (w *worker) canDropReplica(host *api.ChiHost, opts ...*dropReplicaOptions)
 		// Replica's state has to be kept in Zookeeper for retained volumes.
 		// ClickHouse expects to have state of the non-empty replica in-place when replica rejoins.
 		if model.GetReclaimPolicy(pvc.ObjectMeta) == api.PVCReclaimPolicyRetain {
-			w.a.V(1).F().Info("PVC: %s/%s blocks drop replica. Reclaim policy: %s", api.PVCReclaimPolicyRetain.String())
+			w.a.V(1).F().Info("PVC: %s/%s blocks drop replica. Reclaim policy: %s", pvc.Namespace, pvc.Name, api.PVCReclaimPolicyRetain.String())
 			can = false
 		}
 	})
@@ -379,7 +381,7 @@ func (a dropReplicaOptionsArr) First() *dropReplicaOptions {
 // dropReplica drops replica's info from Zookeeper
 func (w *worker) dropReplica(ctx context.Context, hostToDrop *api.ChiHost, opts ...*dropReplicaOptions) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -425,7 +427,7 @@ func (w *worker) dropReplica(ctx context.Context, hostToDrop *api.ChiHost, opts
 // deleteTables
 func (w *worker) deleteTables(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -455,7 +457,7 @@ func (w *worker) deleteTables(ctx context.Context, host *api.ChiHost) error {
 // chi is the new CHI in which there will be no more this host
 func (w *worker) deleteHost(ctx context.Context, chi *api.ClickHouseInstallation, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -517,7 +519,7 @@ func (w *worker) deleteHost(ctx context.Context, chi *api.ClickHouseInstallation
 // chi is the new CHI in which there will be no more this shard
 func (w *worker) deleteShard(ctx context.Context, chi *api.ClickHouseInstallation, shard *api.ChiShard) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -551,7 +553,7 @@ func (w *worker) deleteShard(ctx context.Context, chi *api.ClickHouseInstallatio
 // chi is the new CHI in which there will be no more this cluster
 func (w *worker) deleteCluster(ctx context.Context, chi *api.ClickHouseInstallation, cluster *api.Cluster) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -590,7 +592,7 @@ func (w *worker) deleteCluster(ctx context.Context, chi *api.ClickHouseInstallat
 // deleteCHI
 func (w *worker) deleteCHI(ctx context.Context, old, new *api.ClickHouseInstallation) bool {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return false
 	}
 
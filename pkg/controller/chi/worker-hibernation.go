@@ -0,0 +1,105 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processHibernationTransition reconciles CHI in order to put it to sleep or to wake it up by the hibernation schedule
+func (w *worker) processHibernationTransition(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.c.GetCHIByObjectMeta(cmd.chi, true)
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if model.IsReconcilePaused(chi) {
+		return nil
+	}
+
+	w.a.V(1).M(chi).F().Info("Hibernation schedule transition is due")
+	return w.reconcileCHI(ctx, nil, chi)
+}
+
+// applyHibernation stops normalized CHI in case it has to be hibernated at the moment and records hibernation state in status.
+// Hibernation reuses stop, so StatefulSets are scaled to zero and PVCs are kept
+func (w *worker) applyHibernation(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	prev := chi.EnsureStatus().GetHibernation()
+	if chi.IsStopped() || !model.HasHibernation(chi) {
+		// Explicitly stopped CHI is not hibernated, as well as CHI without schedule
+		if prev != nil {
+			chi.EnsureStatus().SetHibernation(nil)
+			w.updateHibernationStatus(ctx, chi)
+		}
+		return
+	}
+
+	now := time.Now()
+	if _, _, _, err := model.ParseHibernation(chi.Spec.Hibernation); err != nil {
+		w.a.V(1).M(chi).F().Warning("Hibernation schedule is ignored. Err: %v", err)
+	}
+
+	hibernation := &api.ChiHibernationStatus{
+		Hibernated: model.IsHibernationTime(chi, now),
+	}
+	sleep, wake := model.GetNextHibernationTransitions(chi, now)
+	if !sleep.IsZero() {
+		hibernation.NextSleep = sleep.UTC().Format(time.RFC3339)
+	}
+	if !wake.IsZero() {
+		hibernation.NextWake = wake.UTC().Format(time.RFC3339)
+	}
+	if hibernation.Hibernated {
+		chi.Spec.Stop = api.NewStringBool(true)
+	}
+	chi.EnsureStatus().SetHibernation(hibernation)
+
+	switch {
+	case hibernation.Hibernated && !prev.IsHibernated():
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonHibernated).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("CHI is hibernated till %s", hibernation.NextWake)
+	case !hibernation.Hibernated && prev.IsHibernated():
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonWokenUp).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("CHI is woken up till %s", hibernation.NextSleep)
+	}
+
+	w.updateHibernationStatus(ctx, chi)
+}
+
+// updateHibernationStatus writes hibernation state into CHI status
+func (w *worker) updateHibernationStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Hibernation: true,
+		},
+	})
+}
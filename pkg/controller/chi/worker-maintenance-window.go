@@ -0,0 +1,192 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processApplyDeferredChanges reconciles CHI in order to apply changes deferred till maintenance window
+func (w *worker) processApplyDeferredChanges(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.c.GetCHIByObjectMeta(cmd.chi, true)
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if model.IsReconcilePaused(chi) {
+		return nil
+	}
+
+	w.a.V(1).M(chi).F().Info("Maintenance window is open, apply deferred changes of hosts: %d", len(chi.EnsureStatus().GetDeferredHosts()))
+	return w.reconcileCHI(ctx, nil, chi)
+}
+
+// hasDeferredChangesDue checks whether CHI has deferred changes and maintenance window is open to apply them
+func (w *worker) hasDeferredChangesDue(chi *api.ClickHouseInstallation) bool {
+	return (len(chi.EnsureStatus().GetDeferredHosts()) > 0) && model.IsInMaintenanceWindow(chi, time.Now())
+}
+
+// deferHostChanges checks whether disruptive changes of the host have to wait for maintenance window.
+// Host, which changes are deferred, is recorded in CHI status
+func (w *worker) deferHostChanges(ctx context.Context, host *api.ChiHost) bool {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return false
+	}
+
+	chi := host.GetCHI()
+	now := time.Now()
+	if model.IsInMaintenanceWindow(chi, now) {
+		return false
+	}
+
+	reasons := w.getHostDisruptions(ctx, host)
+	prev := chi.EnsureStatus().GetDeferredHost(host.GetName())
+	if (len(reasons) == 0) && (prev == nil) {
+		return false
+	}
+
+	deferred := api.ChiDeferredHost{
+		Host:    host.GetName(),
+		Reasons: reasons,
+		Since:   now.UTC().Format(time.RFC3339),
+	}
+	if next := model.GetNextMaintenanceWindow(chi, now); !next.IsZero() {
+		deferred.NextWindow = next.UTC().Format(time.RFC3339)
+	}
+	chi.EnsureStatus().PushDeferredHost(deferred)
+
+	if cur := chi.EnsureStatus().GetDeferredHost(host.GetName()); (prev == nil) || (len(cur.Reasons) > len(prev.Reasons)) {
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonHostChangesDeferred).
+			WithStatusAction(chi).
+			M(host).F().
+			Info("Disruptive changes of host %s are deferred till maintenance window at %s. Reasons: %s",
+				host.GetName(), deferred.NextWindow, strings.Join(cur.Reasons, ","))
+	}
+
+	w.updateDeferredHostsStatus(ctx, chi)
+	return true
+}
+
+// getHostDisruptions lists reasons of the host to be restarted or re-created by the reconcile
+func (w *worker) getHostDisruptions(ctx context.Context, host *api.ChiHost) (reasons []string) {
+	switch {
+	case host.IsStopped() || (host.HasAncestor() && host.GetAncestor().IsStopped()):
+		// Stop and start are requested explicitly, there is nothing to disrupt
+		return nil
+	case host.GetReconcileAttributes().GetStatus() == api.ObjectStatusNew:
+		// New host is not running yet
+		return nil
+	}
+
+	if host.GetCHI().IsRollingUpdate() || w.isHostRestartRequested(host) ||
+		(host.HasAncestor() && w.isConfigurationChangeRequiresReboot(host)) {
+		reasons = append(reasons, api.DeferredReasonRestart)
+	}
+	if host.GetReconcileAttributes().GetStatus() == api.ObjectStatusModified {
+		reasons = append(reasons, api.DeferredReasonStatefulSet)
+	}
+	if w.hasHostStorageClassMigrations(ctx, host) {
+		reasons = append(reasons, api.DeferredReasonStorageClassMigration)
+	}
+	return reasons
+}
+
+// reconcileDeferredHost reconciles non-disruptive objects of the host, which disruptive changes are deferred
+func (w *worker) reconcileDeferredHost(ctx context.Context, host *api.ChiHost) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	// Config, which requires restart, is deferred along with the restart itself
+	if !host.GetCHI().EnsureStatus().GetDeferredHost(host.GetName()).HasReason(api.DeferredReasonRestart) {
+		if err := w.reconcileHostConfigMap(ctx, host); err != nil {
+			metricsHostReconcilesErrors(ctx, host.GetCHI())
+			w.a.V(1).
+				M(host).F().
+				Warning("Reconcile deferred Host interrupted with an error. Host: %s Err: %v", host.GetName(), err)
+			return err
+		}
+	}
+	_ = w.reconcileHostService(ctx, host)
+
+	w.a.V(1).
+		WithEvent(host.GetCHI(), eventActionReconcile, eventReasonReconcileCompleted).
+		WithStatusAction(host.GetCHI()).
+		M(host).F().
+		Info("Reconcile Host completed, disruptive changes are deferred. Host: %s", host.GetName())
+
+	host.GetCHI().EnsureStatus().HostCompleted()
+	_ = w.c.updateCHIObjectStatus(ctx, host.GetCHI(), UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			MainFields: true,
+		},
+	})
+	metricsHostReconcilesCompleted(ctx, host.GetCHI())
+	return nil
+}
+
+// completeHostDeferredChanges removes record of the host, which deferred changes are applied
+func (w *worker) completeHostDeferredChanges(ctx context.Context, host *api.ChiHost) {
+	chi := host.GetCHI()
+	if chi.EnsureStatus().GetDeferredHost(host.GetName()) == nil {
+		return
+	}
+
+	chi.EnsureStatus().RemoveDeferredHost(host.GetName())
+	w.a.V(1).
+		WithEvent(chi, eventActionReconcile, eventReasonHostDeferredChangesApplied).
+		WithStatusAction(chi).
+		M(host).F().
+		Info("Deferred changes of host %s are applied", host.GetName())
+	w.updateDeferredHostsStatus(ctx, chi)
+}
+
+// syncDeferredHosts removes records of the deferred hosts, which are not present in the CHI anymore
+func (w *worker) syncDeferredHosts(ctx context.Context, chi *api.ClickHouseInstallation) {
+	before := len(chi.EnsureStatus().GetDeferredHosts())
+	if before == 0 {
+		return
+	}
+
+	var hosts []string
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		hosts = append(hosts, host.GetName())
+		return nil
+	})
+	chi.EnsureStatus().SyncDeferredHosts(hosts)
+
+	if len(chi.EnsureStatus().GetDeferredHosts()) != before {
+		w.updateDeferredHostsStatus(ctx, chi)
+	}
+}
+
+// updateDeferredHostsStatus writes deferred hosts into CHI status
+func (w *worker) updateDeferredHostsStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			DeferredHosts: true,
+		},
+	})
+}
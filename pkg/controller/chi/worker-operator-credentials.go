@@ -0,0 +1,223 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	chiCreator "github.com/minorhacks/clickhouse-operator/pkg/model/chi/creator"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// ensureOperatorCredentials ensures generated operator credentials exist for the CHI
+func (w *worker) ensureOperatorCredentials(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	if !chop.Config().ClickHouse.Access.Generate.Enabled {
+		return nil
+	}
+	creds, err := w.c.fetchOperatorCredentials(chi)
+	if err != nil {
+		return err
+	}
+	if creds != nil {
+		// Already generated
+		return nil
+	}
+
+	creds = model.NewOperatorCredentials()
+	// Request present at the moment of generation is fulfilled by the generation itself
+	creds.RotationRequest = chi.GetAnnotations()[model.AnnotationRotateOperatorPassword]
+	if chi.HasAncestor() {
+		// CHI is already running with credentials from CHOp config.
+		// Roll generated credentials out the same way as rotation does, with CHOp config credentials as previous ones
+		creds.StartRotation(creds.RotationRequest)
+		creds.PreviousUsername = chop.Config().ClickHouse.Access.Username
+		creds.PreviousPassword = chop.Config().ClickHouse.Access.Password
+	}
+
+	w.a.V(1).M(chi).F().Info("Generate operator credentials for CHI: %s/%s", chi.Namespace, chi.Name)
+	return w.updateOperatorCredentials(ctx, chi, creds)
+}
+
+// updateOperatorCredentials writes operator credentials into the Secret
+func (w *worker) updateOperatorCredentials(ctx context.Context, chi *api.ClickHouseInstallation, creds *model.OperatorCredentials) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	secret := chiCreator.NewCreator(chi).CreateOperatorCredentialsSecret(creds)
+	_, err := w.c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, controller.NewUpdateOptions())
+	if apiErrors.IsNotFound(err) {
+		err = w.createSecret(ctx, chi, secret)
+	} else if err != nil {
+		w.a.WithEvent(chi, eventActionUpdate, eventReasonUpdateFailed).
+			WithStatusAction(chi).
+			WithStatusError(chi).
+			M(chi).F().
+			Error("Update Secret %s/%s failed with error %v", secret.Namespace, secret.Name, err)
+	}
+	return err
+}
+
+// processRotateOperatorCredentials processes rotation of generated operator credentials
+func (w *worker) processRotateOperatorCredentials(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.c.GetCHIByObjectMeta(cmd.chi, true)
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	// CHI may have been running before password generation was enabled
+	if err := w.ensureOperatorCredentials(ctx, chi); err != nil {
+		return err
+	}
+	chi, err = w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	return w.rotateOperatorCredentials(ctx, chi)
+}
+
+// rotateOperatorCredentials rotates generated operator credentials.
+// Rotation is done in phases, each phase is recorded in the Secret, so interrupted rotation is resumed later:
+// 1. New credentials are added to ClickHouse along with previous ones, connections still use previous credentials
+// 2. As soon as all hosts accept new credentials, connections are switched to new credentials
+// 3. Previous credentials are removed from ClickHouse
+func (w *worker) rotateOperatorCredentials(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	creds, err := w.c.fetchOperatorCredentials(chi)
+	if err != nil {
+		return err
+	}
+	if (creds == nil) || chi.IsStopped() {
+		return nil
+	}
+
+	w.a.V(1).M(chi).S().P()
+	defer w.a.V(1).M(chi).E().P()
+
+	switch creds.Phase {
+	case model.OperatorCredentialsPhaseStable:
+		request := chi.GetAnnotations()[model.AnnotationRotateOperatorPassword]
+		if !creds.IsRotationRequested(chop.Config().ClickHouse.Access.Generate.RotationPeriod, request) {
+			return nil
+		}
+		w.a.V(1).
+			WithEvent(chi, eventActionUpdate, eventReasonUpdateStarted).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("Rotate operator credentials for CHI: %s/%s", chi.Namespace, chi.Name)
+		creds.StartRotation(request)
+		if err := w.updateOperatorCredentials(ctx, chi, creds); err != nil {
+			return err
+		}
+		fallthrough
+
+	case model.OperatorCredentialsPhaseAdded:
+		// Add new credentials along with previous ones and wait for them to be accepted by all hosts
+		if err := w.reconcileUsersConfigMap(ctx, chi, creds); err != nil {
+			return err
+		}
+		if err := w.waitOperatorCredentialsAccepted(ctx, chi, creds); err != nil {
+			w.a.V(1).M(chi).F().Warning("New operator credentials are not accepted yet, will retry. CHI: %s/%s err: %v", chi.Namespace, chi.Name, err)
+			return nil
+		}
+		creds.SwitchRotation()
+		if err := w.updateOperatorCredentials(ctx, chi, creds); err != nil {
+			return err
+		}
+		fallthrough
+
+	case model.OperatorCredentialsPhaseSwitched:
+		// Connections use new credentials - let metrics exporter know about it as well
+		w.c.updateWatch(chi)
+		// Previous credentials are not used anymore and can be removed
+		creds.CompleteRotation()
+		if err := w.updateOperatorCredentials(ctx, chi, creds); err != nil {
+			return err
+		}
+		if err := w.reconcileUsersConfigMap(ctx, chi, creds); err != nil {
+			return err
+		}
+		w.a.V(1).
+			WithEvent(chi, eventActionUpdate, eventReasonUpdateCompleted).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("Rotate operator credentials completed for CHI: %s/%s", chi.Namespace, chi.Name)
+	}
+
+	return nil
+}
+
+// reconcileUsersConfigMap reconciles users ConfigMap of the CHI with specified operator credentials.
+// Nil credentials mean the current ones are used
+func (w *worker) reconcileUsersConfigMap(ctx context.Context, chi *api.ClickHouseInstallation, creds *model.OperatorCredentials) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	opts := normalizer.NewOptions()
+	opts.DefaultUserAdditionalIPs = w.c.getPodsIPs(chi)
+	opts.OperatorCredentials = creds
+	chi, err := w.createCHIFromObjectMeta(&chi.ObjectMeta, true, opts)
+	if err != nil {
+		return err
+	}
+
+	w.newTask(chi)
+	return w.reconcileCHIConfigMapUsers(ctx, chi)
+}
+
+// waitOperatorCredentialsAccepted waits for new operator credentials to be accepted by all hosts of the CHI
+func (w *worker) waitOperatorCredentialsAccepted(ctx context.Context, chi *api.ClickHouseInstallation, creds *model.OperatorCredentials) error {
+	var errs []error
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if len(errs) > 0 {
+			// No need to wait for the rest of the hosts
+			return nil
+		}
+		err := w.c.pollHost(ctx, host, nil, func(ctx context.Context, host *api.ChiHost) bool {
+			_, err := w.newClusterSchemer(host, creds.Username, creds.Password).HostClickHouseVersion(ctx, host)
+			return err == nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host %s: %w", host.GetName(), err))
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
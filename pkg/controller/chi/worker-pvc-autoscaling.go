@@ -0,0 +1,225 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processCheckPVCAutoscaling processes PVC autoscaling check
+func (w *worker) processCheckPVCAutoscaling(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	return w.checkPVCAutoscaling(ctx, chi)
+}
+
+// checkPVCAutoscaling checks disk usage of all hosts of the CHI and expands PVCs,
+// which have autoscaling enabled and utilization above the threshold
+func (w *worker) checkPVCAutoscaling(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	if chi.IsStopped() {
+		return nil
+	}
+
+	w.a.V(2).M(chi).S().P()
+	defer w.a.V(2).M(chi).E().P()
+
+	autoscaling := &api.ChiPVCAutoscaling{
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+		Expansions: chi.EnsureStatus().GetPVCAutoscaling().GetExpansions(),
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		w.checkHostPVCAutoscaling(ctx, host, autoscaling)
+		return nil
+	})
+
+	chi.EnsureStatus().SetPVCAutoscaling(autoscaling)
+	return w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			PVCAutoscaling: true,
+		},
+	})
+}
+
+// checkHostPVCAutoscaling checks disk usage of the host and expands its PVCs, if required.
+// Expansions are recorded into autoscaling status
+func (w *worker) checkHostPVCAutoscaling(ctx context.Context, host *api.ChiHost, autoscaling *api.ChiPVCAutoscaling) {
+	pod, err := w.c.getPod(host)
+	if err != nil {
+		w.a.V(1).M(host).F().Info("Unable to get pod of the host %s err: %v", host.GetName(), err)
+		return
+	}
+
+	// Volume mounts of the PVCs with autoscaling enabled, mapped by mount path
+	mounts := make(map[string]*core.VolumeMount)
+	var mountPaths []string
+	for i := range pod.Spec.Containers {
+		for j := range pod.Spec.Containers[i].VolumeMounts {
+			volumeMount := &pod.Spec.Containers[i].VolumeMounts[j]
+			if template, ok := model.GetVolumeClaimTemplate(host, volumeMount); ok && template.Autoscaling.IsEnabled() {
+				if _, found := mounts[volumeMount.MountPath]; !found {
+					mounts[volumeMount.MountPath] = volumeMount
+					mountPaths = append(mountPaths, volumeMount.MountPath)
+				}
+			}
+		}
+	}
+	if len(mounts) == 0 {
+		return
+	}
+
+	disks, err := w.ensureClusterSchemer(host).HostDisks(ctx, host)
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("Unable to get disks usage of the host %s err: %v", host.GetName(), err)
+		return
+	}
+
+	for mountPath, utilization := range model.GetMountsUtilization(disks, mountPaths) {
+		expansion, ok := w.expandPVC(ctx, host, mounts[mountPath], utilization)
+		if !ok || isPVCExpansionRepeated(autoscaling, expansion) {
+			continue
+		}
+		if expansion.Error != "" {
+			w.a.WithEvent(host.GetCHI(), eventActionUpdate, eventReasonPVCExpansionFailed).
+				M(host).F().
+				Warning("Unable to expand PVC %s of %s with utilization %d%% err: %s", expansion.PVC, expansion.From, expansion.Utilization, expansion.Error)
+		}
+		autoscaling.PushExpansion(*expansion)
+	}
+}
+
+// expandPVC expands PVC mounted by the volume mount in case utilization exceeds autoscaling threshold.
+// Returns expansion made or attempted, if any
+func (w *worker) expandPVC(
+	ctx context.Context,
+	host *api.ChiHost,
+	volumeMount *core.VolumeMount,
+	utilization int,
+) (*api.ChiPVCExpansion, bool) {
+	template, _ := model.GetVolumeClaimTemplate(host, volumeMount)
+	pvcName, _ := model.CreatePVCNameByVolumeMount(host, volumeMount)
+	namespace := host.Runtime.Address.Namespace
+
+	pvc, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, controller.NewGetOptions())
+	if err != nil {
+		w.a.V(1).M(host).F().Warning("Unable to get PVC %s/%s err: %v", namespace, pvcName, err)
+		return nil, false
+	}
+
+	current := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	if capacity, ok := pvc.Status.Capacity[core.ResourceStorage]; ok && (capacity.Cmp(current) < 0) {
+		// Previous expansion is still in progress
+		w.a.V(1).M(host).F().Info("PVC %s/%s is being expanded to %s. Skip", namespace, pvcName, current.String())
+		return nil, false
+	}
+
+	size, expand, err := model.ComputePVCExpansion(template.Autoscaling, utilization, current)
+	if !expand && (err == nil) {
+		return nil, false
+	}
+
+	expansion := &api.ChiPVCExpansion{
+		Time:        time.Now().UTC().Format(time.RFC3339),
+		Host:        host.GetName(),
+		PVC:         pvcName,
+		Utilization: utilization,
+		From:        current.String(),
+	}
+	if err == nil {
+		err = w.isPVCExpansionAllowed(ctx, pvc)
+	}
+	if err == nil {
+		pvc.Spec.Resources.Requests[core.ResourceStorage] = size
+		_, err = w.c.updatePersistentVolumeClaim(ctx, pvc)
+	}
+
+	if err != nil {
+		// Failure is reported by the caller, since the same failure is likely to repeat on each check
+		expansion.Error = err.Error()
+		return expansion, true
+	}
+
+	expansion.To = size.String()
+	w.a.WithEvent(host.GetCHI(), eventActionUpdate, eventReasonPVCExpanded).
+		M(host).F().
+		Info("Expand PVC %s/%s with utilization %d%% from %s to %s", namespace, pvcName, utilization, expansion.From, expansion.To)
+	return expansion, true
+}
+
+// isPVCExpansionAllowed checks whether StorageClass of the PVC allows volume expansion
+func (w *worker) isPVCExpansionAllowed(ctx context.Context, pvc *core.PersistentVolumeClaim) error {
+	if (pvc.Spec.StorageClassName == nil) || (*pvc.Spec.StorageClassName == "") {
+		return fmt.Errorf("PVC has no StorageClass")
+	}
+	storageClass, err := w.c.kubeClient.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, controller.NewGetOptions())
+	if err != nil {
+		return err
+	}
+	if (storageClass.AllowVolumeExpansion == nil) || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf("StorageClass %s does not allow volume expansion", storageClass.Name)
+	}
+	return nil
+}
+
+// isPVCExpansionRepeated checks whether the same failed expansion of the PVC is the latest one recorded already.
+// Used to not flood the history with the same failure on each check
+func isPVCExpansionRepeated(autoscaling *api.ChiPVCAutoscaling, expansion *api.ChiPVCExpansion) bool {
+	if expansion.Error == "" {
+		return false
+	}
+	expansions := autoscaling.GetExpansions()
+	for i := len(expansions) - 1; i >= 0; i-- {
+		if expansions[i].PVC == expansion.PVC {
+			return (expansions[i].From == expansion.From) && (expansions[i].Error == expansion.Error)
+		}
+	}
+	return false
+}
+
+// keepExpandedPVCStorage prevents PVC expanded by autoscaling from being shrunk back to the size specified in the template
+func (w *worker) keepExpandedPVCStorage(
+	pvc *core.PersistentVolumeClaim,
+	template *api.VolumeClaimTemplate,
+) core.ResourceList {
+	desired := template.Spec.Resources.Requests
+	if (desired == nil) || !template.Autoscaling.IsEnabled() {
+		return desired
+	}
+	current, ok := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	if !ok || (current.Cmp(desired[core.ResourceStorage]) <= 0) {
+		return desired
+	}
+	desired = desired.DeepCopy()
+	desired[core.ResourceStorage] = current
+	return desired
+}
@@ -0,0 +1,321 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processCheckReplicationHealth processes replication health check
+func (w *worker) processCheckReplicationHealth(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if chop.Config().ClickHouse.ReplicationHealth.Enabled && !w.c.startReplicationHealthCheck(chi) {
+		// Check may be enqueued a number of times, while status with the time of the previous check may be not updated
+		w.a.V(2).M(chi).F().Info("replication health has been checked recently, skip. CHI: %s/%s", chi.Namespace, chi.Name)
+		return nil
+	}
+	return w.checkReplicationHealth(ctx, chi)
+}
+
+// startReplicationHealthCheck records start of the replication health check of the CHI.
+// Returns false in case the previous check has been made less than the minimal interval ago
+func (c *Controller) startReplicationHealthCheck(chi *api.ClickHouseInstallation) bool {
+	key := chi.Namespace + "/" + chi.Name
+	now := time.Now()
+	last := c.replicationHealthChecks.get(key)
+	if health := chi.Status.GetReplicationHealth(); health != nil {
+		if checkedAt, err := time.Parse(time.RFC3339, health.CheckedAt); (err == nil) && checkedAt.After(last) {
+			last = checkedAt
+		}
+	}
+	if now.Sub(last) < chop.Config().ClickHouse.ReplicationHealth.MinInterval {
+		return false
+	}
+	c.replicationHealthChecks.set(key, now)
+	return true
+}
+
+// replicationHealthChecks specifies when replication health of CHIs has been checked, keyed by namespace/name.
+// Status of the CHI keeps the time of the check as well, however its update may fail or be not observed yet
+type replicationHealthChecks struct {
+	sync.Mutex
+	checkedAt map[string]time.Time
+}
+
+// get gets time of the last check of the CHI
+func (c *replicationHealthChecks) get(key string) time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.checkedAt[key]
+}
+
+// set sets time of the last check of the CHI
+func (c *replicationHealthChecks) set(key string, checkedAt time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	if c.checkedAt == nil {
+		c.checkedAt = make(map[string]time.Time)
+	}
+	c.checkedAt[key] = checkedAt
+}
+
+// forget drops time of the last check of the CHI
+func (c *replicationHealthChecks) forget(key string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.checkedAt, key)
+}
+
+// checkReplicationHealth checks replicated tables on all hosts of the CHI and applies remediations to unhealthy replicas.
+// Hosts with lagging replicas may be excluded from the cluster, they are included back as soon as they catch up
+func (w *worker) checkReplicationHealth(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	if chi.IsStopped() {
+		return nil
+	}
+
+	w.a.V(2).M(chi).S().P()
+	defer w.a.V(2).M(chi).E().P()
+
+	prev := chi.EnsureStatus().GetReplicationHealth()
+	var health *api.ChiReplicationHealth
+	var excluded, included []*api.ChiHost
+
+	if chop.Config().ClickHouse.ReplicationHealth.Enabled {
+		health = &api.ChiReplicationHealth{
+			CheckedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		chi.WalkShards(func(shard *api.ChiShard) error {
+			var states []replicationHealthHost
+			hosts := make(map[string]*api.ChiHost)
+			shard.WalkHosts(func(host *api.ChiHost) error {
+				name := model.CreatePodHostname(host)
+				replicas, exclude, err := w.checkHostReplicationHealth(ctx, host)
+				states = append(states, replicationHealthHost{
+					name:        name,
+					unavailable: err != nil,
+					unhealthy:   exclude,
+				})
+				hosts[name] = host
+				health.Replicas = append(health.Replicas, replicas...)
+				return nil
+			})
+			excludedHosts, exclude, include := decideShardExclusion(states, prev)
+			health.ExcludedHosts = append(health.ExcludedHosts, excludedHosts...)
+			for _, name := range exclude {
+				excluded = append(excluded, hosts[name])
+			}
+			for _, name := range include {
+				included = append(included, hosts[name])
+			}
+			return nil
+		})
+	} else {
+		// Check is disabled, thus hosts excluded previously have to be included back
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			if prev.IsHostExcluded(model.CreatePodHostname(host)) {
+				included = append(included, host)
+			}
+			return nil
+		})
+	}
+
+	for _, replica := range health.GetReplicas() {
+		w.a.WithEvent(chi, eventActionReconcile, eventReasonReplicaUnhealthy).
+			M(chi).F().
+			Warning("Unhealthy replica. Host: %s table: %s problem: %s remediation: %s %s",
+				replica.Host, replica.Table, replica.Problem, replica.Remediation, replica.Error)
+	}
+
+	// Status is the source of excluded hosts for ClickHouse config, thus it has to be set in advance
+	chi.EnsureStatus().SetReplicationHealth(health)
+	if (len(excluded) > 0) || (len(included) > 0) {
+		w.newTask(chi)
+	}
+	for _, host := range excluded {
+		w.a.V(1).
+			WithEvent(chi, eventActionUpdate, eventReasonUpdateStarted).
+			M(chi).F().
+			Warning("Exclude host %s from cluster %s until replicas are healthy", host.GetName(), host.Runtime.Address.ClusterName)
+		w.excludeHostFromClickHouseCluster(ctx, host)
+	}
+	for _, host := range included {
+		w.a.V(1).
+			WithEvent(chi, eventActionUpdate, eventReasonUpdateCompleted).
+			M(chi).F().
+			Info("Include host %s into cluster %s, replicas are healthy", host.GetName(), host.Runtime.Address.ClusterName)
+		w.includeHostIntoClickHouseCluster(ctx, host)
+	}
+
+	return w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			ReplicationHealth: true,
+		},
+	})
+}
+
+// replicationHealthHost specifies result of the replication health check of one host of a shard
+type replicationHealthHost struct {
+	name string
+	// unavailable specifies host, which replicas can not be checked
+	unavailable bool
+	// unhealthy specifies host, which replicas require the host to be excluded from the cluster
+	unhealthy bool
+}
+
+// decideShardExclusion decides which hosts of the shard are to be excluded from the cluster.
+// Returns names of all hosts to be kept out of the cluster, as well as names of hosts to be excluded and included back right now.
+// Shard has to have at least one host in the cluster, thus the last available host is never excluded.
+// Host, which is not available, is not a replication problem, thus it is kept as it is
+func decideShardExclusion(hosts []replicationHealthHost, prev *api.ChiReplicationHealth) (excludedHosts, exclude, include []string) {
+	var candidates []string
+	available := 0
+	for _, host := range hosts {
+		switch {
+		case host.unavailable:
+			if prev.IsHostExcluded(host.name) {
+				excludedHosts = append(excludedHosts, host.name)
+			}
+		case host.unhealthy:
+			candidates = append(candidates, host.name)
+		case prev.IsHostExcluded(host.name):
+			include = append(include, host.name)
+			available++
+		default:
+			available++
+		}
+	}
+	for _, name := range candidates {
+		if !prev.IsHostExcluded(name) {
+			if available == 0 {
+				// The last host is kept in the cluster
+				available++
+				continue
+			}
+			exclude = append(exclude, name)
+		}
+		excludedHosts = append(excludedHosts, name)
+	}
+	return excludedHosts, exclude, include
+}
+
+// checkHostReplicationHealth checks replicated tables of the host and applies remediations to unhealthy replicas.
+// Returns unhealthy replicas and whether the host is to be excluded from the cluster
+func (w *worker) checkHostReplicationHealth(
+	ctx context.Context,
+	host *api.ChiHost,
+) (unhealthy []api.ChiReplicaHealth, exclude bool, err error) {
+	s := w.ensureClusterSchemer(host)
+	replicas, err := s.HostReplicas(ctx, host)
+	if err != nil {
+		return nil, false, err
+	}
+
+	config := chop.Config().ClickHouse.ReplicationHealth
+	for _, replica := range replicas {
+		var problem, remediation string
+		switch {
+		case replica.IsReadOnly:
+			problem = api.ReplicationProblemReadOnly
+			remediation = config.Remediations.ReadOnly
+			if exists, err := s.HostReplicaMetadataExists(ctx, host, replica); (err == nil) && !exists {
+				problem = api.ReplicationProblemLostMetadata
+				remediation = config.Remediations.LostMetadata
+			}
+		case (replica.QueueSize > config.Thresholds.QueueSize) || (replica.AbsoluteDelay > config.Thresholds.AbsoluteDelay):
+			problem = api.ReplicationProblemLagging
+			remediation = config.Remediations.Lagging
+		default:
+			continue
+		}
+
+		entry := api.ChiReplicaHealth{
+			Host:        model.CreatePodHostname(host),
+			Table:       replica.GetName(),
+			Problem:     problem,
+			Remediation: remediation,
+		}
+		if err := w.remediateReplica(ctx, s, host, replica, remediation); err != nil {
+			entry.Error = err.Error()
+		}
+		if remediation == api.ReplicationRemediationExcludeHost {
+			exclude = true
+		}
+		unhealthy = append(unhealthy, entry)
+	}
+
+	return unhealthy, exclude, nil
+}
+
+// remediateReplica applies table-level remediation to the unhealthy replica.
+// Host-level remediations are applied by the caller
+func (w *worker) remediateReplica(
+	ctx context.Context,
+	s *schemer.ClusterSchemer,
+	host *api.ChiHost,
+	replica *schemer.ReplicaState,
+	remediation string,
+) error {
+	switch remediation {
+	case api.ReplicationRemediationRestartReplica:
+		return s.HostRestartReplica(ctx, host, replica)
+	case api.ReplicationRemediationRestoreReplica:
+		return s.HostRestoreReplica(ctx, host, replica)
+	}
+	return nil
+}
+
+// excludeUnhealthyHosts excludes hosts, which are excluded due to unhealthy replicas, from ClickHouse config
+func (w *worker) excludeUnhealthyHosts(
+	chi *api.ClickHouseInstallation,
+	options *model.ClickHouseConfigFilesGeneratorOptions,
+) *model.ClickHouseConfigFilesGeneratorOptions {
+	health := chi.EnsureStatus().GetReplicationHealth()
+	if len(health.GetExcludedHosts()) == 0 {
+		return options
+	}
+
+	if options == nil {
+		options = model.NewClickHouseConfigFilesGeneratorOptions()
+	}
+	if options.GetRemoteServersGeneratorOptions() == nil {
+		options.SetRemoteServersGeneratorOptions(model.NewRemoteServersGeneratorOptions())
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if health.IsHostExcluded(model.CreatePodHostname(host)) {
+			options.GetRemoteServersGeneratorOptions().ExcludeHost(host)
+		}
+		return nil
+	})
+	return options
+}
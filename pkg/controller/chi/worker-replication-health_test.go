@@ -0,0 +1,168 @@
+package chi
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+)
+
+var initTestCHOpOnce sync.Once
+
+// initTestCHOp initializes operator with the default config
+func initTestCHOp() {
+	initTestCHOpOnce.Do(func() {
+		chop.New(nil, nil, "../../../config/config.yaml")
+	})
+}
+
+func TestDecideShardExclusion(t *testing.T) {
+	tests := []struct {
+		name     string
+		hosts    []replicationHealthHost
+		excluded []string
+		// expected results
+		excludedHosts []string
+		exclude       []string
+		include       []string
+	}{
+		{
+			name: "healthy shard",
+			hosts: []replicationHealthHost{
+				{name: "a"},
+				{name: "b"},
+			},
+		},
+		{
+			name: "unhealthy host is excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+				{name: "b"},
+			},
+			excludedHosts: []string{"a"},
+			exclude:       []string{"a"},
+		},
+		{
+			name: "last healthy replica is not excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+				{name: "b", unhealthy: true},
+			},
+			excludedHosts: []string{"b"},
+			exclude:       []string{"b"},
+		},
+		{
+			name: "single host shard is not excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+			},
+		},
+		{
+			name: "unavailable hosts are not counted as available",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+				{name: "b", unavailable: true},
+			},
+		},
+		{
+			name: "the only host left in the cluster becomes unhealthy",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+				{name: "b", unhealthy: true},
+			},
+			excluded:      []string{"a"},
+			excludedHosts: []string{"a"},
+		},
+		{
+			name: "unhealthy excluded host is kept excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unhealthy: true},
+				{name: "b"},
+			},
+			excluded:      []string{"a"},
+			excludedHosts: []string{"a"},
+		},
+		{
+			name: "unavailable excluded host is kept excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unavailable: true},
+				{name: "b"},
+			},
+			excluded:      []string{"a"},
+			excludedHosts: []string{"a"},
+		},
+		{
+			name: "unavailable host is not excluded",
+			hosts: []replicationHealthHost{
+				{name: "a", unavailable: true},
+				{name: "b"},
+			},
+		},
+		{
+			name: "healthy excluded host is included back",
+			hosts: []replicationHealthHost{
+				{name: "a"},
+				{name: "b"},
+			},
+			excluded: []string{"a"},
+			include:  []string{"a"},
+		},
+		{
+			name: "host included back makes room for exclusion of another one",
+			hosts: []replicationHealthHost{
+				{name: "a"},
+				{name: "b", unhealthy: true},
+			},
+			excluded:      []string{"a"},
+			excludedHosts: []string{"b"},
+			exclude:       []string{"b"},
+			include:       []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := &api.ChiReplicationHealth{
+				ExcludedHosts: tt.excluded,
+			}
+			excludedHosts, exclude, include := decideShardExclusion(tt.hosts, prev)
+			require.Equal(t, tt.excludedHosts, excludedHosts)
+			require.Equal(t, tt.exclude, exclude)
+			require.Equal(t, tt.include, include)
+		})
+	}
+}
+
+func TestStartReplicationHealthCheck(t *testing.T) {
+	initTestCHOp()
+	minInterval := chop.Config().ClickHouse.ReplicationHealth.MinInterval
+	require.True(t, minInterval > 0)
+
+	newCHI := func(checkedAt time.Time) *api.ClickHouseInstallation {
+		chi := &api.ClickHouseInstallation{}
+		chi.Namespace = "ns"
+		chi.Name = "chi"
+		if !checkedAt.IsZero() {
+			chi.EnsureStatus().SetReplicationHealth(&api.ChiReplicationHealth{
+				CheckedAt: checkedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		return chi
+	}
+
+	c := &Controller{}
+	// Never checked before
+	require.True(t, c.startReplicationHealthCheck(newCHI(time.Time{})))
+	// Checked just now, while status is not updated yet
+	require.False(t, c.startReplicationHealthCheck(newCHI(time.Time{})))
+	c.replicationHealthChecks.forget("ns/chi")
+
+	// Status of the CHI is taken into account as well, e.g. after restart of the operator
+	require.False(t, c.startReplicationHealthCheck(newCHI(time.Now())))
+	require.True(t, c.startReplicationHealthCheck(newCHI(time.Now().Add(-2*minInterval))))
+	require.False(t, c.startReplicationHealthCheck(newCHI(time.Now().Add(-2*minInterval))))
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// startRestartRequests acknowledges restart requests, which are not acknowledged yet, in CHI status.
+// Hosts addressed by the acknowledged request are restarted by the reconcile one by one, exactly once
+func (w *worker) startRestartRequests(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	changed := false
+
+	// Forget requests, which are removed from the spec, so the same ID can be reused later
+	var ids []string
+	for i := range chi.Spec.RestartRequests {
+		ids = append(ids, chi.Spec.RestartRequests[i].ID)
+	}
+	if before := len(chi.EnsureStatus().GetRestartRequests()); before > 0 {
+		chi.EnsureStatus().SyncRestartRequests(ids)
+		changed = len(chi.EnsureStatus().GetRestartRequests()) != before
+	}
+
+	for i := range chi.Spec.RestartRequests {
+		request := &chi.Spec.RestartRequests[i]
+		if request.ID == "" {
+			w.a.V(1).M(chi).F().Warning("Restart request without ID is skipped: %+v", *request)
+			continue
+		}
+		if chi.EnsureStatus().GetRestartRequest(request.ID) != nil {
+			// Request is acknowledged already
+			continue
+		}
+
+		status := api.ChiRestartRequestStatus{
+			ID:        request.ID,
+			StartedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		chi.WalkHosts(func(host *api.ChiHost) error {
+			if request.IsHostAddressed(&host.Runtime.Address) {
+				status.Hosts = append(status.Hosts, host.GetName())
+			}
+			return nil
+		})
+
+		if len(status.Hosts) == 0 {
+			status.Phase = api.RestartRequestPhaseFailed
+			status.FinishedAt = status.StartedAt
+			status.Error = "request does not address any host"
+			w.a.V(1).
+				WithEvent(chi, eventActionReconcile, eventReasonRestartRequestFailed).
+				WithStatusAction(chi).
+				M(chi).F().
+				Warning("Restart request %s does not address any host", request.ID)
+		} else {
+			status.Phase = api.RestartRequestPhaseInProgress
+			w.a.V(1).
+				WithEvent(chi, eventActionReconcile, eventReasonRestartRequestStarted).
+				WithStatusAction(chi).
+				M(chi).F().
+				Info("Restart request %s started. Hosts: %s", request.ID, strings.Join(status.Hosts, ","))
+		}
+
+		chi.EnsureStatus().PushRestartRequest(status)
+		changed = true
+	}
+
+	if changed {
+		w.updateRestartRequestsStatus(ctx, chi)
+	}
+}
+
+// isHostRestartRequested checks whether host is addressed by the restart request, which has not restarted it yet
+func (w *worker) isHostRestartRequested(host *api.ChiHost) bool {
+	requests := host.GetCHI().EnsureStatus().GetRestartRequests()
+	for i := range requests {
+		if requests[i].IsHostPending(host.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// completeHostRestartRequests marks host as restarted in all restart requests addressing it
+func (w *worker) completeHostRestartRequests(ctx context.Context, host *api.ChiHost) {
+	chi := host.GetCHI()
+	changed := false
+
+	for _, request := range chi.EnsureStatus().GetRestartRequests() {
+		updated := chi.EnsureStatus().MarkRestartRequestHost(request.ID, host.GetName())
+		if updated == nil {
+			continue
+		}
+		changed = true
+
+		if updated.Phase == api.RestartRequestPhaseCompleted {
+			w.a.V(1).
+				WithEvent(chi, eventActionReconcile, eventReasonRestartRequestCompleted).
+				WithStatusAction(chi).
+				M(host).F().
+				Info("Restart request %s completed. Hosts restarted: %d", updated.ID, len(updated.RestartedHosts))
+		}
+	}
+
+	if changed {
+		w.updateRestartRequestsStatus(ctx, chi)
+	}
+}
+
+// updateRestartRequestsStatus writes restart requests into CHI status
+func (w *worker) updateRestartRequestsStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			RestartRequests: true,
+		},
+	})
+}
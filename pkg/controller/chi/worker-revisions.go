@@ -0,0 +1,157 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// recordRevision persists spec supplied by the user, which the completed CHI has been normalized from, as a new revision,
+// in case it differs from the latest one.
+// Revisions pushed out of the history are deleted. Revision is recorded into CHI status, which is to be written by the caller
+func (w *worker) recordRevision(ctx context.Context, chi *api.ClickHouseInstallation, userSpec *api.ChiSpec) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	completed := chi.GetAncestor()
+	if completed == nil {
+		return
+	}
+
+	spec, hash, err := model.NewRevisionSpec(userSpec)
+	if err != nil {
+		w.a.V(1).M(chi).F().Error("Unable to build revision of CHI: %s/%s err: %v", chi.Namespace, chi.Name, err)
+		return
+	}
+
+	number := 1
+	if latest := chi.EnsureStatus().GetLatestRevision(); latest != nil {
+		if latest.Hash == hash {
+			// Spec has not changed since the latest revision
+			return
+		}
+		number = latest.Revision + 1
+	}
+
+	configMap := w.task.creator.CreateConfigMapCHIRevision(number, spec)
+	configMaps := w.c.kubeClient.CoreV1().ConfigMaps(configMap.Namespace)
+	_, err = configMaps.Create(ctx, configMap, controller.NewCreateOptions())
+	if apiErrors.IsAlreadyExists(err) {
+		// Leftover of the history, which has been lost along with the status
+		_, err = configMaps.Update(ctx, configMap, controller.NewUpdateOptions())
+	}
+	if err != nil {
+		w.a.V(1).M(chi).F().Error("Unable to persist revision %d of CHI: %s/%s err: %v", number, chi.Namespace, chi.Name, err)
+		return
+	}
+
+	pushedOut := chi.EnsureStatus().PushRevision(api.ChiRevision{
+		Revision:    number,
+		Generation:  completed.Generation,
+		TaskID:      completed.Spec.GetTaskID(),
+		CompletedAt: time.Now().UTC().Format(time.RFC3339),
+		ConfigMap:   configMap.Name,
+		Hash:        hash,
+	}, chop.Config().Reconcile.History.Revisions)
+	w.a.V(1).M(chi).F().Info("Revision %d of CHI: %s/%s recorded in ConfigMap: %s", number, chi.Namespace, chi.Name, configMap.Name)
+
+	for i := range pushedOut {
+		err := configMaps.Delete(ctx, pushedOut[i].ConfigMap, controller.NewDeleteOptions())
+		if err != nil && !apiErrors.IsNotFound(err) {
+			w.a.V(1).M(chi).F().Error("Unable to delete revision ConfigMap: %s/%s err: %v", chi.Namespace, pushedOut[i].ConfigMap, err)
+		}
+	}
+}
+
+// registerRevisions registers revision ConfigMaps as reconciled, thus they are not purged as unknown objects
+func (w *worker) registerRevisions(chi *api.ClickHouseInstallation) {
+	revisions := chi.EnsureStatus().GetRevisions()
+	for i := range revisions {
+		configMap := w.task.creator.CreateConfigMapCHIRevision(revisions[i].Revision, "")
+		w.task.registryReconciled.RegisterConfigMap(configMap.ObjectMeta)
+	}
+}
+
+// rollbackCHI writes spec of the revision rollback is requested to into the CHI object.
+// Restored spec is reconciled then as any other change of the CHI
+func (w *worker) rollbackCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	// Rollback is applied to the latest state of the object, thus changes made meanwhile are not overwritten silently
+	cur, err := w.c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Get(ctx, chi.Name, controller.NewGetOptions())
+	if err != nil {
+		w.a.V(1).M(chi).F().Error("Unable to get CHI: %s/%s err: %v", chi.Namespace, chi.Name, err)
+		return err
+	}
+	if !cur.Spec.HasRollbackTo() {
+		// Rollback request has been withdrawn meanwhile
+		return nil
+	}
+
+	revision := cur.Spec.GetRollbackTo()
+	if err := w.doRollbackCHI(ctx, cur, revision); err != nil {
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonRollbackFailed).
+			WithStatusError(chi).
+			M(chi).F().
+			Error("Rollback to revision %d FAILED, remove .spec.rollbackTo to resume reconcile. err: %v", revision, err)
+		return nil
+	}
+
+	w.a.V(1).
+		WithEvent(chi, eventActionReconcile, eventReasonRollbackStarted).
+		WithStatusAction(chi).
+		M(chi).F().
+		Info("Rollback to revision %d started, spec of the revision is restored", revision)
+	return nil
+}
+
+// doRollbackCHI replaces spec of the current CHI object with the spec of the revision
+func (w *worker) doRollbackCHI(ctx context.Context, cur *api.ClickHouseInstallation, revision int) error {
+	rev := cur.EnsureStatus().GetRevision(revision)
+	if rev == nil {
+		return fmt.Errorf("revision %d is not found in status", revision)
+	}
+
+	configMap, err := w.c.kubeClient.CoreV1().ConfigMaps(cur.Namespace).Get(ctx, rev.ConfigMap, controller.NewGetOptions())
+	if err != nil {
+		return err
+	}
+	spec, err := model.ParseRevisionSpec(configMap)
+	if err != nil {
+		return err
+	}
+
+	// Spec of the revision has neither task ID nor rollback request, thus it is reconciled as a new task
+	cur.Spec = *spec
+	_, err = w.c.chopClient.ClickhouseV1().ClickHouseInstallations(cur.Namespace).Update(ctx, cur, controller.NewUpdateOptions())
+	return err
+}
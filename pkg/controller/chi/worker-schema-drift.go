@@ -0,0 +1,143 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processCheckSchemaDrift processes schema drift check
+func (w *worker) processCheckSchemaDrift(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	return w.checkSchemaDrift(ctx, chi)
+}
+
+// checkSchemaDrift compares tables schema across all hosts of each cluster of the CHI.
+// Results are reported in the CHI status, via Warning events and via metrics
+func (w *worker) checkSchemaDrift(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	if chi.IsStopped() {
+		return nil
+	}
+
+	w.a.V(2).M(chi).S().P()
+	defer w.a.V(2).M(chi).E().P()
+
+	drift := &api.ChiSchemaDrift{
+		CheckedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	counts := make(map[string]int)
+	var errs []string
+	chi.WalkClusters(func(cluster *api.Cluster) error {
+		tables, err := w.checkClusterSchemaDrift(ctx, cluster)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %s: %v", cluster.Name, err))
+			return nil
+		}
+		counts[cluster.Name] = len(tables)
+		drift.Tables = append(drift.Tables, tables...)
+		return nil
+	})
+	drift.Error = strings.Join(errs, "; ")
+
+	// Events are emitted on change of the drift state only, otherwise each check would repeat them
+	prev := w.getReportedSchemaDrift(chi)
+	for _, table := range drift.Tables {
+		if hasSchemaDriftTable(prev, table) {
+			continue
+		}
+		w.a.WithEvent(chi, eventActionReconcile, eventReasonSchemaDriftDetected).
+			M(chi).F().
+			Warning("Schema drift detected. Cluster: %s host: %s table: %s is %s", table.Cluster, table.Host, table.Table, strings.ToLower(table.Kind))
+	}
+	if drift.Error != "" {
+		w.a.V(1).M(chi).F().Warning("Schema drift check failed for CHI: %s/%s err: %s", chi.Namespace, chi.Name, drift.Error)
+	}
+	for _, table := range prev {
+		if _, checked := counts[table.Cluster]; !checked || hasSchemaDriftTable(drift.Tables, table) {
+			// Cluster was not checked this time or drift is still there
+			continue
+		}
+		w.a.WithEvent(chi, eventActionReconcile, eventReasonSchemaDriftResolved).
+			M(chi).F().
+			Info("Schema drift resolved. Cluster: %s host: %s table: %s", table.Cluster, table.Host, table.Table)
+	}
+	metricsSchemaDrift(chi, counts)
+
+	chi.EnsureStatus().SetSchemaDrift(drift)
+	return w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			SchemaDrift: true,
+		},
+	})
+}
+
+// getReportedSchemaDrift gets drifted tables reported by the previous check
+func (w *worker) getReportedSchemaDrift(chi *api.ClickHouseInstallation) []api.ChiSchemaDriftTable {
+	cur, err := w.c.GetCHIByObjectMeta(&chi.ObjectMeta, true)
+	if err != nil {
+		return nil
+	}
+	if drift := cur.EnsureStatus().GetSchemaDrift(); drift != nil {
+		return drift.Tables
+	}
+	return nil
+}
+
+// hasSchemaDriftTable checks whether the same drift of the table is present in the list
+func hasSchemaDriftTable(tables []api.ChiSchemaDriftTable, table api.ChiSchemaDriftTable) bool {
+	for i := range tables {
+		if tables[i] == table {
+			return true
+		}
+	}
+	return false
+}
+
+// checkClusterSchemaDrift compares tables schema across all hosts of the cluster.
+// Cluster-wide view is fetched from the first host, which is able to provide it
+func (w *worker) checkClusterSchemaDrift(ctx context.Context, cluster *api.Cluster) (drift []api.ChiSchemaDriftTable, err error) {
+	err = fmt.Errorf("no hosts available")
+	cluster.WalkHosts(func(host *api.ChiHost) error {
+		if err == nil {
+			// Already fetched
+			return nil
+		}
+		var hosts []string
+		var tables []*model.SchemaTable
+		if hosts, tables, err = w.ensureClusterSchemer(host).HostClusterTables(ctx, host); err == nil {
+			drift = model.DetectSchemaDrift(cluster.Name, hosts, tables)
+		}
+		return nil
+	})
+	return drift, err
+}
@@ -0,0 +1,638 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/controller"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// storageClassMigrationGracePeriod specifies how long migrated host is given to start replication
+// before it is checked to catch up with other replicas
+const storageClassMigrationGracePeriod = 15 * time.Second
+
+// processCheckStorageClassMigrations processes check of storage class migrations, which old volumes are retained
+func (w *worker) processCheckStorageClassMigrations(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	return w.checkStorageClassMigrations(ctx, chi)
+}
+
+// checkStorageClassMigrations checks whether hosts with migrated PVCs have caught up with other replicas.
+// Hosts, which caught up, are included back into the cluster and their old volumes are released.
+// Reconcile is resumed afterwards, since other replicas of the shard may wait for their turn to be migrated
+func (w *worker) checkStorageClassMigrations(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	if chi.IsStopped() {
+		return nil
+	}
+
+	w.a.V(2).M(chi).S().P()
+	defer w.a.V(2).M(chi).E().P()
+
+	w.newTask(chi)
+
+	hosts := make(map[string]bool)
+	var included []*api.ChiHost
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		hosts[host.GetName()] = true
+		if w.hasHostRetainedVolumes(host) && w.checkHostStorageClassMigrations(ctx, host) {
+			included = append(included, host)
+		}
+		return nil
+	})
+
+	// Hosts removed from the CHI have nothing to catch up with, so their old volumes are released right away
+	for _, migration := range chi.EnsureStatus().GetRetainedStorageClassMigrations() {
+		if hosts[migration.Host] {
+			continue
+		}
+		w.releaseStorageClassMigration(ctx, chi, migration)
+	}
+
+	for _, host := range included {
+		_ = w.includeHost(ctx, host)
+	}
+
+	if err := w.updateStorageClassMigrationsStatus(ctx, chi); err != nil {
+		return err
+	}
+
+	if (len(included) == 0) || !w.hasStorageClassMigrations(ctx, chi) {
+		return nil
+	}
+
+	cur, err := w.c.GetCHIByObjectMeta(&chi.ObjectMeta, true)
+	if err != nil {
+		return nil
+	}
+	if model.IsReconcilePaused(cur) {
+		return nil
+	}
+	w.a.V(1).M(chi).F().Info("Storage class migration of hosts: %d is completed, resume migration of other hosts", len(included))
+	return w.reconcileCHI(ctx, nil, cur)
+}
+
+// startHostStorageClassMigrations starts migration of the host's PVCs to the storage class specified in VolumeClaimTemplate.
+// StatefulSet and old PVCs of the host are deleted, so the host is re-created empty and is re-populated by replication.
+// Old volumes are retained until the host catches up with other replicas, which is checked periodically.
+// Returns true in case migration is started
+func (w *worker) startHostStorageClassMigrations(ctx context.Context, host *api.ChiHost) bool {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return false
+	}
+
+	if host.IsStopped() {
+		return false
+	}
+
+	var pvcs []*core.PersistentVolumeClaim
+	var migrations []*api.ChiStorageClassMigration
+	host.WalkVolumeMounts(api.DesiredStatefulSet, func(volumeMount *core.VolumeMount) {
+		if pvc, migration := w.getStorageClassMigration(ctx, host, volumeMount); migration != nil {
+			pvcs = append(pvcs, pvc)
+			migrations = append(migrations, migration)
+		}
+	})
+	if len(migrations) == 0 {
+		return false
+	}
+
+	if replica := w.getShardMigratingReplica(host); replica != "" {
+		// Only one replica of the shard is re-populated at a time, the host is migrated once the replica catches up
+		w.a.V(1).
+			M(host).F().
+			Info("Storage class migration of host %s is postponed till host %s catches up", host.GetName(), replica)
+		return false
+	}
+
+	if err := w.isStorageClassMigrationPossible(ctx, host); err != nil {
+		w.failStorageClassMigrations(ctx, host, migrations, err)
+		return false
+	}
+
+	// Old volumes have to survive PVC deletion till the host catches up
+	if err := w.retainPVs(ctx, pvcs, migrations); err != nil {
+		w.failStorageClassMigrations(ctx, host, migrations, err)
+		return false
+	}
+
+	// Retained volumes are recorded before PVCs are deleted, so they are not lost track of
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, migration := range migrations {
+		migration.Phase = api.StorageClassMigrationPhaseInProgress
+		migration.StartedAt = startedAt
+		migration.Retained = migration.PV != ""
+		host.GetCHI().EnsureStatus().PushStorageClassMigration(*migration)
+	}
+	if err := w.updateStorageClassMigrationsStatus(ctx, host.GetCHI()); err != nil {
+		for _, migration := range migrations {
+			if err := w.releasePV(ctx, migration); err == nil {
+				migration.Retained = false
+			}
+			migration.StartedAt = ""
+		}
+		w.failStorageClassMigrations(ctx, host, migrations, fmt.Errorf("unable to record migration err: %v", err))
+		return false
+	}
+
+	_ = w.c.deleteStatefulSet(ctx, host)
+	for _, pvc := range pvcs {
+		w.deletePVC(ctx, pvc)
+	}
+
+	for _, migration := range migrations {
+		w.a.V(1).
+			WithEvent(host.GetCHI(), eventActionUpdate, eventReasonStorageClassMigrationStarted).
+			WithStatusAction(host.GetCHI()).
+			M(host).F().
+			Info("Migrate PVC %s of host %s from storage class %s to %s. Old volume %s is retained till host catches up",
+				migration.PVC, host.GetName(), migration.From, migration.To, migration.PV)
+	}
+
+	return true
+}
+
+// checkHostStorageClassMigrations checks whether the host with migrated PVCs has caught up with other replicas
+// and releases old volumes in this case. Host is not waited for, since replication may take hours.
+// In case host does not catch up in time, migration is reported as failed, but old volumes are kept retained
+// and are released as soon as the host catches up.
+// Returns true in case host has no retained volumes left and may be included into the cluster
+func (w *worker) checkHostStorageClassMigrations(ctx context.Context, host *api.ChiHost) bool {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return false
+	}
+
+	migrations := w.getHostRetainedStorageClassMigrations(host)
+	if len(migrations) == 0 {
+		return true
+	}
+
+	if err := w.isHostCaughtUp(ctx, host, migrations); err != nil {
+		w.a.V(1).M(host).F().Info("Host %s with migrated storage has not caught up yet: %v", host.GetName(), err)
+		timeout := w.getStorageClassMigrationVerifyTimeout(host)
+		for _, migration := range migrations {
+			if migration.IsInProgress() && isStorageClassMigrationTimedOut(migration, timeout) {
+				w.failStorageClassMigration(host, migration, fmt.Errorf("host did not catch up with other replicas in %s", timeout))
+			}
+		}
+		return false
+	}
+
+	for _, migration := range migrations {
+		w.releaseStorageClassMigration(ctx, host.GetCHI(), migration)
+	}
+	return true
+}
+
+// releaseStorageClassMigration releases old volume of the migration, which host caught up with other replicas.
+// Migration is completed, unless it is failed already
+func (w *worker) releaseStorageClassMigration(
+	ctx context.Context,
+	chi *api.ClickHouseInstallation,
+	migration *api.ChiStorageClassMigration,
+) {
+	if err := w.releasePV(ctx, migration); err != nil {
+		// Volume stays recorded as retained, so release is retried on the next check
+		w.a.V(1).M(chi).F().Warning("Unable to release volume %s of PVC %s err: %v", migration.PV, migration.PVC, err)
+		return
+	}
+	migration.Retained = false
+
+	if !migration.IsInProgress() {
+		chi.EnsureStatus().PushStorageClassMigration(*migration)
+		w.a.V(1).
+			WithEvent(chi, eventActionUpdate, eventReasonStorageClassMigrationCompleted).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("Released old volume %s of PVC %s of host %s after failed migration", migration.PV, migration.PVC, migration.Host)
+		return
+	}
+
+	migration.Phase = api.StorageClassMigrationPhaseCompleted
+	migration.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	chi.EnsureStatus().PushStorageClassMigration(*migration)
+	w.a.V(1).
+		WithEvent(chi, eventActionUpdate, eventReasonStorageClassMigrationCompleted).
+		WithStatusAction(chi).
+		M(chi).F().
+		Info("Migrated PVC %s of host %s from storage class %s to %s", migration.PVC, migration.Host, migration.From, migration.To)
+}
+
+// getHostRetainedStorageClassMigrations gets migrations of the host, which old volumes are retained
+func (w *worker) getHostRetainedStorageClassMigrations(host *api.ChiHost) (migrations []*api.ChiStorageClassMigration) {
+	for _, migration := range host.GetCHI().EnsureStatus().GetRetainedStorageClassMigrations() {
+		if migration.Host == host.GetName() {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations
+}
+
+// hasHostRetainedVolumes checks whether the host has old volumes retained, so it has not caught up with other replicas yet
+func (w *worker) hasHostRetainedVolumes(host *api.ChiHost) bool {
+	return len(w.getHostRetainedStorageClassMigrations(host)) > 0
+}
+
+// getShardMigratingReplica gets name of the replica of the host's shard, which has not caught up after migration yet.
+// The host itself is reported as well, since its previous migration has to be completed first
+func (w *worker) getShardMigratingReplica(host *api.ChiHost) (name string) {
+	host.GetShard().WalkHosts(func(replica *api.ChiHost) error {
+		if (name == "") && w.hasHostRetainedVolumes(replica) {
+			name = replica.GetName()
+		}
+		return nil
+	})
+	return name
+}
+
+// excludeMigratingHosts excludes hosts, which have not caught up after storage class migration, from ClickHouse config
+func (w *worker) excludeMigratingHosts(
+	chi *api.ClickHouseInstallation,
+	options *model.ClickHouseConfigFilesGeneratorOptions,
+) *model.ClickHouseConfigFilesGeneratorOptions {
+	if len(chi.EnsureStatus().GetRetainedStorageClassMigrations()) == 0 {
+		return options
+	}
+
+	if options == nil {
+		options = model.NewClickHouseConfigFilesGeneratorOptions()
+	}
+	if options.GetRemoteServersGeneratorOptions() == nil {
+		options.SetRemoteServersGeneratorOptions(model.NewRemoteServersGeneratorOptions())
+	}
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		if w.hasHostRetainedVolumes(host) {
+			options.GetRemoteServersGeneratorOptions().ExcludeHost(host)
+		}
+		return nil
+	})
+	return options
+}
+
+// getStorageClassMigrationVerifyTimeout gets time the host is given to catch up with other replicas
+func (w *worker) getStorageClassMigrationVerifyTimeout(host *api.ChiHost) time.Duration {
+	timeout := 0
+	host.WalkVolumeMounts(api.DesiredStatefulSet, func(volumeMount *core.VolumeMount) {
+		if template, ok := model.GetVolumeClaimTemplate(host, volumeMount); ok && (template.StorageClassMigration != nil) {
+			if template.StorageClassMigration.VerifyTimeout > timeout {
+				timeout = template.StorageClassMigration.VerifyTimeout
+			}
+		}
+	})
+	return time.Duration(timeout) * time.Second
+}
+
+// isStorageClassMigrationTimedOut checks whether the migration is started longer than timeout ago
+func isStorageClassMigrationTimedOut(migration *api.ChiStorageClassMigration, timeout time.Duration) bool {
+	startedAt, err := time.Parse(time.RFC3339, migration.StartedAt)
+	if err != nil {
+		return true
+	}
+	return time.Since(startedAt) > timeout
+}
+
+// getStorageClassMigration checks whether PVC mounted by the volume mount has storage class different
+// from the one specified in VolumeClaimTemplate with storage class migration enabled.
+// Returns PVC and migration to be made, if any
+func (w *worker) getStorageClassMigration(
+	ctx context.Context,
+	host *api.ChiHost,
+	volumeMount *core.VolumeMount,
+) (*core.PersistentVolumeClaim, *api.ChiStorageClassMigration) {
+	template, ok := model.GetVolumeClaimTemplate(host, volumeMount)
+	if !ok || !template.StorageClassMigration.IsEnabled() {
+		return nil, nil
+	}
+	to := storageClassName(template.Spec.StorageClassName)
+	if to == "" {
+		// Default storage class is not tracked
+		return nil, nil
+	}
+
+	pvcName, _ := model.CreatePVCNameByVolumeMount(host, volumeMount)
+	namespace := host.Runtime.Address.Namespace
+	pvc, err := w.c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, controller.NewGetOptions())
+	if err != nil {
+		// Nothing to migrate in case PVC is not created yet
+		return nil, nil
+	}
+
+	from := storageClassName(pvc.Spec.StorageClassName)
+	if from == to {
+		return nil, nil
+	}
+
+	return pvc, &api.ChiStorageClassMigration{
+		Host: host.GetName(),
+		PVC:  pvc.Name,
+		From: from,
+		To:   to,
+		PV:   pvc.Spec.VolumeName,
+	}
+}
+
+// hasHostStorageClassMigrations checks whether any PVC of the host is to be migrated to another storage class
+func (w *worker) hasHostStorageClassMigrations(ctx context.Context, host *api.ChiHost) bool {
+	found := false
+	host.WalkVolumeMounts(api.DesiredStatefulSet, func(volumeMount *core.VolumeMount) {
+		if _, migration := w.getStorageClassMigration(ctx, host, volumeMount); migration != nil {
+			found = true
+		}
+	})
+	return found
+}
+
+// hasStorageClassMigrations checks whether any PVC of the CHI is to be migrated to another storage class
+func (w *worker) hasStorageClassMigrations(ctx context.Context, chi *api.ClickHouseInstallation) bool {
+	found := false
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		found = found || w.hasHostStorageClassMigrations(ctx, host)
+		return nil
+	})
+	return found
+}
+
+// storageClassName gets name of the storage class, empty name stands for default storage class
+func storageClassName(name *string) string {
+	if name == nil {
+		return ""
+	}
+	return *name
+}
+
+// isStorageClassMigrationPossible checks whether all data of the host can be re-populated by replication
+func (w *worker) isStorageClassMigrationPossible(ctx context.Context, host *api.ChiHost) error {
+	if host.GetShard().HostsCount() < 2 {
+		return fmt.Errorf("shard has no other replicas to replicate data from")
+	}
+
+	tables, err := w.ensureClusterSchemer(host).HostNonReplicatedTables(ctx, host)
+	if err != nil {
+		return fmt.Errorf("unable to check tables of the host err: %v", err)
+	}
+	if len(tables) > 0 {
+		return fmt.Errorf("host has non-replicated tables with data: %s", strings.Join(tables, ", "))
+	}
+
+	if _, err := w.getShardHealthyPeerReplicas(ctx, host); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getShardHealthyPeerReplicas gets replicated tables of another replica of the host's shard, which has all tables
+// caught up and is not migrated itself, thus it is able to serve as a source of data for the host
+func (w *worker) getShardHealthyPeerReplicas(ctx context.Context, host *api.ChiHost) (peer []*schemer.ReplicaState, err error) {
+	found := false
+	host.GetShard().WalkHosts(func(replica *api.ChiHost) error {
+		if found || (replica == host) || w.hasHostRetainedVolumes(replica) {
+			return nil
+		}
+		if replicas, err := w.ensureClusterSchemer(replica).HostReplicas(ctx, replica); (err == nil) && (countLaggingReplicas(replicas) == 0) {
+			peer = replicas
+			found = true
+		}
+		return nil
+	})
+	if !found {
+		return nil, fmt.Errorf("shard has no other healthy replicas to replicate data from")
+	}
+	return peer, nil
+}
+
+// isHostCaughtUp checks whether the host has all replicated tables of the healthy peer replica and all of them have caught up
+func (w *worker) isHostCaughtUp(ctx context.Context, host *api.ChiHost, migrations []*api.ChiStorageClassMigration) error {
+	for _, migration := range migrations {
+		// Replication queue of the re-created host may be empty till it fetches the log of other replicas
+		if startedAt, err := time.Parse(time.RFC3339, migration.StartedAt); (err == nil) && (time.Since(startedAt) < storageClassMigrationGracePeriod) {
+			return fmt.Errorf("migration is started just now")
+		}
+	}
+
+	peer, err := w.getShardHealthyPeerReplicas(ctx, host)
+	if err != nil {
+		return err
+	}
+	replicas, err := w.ensureClusterSchemer(host).HostReplicas(ctx, host)
+	if err != nil {
+		return fmt.Errorf("unable to check replicas of the host err: %v", err)
+	}
+	return checkReplicasCaughtUp(replicas, peer)
+}
+
+// checkReplicasCaughtUp checks whether replicated tables of the host have caught up with the tables of the healthy peer replica.
+// Each table of the peer has to exist on the host and to be writable, and either to have replication queue processed
+// or to have at least as many rows as the peer has. Empty host is not considered to be caught up, since its tables may be not created yet
+func checkReplicasCaughtUp(replicas, peer []*schemer.ReplicaState) error {
+	tables := make(map[string]*schemer.ReplicaState, len(replicas))
+	for _, replica := range replicas {
+		tables[replica.GetName()] = replica
+	}
+
+	var missing, lagging []string
+	for _, source := range peer {
+		replica, ok := tables[source.GetName()]
+		switch {
+		case !ok:
+			missing = append(missing, source.GetName())
+		case replica.IsReadOnly:
+			lagging = append(lagging, source.GetName())
+		case (replica.QueueSize == 0) && (replica.AbsoluteDelay == 0):
+		case replica.TotalRows >= source.TotalRows:
+		default:
+			lagging = append(lagging, source.GetName())
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		return fmt.Errorf("tables are not created yet: %s", strings.Join(missing, ", "))
+	case len(lagging) > 0:
+		return fmt.Errorf("tables lagging behind other replicas: %s", strings.Join(lagging, ", "))
+	}
+	return nil
+}
+
+// countLaggingReplicas counts replicated tables, which are either read-only or have not caught up yet
+func countLaggingReplicas(replicas []*schemer.ReplicaState) (lagging int) {
+	for _, replica := range replicas {
+		if replica.IsReadOnly || (replica.QueueSize > 0) || (replica.AbsoluteDelay > 0) {
+			lagging++
+		}
+	}
+	return lagging
+}
+
+// retainPVs retains volumes bound to the PVCs and records their original reclaim policies in the migrations.
+// Volumes are retained either all or none, so in case of failure volumes retained already are released back
+func (w *worker) retainPVs(
+	ctx context.Context,
+	pvcs []*core.PersistentVolumeClaim,
+	migrations []*api.ChiStorageClassMigration,
+) error {
+	for i := range pvcs {
+		policy, err := w.retainPV(ctx, pvcs[i])
+		if err != nil {
+			for j := 0; j < i; j++ {
+				_ = w.releasePV(ctx, migrations[j])
+			}
+			return fmt.Errorf("unable to retain volume %s err: %v", migrations[i].PV, err)
+		}
+		migrations[i].PVReclaimPolicy = policy
+	}
+	return nil
+}
+
+// retainPV sets reclaim policy of the volume bound to the PVC to Retain, so the volume survives PVC deletion.
+// Original reclaim policy is kept in the annotation of the volume, so it is not lost in case migration is not recorded.
+// Volume is verified to be retained and to be bound to the PVC afterwards.
+// Returns original reclaim policy
+func (w *worker) retainPV(ctx context.Context, pvc *core.PersistentVolumeClaim) (string, error) {
+	if pvc.Spec.VolumeName == "" {
+		// PVC is not bound, nothing to retain
+		return "", nil
+	}
+
+	pv, err := w.c.kubeClient.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, controller.NewGetOptions())
+	if err != nil {
+		return "", err
+	}
+	policy := string(pv.Spec.PersistentVolumeReclaimPolicy)
+	if original, ok := pv.Annotations[model.AnnotationPVReclaimPolicy]; ok {
+		// Volume is retained by the previous attempt already
+		policy = original
+	}
+	if policy != string(core.PersistentVolumeReclaimRetain) {
+		pv.Annotations = util.MergeStringMapsOverwrite(pv.Annotations, map[string]string{
+			model.AnnotationPVReclaimPolicy: policy,
+		})
+		pv.Spec.PersistentVolumeReclaimPolicy = core.PersistentVolumeReclaimRetain
+		if pv, err = w.c.kubeClient.CoreV1().PersistentVolumes().Update(ctx, pv, controller.NewUpdateOptions()); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case pv.Spec.PersistentVolumeReclaimPolicy != core.PersistentVolumeReclaimRetain:
+		return "", fmt.Errorf("reclaim policy is %s", pv.Spec.PersistentVolumeReclaimPolicy)
+	case (pv.Spec.ClaimRef == nil) || (pv.Spec.ClaimRef.Namespace != pvc.Namespace) || (pv.Spec.ClaimRef.Name != pvc.Name):
+		return "", fmt.Errorf("volume is not bound to PVC %s", pvc.Name)
+	}
+	return policy, nil
+}
+
+// releasePV restores original reclaim policy of the volume retained during migration.
+// In case original policy is Delete, volume is deleted by Kubernetes, since its PVC is deleted already
+func (w *worker) releasePV(ctx context.Context, migration *api.ChiStorageClassMigration) error {
+	if migration.PV == "" {
+		return nil
+	}
+
+	pv, err := w.c.kubeClient.CoreV1().PersistentVolumes().Get(ctx, migration.PV, controller.NewGetOptions())
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	policy := core.PersistentVolumeReclaimPolicy(migration.PVReclaimPolicy)
+	original, annotated := pv.Annotations[model.AnnotationPVReclaimPolicy]
+	if annotated {
+		policy = core.PersistentVolumeReclaimPolicy(original)
+	}
+	if !annotated && ((policy == "") || (policy == pv.Spec.PersistentVolumeReclaimPolicy)) {
+		return nil
+	}
+
+	delete(pv.Annotations, model.AnnotationPVReclaimPolicy)
+	if policy != "" {
+		pv.Spec.PersistentVolumeReclaimPolicy = policy
+	}
+	_, err = w.c.kubeClient.CoreV1().PersistentVolumes().Update(ctx, pv, controller.NewUpdateOptions())
+	return err
+}
+
+// failStorageClassMigrations records failure of all migrations of the host
+func (w *worker) failStorageClassMigrations(
+	ctx context.Context,
+	host *api.ChiHost,
+	migrations []*api.ChiStorageClassMigration,
+	err error,
+) {
+	for _, migration := range migrations {
+		w.failStorageClassMigration(host, migration, err)
+	}
+	_ = w.updateStorageClassMigrationsStatus(ctx, host.GetCHI())
+}
+
+// failStorageClassMigration records failed migration.
+// The same failure is recorded and reported once, since it is likely to repeat on each reconcile
+func (w *worker) failStorageClassMigration(host *api.ChiHost, migration *api.ChiStorageClassMigration, err error) {
+	prev := host.GetCHI().EnsureStatus().GetStorageClassMigration(migration.PVC)
+	if (prev != nil) && (prev.Phase == api.StorageClassMigrationPhaseFailed) &&
+		(prev.From == migration.From) && (prev.To == migration.To) && (prev.Error == err.Error()) {
+		return
+	}
+
+	migration.Phase = api.StorageClassMigrationPhaseFailed
+	migration.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	migration.Error = err.Error()
+	host.GetCHI().EnsureStatus().PushStorageClassMigration(*migration)
+
+	retained := ""
+	if migration.IsRetained() {
+		retained = fmt.Sprintf(" Old volume %s is retained till host catches up", migration.PV)
+	}
+	w.a.V(1).
+		WithEvent(host.GetCHI(), eventActionUpdate, eventReasonStorageClassMigrationFailed).
+		WithStatusAction(host.GetCHI()).
+		M(host).F().
+		Warning("Unable to migrate PVC %s of host %s from storage class %s to %s err: %v.%s",
+			migration.PVC, host.GetName(), migration.From, migration.To, err, retained)
+}
+
+// updateStorageClassMigrationsStatus writes storage class migrations into CHI status
+func (w *worker) updateStorageClassMigrationsStatus(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	return w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			StorageClassMigrations: true,
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package chi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/schemer"
+)
+
+func newTestReplica(table string, readOnly bool, queueSize int, delay time.Duration, rows uint64) *schemer.ReplicaState {
+	return &schemer.ReplicaState{
+		Database:      "default",
+		Table:         table,
+		IsReadOnly:    readOnly,
+		QueueSize:     queueSize,
+		AbsoluteDelay: delay,
+		TotalRows:     rows,
+	}
+}
+
+func TestCountLaggingReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas []*schemer.ReplicaState
+		lagging  int
+	}{
+		{
+			name: "no tables",
+		},
+		{
+			name: "all caught up",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, 0, 10),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+		},
+		{
+			name: "read-only, queued and delayed tables",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", true, 0, 0, 10),
+				newTestReplica("b", false, 3, 0, 10),
+				newTestReplica("c", false, 0, time.Second, 10),
+				newTestReplica("d", false, 0, 0, 10),
+			},
+			lagging: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.lagging, countLaggingReplicas(tt.replicas))
+		})
+	}
+}
+
+func TestCheckReplicasCaughtUp(t *testing.T) {
+	peer := []*schemer.ReplicaState{
+		newTestReplica("a", false, 0, 0, 100),
+		newTestReplica("b", false, 0, 0, 0),
+	}
+
+	tests := []struct {
+		name     string
+		replicas []*schemer.ReplicaState
+		peer     []*schemer.ReplicaState
+		caughtUp bool
+	}{
+		{
+			name:     "host and peer have no tables",
+			caughtUp: true,
+		},
+		{
+			name: "all tables caught up",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, 0, 100),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: true,
+		},
+		{
+			name:     "host has no tables created yet",
+			peer:     peer,
+			caughtUp: false,
+		},
+		{
+			name: "host misses a table of the peer",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, 0, 100),
+			},
+			peer:     peer,
+			caughtUp: false,
+		},
+		{
+			name: "extra tables of the host are ignored",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, 0, 100),
+				newTestReplica("b", false, 0, 0, 0),
+				newTestReplica("c", false, 5, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: true,
+		},
+		{
+			name: "read-only table",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", true, 0, 0, 100),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: false,
+		},
+		{
+			name: "queued table with rows of the peer",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 2, time.Second, 100),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: true,
+		},
+		{
+			name: "queued table behind the peer",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 2, 0, 50),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: false,
+		},
+		{
+			name: "delayed table behind the peer",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, time.Second, 50),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: false,
+		},
+		{
+			name: "processed queue with less rows than the peer",
+			replicas: []*schemer.ReplicaState{
+				newTestReplica("a", false, 0, 0, 90),
+				newTestReplica("b", false, 0, 0, 0),
+			},
+			peer:     peer,
+			caughtUp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkReplicasCaughtUp(tt.replicas, tt.peer)
+			if tt.caughtUp {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
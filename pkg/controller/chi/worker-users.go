@@ -0,0 +1,36 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+)
+
+// processReconcileUsers regenerates users config of the CHI with users specified by ClickHouseUser resources in config mode
+func (w *worker) processReconcileUsers(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.c.GetCHIByObjectMeta(cmd.chi, true)
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if model.IsReconcilePaused(chi) {
+		return nil
+	}
+
+	w.a.V(1).M(chi).F().Info("ClickHouseUsers are changed, reconcile users of CHI: %s/%s", chi.Namespace, chi.Name)
+	return w.reconcileUsersConfigMap(ctx, chi, nil)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"context"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// processUpdateVirtualClusters re-renders virtual clusters of the CHI in case layouts of the referenced CHIs have changed.
+// Only common ConfigMap is updated, since remote_servers does not require restart of the hosts
+func (w *worker) processUpdateVirtualClusters(ctx context.Context, cmd *PerCHICommand) error {
+	chi, err := w.createCHIFromObjectMeta(cmd.chi, true, normalizer.NewOptions())
+	if err != nil {
+		w.a.M(cmd.chi).F().Error("unable to find CHI by %v err: %v", cmd.chi.Labels, err)
+		return nil
+	}
+	if model.IsReconcilePaused(chi) {
+		return nil
+	}
+
+	w.a.V(1).M(chi).F().Info("Layout of the referenced CHIs has changed, update virtual clusters")
+	w.resolveVirtualClusters(ctx, chi)
+
+	w.newTask(chi)
+	chi.EnsureRuntime().LockCommonConfig()
+	err = w.reconcileCHIConfigMapCommon(ctx, chi, nil)
+	chi.EnsureRuntime().UnlockCommonConfig()
+	return err
+}
+
+// resolveVirtualClusters fills virtual clusters of the normalized CHI with layouts of the referenced CHIs
+// and records resolved layouts in status
+func (w *worker) resolveVirtualClusters(ctx context.Context, chi *api.ClickHouseInstallation) {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return
+	}
+
+	var virtualClusters []api.ChiVirtualClusterStatus
+	for _, virtualCluster := range chi.Spec.Configuration.VirtualClusters {
+		virtualCluster.Runtime = w.c.getVirtualClusterLayout(virtualCluster)
+		hosts := virtualCluster.Runtime.GetHosts()
+		if len(hosts) == 0 {
+			w.a.V(1).M(chi).F().Warning("Virtual cluster %s references no hosts", virtualCluster.Name)
+		}
+		virtualClusters = append(virtualClusters, api.ChiVirtualClusterStatus{
+			Name:  virtualCluster.Name,
+			Hosts: hosts,
+		})
+	}
+
+	if api.EqualVirtualClustersStatus(virtualClusters, chi.EnsureStatus().GetVirtualClusters()) {
+		return
+	}
+
+	chi.EnsureStatus().SetVirtualClusters(virtualClusters)
+	if len(virtualClusters) > 0 {
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonVirtualClustersUpdated).
+			WithStatusAction(chi).
+			M(chi).F().
+			Info("Virtual clusters are updated with layouts of the referenced CHIs")
+	}
+	w.updateVirtualClustersStatus(ctx, chi)
+}
+
+// updateVirtualClustersStatus writes resolved layouts of the virtual clusters into CHI status
+func (w *worker) updateVirtualClustersStatus(ctx context.Context, chi *api.ClickHouseInstallation) {
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			VirtualClusters: true,
+		},
+	})
+}
@@ -65,16 +65,19 @@ type task struct {
 	registryFailed     *model.Registry
 	cmUpdate           time.Time
 	start              time.Time
+	// configurationChanges caches configuration changes of the hosts reconciled by the task
+	configurationChanges *hostConfigurationChanges
 }
 
 // newTask creates new context
 func newTask(creator *chiCreator.Creator) task {
 	return task{
-		creator:            creator,
-		registryReconciled: model.NewRegistry(),
-		registryFailed:     model.NewRegistry(),
-		cmUpdate:           time.Time{},
-		start:              time.Now(),
+		creator:              creator,
+		registryReconciled:   model.NewRegistry(),
+		registryFailed:       model.NewRegistry(),
+		cmUpdate:             time.Time{},
+		start:                time.Now(),
+		configurationChanges: newHostConfigurationChanges(),
 	}
 }
 
@@ -102,6 +105,22 @@ func (w *worker) newTask(chi *api.ClickHouseInstallation) {
 	w.task = newTask(chiCreator.NewCreator(chi))
 }
 
+// startReconcileLog attaches task ID of the CHI and ID of the new reconcile to log lines of the worker,
+// thus log lines of the whole reconcile can be correlated. Returns function, which detaches them
+func (w *worker) startReconcileLog(ctx context.Context, chi *api.ClickHouseInstallation) (context.Context, func()) {
+	var taskID string
+	if chi != nil {
+		taskID = chi.Spec.GetTaskID()
+	}
+	ctx = log.NewReconcileContext(ctx, taskID)
+
+	a := w.a
+	w.a = w.a.Ctx(ctx)
+	return ctx, func() {
+		w.a = a
+	}
+}
+
 // timeToStart specifies time that operator does not accept changes
 const timeToStart = 1 * time.Minute
 
@@ -111,7 +130,7 @@ func (w *worker) isJustStarted() bool {
 }
 
 func (w *worker) isConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
-	return model.IsConfigurationChangeRequiresReboot(host)
+	return w.getHostConfigurationChanges(host).RequiresRestart()
 }
 
 // shouldForceRestartHost checks whether cluster requires hosts restart
@@ -128,14 +147,24 @@ func (w *worker) shouldForceRestartHost(host *api.ChiHost) bool {
 		return false
 	}
 
+	if host.GetCHI().EnsureStatus().GetDeferredHost(host.GetName()).HasReason(api.DeferredReasonRestart) {
+		w.a.V(1).M(host).F().Info("Config change(s) deferred till maintenance window require host restart. Host: %s", host.GetName())
+		return true
+	}
+
+	if w.isHostRestartRequested(host) {
+		w.a.V(1).M(host).F().Info("Restart request requires host restart. Host: %s", host.GetName())
+		return true
+	}
+
 	if (host.GetReconcileAttributes().GetStatus() == api.ObjectStatusSame) && !host.HasAncestor() {
 		w.a.V(1).M(host).F().Info("Host already exists, but has no ancestor, no restart applicable. Host: %s", host.GetName())
 		return false
 	}
 
 	// For some configuration changes we have to force restart host
-	if w.isConfigurationChangeRequiresReboot(host) {
-		w.a.V(1).M(host).F().Info("Config change(s) require host restart. Host: %s", host.GetName())
+	if changes := w.getHostConfigurationChanges(host); changes.RequiresRestart() {
+		w.reportHostRestartReasons(host, changes)
 		return true
 	}
 
@@ -200,7 +229,19 @@ func (w *worker) run() {
 	}
 }
 
-func (w *worker) processReconcileCHI(ctx context.Context, cmd *ReconcileCHI) error {
+func (w *worker) processReconcileCHI(ctx context.Context, cmd *ReconcileCHI) (err error) {
+	chi := cmd.new
+	if chi == nil {
+		chi = cmd.old
+	}
+	ctx, done := w.startReconcileLog(ctx, chi)
+	defer done()
+
+	ctx, span := startSpanCHI(ctx, cmd.cmd, chi)
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	switch cmd.cmd {
 	case reconcileAdd:
 		return w.updateCHI(ctx, nil, cmd.new)
@@ -208,6 +249,10 @@ func (w *worker) processReconcileCHI(ctx context.Context, cmd *ReconcileCHI) err
 		return w.updateCHI(ctx, cmd.old, cmd.new)
 	case reconcileDelete:
 		return w.discoveryAndDeleteCHI(ctx, cmd.old)
+	case reconcilePause:
+		return w.pauseCHI(ctx, cmd.new)
+	case reconcileResume:
+		return w.resumeCHI(ctx, cmd.new)
 	}
 
 	// Unknown item type, don't know what to do with it
@@ -296,13 +341,29 @@ func (w *worker) processDropDns(ctx context.Context, cmd *DropDns) error {
 // processItem processes one work item according to its type
 func (w *worker) processItem(ctx context.Context, item interface{}) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
 	w.a.V(3).S().P()
 	defer w.a.V(3).E().P()
 
+	if cmd, ok := item.(queue.PriorityQueueItem); ok {
+		if !w.c.isOwnedItem(cmd) {
+			// CHI has been moved to another operator instance since the item was enqueued
+			w.a.V(2).Info("skip item of CHI owned by another operator instance: %s", cmd.Handle())
+			return nil
+		}
+		acquired, release := w.c.acquireItem(ctx, cmd)
+		if !acquired {
+			// CHI has been moved from another operator instance, which has not handed it off yet
+			w.a.V(1).Info("retry item of CHI not handed off by the previous owner yet: %s", cmd.Handle())
+			w.c.retryItem(cmd)
+			return nil
+		}
+		defer release()
+	}
+
 	switch cmd := item.(type) {
 	case *ReconcileCHI:
 		return w.processReconcileCHI(ctx, cmd)
@@ -316,6 +377,8 @@ func (w *worker) processItem(ctx context.Context, item interface{}) error {
 		return w.processReconcilePod(ctx, cmd)
 	case *DropDns:
 		return w.processDropDns(ctx, cmd)
+	case *PerCHICommand:
+		return w.processPerCHICommand(ctx, cmd)
 	}
 
 	// Unknown item type, don't know what to do with it
@@ -324,10 +387,62 @@ func (w *worker) processItem(ctx context.Context, item interface{}) error {
 	return nil
 }
 
-// normalize
-func (w *worker) normalize(c *api.ClickHouseInstallation) *api.ClickHouseInstallation {
+// processPerCHICommand processes command run against one CHI according to its kind
+func (w *worker) processPerCHICommand(ctx context.Context, cmd *PerCHICommand) error {
+	switch cmd.kind {
+	case commandRotateOperatorCredentials:
+		return w.processRotateOperatorCredentials(ctx, cmd)
+	case commandCheckSchemaDrift:
+		return w.processCheckSchemaDrift(ctx, cmd)
+	case commandCheckReplicationHealth:
+		return w.processCheckReplicationHealth(ctx, cmd)
+	case commandCheckPVCAutoscaling:
+		return w.processCheckPVCAutoscaling(ctx, cmd)
+	case commandApplyDeferredChanges:
+		return w.processApplyDeferredChanges(ctx, cmd)
+	case commandReconcileDataSources:
+		return w.processReconcileDataSources(ctx, cmd)
+	case commandHibernationTransition:
+		return w.processHibernationTransition(ctx, cmd)
+	case commandUpdateVirtualClusters:
+		return w.processUpdateVirtualClusters(ctx, cmd)
+	case commandReconcileUsers:
+		return w.processReconcileUsers(ctx, cmd)
+	case commandCheckStorageClassMigrations:
+		return w.processCheckStorageClassMigrations(ctx, cmd)
+	}
+
+	// Unknown command, don't know what to do with it
+	// Just skip it and behave like it never existed
+	utilRuntime.HandleError(fmt.Errorf("unexpected command - %#v", cmd))
+	return nil
+}
+
+// normalize normalizes CHI. Returns error in case data required for normalization is not available
+func (w *worker) normalize(c *api.ClickHouseInstallation) (*api.ClickHouseInstallation, error) {
+	return w.doNormalize(c, false)
+}
 
-	chi, err := w.normalizer.CreateTemplatedCHI(c, normalizer.NewOptions())
+// normalizeAndValidate normalizes CHI and validates its spec as well as its settings against the catalog of ClickHouse settings.
+// Returns normalized CHI along with validation error, if any
+func (w *worker) normalizeAndValidate(c *api.ClickHouseInstallation) (*api.ClickHouseInstallation, error) {
+	return w.doNormalize(c, true)
+}
+
+// doNormalize normalizes CHI in two passes - the first one provides CHI to look up IPs of its pods,
+// the second one includes IPs into the default user. Validates spec and settings of the CHI in case validate is set.
+// Validation errors are returned as-is in order to be reported by the caller, other errors are reported right away
+func (w *worker) doNormalize(c *api.ClickHouseInstallation, validate bool) (*api.ClickHouseInstallation, error) {
+	creds, err := w.c.fetchOperatorCredentials(c)
+	if err != nil {
+		return c, fmt.Errorf("unable to get operator credentials: %w", err)
+	}
+	users := w.c.getUsers(c)
+
+	opts := normalizer.NewOptions()
+	opts.OperatorCredentials = creds
+	opts.Users = users
+	chi, err := w.normalizer.CreateTemplatedCHI(c, opts)
 	if err != nil {
 		w.a.WithEvent(chi, eventActionReconcile, eventReasonReconcileFailed).
 			WithStatusError(chi).
@@ -337,24 +452,32 @@ func (w *worker) normalize(c *api.ClickHouseInstallation) *api.ClickHouseInstall
 
 	ips := w.c.getPodsIPs(chi)
 	w.a.V(1).M(chi).Info("IPs of the CHI normalizer %s/%s: len: %d %v", chi.Namespace, chi.Name, len(ips), ips)
-	opts := normalizer.NewOptions()
+	opts = normalizer.NewOptions()
 	opts.DefaultUserAdditionalIPs = ips
+	opts.OperatorCredentials = creds
+	opts.Users = users
+	opts.ValidateSettings = validate
+	opts.ValidateSpec = validate
 
 	chi, err = w.normalizer.CreateTemplatedCHI(c, opts)
-	if err != nil {
+	switch {
+	case errors.Is(err, normalizer.ErrSettingsValidation), errors.Is(err, normalizer.ErrSpecValidation):
+		// Invalid spec and settings are reported by the caller
+		return chi, err
+	case err != nil:
 		w.a.WithEvent(chi, eventActionReconcile, eventReasonReconcileFailed).
 			WithStatusError(chi).
 			M(chi).F().
 			Error("FAILED to normalize CHI 2: %v", err)
 	}
 
-	return chi
+	return chi, nil
 }
 
 // ensureFinalizer
 func (w *worker) ensureFinalizer(ctx context.Context, chi *api.ClickHouseInstallation) bool {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return false
 	}
 
@@ -413,7 +536,7 @@ func (w *worker) updateEndpoints(ctx context.Context, old, new *core.Endpoints)
 // updateCHI sync CHI which was already created earlier
 func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstallation) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -440,7 +563,7 @@ func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstalla
 	}
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -450,7 +573,7 @@ func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstalla
 	}
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -461,14 +584,92 @@ func (w *worker) updateCHI(ctx context.Context, old, new *api.ClickHouseInstalla
 	}
 
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
+	if new.Spec.HasRollbackTo() {
+		// Spec of the revision is restored instead of the current spec being reconciled
+		return w.rollbackCHI(ctx, new)
+	}
+
 	// CHI is being reconciled
 	return w.reconcileCHI(ctx, old, new)
 }
 
+// pauseCHI reports reconcile of the CHI paused along with changes pending to be reconciled on resume
+func (w *worker) pauseCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	// Pending changes are the ones between the latest completed CHI and the current one
+	var old *api.ClickHouseInstallation
+	if chi.HasAncestor() {
+		var err error
+		if old, err = w.normalize(chi.GetAncestor()); err != nil {
+			return err
+		}
+	}
+	normalized, err := w.normalize(chi)
+	if err != nil {
+		return err
+	}
+	actionPlan := model.NewActionPlan(old, normalized)
+
+	paused := &api.ChiReconcilePaused{
+		Since:             time.Now().UTC().Format(time.RFC3339),
+		PendingActionPlan: actionPlan.Summary(),
+	}
+	switch prev := chi.EnsureStatus().GetPaused(); {
+	case prev == nil:
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonReconcilePaused).
+			M(chi).F().
+			Info("Reconcile paused. CHI: %s/%s Pending changes: %s", chi.Namespace, chi.Name, paused.PendingActionPlan)
+	case prev.PendingActionPlan == paused.PendingActionPlan:
+		// Already reported
+		return nil
+	default:
+		paused.Since = prev.Since
+		w.a.V(1).
+			WithEvent(chi, eventActionReconcile, eventReasonReconcilePaused).
+			M(chi).F().
+			Info("Reconcile is paused, changes are pending. CHI: %s/%s Pending changes: %s", chi.Namespace, chi.Name, paused.PendingActionPlan)
+	}
+
+	chi.EnsureStatus().ReconcilePause(paused)
+	return w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Paused: true,
+		},
+	})
+}
+
+// resumeCHI resumes paused reconcile of the CHI and reconciles the latest spec once
+func (w *worker) resumeCHI(ctx context.Context, chi *api.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.Ctx(ctx).V(2).Info("task is done")
+		return nil
+	}
+
+	w.a.V(1).
+		WithEvent(chi, eventActionReconcile, eventReasonReconcileResumed).
+		M(chi).F().
+		Info("Reconcile resumed. CHI: %s/%s", chi.Namespace, chi.Name)
+
+	chi.EnsureStatus().ReconcileResume()
+	_ = w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
+		CopyCHIStatusOptions: api.CopyCHIStatusOptions{
+			Paused: true,
+		},
+	})
+
+	// Whatever was changed while paused, the latest spec is reconciled against the latest completed one
+	return w.updateCHI(ctx, nil, chi)
+}
+
 // isCHIProcessedOnTheSameIP checks whether it is just a restart of the operator on the same IP
 func (w *worker) isCHIProcessedOnTheSameIP(chi *api.ClickHouseInstallation) bool {
 	ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP)
@@ -585,7 +786,7 @@ func (w *worker) logOldAndNew(name string, old, new *api.ClickHouseInstallation)
 
 func (w *worker) waitForIPAddresses(ctx context.Context, chi *api.ClickHouseInstallation) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 	if chi.IsStopped() {
@@ -643,7 +844,7 @@ func (w *worker) addCHIToMonitoring(chi *api.ClickHouseInstallation) {
 
 func (w *worker) markReconcileStart(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -664,9 +865,10 @@ func (w *worker) markReconcileStart(ctx context.Context, chi *api.ClickHouseInst
 	w.a.V(2).M(chi).F().Info("action plan\n%s\n", ap.String())
 }
 
-func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _chi *api.ClickHouseInstallation) {
+// finalizeReconcileAndMarkCompleted writes completed CHI into the status along with the revision of the spec supplied by the user
+func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _chi *api.ClickHouseInstallation, userSpec *api.ChiSpec) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -686,6 +888,7 @@ func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _chi *ap
 			chi.EnsureStatus().ReconcileComplete()
 			// TODO unify with update endpoints
 			w.newTask(chi)
+			w.recordRevision(ctx, chi, userSpec)
 			w.reconcileCHIConfigMapUsers(ctx, chi)
 			w.c.updateCHIObjectStatus(ctx, chi, UpdateCHIStatusOptions{
 				CopyCHIStatusOptions: api.CopyCHIStatusOptions{
@@ -709,7 +912,7 @@ func (w *worker) finalizeReconcileAndMarkCompleted(ctx context.Context, _chi *ap
 
 func (w *worker) markReconcileCompletedUnsuccessfully(ctx context.Context, chi *api.ClickHouseInstallation, err error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -735,7 +938,7 @@ func (w *worker) markReconcileCompletedUnsuccessfully(ctx context.Context, chi *
 
 func (w *worker) walkHosts(ctx context.Context, chi *api.ClickHouseInstallation, ap *model.ActionPlan) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -848,7 +1051,7 @@ func (w *worker) options() *model.ClickHouseConfigFilesGeneratorOptions {
 // prepareHostStatefulSetWithStatus prepares host's StatefulSet status
 func (w *worker) prepareHostStatefulSetWithStatus(ctx context.Context, host *api.ChiHost, shutdown bool) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -898,7 +1101,7 @@ func (a migrateTableOptionsArr) First() *migrateTableOptions {
 // migrateTables
 func (w *worker) migrateTables(ctx context.Context, host *api.ChiHost, opts ...*migrateTableOptions) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -994,12 +1197,12 @@ func (w *worker) shouldDropReplica(host *api.ChiHost, opts ...*migrateTableOptio
 // excludeHost excludes host from ClickHouse clusters if required
 func (w *worker) excludeHost(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
-	log.V(1).M(host).F().S().Info("exclude host start")
-	defer log.V(1).M(host).F().E().Info("exclude host end")
+	log.Ctx(ctx).V(1).M(host).F().S().Info("exclude host start")
+	defer log.Ctx(ctx).V(1).M(host).F().E().Info("exclude host end")
 
 	if !w.shouldExcludeHost(host) {
 		return nil
@@ -1017,8 +1220,8 @@ func (w *worker) excludeHost(ctx context.Context, host *api.ChiHost) error {
 
 // completeQueries wait for running queries to complete
 func (w *worker) completeQueries(ctx context.Context, host *api.ChiHost) error {
-	log.V(1).M(host).F().S().Info("complete queries start")
-	defer log.V(1).M(host).F().E().Info("complete queries end")
+	log.Ctx(ctx).V(1).M(host).F().S().Info("complete queries start")
+	defer log.Ctx(ctx).V(1).M(host).F().E().Info("complete queries end")
 
 	if w.shouldWaitQueries(host) {
 		return w.waitHostNoActiveQueries(ctx, host)
@@ -1033,6 +1236,9 @@ func (w *worker) shouldIncludeHost(host *api.ChiHost) bool {
 	case host.IsStopped():
 		// No need to include stopped host
 		return false
+	case w.hasHostRetainedVolumes(host):
+		// Host with migrated storage is included by storage class migrations check as soon as it catches up
+		return false
 	}
 	return true
 }
@@ -1040,7 +1246,7 @@ func (w *worker) shouldIncludeHost(host *api.ChiHost) bool {
 // includeHost includes host back back into ClickHouse clusters
 func (w *worker) includeHost(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1066,7 +1272,7 @@ func (w *worker) includeHost(ctx context.Context, host *api.ChiHost) error {
 // excludeHostFromService
 func (w *worker) excludeHostFromService(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1078,7 +1284,7 @@ func (w *worker) excludeHostFromService(ctx context.Context, host *api.ChiHost)
 // includeHostIntoService
 func (w *worker) includeHostIntoService(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1090,7 +1296,7 @@ func (w *worker) includeHostIntoService(ctx context.Context, host *api.ChiHost)
 // excludeHostFromClickHouseCluster excludes host from ClickHouse configuration
 func (w *worker) excludeHostFromClickHouseCluster(ctx context.Context, host *api.ChiHost) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -1115,7 +1321,7 @@ func (w *worker) excludeHostFromClickHouseCluster(ctx context.Context, host *api
 // includeHostIntoClickHouseCluster includes host into ClickHouse configuration
 func (w *worker) includeHostIntoClickHouseCluster(ctx context.Context, host *api.ChiHost) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return
 	}
 
@@ -1291,6 +1497,14 @@ func (w *worker) createCHIFromObjectMeta(objectMeta *meta.ObjectMeta, isCHI bool
 		return nil, err
 	}
 
+	if options.OperatorCredentials == nil {
+		if options.OperatorCredentials, err = w.c.fetchOperatorCredentials(chi); err != nil {
+			return nil, err
+		}
+	}
+	if options.Users == nil {
+		options.Users = w.c.getUsers(chi)
+	}
 	chi, err = w.normalizer.CreateTemplatedCHI(chi, options)
 	if err != nil {
 		return nil, err
@@ -1302,7 +1516,7 @@ func (w *worker) createCHIFromObjectMeta(objectMeta *meta.ObjectMeta, isCHI bool
 // updateConfigMap
 func (w *worker) updateConfigMap(ctx context.Context, chi *api.ClickHouseInstallation, configMap *core.ConfigMap) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1330,7 +1544,7 @@ func (w *worker) updateConfigMap(ctx context.Context, chi *api.ClickHouseInstall
 // createConfigMap
 func (w *worker) createConfigMap(ctx context.Context, chi *api.ClickHouseInstallation, configMap *core.ConfigMap) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1360,7 +1574,7 @@ func (w *worker) updateService(
 	targetService *core.Service,
 ) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1467,7 +1681,7 @@ func (w *worker) updateService(
 // createService
 func (w *worker) createService(ctx context.Context, chi *api.ClickHouseInstallation, service *core.Service) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1492,7 +1706,7 @@ func (w *worker) createService(ctx context.Context, chi *api.ClickHouseInstallat
 // createSecret
 func (w *worker) createSecret(ctx context.Context, chi *api.ClickHouseInstallation, secret *core.Secret) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1580,7 +1794,7 @@ func (w *worker) getObjectStatusFromMetas(curMeta, newMeta meta.ObjectMeta) api.
 // createStatefulSet
 func (w *worker) createStatefulSet(ctx context.Context, host *api.ChiHost, register bool) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1673,7 +1887,7 @@ func (w *worker) waitConfigMapPropagation(ctx context.Context, host *api.ChiHost
 	wait := timeout - elapsed
 	w.a.V(1).M(host).F().Info("Wait for ConfigMap propagation for %s %s/%s", wait, elapsed, timeout)
 	if util.WaitContextDoneOrTimeout(ctx, wait) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return true
 	}
 
@@ -1683,7 +1897,7 @@ func (w *worker) waitConfigMapPropagation(ctx context.Context, host *api.ChiHost
 // updateStatefulSet
 func (w *worker) updateStatefulSet(ctx context.Context, host *api.ChiHost, register bool) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1704,7 +1918,7 @@ func (w *worker) updateStatefulSet(ctx context.Context, host *api.ChiHost, regis
 		Info("Update StatefulSet(%s/%s) - started", namespace, name)
 
 	if w.waitConfigMapPropagation(ctx, host) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1754,7 +1968,7 @@ func (w *worker) updateStatefulSet(ctx context.Context, host *api.ChiHost, regis
 // recreateStatefulSet
 func (w *worker) recreateStatefulSet(ctx context.Context, host *api.ChiHost, register bool) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("task is done")
+		log.Ctx(ctx).V(2).Info("task is done")
 		return nil
 	}
 
@@ -1768,7 +1982,7 @@ func (w *worker) applyPVCResourcesRequests(
 	pvc *core.PersistentVolumeClaim,
 	template *api.VolumeClaimTemplate,
 ) bool {
-	return w.applyResourcesList(pvc.Spec.Resources.Requests, template.Spec.Resources.Requests)
+	return w.applyResourcesList(pvc.Spec.Resources.Requests, w.keepExpandedPVCStorage(pvc, template))
 }
 
 // applyResourcesList
@@ -1833,25 +2047,22 @@ func (w *worker) ensureClusterSchemer(host *api.ChiHost) *schemer.ClusterSchemer
 	if w == nil {
 		return nil
 	}
-	// Make base cluster connection params
-	clusterConnectionParams := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config())
-	// Adjust base cluster connection params with per-host props
-	switch clusterConnectionParams.Scheme {
-	case api.ChSchemeAuto:
-		switch {
-		case api.IsPortAssigned(host.HTTPPort):
-			clusterConnectionParams.Scheme = "http"
-			clusterConnectionParams.Port = int(host.HTTPPort)
-		case api.IsPortAssigned(host.HTTPSPort):
-			clusterConnectionParams.Scheme = "https"
-			clusterConnectionParams.Port = int(host.HTTPSPort)
-		}
-	case api.ChSchemeHTTP:
-		clusterConnectionParams.Port = int(host.HTTPPort)
-	case api.ChSchemeHTTPS:
-		clusterConnectionParams.Port = int(host.HTTPSPort)
+	// Generated operator credentials (if any) have priority over credentials from CHOp config
+	creds, err := w.c.getOperatorCredentials(host.GetCHI())
+	if err != nil {
+		// Connection may fail, but nothing is rendered with these credentials, so it is safe to proceed
+		w.a.V(1).F().Warning("unable to get operator credentials err: %v", err)
 	}
-	w.schemer = schemer.NewClusterSchemer(clusterConnectionParams, host.Runtime.Version)
+	username, password := creds.GetConnectionCredentials()
+	w.schemer = w.newClusterSchemer(host, username, password)
 
 	return w.schemer
 }
+
+// newClusterSchemer creates cluster schemer connecting to the host with specified credentials.
+// Empty username means credentials from CHOp config are used
+func (w *worker) newClusterSchemer(host *api.ChiHost, username, password string) *schemer.ClusterSchemer {
+	// Make base cluster connection params
+	clusterConnectionParams := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config()).SetCredentials(username, password)
+	return schemer.NewClusterSchemerForHost(clusterConnectionParams, host)
+}
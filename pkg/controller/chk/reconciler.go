@@ -32,6 +32,7 @@ import (
 	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
 	apiChk "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse-keeper.altinity.com/v1"
 	apiChi "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
 
 	//	apiChi "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
@@ -72,6 +73,12 @@ func (r *ChkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	if !chop.Config().IsWatchedOperatorClass(new.Spec.GetOperatorClass()) {
+		// CHK is in charge of another operator
+		log.V(2).M(new).F().Info("skip CHK of another operator class: %s", new.Spec.GetOperatorClass())
+		return ctrl.Result{}, nil
+	}
+
 	if new.HasAncestor() {
 		log.V(2).M(new).F().Info("has ancestor, use it as a base for reconcile. CHK: %s/%s", new.Namespace, new.Name)
 		old = new.GetAncestor()
@@ -0,0 +1,255 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	apiMachinery "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/clickhouse"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// ReconcileTime is the delay between reconciliations of migrations, which are not able to be applied yet
+const ReconcileTime = 30 * time.Second
+
+// ChsmReconciler reconciles a ClickHouseSchemaMigration object
+type ChsmReconciler struct {
+	client.Client
+	Scheme *apiMachinery.Scheme
+}
+
+func (r *ChsmReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the ClickHouseSchemaMigration instance
+	migration := &api.ClickHouseSchemaMigration{}
+	if err := r.Get(ctx, req.NamespacedName, migration); err != nil {
+		if apiErrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return ctrl.Result{}, nil
+		}
+		// Return and requeue
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the ClickHouseInstallation migrations are applied to
+	chi := &api.ClickHouseInstallation{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Spec.Target.CHI}, chi); err != nil {
+		log.V(1).M(migration).F().Warning("unable to get CHI %s/%s err: %v", migration.Namespace, migration.Spec.Target.CHI, err)
+		r.updateStatus(ctx, migration, api.ClickHouseSchemaMigrationStatusPending, nil, fmt.Errorf("CHI %s is not available", migration.Spec.Target.CHI))
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	if !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) {
+		// CHI is managed by another operator, which applies migrations of the CHI as well
+		log.V(2).M(migration).F().Info("CHI %s/%s is of not watched operator class", chi.Namespace, chi.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if model.IsReconcilePaused(chi) {
+		// Schema of the CHI is not touched while its reconcile is paused
+		log.V(2).M(migration).F().Info("reconcile of CHI %s/%s is paused", chi.Namespace, chi.Name)
+		r.updateStatus(ctx, migration, api.ClickHouseSchemaMigrationStatusPending, nil, fmt.Errorf("reconcile of CHI %s is paused", chi.Name))
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	// Fetch the ConfigMap with migrations
+	configMap := &core.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Spec.ConfigMapRef.Name}, configMap); err != nil {
+		log.V(1).M(migration).F().Warning("unable to get ConfigMap %s/%s err: %v", migration.Namespace, migration.Spec.ConfigMapRef.Name, err)
+		r.updateStatus(ctx, migration, api.ClickHouseSchemaMigrationStatusPending, nil, fmt.Errorf("ConfigMap %s is not available", migration.Spec.ConfigMapRef.Name))
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	entries, err := r.migrate(ctx, migration, chi, model.NewSchemaMigrationsFromConfigMap(configMap))
+	if err != nil {
+		log.V(1).M(migration).F().Error("unable to apply migrations err: %v", err)
+		r.updateStatus(ctx, migration, api.ClickHouseSchemaMigrationStatusFailed, entries, err)
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	r.updateStatus(ctx, migration, api.ClickHouseSchemaMigrationStatusCompleted, entries, nil)
+	return ctrl.Result{}, nil
+}
+
+// migrate applies migrations, which are not applied yet, in order.
+// Stops on the first failed migration as well as on the first applied migration, which is edited afterwards
+func (r *ChsmReconciler) migrate(
+	ctx context.Context,
+	migration *api.ClickHouseSchemaMigration,
+	chi *api.ClickHouseInstallation,
+	migrations []*model.SchemaMigration,
+) (entries []api.SchemaMigrationEntryStatus, err error) {
+	host, s, err := r.newSchemer(ctx, migration, chi)
+	if err != nil {
+		return nil, err
+	}
+
+	table := migration.GetHistoryTable()
+	if err := s.HostMigrationsEnsureHistoryTable(ctx, host, table); err != nil {
+		return nil, err
+	}
+	history, err := s.HostMigrationsHistory(ctx, host, table)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range migrations {
+		entry := api.SchemaMigrationEntryStatus{
+			Version:    m.Version,
+			Checksum:   m.Checksum,
+			Status:     api.SchemaMigrationPending,
+			Statements: len(m.SQLs),
+		}
+		record, recorded := history[m.Version]
+		if recorded {
+			entry.AppliedStatements = record.Statements
+		}
+		switch {
+		case recorded && (record.Checksum != m.Checksum):
+			entry.Status = api.SchemaMigrationModified
+			entry.AppliedAt = record.AppliedAt
+			entry.Error = fmt.Sprintf("checksum mismatch, applied: %s", record.Checksum)
+			err = fmt.Errorf("migration %s is modified after being applied", m.Version)
+		case recorded && record.Completed:
+			entry.Status = api.SchemaMigrationApplied
+			entry.AppliedAt = record.AppliedAt
+			entry.AppliedStatements = len(m.SQLs)
+		case err != nil:
+			// Migrations after the failed one are not applied
+		case m.Err != nil:
+			entry.Status = api.SchemaMigrationFailed
+			entry.Error = m.Err.Error()
+			err = fmt.Errorf("migration %s is malformed: %w", m.Version, m.Err)
+		default:
+			// Partially applied migration is resumed from the first statement not applied yet
+			from := min(entry.AppliedStatements, len(m.SQLs))
+			applied, e := s.HostMigrationApply(ctx, host, table, m, from)
+			entry.AppliedStatements = applied
+			if e != nil {
+				entry.Status = api.SchemaMigrationFailed
+				entry.Error = e.Error()
+				err = fmt.Errorf("migration %s failed: %w", m.Version, e)
+			} else {
+				entry.Status = api.SchemaMigrationApplied
+				entry.AppliedAt = time.Now().UTC().Format(time.DateTime)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, err
+}
+
+// newSchemer creates schemer connected to the host migrations are applied at
+func (r *ChsmReconciler) newSchemer(
+	ctx context.Context,
+	migration *api.ClickHouseSchemaMigration,
+	chi *api.ClickHouseInstallation,
+) (*api.ChiHost, *schemer.ClusterSchemer, error) {
+	secretGet := func(namespace, name string) (*core.Secret, error) {
+		secret := &core.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+		return secret, err
+	}
+	normalized, err := normalizer.NewNormalizer(secretGet).CreateTemplatedCHI(chi.DeepCopy(), normalizer.NewOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var host *api.ChiHost
+	if migration.Spec.Target.Cluster == "" {
+		host = normalized.FirstHost()
+	} else if cluster := normalized.FindCluster(migration.Spec.Target.Cluster); cluster != nil {
+		host = cluster.FirstHost()
+	}
+	if host == nil {
+		return nil, nil, fmt.Errorf("no hosts available in CHI %s/%s cluster '%s'", chi.Namespace, chi.Name, migration.Spec.Target.Cluster)
+	}
+
+	// Generated operator credentials (if any) have priority over credentials from CHOp config
+	creds, err := model.GetOperatorCredentials(chi, secretGet)
+	if err != nil {
+		return nil, nil, err
+	}
+	username, password := creds.GetConnectionCredentials()
+	params := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config()).SetCredentials(username, password)
+	return host, schemer.NewClusterSchemerForHost(params, host), nil
+}
+
+// FindMigrationsForConfigMap maps ConfigMap to migrations referring to it
+func (r *ChsmReconciler) FindMigrationsForConfigMap(ctx context.Context, obj client.Object) (requests []reconcile.Request) {
+	list := &api.ClickHouseSchemaMigrationList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	for i := range list.Items {
+		if list.Items[i].Spec.ConfigMapRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name},
+			})
+		}
+	}
+	return requests
+}
+
+// updateStatus updates status of the migration
+func (r *ChsmReconciler) updateStatus(
+	ctx context.Context,
+	migration *api.ClickHouseSchemaMigration,
+	status string,
+	entries []api.SchemaMigrationEntryStatus,
+	err error,
+) {
+	cur := &api.ClickHouseSchemaMigration{}
+	if e := r.Get(ctx, types.NamespacedName{Namespace: migration.Namespace, Name: migration.Name}, cur); e != nil {
+		log.V(1).M(migration).F().Error("unable to get migration err: %v", e)
+		return
+	}
+
+	cur.EnsureStatus().Status = status
+	cur.Status.Error = ""
+	if err != nil {
+		cur.Status.Error = err.Error()
+	}
+	cur.Status.ObservedGeneration = cur.Generation
+	if entries != nil {
+		cur.Status.Migrations = entries
+	}
+
+	if e := r.Status().Update(ctx, cur); e != nil {
+		log.V(1).M(migration).F().Error("unable to update status err: %v", e)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chu
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	apiMachinery "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/normalizer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/chi/schemer"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/clickhouse"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// ReconcileTime is the delay between reconciliations of the user, which is not able to be reconciled yet
+const ReconcileTime = 30 * time.Second
+
+// FinalizerName specifies name of the finalizer, which is used to remove the user from ClickHouse
+const FinalizerName = "finalizer.clickhouseuser.altinity.com"
+
+// ChuReconciler reconciles a ClickHouseUser object
+type ChuReconciler struct {
+	client.Client
+	Scheme *apiMachinery.Scheme
+}
+
+func (r *ChuReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("task is done")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the ClickHouseUser instance
+	user := &api.ClickHouseUser{}
+	if err := r.Get(ctx, req.NamespacedName, user); err != nil {
+		if apiErrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return ctrl.Result{}, nil
+		}
+		// Return and requeue
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the ClickHouseInstallation the user belongs to
+	chi := &api.ClickHouseInstallation{}
+	chiErr := r.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: user.Spec.Target.CHI}, chi)
+	if chiErr != nil {
+		chi = nil
+	}
+
+	if (chi != nil) && !chop.Config().IsWatchedOperatorClass(chi.Spec.GetOperatorClass()) {
+		// CHI is managed by another operator, which reconciles users of the CHI as well
+		log.V(2).M(user).F().Info("CHI %s/%s is of not watched operator class", chi.Namespace, chi.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if (chi != nil) && model.IsReconcilePaused(chi) {
+		// Hosts of the CHI are not touched while its reconcile is paused, including removal of the user
+		log.V(2).M(user).F().Info("reconcile of CHI %s/%s is paused", chi.Namespace, chi.Name)
+		r.updateStatus(ctx, user, api.ClickHouseUserStatusPending, fmt.Errorf("reconcile of CHI %s is paused", chi.Name))
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	if user.IsDeleting() {
+		return ctrl.Result{}, r.delete(ctx, user)
+	}
+
+	if !util.InArray(FinalizerName, user.Finalizers) {
+		log.V(2).M(user).F().Info("install finalizer")
+		user.Finalizers = append(user.Finalizers, FinalizerName)
+		if err := r.Update(ctx, user); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if chi == nil {
+		log.V(1).M(user).F().Warning("unable to get CHI %s/%s err: %v", user.Namespace, user.Spec.Target.CHI, chiErr)
+		r.updateStatus(ctx, user, api.ClickHouseUserStatusPending, fmt.Errorf("CHI %s is not available", user.Spec.Target.CHI))
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	err := model.ValidateUser(user)
+	if err == nil {
+		err = r.reconcileUser(ctx, user, chi)
+	}
+	if err != nil {
+		log.V(1).M(user).F().Error("unable to reconcile user %s err: %v", user.GetUsername(), err)
+		r.updateStatus(ctx, user, api.ClickHouseUserStatusFailed, err)
+		return ctrl.Result{RequeueAfter: ReconcileTime}, nil
+	}
+
+	r.updateStatus(ctx, user, api.ClickHouseUserStatusCompleted, nil)
+	return ctrl.Result{}, nil
+}
+
+// reconcileUser provisions the user according to its mode
+func (r *ChuReconciler) reconcileUser(ctx context.Context, user *api.ClickHouseUser, chi *api.ClickHouseInstallation) error {
+	switch user.GetMode() {
+	case api.ClickHouseUserModeConfig:
+		// User is moved from SQL to config, SQL-provisioned user has to be removed first
+		if err := r.dropProvisionedSQLUser(ctx, user); err != nil {
+			return err
+		}
+		// Users config is owned by the CHI and is regenerated by the CHI controller as soon as the user changes.
+		// Grants are validated here in order to report invalid ones in the status of the user
+		_, err := model.CreateUserGrantQueries(user)
+		return err
+	case api.ClickHouseUserModeSQL:
+		// User is moved to another CHI or cluster, it has to be removed from the previous one first
+		if user.GetProvisionedTarget() != user.Spec.Target {
+			if err := r.dropProvisionedSQLUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		// In case user is moved from config to SQL, the CHI controller removes it from users config
+		return r.reconcileSQLUser(ctx, user, chi)
+	}
+	return nil
+}
+
+// delete removes the user from ClickHouse and releases the finalizer
+func (r *ChuReconciler) delete(ctx context.Context, user *api.ClickHouseUser) error {
+	if !util.InArray(FinalizerName, user.Finalizers) {
+		// No finalizer - nothing to do
+		return nil
+	}
+
+	// Config-provisioned user is removed from users config by the CHI controller
+	if err := r.dropProvisionedSQLUser(ctx, user); err != nil {
+		// CHI may be not operational at all, do not block user deletion
+		log.V(1).M(user).F().Warning("unable to remove user %s err: %v", user.GetUsername(), err)
+	}
+
+	log.V(2).M(user).F().Info("uninstall finalizer")
+	user.Finalizers = util.RemoveFromArray(FinalizerName, user.Finalizers)
+	return r.Update(ctx, user)
+}
+
+// reconcileSQLUser creates the user with SQL and brings its grants and roles up to the spec
+func (r *ChuReconciler) reconcileSQLUser(ctx context.Context, user *api.ClickHouseUser, chi *api.ClickHouseInstallation) error {
+	password, err := r.getPassword(ctx, user)
+	if err != nil {
+		return err
+	}
+	passwordSHA256 := ""
+	if password != "" {
+		sum := sha256.Sum256([]byte(password))
+		passwordSHA256 = hex.EncodeToString(sum[:])
+	}
+
+	// Grants are validated before anything is executed
+	grants, err := model.CreateUserGrantSQLs(user, user.Spec.Grants, user.Spec.Roles)
+	if err != nil {
+		return err
+	}
+	// Revoke grants and roles, which were applied previously and are not in the spec anymore
+	status := user.EnsureStatus()
+	revokes, err := model.CreateUserRevokeSQLs(user,
+		subtract(status.Grants, user.Spec.Grants),
+		subtract(status.Roles, user.Spec.Roles),
+	)
+	if err != nil {
+		return err
+	}
+
+	sqls := model.CreateUserSQLs(user, passwordSHA256)
+	sqls = append(sqls, revokes...)
+	sqls = append(sqls, grants...)
+	return r.exec(ctx, chi, sqls)
+}
+
+// dropProvisionedSQLUser drops the user from the target it is provisioned into with SQL, as recorded in the status.
+// Nothing is done in case the user is not provisioned with SQL or the CHI it is provisioned into does not exist anymore
+func (r *ChuReconciler) dropProvisionedSQLUser(ctx context.Context, user *api.ClickHouseUser) error {
+	if user.EnsureStatus().Mode != api.ClickHouseUserModeSQL {
+		return nil
+	}
+
+	provisioned := user.DeepCopy()
+	provisioned.Spec.Target = user.GetProvisionedTarget()
+	chi := &api.ClickHouseInstallation{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: provisioned.Spec.Target.CHI}, chi); err != nil {
+		if apiErrors.IsNotFound(err) {
+			// Users of the deleted CHI are deleted along with it
+			return nil
+		}
+		return err
+	}
+	log.V(1).M(user).F().Info("drop user %s from CHI %s", user.GetUsername(), chi.Name)
+	return r.exec(ctx, chi, model.CreateUserDropSQLs(provisioned))
+}
+
+// exec executes SQLs on the first available host of the CHI. SQLs are expected to be ON CLUSTER ones
+func (r *ChuReconciler) exec(ctx context.Context, chi *api.ClickHouseInstallation, sqls []string) error {
+	normalized, err := r.normalize(ctx, chi)
+	if err != nil {
+		return err
+	}
+
+	// Generated operator credentials (if any) have priority over credentials from CHOp config
+	creds, err := model.GetOperatorCredentials(chi, func(namespace, name string) (*core.Secret, error) {
+		return r.getSecret(ctx, namespace, name)
+	})
+	if err != nil {
+		return err
+	}
+	username, password := creds.GetConnectionCredentials()
+
+	err = fmt.Errorf("no hosts available in CHI %s/%s", chi.Namespace, chi.Name)
+	normalized.WalkHosts(func(host *api.ChiHost) error {
+		if err == nil {
+			// Already executed
+			return nil
+		}
+		params := clickhouse.NewClusterConnectionParamsFromCHOpConfig(chop.Config()).SetCredentials(username, password)
+		err = schemer.NewClusterSchemerForHost(params, host).ExecHost(ctx, host, sqls)
+		return nil
+	})
+	return err
+}
+
+// normalize normalizes the CHI in order to find its hosts
+func (r *ChuReconciler) normalize(ctx context.Context, chi *api.ClickHouseInstallation) (*api.ClickHouseInstallation, error) {
+	return normalizer.NewNormalizer(func(namespace, name string) (*core.Secret, error) {
+		return r.getSecret(ctx, namespace, name)
+	}).CreateTemplatedCHI(chi.DeepCopy(), normalizer.NewOptions())
+}
+
+// updateStatus updates status of the user
+func (r *ChuReconciler) updateStatus(ctx context.Context, user *api.ClickHouseUser, status string, err error) {
+	cur := &api.ClickHouseUser{}
+	if e := r.Get(ctx, getNamespacedName(user), cur); e != nil {
+		log.V(1).M(user).F().Error("unable to get user err: %v", e)
+		return
+	}
+
+	cur.EnsureStatus().Status = status
+	cur.Status.Error = ""
+	if err != nil {
+		cur.Status.Error = err.Error()
+	}
+	cur.Status.ObservedGeneration = cur.Generation
+	if status == api.ClickHouseUserStatusCompleted {
+		// Remember what is provisioned, to be able to clean it up later
+		cur.Status.Mode = user.GetMode()
+		cur.Status.Target = nil
+		cur.Status.Grants = nil
+		cur.Status.Roles = nil
+		if cur.Status.Mode == api.ClickHouseUserModeSQL {
+			target := user.Spec.Target
+			cur.Status.Target = &target
+			cur.Status.Grants = append([]string{}, user.Spec.Grants...)
+			cur.Status.Roles = append([]string{}, user.Spec.Roles...)
+		}
+	}
+
+	if e := r.Status().Update(ctx, cur); e != nil {
+		log.V(1).M(user).F().Error("unable to update status err: %v", e)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chu
+
+import (
+	"context"
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+func getNamespacedName(obj meta.Object) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// getSecret gets Secret by namespace and name
+func (r *ChuReconciler) getSecret(ctx context.Context, namespace, name string) (*core.Secret, error) {
+	secret := &core.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	return secret, err
+}
+
+// getPassword gets plaintext password of the user from the Secret.
+// Returns default user password in case no Secret specified
+func (r *ChuReconciler) getPassword(ctx context.Context, user *api.ClickHouseUser) (string, error) {
+	ref := user.Spec.PasswordSecretRef
+	if ref == nil {
+		return chop.Config().ClickHouse.Config.User.Default.Password, nil
+	}
+	secret := &core.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: ref.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, user.Namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// subtract returns items of a, which are not present in b
+func subtract(a, b []string) (res []string) {
+	for _, item := range a {
+		if !util.InArray(item, b) {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// FindUsersForSecret maps Secret to SQL-provisioned users, which passwords are sourced from it.
+// Config-provisioned users are re-rendered by the CHI controller, which watches Secrets on its own
+func (r *ChuReconciler) FindUsersForSecret(ctx context.Context, obj client.Object) (requests []reconcile.Request) {
+	list := &api.ClickHouseUserList{}
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	for i := range list.Items {
+		user := &list.Items[i]
+		if (user.GetMode() == api.ClickHouseUserModeSQL) && user.IsPasswordSourcedFrom(obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: getNamespacedName(user),
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/version"
+)
+
+// Keys of the span attributes
+const (
+	AttributeNamespace   = "clickhouse.namespace"
+	AttributeCHI         = "clickhouse.chi"
+	AttributeCluster     = "clickhouse.cluster"
+	AttributeShard       = "clickhouse.shard"
+	AttributeHost        = "clickhouse.host"
+	AttributeCommand     = "clickhouse.command"
+	AttributeTaskID      = "clickhouse.task_id"
+	AttributeReconcileID = "clickhouse.reconcile_id"
+	AttributeOutcome     = "outcome"
+)
+
+// Values of the outcome attribute
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+const tracerName = "clickhouse-operator-tracer"
+
+// statementsTraced specifies whether SQL statements are exported as span attributes
+var statementsTraced bool
+
+// IsStatementTracingEnabled checks whether SQL statements are to be exported as span attributes
+func IsStatementTracingEnabled() bool {
+	return statementsTraced
+}
+
+// StartTracing starts export of spans via OTLP/HTTP.
+// In case endpoint is not specified, standard OTEL_EXPORTER_OTLP_* env vars are respected.
+// SQL statements are exported only in case statements is set, since they may carry sensitive data.
+// Returns function, which flushes spans remaining and stops export
+func StartTracing(
+	ctx context.Context,
+	endpoint string,
+	insecure bool,
+	sampleRatio float64,
+	statements bool,
+) (func(context.Context), error) {
+	resource, err := newOTELResource()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+	}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdkTrace.NewTracerProvider(
+		sdkTrace.WithResource(resource),
+		sdkTrace.WithBatcher(exporter),
+		sdkTrace.WithSampler(sdkTrace.ParentBased(sdkTrace.TraceIDRatioBased(sampleRatio))),
+	)
+
+	statementsTraced = statements
+
+	// Register as global tracer provider, thus spans of the instrumentation libraries are exported as well
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) {
+		_ = tracerProvider.Shutdown(ctx)
+	}, nil
+}
+
+// Tracer gets tracer of the operator. Spans are not recorded till tracing is started
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName, trace.WithInstrumentationVersion(version.Version))
+}
+
+// StartSpan starts span as a child of the span of the context, if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends span with the outcome of the operation
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String(AttributeOutcome, OutcomeError))
+	} else {
+		span.SetStatus(codes.Ok, "")
+		span.SetAttributes(attribute.String(AttributeOutcome, OutcomeSuccess))
+	}
+	span.End()
+}
@@ -15,6 +15,9 @@
 package chi
 
 import (
+	"fmt"
+	"strings"
+
 	"gopkg.in/d4l3k/messagediff.v1"
 
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -234,6 +237,55 @@ func (ap *ActionPlan) String() string {
 	return str
 }
 
+// Summary summarizes ActionPlan in a short single-line form
+func (ap *ActionPlan) Summary() string {
+	if !ap.HasActionsToDo() {
+		return ""
+	}
+
+	var items []string
+
+	if (ap.specDiff != nil) && (len(ap.specDiff.Added)+len(ap.specDiff.Removed)+len(ap.specDiff.Modified) > 0) {
+		items = append(items, fmt.Sprintf(
+			"spec items added: %d, removed: %d, modified: %d",
+			len(ap.specDiff.Added), len(ap.specDiff.Removed), len(ap.specDiff.Modified),
+		))
+
+		var added int
+		ap.WalkAdded(
+			func(cluster *api.Cluster) {
+				added += cluster.HostsCount()
+			},
+			func(shard *api.ChiShard) {
+				added += shard.HostsCount()
+			},
+			func(host *api.ChiHost) {
+				added++
+			},
+		)
+		if added > 0 {
+			items = append(items, fmt.Sprintf("hosts to add: %d", added))
+		}
+		if removed := ap.GetRemovedHostsNum(); removed > 0 {
+			items = append(items, fmt.Sprintf("hosts to remove: %d", removed))
+		}
+	}
+
+	if !ap.labelsEqual {
+		items = append(items, "labels modified")
+	}
+
+	if !ap.deletionTimestampEqual {
+		items = append(items, "deletion timestamp modified")
+	}
+
+	if !ap.finalizersEqual {
+		items = append(items, "finalizers modified")
+	}
+
+	return strings.Join(items, "; ")
+}
+
 // GetNewHostsNum - total number of hosts to be achieved
 func (ap *ActionPlan) GetNewHostsNum() int {
 	return ap.new.HostsCount()
@@ -262,6 +314,8 @@ func (ap *ActionPlan) WalkRemoved(
 	shardFunc func(shard *api.ChiShard),
 	hostFunc func(host *api.ChiHost),
 ) {
+	// External hosts are not managed by the operator
+	hostFunc = skipExternalHosts(hostFunc)
 	// TODO refactor to map[string]object handling, instead of slice
 	for path := range ap.specDiff.Removed {
 		switch ap.specDiff.Removed[path].(type) {
@@ -293,6 +347,8 @@ func (ap *ActionPlan) WalkAdded(
 	shardFunc func(shard *api.ChiShard),
 	hostFunc func(host *api.ChiHost),
 ) {
+	// External hosts are not managed by the operator
+	hostFunc = skipExternalHosts(hostFunc)
 	// TODO refactor to map[string]object handling, instead of slice
 	for path := range ap.specDiff.Added {
 		switch ap.specDiff.Added[path].(type) {
@@ -324,6 +380,8 @@ func (ap *ActionPlan) WalkModified(
 	shardFunc func(shard *api.ChiShard),
 	hostFunc func(host *api.ChiHost),
 ) {
+	// External hosts are not managed by the operator
+	hostFunc = skipExternalHosts(hostFunc)
 	// TODO refactor to map[string]object handling, instead of slice
 	for path := range ap.specDiff.Modified {
 		switch ap.specDiff.Modified[path].(type) {
@@ -348,3 +406,12 @@ func (ap *ActionPlan) WalkModified(
 		}
 	}
 }
+
+// skipExternalHosts wraps host function in order to skip hosts, which are not managed by the operator
+func skipExternalHosts(f func(host *api.ChiHost)) func(host *api.ChiHost) {
+	return func(host *api.ChiHost) {
+		if !host.IsExternal() {
+			f(host)
+		}
+	}
+}
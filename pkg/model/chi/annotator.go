@@ -17,11 +17,33 @@ package chi
 import (
 	core "k8s.io/api/core/v1"
 
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com"
 	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
 
+// Set of kubernetes annotations used by the operator
+const (
+	// AnnotationReconcile specifies reconcile mode of the CHI
+	AnnotationReconcile = clickhouse_altinity_com.APIGroupName + "/" + "reconcile"
+	// AnnotationReconcileValuePaused means CHI is not reconciled until the annotation is removed
+	AnnotationReconcileValuePaused = "paused"
+	// AnnotationDataSourcesVersion specifies version of the data of config maps, which settings and files of the pod are sourced from
+	AnnotationDataSourcesVersion = clickhouse_altinity_com.APIGroupName + "/" + "data-sources-version"
+	// AnnotationPVReclaimPolicy specifies original reclaim policy of the volume retained by storage class migration
+	AnnotationPVReclaimPolicy = clickhouse_altinity_com.APIGroupName + "/" + "pv-reclaim-policy"
+)
+
+// IsReconcilePaused checks whether reconcile of the CHI is paused by annotation.
+// Deletion of the CHI is never paused
+func IsReconcilePaused(chi *api.ClickHouseInstallation) bool {
+	if (chi == nil) || (chi.DeletionTimestamp != nil) {
+		return false
+	}
+	return chi.Annotations[AnnotationReconcile] == AnnotationReconcileValuePaused
+}
+
 // Annotator is an entity which can annotate CHI artifacts
 type Annotator struct {
 	chi *api.ClickHouseInstallation
@@ -50,6 +72,14 @@ func (a *Annotator) GetConfigMapCHICommonUsers() map[string]string {
 	)
 }
 
+// GetConfigMapCHIRevision
+func (a *Annotator) GetConfigMapCHIRevision() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		a.getCHIScope(),
+		nil,
+	)
+}
+
 // GetConfigMapHost
 func (a *Annotator) GetConfigMapHost(host *api.ChiHost) map[string]string {
 	return util.MergeStringMapsOverwrite(
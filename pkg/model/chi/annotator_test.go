@@ -0,0 +1,62 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestIsReconcilePaused(t *testing.T) {
+	newCHI := func(annotations map[string]string, deleted bool) *api.ClickHouseInstallation {
+		chi := &api.ClickHouseInstallation{}
+		chi.Annotations = annotations
+		if deleted {
+			chi.DeletionTimestamp = &meta.Time{}
+		}
+		return chi
+	}
+
+	tests := []struct {
+		name   string
+		chi    *api.ClickHouseInstallation
+		paused bool
+	}{
+		{
+			name: "no CHI",
+		},
+		{
+			name: "no annotations",
+			chi:  newCHI(nil, false),
+		},
+		{
+			name:   "paused",
+			chi:    newCHI(map[string]string{AnnotationReconcile: AnnotationReconcileValuePaused}, false),
+			paused: true,
+		},
+		{
+			name: "annotation of another value",
+			chi:  newCHI(map[string]string{AnnotationReconcile: "enabled"}, false),
+		},
+		{
+			name: "value is case sensitive",
+			chi:  newCHI(map[string]string{AnnotationReconcile: "Paused"}, false),
+		},
+		{
+			name: "value of another annotation",
+			chi:  newCHI(map[string]string{"reconcile": AnnotationReconcileValuePaused}, false),
+		},
+		{
+			name: "deletion is never paused",
+			chi:  newCHI(map[string]string{AnnotationReconcile: AnnotationReconcileValuePaused}, true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.paused, IsReconcilePaused(tt.chi))
+		})
+	}
+}
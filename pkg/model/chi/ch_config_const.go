@@ -27,6 +27,7 @@ const (
 	configQuotas        = "quotas"
 	configRemoteServers = "remote_servers"
 	configSettings      = "settings"
+	configStorage       = "storage"
 	configUsers         = "users"
 	configZookeeper     = "zookeeper"
 )
@@ -58,6 +59,12 @@ const (
 	// DirPathSecretFilesConfig specifies full path to folder, where secrets are mounted
 	DirPathSecretFilesConfig = "/etc/clickhouse-server/secrets.d/"
 
+	// DirPathConfigMapFilesConfig specifies full path to folder, where config maps are mounted
+	DirPathConfigMapFilesConfig = "/etc/clickhouse-server/configmaps.d/"
+
+	// DirPathDownwardAPIFilesConfig specifies full path to folder, where pod and container fields are mounted
+	DirPathDownwardAPIFilesConfig = "/etc/clickhouse-server/podinfo.d/"
+
 	// DirPathClickHouseData specifies full path of data folder where ClickHouse would place its data storage
 	DirPathClickHouseData = "/var/lib/clickhouse"
 
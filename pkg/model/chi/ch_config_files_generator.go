@@ -15,6 +15,7 @@
 package chi
 
 import (
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
 	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
@@ -25,6 +26,8 @@ type ClickHouseConfigFilesGenerator struct {
 	chConfigGenerator *ClickHouseConfigGenerator
 	// clickhouse-operator configuration
 	chopConfig *api.OperatorConfig
+	// Renderer of the generated config files
+	renderer ConfigRenderer
 }
 
 // NewClickHouseConfigFilesGenerator creates new clickhouse configuration generator object
@@ -35,6 +38,7 @@ func NewClickHouseConfigFilesGenerator(
 	return &ClickHouseConfigFilesGenerator{
 		chConfigGenerator: chConfigGenerator,
 		chopConfig:        chopConfig,
+		renderer:          NewConfigRenderer(chConfigGenerator.chi.Spec.Configuration.GetFormat()),
 	}
 }
 
@@ -80,9 +84,11 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupCommon(options *C
 	// commonConfigSections maps section name to section XML chopConfig of the following sections:
 	// 1. remote servers
 	// 2. common settings
-	// 3. common files
-	util.IncludeNonEmpty(commonConfigSections, createConfigSectionFilename(configRemoteServers), c.chConfigGenerator.GetRemoteServers(options.GetRemoteServersGeneratorOptions()))
-	util.IncludeNonEmpty(commonConfigSections, createConfigSectionFilename(configSettings), c.chConfigGenerator.GetSettingsGlobal())
+	// 3. storage configuration
+	// 4. common files
+	c.includeConfigSection(commonConfigSections, configRemoteServers, c.chConfigGenerator.GetRemoteServers(options.GetRemoteServersGeneratorOptions()))
+	c.includeConfigSection(commonConfigSections, configSettings, c.chConfigGenerator.GetSettingsGlobal())
+	c.includeConfigSection(commonConfigSections, configStorage, c.chConfigGenerator.GetStorageConfiguration())
 	util.MergeStringMapsOverwrite(commonConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionCommon, true, nil))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(commonConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.CommonConfigFiles)
@@ -98,9 +104,9 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupUsers() map[strin
 	// 2. quotas
 	// 3. profiles
 	// 4. user files
-	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configUsers), c.chConfigGenerator.GetUsers())
-	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configQuotas), c.chConfigGenerator.GetQuotas())
-	util.IncludeNonEmpty(commonUsersConfigSections, createConfigSectionFilename(configProfiles), c.chConfigGenerator.GetProfiles())
+	c.includeConfigSection(commonUsersConfigSections, configUsers, c.chConfigGenerator.GetUsers())
+	c.includeConfigSection(commonUsersConfigSections, configQuotas, c.chConfigGenerator.GetQuotas())
+	c.includeConfigSection(commonUsersConfigSections, configProfiles, c.chConfigGenerator.GetProfiles())
 	util.MergeStringMapsOverwrite(commonUsersConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionUsers, false, nil))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(commonUsersConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.UsersConfigFiles)
@@ -112,10 +118,10 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupUsers() map[strin
 func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupHost(host *api.ChiHost) map[string]string {
 	// Prepare for this replica deployment chopConfig files map as filename->content
 	hostConfigSections := make(map[string]string)
-	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configMacros), c.chConfigGenerator.GetHostMacros(host))
-	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configHostnamePorts), c.chConfigGenerator.GetHostHostnameAndPorts(host))
-	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configZookeeper), c.chConfigGenerator.GetHostZookeeper(host))
-	util.IncludeNonEmpty(hostConfigSections, createConfigSectionFilename(configSettings), c.chConfigGenerator.GetSettings(host))
+	c.includeConfigSection(hostConfigSections, configMacros, c.chConfigGenerator.GetHostMacros(host))
+	c.includeConfigSection(hostConfigSections, configHostnamePorts, c.chConfigGenerator.GetHostHostnameAndPorts(host))
+	c.includeConfigSection(hostConfigSections, configZookeeper, c.chConfigGenerator.GetHostZookeeper(host))
+	c.includeConfigSection(hostConfigSections, configSettings, c.chConfigGenerator.GetSettings(host))
 	util.MergeStringMapsOverwrite(hostConfigSections, c.chConfigGenerator.GetSectionFromFiles(api.SectionHost, true, host))
 	// Extra user-specified config files
 	util.MergeStringMapsOverwrite(hostConfigSections, c.chopConfig.ClickHouse.Config.File.Runtime.HostConfigFiles)
@@ -123,8 +129,23 @@ func (c *ClickHouseConfigFilesGenerator) CreateConfigFilesGroupHost(host *api.Ch
 	return hostConfigSections
 }
 
+// includeConfigSection renders XML config of a section and includes it into config files in case it is not empty.
+// Section, which is failed to be rendered, is included as XML
+func (c *ClickHouseConfigFilesGenerator) includeConfigSection(files map[string]string, section, xmlConfig string) {
+	if xmlConfig == "" {
+		return
+	}
+	config, err := c.renderer.Render(xmlConfig)
+	if err != nil {
+		log.V(1).M(c.chConfigGenerator.chi).F().Warning("unable to render config section %s, XML is used. err: %v", section, err)
+		util.IncludeNonEmpty(files, createConfigSectionFilename(section, NewConfigRenderer(api.ConfigFormatXML).Extension()), xmlConfig)
+		return
+	}
+	util.IncludeNonEmpty(files, createConfigSectionFilename(section, c.renderer.Extension()), config)
+}
+
 // createConfigSectionFilename creates filename of a configuration file.
-// filename depends on a section which it will contain
-func createConfigSectionFilename(section string) string {
-	return "chop-generated-" + section + ".xml"
+// filename depends on a section which it will contain and on format of the file
+func createConfigSectionFilename(section, extension string) string {
+	return "chop-generated-" + section + extension
 }
@@ -290,11 +290,11 @@ func (c *ClickHouseConfigGenerator) ClusterHostsNum(cluster *api.Cluster, option
 	return num
 }
 
-// ShardHostsNum count hosts according to the options
+// ShardHostsNum count hosts according to the options. External hosts are counted as well
 func (c *ClickHouseConfigGenerator) ShardHostsNum(shard *api.ChiShard, options *RemoteServersGeneratorOptions) int {
 	num := 0
-	shard.WalkHosts(func(host *api.ChiHost) error {
-		if options.Include(host) {
+	shard.WalkAllHosts(func(host *api.ChiHost) error {
+		if c.includeRemoteServersReplica(host, options) {
 			num++
 		}
 		return nil
@@ -302,6 +302,15 @@ func (c *ClickHouseConfigGenerator) ShardHostsNum(shard *api.ChiShard, options *
 	return num
 }
 
+// includeRemoteServersReplica tells whether to include the host into user-specified clusters.
+// External host is not reconciled by the operator, thus it is included regardless of the options
+func (c *ClickHouseConfigGenerator) includeRemoteServersReplica(host *api.ChiHost, options *RemoteServersGeneratorOptions) bool {
+	if host.IsExternal() {
+		return host.External.GetHostname() != ""
+	}
+	return options.Include(host)
+}
+
 func (c *ClickHouseConfigGenerator) getRemoteServersReplica(host *api.ChiHost, b *bytes.Buffer) {
 	// <replica>
 	//		<host>XXX</host>
@@ -372,8 +381,8 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 				util.Iline(b, 16, "<weight>%d</weight>", shard.GetWeight())
 			}
 
-			shard.WalkHosts(func(host *api.ChiHost) error {
-				if options.Include(host) {
+			shard.WalkAllHosts(func(host *api.ChiHost) error {
+				if c.includeRemoteServersReplica(host, options) {
 					c.getRemoteServersReplica(host, b)
 				}
 				return nil
@@ -390,6 +399,9 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 		return nil
 	})
 
+	// Virtual clusters
+	c.getRemoteServersVirtualClusters(b)
+
 	// Auto-generated clusters
 
 	if c.CHIHostsNum(options) < 1 {
@@ -548,6 +560,9 @@ func (c *ClickHouseConfigGenerator) getDistributedDDLPath() string {
 // getRemoteServersReplicaHostname returns hostname (podhostname + service or FQDN) for "remote_servers.xml"
 // based on .Spec.Defaults.ReplicasUseFQDN
 func (c *ClickHouseConfigGenerator) getRemoteServersReplicaHostname(host *api.ChiHost) string {
+	if host.IsExternal() {
+		return host.External.GetHostname()
+	}
 	return CreateInstanceHostname(host)
 }
 
@@ -0,0 +1,166 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// Set of storage disk fields, which are sourced from secrets
+const (
+	StorageDiskFieldAccessKeyID     = "access_key_id"
+	StorageDiskFieldSecretAccessKey = "secret_access_key"
+	StorageDiskFieldAccountName     = "account_name"
+	StorageDiskFieldAccountKey      = "account_key"
+)
+
+const envVarNamePrefixConfigurationStorage = "CONFIGURATION_STORAGE_DISKS"
+
+// CreateStorageDiskEnvVarName creates name of the ENV var, which provides value of the disk's field sourced from a secret
+func CreateStorageDiskEnvVarName(disk *api.StorageDisk, field string) string {
+	// In case not OK env var name will be empty and config will be incorrect. CH may not start
+	name, _ := util.BuildShellEnvVarName(envVarNamePrefixConfigurationStorage + "_" + disk.Name + "_" + field)
+	return name
+}
+
+// GetStorageConfiguration creates data for "storage.xml"
+func (c *ClickHouseConfigGenerator) GetStorageConfiguration() string {
+	storage := c.chi.Spec.Configuration.Storage
+
+	if storage.IsEmpty() {
+		// No storage configuration provided
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//		<storage_configuration>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<storage_configuration>")
+
+	if len(storage.Disks) > 0 {
+		// <disks>
+		util.Iline(b, 8, "<disks>")
+		for _, disk := range storage.Disks {
+			c.getStorageDisk(b, disk)
+		}
+		// </disks>
+		util.Iline(b, 8, "</disks>")
+	}
+
+	if len(storage.Policies) > 0 {
+		// <policies>
+		util.Iline(b, 8, "<policies>")
+		for _, policy := range storage.Policies {
+			c.getStoragePolicy(b, policy)
+		}
+		// </policies>
+		util.Iline(b, 8, "</policies>")
+	}
+
+	//		</storage_configuration>
+	// </yandex>
+	util.Iline(b, 4, "</storage_configuration>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
+// getStorageDisk writes disk section.
+// Names of disks, policies and volumes are validated by the normalizer, since they are used as tag names
+func (c *ClickHouseConfigGenerator) getStorageDisk(b *bytes.Buffer, disk *api.StorageDisk) {
+	// <NAME>
+	//		<type>TYPE</type>
+	util.Iline(b, 12, "<%s>", disk.Name)
+	util.Iline(b, 12, "    <type>%s</type>", util.EscapeXML(disk.Type))
+
+	c.getStorageDiskField(b, "path", disk.Path)
+	c.getStorageDiskField(b, "keep_free_space_bytes", disk.KeepFreeSpaceBytes)
+
+	// S3
+	c.getStorageDiskField(b, "endpoint", disk.Endpoint)
+	c.getStorageDiskField(b, "region", disk.Region)
+	c.getStorageDiskSourceField(b, disk, StorageDiskFieldAccessKeyID, disk.AccessKeyID)
+	c.getStorageDiskSourceField(b, disk, StorageDiskFieldSecretAccessKey, disk.SecretAccessKey)
+	if disk.UseEnvironmentCredentials.HasValue() {
+		c.getStorageDiskField(b, "use_environment_credentials", disk.UseEnvironmentCredentials.CastToStringTrueFalse(false))
+	}
+
+	// Azure blob storage
+	c.getStorageDiskField(b, "storage_account_url", disk.StorageAccountURL)
+	c.getStorageDiskField(b, "container_name", disk.ContainerName)
+	c.getStorageDiskSourceField(b, disk, StorageDiskFieldAccountName, disk.AccountName)
+	c.getStorageDiskSourceField(b, disk, StorageDiskFieldAccountKey, disk.AccountKey)
+
+	c.getStorageDiskField(b, "metadata_path", disk.MetadataPath)
+
+	// Cache
+	c.getStorageDiskField(b, "disk", disk.Disk)
+	c.getStorageDiskField(b, "max_size", disk.MaxSize)
+
+	// </NAME>
+	util.Iline(b, 12, "</%s>", disk.Name)
+}
+
+// getStorageDiskField writes disk field in case it has a value
+func (c *ClickHouseConfigGenerator) getStorageDiskField(b *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	util.Iline(b, 12, "    <%s>%s</%s>", name, util.EscapeXML(value), name)
+}
+
+// getStorageDiskSourceField writes disk field sourced from a secret, a config map or a field of the pod.
+// Value itself is not written, ClickHouse reads it from the ENV var
+func (c *ClickHouseConfigGenerator) getStorageDiskSourceField(b *bytes.Buffer, disk *api.StorageDisk, name string, src *api.SettingSource) {
+	if !src.HasValue() {
+		return
+	}
+	util.Iline(b, 12, "    <%s from_env=\"%s\"/>", name, CreateStorageDiskEnvVarName(disk, name))
+}
+
+// getStoragePolicy writes policy section
+func (c *ClickHouseConfigGenerator) getStoragePolicy(b *bytes.Buffer, policy *api.StoragePolicy) {
+	// <NAME>
+	//		<volumes>
+	util.Iline(b, 12, "<%s>", policy.Name)
+	util.Iline(b, 12, "    <volumes>")
+	for _, volume := range policy.Volumes {
+		// <VOLUME>
+		//		<disk>DISK</disk>
+		util.Iline(b, 20, "<%s>", volume.Name)
+		for _, disk := range volume.Disks {
+			util.Iline(b, 20, "    <disk>%s</disk>", util.EscapeXML(disk))
+		}
+		if volume.MaxDataPartSizeBytes != "" {
+			util.Iline(b, 20, "    <max_data_part_size_bytes>%s</max_data_part_size_bytes>", util.EscapeXML(volume.MaxDataPartSizeBytes))
+		}
+		if volume.PreferNotToMerge.HasValue() {
+			util.Iline(b, 20, "    <prefer_not_to_merge>%s</prefer_not_to_merge>", volume.PreferNotToMerge.CastToStringTrueFalse(false))
+		}
+		// </VOLUME>
+		util.Iline(b, 20, "</%s>", volume.Name)
+	}
+	//		</volumes>
+	util.Iline(b, 12, "    </volumes>")
+	if policy.MoveFactor != "" {
+		util.Iline(b, 12, "    <move_factor>%s</move_factor>", util.EscapeXML(policy.MoveFactor))
+	}
+	// </NAME>
+	util.Iline(b, 12, "</%s>", policy.Name)
+}
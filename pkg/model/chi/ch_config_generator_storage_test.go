@@ -0,0 +1,187 @@
+package chi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/xml"
+)
+
+// minioEndpoint is an S3 endpoint of MinIO, which stands in for S3 in tests.
+// Query string checks values with XML special characters are escaped
+const minioEndpoint = "http://minio.minio.svc:9000/clickhouse/data/?a=1&b=<2>"
+
+// getChild gets child element by the path of names
+func getChild(t *testing.T, node *xml.Node, path ...string) *xml.Node {
+	for _, name := range path {
+		var found *xml.Node
+		for _, child := range node.Children {
+			if child.Name == name {
+				found = child
+				break
+			}
+		}
+		require.NotNil(t, found, "element %s not found in %s", name, strings.Join(path, "/"))
+		node = found
+	}
+	return node
+}
+
+func newTestStorageCHI() *api.ClickHouseInstallation {
+	chi := &api.ClickHouseInstallation{}
+	chi.Spec.Configuration = &api.Configuration{
+		Storage: &api.StorageConfiguration{
+			Disks: []*api.StorageDisk{
+				{
+					Name:     "minio",
+					Type:     api.StorageDiskTypeS3,
+					Endpoint: minioEndpoint,
+					Region:   "us-east-1",
+					AccessKeyID: &api.SettingSource{
+						ValueFrom: &api.DataSource{
+							SecretKeyRef: &core.SecretKeySelector{
+								LocalObjectReference: core.LocalObjectReference{Name: "minio"},
+								Key:                  "access",
+							},
+						},
+					},
+					SecretAccessKey: &api.SettingSource{
+						ValueFrom: &api.DataSource{
+							SecretKeyRef: &core.SecretKeySelector{
+								LocalObjectReference: core.LocalObjectReference{Name: "minio"},
+								Key:                  "secret",
+							},
+						},
+					},
+					MetadataPath: "/var/lib/clickhouse/disks/minio/",
+				},
+				{
+					Name:    "minio-cache",
+					Type:    api.StorageDiskTypeCache,
+					Disk:    "minio",
+					Path:    "/var/lib/clickhouse/disks/minio-cache/",
+					MaxSize: "10Gi",
+				},
+			},
+			Policies: []*api.StoragePolicy{
+				{
+					Name: "tiered",
+					Volumes: []*api.StorageVolume{
+						{
+							Name:  "hot",
+							Disks: []string{"default"},
+						},
+						{
+							Name:                 "cold",
+							Disks:                []string{"minio-cache"},
+							MaxDataPartSizeBytes: "1073741824",
+						},
+					},
+					MoveFactor: "0.2",
+				},
+			},
+		},
+	}
+	return chi
+}
+
+func TestGetStorageConfiguration(t *testing.T) {
+	chi := newTestStorageCHI()
+	config := NewClickHouseConfigGenerator(chi).GetStorageConfiguration()
+
+	root, err := xml.Parse(strings.NewReader(config))
+	require.NoError(t, err, config)
+	storage := getChild(t, root, "storage_configuration")
+
+	minio := getChild(t, storage, "disks", "minio")
+	require.Equal(t, "s3", getChild(t, minio, "type").Text)
+	require.Equal(t, minioEndpoint, getChild(t, minio, "endpoint").Text)
+	require.Equal(t, "us-east-1", getChild(t, minio, "region").Text)
+	require.Equal(t, "/var/lib/clickhouse/disks/minio/", getChild(t, minio, "metadata_path").Text)
+
+	// Credentials are not written into config, they are read from ENV vars
+	disk := chi.Spec.Configuration.Storage.Disks[0]
+	for _, field := range []string{StorageDiskFieldAccessKeyID, StorageDiskFieldSecretAccessKey} {
+		element := getChild(t, minio, field)
+		require.Empty(t, element.Text)
+		env, ok := element.GetAttribute("from_env")
+		require.True(t, ok)
+		require.Equal(t, CreateStorageDiskEnvVarName(disk, field), env)
+	}
+	require.NotContains(t, config, "access<")
+
+	cache := getChild(t, storage, "disks", "minio-cache")
+	require.Equal(t, "cache", getChild(t, cache, "type").Text)
+	require.Equal(t, "minio", getChild(t, cache, "disk").Text)
+	require.Equal(t, "10Gi", getChild(t, cache, "max_size").Text)
+
+	policy := getChild(t, storage, "policies", "tiered")
+	require.Equal(t, "default", getChild(t, policy, "volumes", "hot", "disk").Text)
+	require.Equal(t, "minio-cache", getChild(t, policy, "volumes", "cold", "disk").Text)
+	require.Equal(t, "1073741824", getChild(t, policy, "volumes", "cold", "max_data_part_size_bytes").Text)
+	require.Equal(t, "0.2", getChild(t, policy, "move_factor").Text)
+}
+
+func TestGetStorageConfigurationSourceFields(t *testing.T) {
+	chi := newTestStorageCHI()
+	disk := chi.Spec.Configuration.Storage.Disks[0]
+	disk.AccessKeyID = &api.SettingSource{
+		ValueFrom: &api.DataSource{
+			ConfigMapKeyRef: &core.ConfigMapKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: "minio"},
+				Key:                  "access",
+			},
+		},
+	}
+	disk.SecretAccessKey = &api.SettingSource{
+		ValueFrom: &api.DataSource{
+			FieldRef: &core.ObjectFieldSelector{
+				FieldPath: "metadata.annotations['minio/secret']",
+			},
+		},
+	}
+	// Source with no value is not written
+	disk.AccountName = &api.SettingSource{
+		ValueFrom: &api.DataSource{},
+	}
+	config := NewClickHouseConfigGenerator(chi).GetStorageConfiguration()
+
+	root, err := xml.Parse(strings.NewReader(config))
+	require.NoError(t, err, config)
+	minio := getChild(t, root, "storage_configuration", "disks", "minio")
+	for _, field := range []string{StorageDiskFieldAccessKeyID, StorageDiskFieldSecretAccessKey} {
+		env, ok := getChild(t, minio, field).GetAttribute("from_env")
+		require.True(t, ok)
+		require.Equal(t, CreateStorageDiskEnvVarName(disk, field), env)
+	}
+	for _, child := range minio.Children {
+		require.NotEqual(t, StorageDiskFieldAccountName, child.Name)
+	}
+}
+
+func TestGetStorageConfigurationEscapesValues(t *testing.T) {
+	chi := newTestStorageCHI()
+	disk := chi.Spec.Configuration.Storage.Disks[0]
+	disk.Region = "</region><path>/etc</path><region>"
+	disk.MetadataPath = `/data/"quoted"&'apos'`
+	config := NewClickHouseConfigGenerator(chi).GetStorageConfiguration()
+
+	root, err := xml.Parse(strings.NewReader(config))
+	require.NoError(t, err, config)
+	minio := getChild(t, root, "storage_configuration", "disks", "minio")
+	require.Equal(t, disk.Region, getChild(t, minio, "region").Text)
+	require.Equal(t, disk.MetadataPath, getChild(t, minio, "metadata_path").Text)
+	for _, child := range minio.Children {
+		require.NotEqual(t, "path", child.Name, "value is expected not to inject elements")
+	}
+}
+
+func TestGetStorageConfigurationEmpty(t *testing.T) {
+	chi := &api.ClickHouseInstallation{}
+	chi.Spec.Configuration = &api.Configuration{}
+	require.Empty(t, NewClickHouseConfigGenerator(chi).GetStorageConfiguration())
+}
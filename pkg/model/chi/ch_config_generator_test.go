@@ -0,0 +1,216 @@
+package chi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// parseTestRemoteServers parses "remote_servers.xml" into clusters, each one being a list of shards of replicas as host:port:secure
+func parseTestRemoteServers(t *testing.T, config string) map[string][][]string {
+	var doc struct {
+		RemoteServers struct {
+			Inner []byte `xml:",innerxml"`
+		} `xml:"remote_servers"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(config), &doc))
+
+	// Clusters are named by tags, thus they are decoded one by one
+	clusters := map[string][][]string{}
+	decoder := xml.NewDecoder(bytes.NewReader(doc.RemoteServers.Inner))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var cluster struct {
+			Shards []struct {
+				Replicas []struct {
+					Host   string `xml:"host"`
+					Port   int32  `xml:"port"`
+					Secure int    `xml:"secure"`
+				} `xml:"replica"`
+			} `xml:"shard"`
+		}
+		require.NoError(t, decoder.DecodeElement(&cluster, &start))
+		shards := [][]string{}
+		for _, shard := range cluster.Shards {
+			replicas := []string{}
+			for _, replica := range shard.Replicas {
+				replicas = append(replicas, fmt.Sprintf("%s:%d:%d", replica.Host, replica.Port, replica.Secure))
+			}
+			shards = append(shards, replicas)
+		}
+		clusters[start.Name.Local] = shards
+	}
+	return clusters
+}
+
+// newTestLayoutCHI creates CHI with the only cluster of the specified shards, host of the shard is either managed one,
+// specified by empty string, or external one, specified by its hostname
+func newTestLayoutCHI(shards ...[]string) *api.ClickHouseInstallation {
+	chi := &api.ClickHouseInstallation{}
+	chi.Namespace = "ns"
+	chi.Name = "chi"
+	chi.Spec.Defaults = &api.ChiDefaults{}
+	cluster := &api.Cluster{
+		Name:   "cluster",
+		Layout: &api.ChiClusterLayout{},
+	}
+	chi.Spec.Configuration = &api.Configuration{
+		Clusters: []*api.Cluster{cluster},
+	}
+	for shardIndex, hostnames := range shards {
+		shard := api.ChiShard{
+			Name:                fmt.Sprintf("%d", shardIndex),
+			InternalReplication: api.NewStringBool(true),
+		}
+		for replicaIndex, hostname := range hostnames {
+			host := &api.ChiHost{
+				Name:    fmt.Sprintf("%d-%d", shardIndex, replicaIndex),
+				TCPPort: 9000,
+				TLSPort: 9440,
+			}
+			if hostname != "" {
+				host.External = &api.ChiExternalHost{Hostname: hostname}
+			}
+			host.Runtime.Address = api.ChiHostAddress{
+				Namespace:    chi.Namespace,
+				CHIName:      chi.Name,
+				ClusterName:  cluster.Name,
+				ShardName:    shard.Name,
+				ShardIndex:   shardIndex,
+				ReplicaIndex: replicaIndex,
+				HostName:     host.Name,
+			}
+			host.Runtime.CHI = chi
+			shard.Hosts = append(shard.Hosts, host)
+		}
+		cluster.Layout.Shards = append(cluster.Layout.Shards, shard)
+	}
+	return chi
+}
+
+func TestGetRemoteServersExternalHosts(t *testing.T) {
+	initTestCHOp()
+
+	// managed formats managed host of the CHI as host:port:secure
+	managed := func(chi *api.ClickHouseInstallation, shard, replica int) string {
+		host := chi.Spec.Configuration.Clusters[0].Layout.Shards[shard].Hosts[replica]
+		return CreateInstanceHostname(host) + ":9000:0"
+	}
+
+	tests := []struct {
+		name   string
+		shards [][]string
+		secure bool
+		// exclude specifies managed hosts excluded from the config as shard/replica
+		exclude [][2]int
+		// expected builds expected shards of the cluster
+		expected func(chi *api.ClickHouseInstallation) [][]string
+	}{
+		{
+			name:   "managed hosts only",
+			shards: [][]string{{"", ""}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{{managed(chi, 0, 0), managed(chi, 0, 1)}}
+			},
+		},
+		{
+			name:   "external replica of the shard",
+			shards: [][]string{{"", "legacy-0.example.com"}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{{managed(chi, 0, 0), "legacy-0.example.com:9000:0"}}
+			},
+		},
+		{
+			name:   "external shard",
+			shards: [][]string{{""}, {"legacy-0.example.com", "legacy-1.example.com"}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{
+					{managed(chi, 0, 0)},
+					{"legacy-0.example.com:9000:0", "legacy-1.example.com:9000:0"},
+				}
+			},
+		},
+		{
+			name:   "external host uses secure port of the cluster",
+			shards: [][]string{{"legacy-0.example.com"}},
+			secure: true,
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{{"legacy-0.example.com:9440:1"}}
+			},
+		},
+		{
+			name:    "external host is kept while managed host is excluded",
+			shards:  [][]string{{"", "legacy-0.example.com"}},
+			exclude: [][2]int{{0, 0}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{{"legacy-0.example.com:9000:0"}}
+			},
+		},
+		{
+			name:    "shard of the excluded managed hosts is skipped",
+			shards:  [][]string{{""}, {"legacy-0.example.com"}},
+			exclude: [][2]int{{0, 0}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				return [][]string{{"legacy-0.example.com:9000:0"}}
+			},
+		},
+		{
+			name:   "external host without hostname is skipped",
+			shards: [][]string{{""}, {"legacy-0.example.com"}},
+			expected: func(chi *api.ClickHouseInstallation) [][]string {
+				chi.Spec.Configuration.Clusters[0].Layout.Shards[1].Hosts[0].External.Hostname = ""
+				return [][]string{{managed(chi, 0, 0)}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chi := newTestLayoutCHI(tt.shards...)
+			if tt.secure {
+				chi.Spec.Configuration.Clusters[0].Secure = api.NewStringBool(true)
+			}
+			expected := tt.expected(chi)
+
+			options := NewRemoteServersGeneratorOptions()
+			for _, exclude := range tt.exclude {
+				options.ExcludeHost(chi.Spec.Configuration.Clusters[0].Layout.Shards[exclude[0]].Hosts[exclude[1]])
+			}
+			clusters := parseTestRemoteServers(t, NewClickHouseConfigGenerator(chi).GetRemoteServers(options))
+			require.Equal(t, expected, clusters["cluster"])
+
+			// Autogenerated clusters consist of managed hosts only
+			for _, name := range []string{"all-replicated", "all-sharded"} {
+				for _, shard := range clusters[name] {
+					for _, replica := range shard {
+						require.NotContains(t, replica, "legacy", name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateExternalFQDNs(t *testing.T) {
+	chi := newTestLayoutCHI(
+		[]string{"", "legacy-0.example.com"},
+		[]string{"legacy-1.example.com", "legacy-2.example.com"},
+	)
+	host := chi.Spec.Configuration.Clusters[0].Layout.Shards[0].Hosts[0]
+
+	require.Equal(t, []string{"legacy-0.example.com"}, CreateExternalFQDNs(host, api.ChiShard{}))
+	require.Equal(t, []string{"legacy-0.example.com", "legacy-1.example.com", "legacy-2.example.com"}, CreateExternalFQDNs(host, api.Cluster{}))
+	require.Empty(t, CreateExternalFQDNs(host, nil))
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"bytes"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+const envVarNamePrefixVirtualClusterSecret = InternodeClusterSecretEnvName + "_VIRTUAL"
+
+// CreateVirtualClusterSecretEnvVarName creates name of the ENV var, which provides secret of the virtual cluster sourced from a secret
+func CreateVirtualClusterSecretEnvVarName(virtualCluster *api.ChiVirtualCluster) string {
+	// In case not OK env var name will be empty and config will be incorrect. CH may not start
+	name, _ := util.BuildShellEnvVarName(envVarNamePrefixVirtualClusterSecret + "_" + virtualCluster.Name)
+	return name
+}
+
+// getRemoteServersVirtualClusters appends virtual clusters, resolved from the referenced CHIs, to "remote_servers.xml"
+func (c *ClickHouseConfigGenerator) getRemoteServersVirtualClusters(b *bytes.Buffer) {
+	if len(c.chi.Spec.Configuration.VirtualClusters) == 0 {
+		return
+	}
+
+	util.Iline(b, 8, "<!-- Virtual clusters -->")
+	for _, virtualCluster := range c.chi.Spec.Configuration.VirtualClusters {
+		if len(virtualCluster.Runtime.Shards) == 0 {
+			// Skip virtual cluster, which references no hosts
+			util.Iline(b, 8, "<!-- Virtual cluster %s is skipped due to absence of hosts -->", virtualCluster.Name)
+			continue
+		}
+
+		// <my_cluster_name>
+		util.Iline(b, 8, "<%s>", virtualCluster.Name)
+
+		// <secret>VALUE</secret>
+		switch virtualCluster.Secret.Source() {
+		case api.ClusterSecretSourcePlaintext:
+			// Secret value is explicitly specified
+			util.Iline(b, 12, "<secret>%s</secret>", virtualCluster.Secret.Value)
+		case api.ClusterSecretSourceSecretRef:
+			// Use secret via ENV var from secret
+			util.Iline(b, 12, `<secret from_env="%s" />`, CreateVirtualClusterSecretEnvVarName(virtualCluster))
+		}
+
+		for i := range virtualCluster.Runtime.Shards {
+			shard := &virtualCluster.Runtime.Shards[i]
+
+			// <shard>
+			//		<internal_replication>VALUE(true/false)</internal_replication>
+			util.Iline(b, 12, "<shard>")
+			util.Iline(b, 16, "<internal_replication>%s</internal_replication>", shard.InternalReplication)
+
+			//		<weight>X</weight>
+			if shard.Weight != nil {
+				util.Iline(b, 16, "<weight>%d</weight>", *shard.Weight)
+			}
+
+			for _, replica := range shard.Replicas {
+				secure := 0
+				if replica.Secure {
+					secure = 1
+				}
+				util.Iline(b, 16, "<replica>")
+				util.Iline(b, 16, "    <host>%s</host>", replica.Host)
+				util.Iline(b, 16, "    <port>%d</port>", replica.Port)
+				util.Iline(b, 16, "    <secure>%d</secure>", secure)
+				util.Iline(b, 16, "</replica>")
+			}
+
+			// </shard>
+			util.Iline(b, 12, "</shard>")
+		}
+
+		// </my_cluster_name>
+		util.Iline(b, 8, "</%s>", virtualCluster.Name)
+	}
+}
@@ -0,0 +1,159 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestGetRemoteServersVirtualClusters(t *testing.T) {
+	initTestCHOp()
+
+	weight := 3
+	shard := func(replicas ...api.ChiVirtualClusterReplica) api.ChiVirtualClusterShard {
+		return api.ChiVirtualClusterShard{
+			InternalReplication: api.NewStringBool(true),
+			Replicas:            replicas,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		virtualCluster *api.ChiVirtualCluster
+		// expected shards of the virtual cluster, nil in case cluster is skipped
+		expected [][]string
+		// contains lists lines expected in the config
+		contains []string
+		// notContains lists lines not expected in the config
+		notContains []string
+	}{
+		{
+			name: "shards of the referenced CHIs",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name: "virtual",
+				Runtime: api.ChiVirtualClusterRuntime{
+					Shards: []api.ChiVirtualClusterShard{
+						shard(
+							api.ChiVirtualClusterReplica{Host: "chi-a-0-0", Port: 9000},
+							api.ChiVirtualClusterReplica{Host: "chi-a-0-1", Port: 9000},
+						),
+						shard(api.ChiVirtualClusterReplica{Host: "chi-b-0-0", Port: 9000}),
+					},
+				},
+			},
+			expected: [][]string{
+				{"chi-a-0-0:9000:0", "chi-a-0-1:9000:0"},
+				{"chi-b-0-0:9000:0"},
+			},
+			contains:    []string{"<internal_replication>true</internal_replication>"},
+			notContains: []string{"<weight>", "<secret"},
+		},
+		{
+			name: "secure replica and shard weight",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name: "virtual",
+				Runtime: api.ChiVirtualClusterRuntime{
+					Shards: []api.ChiVirtualClusterShard{
+						{
+							InternalReplication: api.NewStringBool(false),
+							Weight:              &weight,
+							Replicas: []api.ChiVirtualClusterReplica{
+								{Host: "chi-a-0-0", Port: 9440, Secure: true},
+							},
+						},
+					},
+				},
+			},
+			expected: [][]string{{"chi-a-0-0:9440:1"}},
+			contains: []string{
+				"<internal_replication>false</internal_replication>",
+				"<weight>3</weight>",
+			},
+		},
+		{
+			name: "plaintext secret",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name:   "virtual",
+				Secret: &api.ClusterSecret{Value: "qwerty"},
+				Runtime: api.ChiVirtualClusterRuntime{
+					Shards: []api.ChiVirtualClusterShard{
+						shard(api.ChiVirtualClusterReplica{Host: "chi-a-0-0", Port: 9000}),
+					},
+				},
+			},
+			expected: [][]string{{"chi-a-0-0:9000:0"}},
+			contains: []string{"<secret>qwerty</secret>"},
+		},
+		{
+			name: "secret sourced from k8s secret",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name: "virtual",
+				Secret: &api.ClusterSecret{
+					ValueFrom: &api.DataSource{
+						SecretKeyRef: &core.SecretKeySelector{
+							LocalObjectReference: core.LocalObjectReference{Name: "secret"},
+							Key:                  "key",
+						},
+					},
+				},
+				Runtime: api.ChiVirtualClusterRuntime{
+					Shards: []api.ChiVirtualClusterShard{
+						shard(api.ChiVirtualClusterReplica{Host: "chi-a-0-0", Port: 9000}),
+					},
+				},
+			},
+			expected: [][]string{{"chi-a-0-0:9000:0"}},
+			contains: []string{`<secret from_env="` + InternodeClusterSecretEnvName + `_VIRTUAL_VIRTUAL" />`},
+		},
+		{
+			name: "auto secret is not rendered",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name:   "virtual",
+				Secret: &api.ClusterSecret{Auto: api.NewStringBool(true)},
+				Runtime: api.ChiVirtualClusterRuntime{
+					Shards: []api.ChiVirtualClusterShard{
+						shard(api.ChiVirtualClusterReplica{Host: "chi-a-0-0", Port: 9000}),
+					},
+				},
+			},
+			expected:    [][]string{{"chi-a-0-0:9000:0"}},
+			notContains: []string{"<secret"},
+		},
+		{
+			name: "virtual cluster without hosts is skipped",
+			virtualCluster: &api.ChiVirtualCluster{
+				Name: "virtual",
+			},
+			contains: []string{"<!-- Virtual cluster virtual is skipped due to absence of hosts -->"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chi := newTestLayoutCHI([]string{""})
+			chi.Spec.Configuration.VirtualClusters = []*api.ChiVirtualCluster{tt.virtualCluster}
+
+			config := NewClickHouseConfigGenerator(chi).GetRemoteServers(nil)
+			clusters := parseTestRemoteServers(t, config)
+			require.Equal(t, tt.expected, clusters["virtual"])
+			// Clusters of the CHI itself are rendered along with the virtual one
+			require.Len(t, clusters["cluster"], 1)
+			for _, line := range tt.contains {
+				require.Contains(t, config, line)
+			}
+			for _, line := range tt.notContains {
+				require.NotContains(t, config, line)
+			}
+		})
+	}
+}
+
+func TestGetRemoteServersNoVirtualClusters(t *testing.T) {
+	initTestCHOp()
+
+	config := NewClickHouseConfigGenerator(newTestLayoutCHI([]string{""})).GetRemoteServers(nil)
+	require.NotContains(t, config, "Virtual clusters")
+}
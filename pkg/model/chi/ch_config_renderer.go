@@ -0,0 +1,65 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/xml"
+)
+
+// ConfigRenderer renders config files generated by the operator in the format to be read by ClickHouse.
+// Config generator produces XML, which is rendered by the renderer into the target format
+type ConfigRenderer interface {
+	// Render renders XML config into the target format
+	Render(xmlConfig string) (string, error)
+	// Extension specifies extension of the config file in the target format
+	Extension() string
+}
+
+// NewConfigRenderer creates renderer of the config files of the specified format
+func NewConfigRenderer(format string) ConfigRenderer {
+	switch format {
+	case api.ConfigFormatYAML:
+		return &yamlConfigRenderer{}
+	default:
+		return &xmlConfigRenderer{}
+	}
+}
+
+// xmlConfigRenderer renders config files as XML, which is native format of the config generator
+type xmlConfigRenderer struct{}
+
+// Render renders XML config as is
+func (r *xmlConfigRenderer) Render(xmlConfig string) (string, error) {
+	return xmlConfig, nil
+}
+
+// Extension specifies extension of the XML config file
+func (r *xmlConfigRenderer) Extension() string {
+	return ".xml"
+}
+
+// yamlConfigRenderer renders config files as YAML
+type yamlConfigRenderer struct{}
+
+// Render converts XML config into semantically equivalent YAML config
+func (r *yamlConfigRenderer) Render(xmlConfig string) (string, error) {
+	return xml.ConvertToYAML(xmlConfig)
+}
+
+// Extension specifies extension of the YAML config file
+func (r *yamlConfigRenderer) Extension() string {
+	return ".yaml"
+}
@@ -16,6 +16,7 @@ package chi
 
 import (
 	"fmt"
+	"sort"
 
 	"gopkg.in/d4l3k/messagediff.v1"
 
@@ -23,20 +24,116 @@ import (
 	"github.com/minorhacks/clickhouse-operator/pkg/chop"
 )
 
-// isZookeeperChangeRequiresReboot checks two ZooKeeper configs and decides,
-// whether config modifications require a reboot to be applied
-func isZookeeperChangeRequiresReboot(host *api.ChiHost, a, b *api.ChiZookeeperConfig) bool {
-	return !a.Equals(b)
+// ConfigurationChange describes one changed path of the host configuration along with the decision,
+// whether the change requires host restart or can be applied by config reload
+type ConfigurationChange struct {
+	// Path specifies changed path, prefixed with configuration restart policy rules section. Ex.: settings/logger/level
+	Path string
+	// RequiresRestart specifies whether the change requires host restart
+	RequiresRestart bool
+	// Rule specifies pattern of the configuration restart policy rule the decision is made by.
+	// Empty in case no rule matches the path
+	Rule string
+	// Version specifies ClickHouse version constraint of the rule the decision is made by
+	Version string
 }
 
-// isSettingsChangeRequiresReboot checks whether changes between two settings requires ClickHouse reboot
-func isSettingsChangeRequiresReboot(host *api.ChiHost, configurationRestartPolicyRulesSection string, a, b *api.Settings) bool {
+// String returns human-readable description of the change
+func (c ConfigurationChange) String() string {
+	if c.Rule == "" {
+		return c.Path
+	}
+	return fmt.Sprintf("%s (rule %s for version %s)", c.Path, c.Rule, c.Version)
+}
+
+// ConfigurationChanges is a list of changes of the host configuration
+type ConfigurationChanges []ConfigurationChange
+
+// RequiresRestart checks whether any of the changes requires host restart
+func (changes ConfigurationChanges) RequiresRestart() bool {
+	for _, change := range changes {
+		if change.RequiresRestart {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReloadable checks whether there are changes and all of them can be applied by config reload
+func (changes ConfigurationChanges) IsReloadable() bool {
+	return (len(changes) > 0) && !changes.RequiresRestart()
+}
+
+// RestartReasons lists changes, which require host restart
+func (changes ConfigurationChanges) RestartReasons() (reasons []string) {
+	for _, change := range changes {
+		if change.RequiresRestart {
+			reasons = append(reasons, change.String())
+		}
+	}
+	return reasons
+}
+
+// Paths lists changed paths
+func (changes ConfigurationChanges) Paths() (paths []string) {
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+// getZookeeperChanges checks two ZooKeeper configs for changes.
+// ZooKeeper config is not reloadable, thus any change requires a reboot to be applied
+func getZookeeperChanges(host *api.ChiHost, a, b *api.ChiZookeeperConfig) ConfigurationChanges {
+	if a.Equals(b) {
+		return nil
+	}
+	return ConfigurationChanges{
+		{
+			Path:            configurationRestartPolicyRulesSectionZookeeper,
+			RequiresRestart: true,
+		},
+	}
+}
+
+// getStorageChanges checks two storage configurations for changes.
+// Storage configuration is not reloadable, thus any change requires a reboot to be applied
+func getStorageChanges(host *api.ChiHost, a, b *api.StorageConfiguration) ConfigurationChanges {
+	if a.Equals(b) {
+		return nil
+	}
+	return ConfigurationChanges{
+		{
+			Path:            configurationRestartPolicyRulesSectionStorage,
+			RequiresRestart: true,
+		},
+	}
+}
+
+// getSettingsChanges lists paths changed between two settings along with the decision of the restart policy rules for each path
+func getSettingsChanges(host *api.ChiHost, configurationRestartPolicyRulesSection string, a, b *api.Settings) (changes ConfigurationChanges) {
 	diff, equal := messagediff.DeepDiff(a, b)
 	if equal {
-		return false
+		return nil
 	}
 	affectedPaths := api.ListAffectedSettingsPathsFromDiff(a, b, diff, configurationRestartPolicyRulesSection)
-	return isListedChangeRequiresReboot(host, affectedPaths)
+	sort.Strings(affectedPaths)
+	for i, path := range affectedPaths {
+		if (i > 0) && (affectedPaths[i-1] == path) {
+			// Skip duplicates
+			continue
+		}
+		change := ConfigurationChange{
+			Path: path,
+		}
+		if matches, value, rule, version := getLatestConfigMatchValue(host, path); matches {
+			change.RequiresRestart = value
+			change.Rule = rule
+			change.Version = version
+		}
+		changes = append(changes, change)
+	}
+	return changes
 }
 
 // hostVersionMatches checks whether host's ClickHouse version matches specified constraint
@@ -49,62 +146,39 @@ func hostVersionMatches(host *api.ChiHost, versionConstraint string) bool {
 }
 
 // ruleMatches checks whether provided rule (rule set) matches specified `path`
-func ruleMatches(set api.OperatorConfigRestartPolicyRuleSet, path string) (matches bool, value bool) {
-	for pattern, val := range set {
-		if pattern.Match(path) {
-			matches = true
-			value = val.IsTrue()
-			return matches, value
+func ruleMatches(set api.OperatorConfigRestartPolicyRuleSet, path string) (matches bool, value bool, pattern string) {
+	for p, val := range set {
+		if p.Match(path) {
+			return true, val.IsTrue(), string(p)
 		}
 		// Only one check has to be performed since we are expecting rule to have one entry
-		matches = false
-		value = false
-		return matches, value
+		return false, false, ""
 	}
-	matches = false
-	value = false
-	return matches, value
+	return false, false, ""
 }
 
 // getLatestConfigMatchValue returns value of the latest match of a specified `path` in ConfigRestartPolicy.Rules
-// in case match found in ConfigRestartPolicy.Rules or false
-func getLatestConfigMatchValue(host *api.ChiHost, path string) (matches bool, value bool) {
+// in case match found in ConfigRestartPolicy.Rules or false.
+// Pattern and ClickHouse version constraint of the matched rule are returned as well
+func getLatestConfigMatchValue(host *api.ChiHost, path string) (matches bool, value bool, rule string, version string) {
 	// Check all rules
 	for _, r := range chop.Config().ClickHouse.ConfigRestartPolicy.Rules {
 		// Check ClickHouse version of a particular rule
-		_ = fmt.Sprintf("%s", r.Version)
 		if hostVersionMatches(host, r.Version) {
 			// Yes, this is ClickHouse version of the host.
 			// Check whether any rule matches specified path.
-			for _, rule := range r.Rules {
-				if ruleMatches, ruleValue := ruleMatches(rule, path); ruleMatches {
+			for _, set := range r.Rules {
+				if ruleMatches, ruleValue, pattern := ruleMatches(set, path); ruleMatches {
 					// Yes, rule matches specified path.
 					matches = true
 					value = ruleValue
+					rule = pattern
+					version = r.Version
 				}
 			}
 		}
 	}
-	return matches, value
-}
-
-// isListedChangeRequiresReboot checks whether any of the provided paths requires reboot to apply configuration
-func isListedChangeRequiresReboot(host *api.ChiHost, paths []string) bool {
-	// Check whether any path matches ClickHouse configuration restart policy rules requires reboot
-	for _, path := range paths {
-		if matches, value := getLatestConfigMatchValue(host, path); matches {
-			// This path matches configuration restart policy rule
-			if value {
-				// And this path not only matches, but requires reboot also - no need to find any other who requires reboot
-				return true
-			}
-		}
-	}
-	// Last matching path with value "yes" not found.
-	// This means either:
-	// 1. Last matching path has value "no"
-	// 2. No matching path found
-	return false
+	return matches, value, rule, version
 }
 
 // Set of configurationRestartPolicyRulesSection<XXX> constants specifies prefixes used in
@@ -145,10 +219,17 @@ const (
 	configurationRestartPolicyRulesSectionSettings  = "settings"
 	configurationRestartPolicyRulesSectionFiles     = "files"
 	configurationRestartPolicyRulesSectionZookeeper = "zookeeper"
+	configurationRestartPolicyRulesSectionStorage   = "storage"
 )
 
 // IsConfigurationChangeRequiresReboot checks whether configuration changes requires a reboot
 func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
+	return GetConfigurationChanges(host).RequiresRestart()
+}
+
+// GetConfigurationChanges lists changes of the host configuration comparing to the ancestor.
+// Each change is classified by the configuration restart policy rules matching ClickHouse version of the host
+func GetConfigurationChanges(host *api.ChiHost) (changes ConfigurationChanges) {
 	// Zookeeper
 	{
 		var old, new *api.ChiZookeeperConfig
@@ -156,9 +237,18 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 			old = host.GetAncestor().GetZookeeper()
 		}
 		new = host.GetZookeeper()
-		if isZookeeperChangeRequiresReboot(host, old, new) {
-			return true
+		changes = append(changes, getZookeeperChanges(host, old, new)...)
+	}
+	// Storage
+	{
+		var old, new *api.StorageConfiguration
+		if host.HasAncestorCHI() {
+			old = host.GetAncestorCHI().Spec.Configuration.Storage
 		}
+		if host.HasCHI() {
+			new = host.GetCHI().Spec.Configuration.Storage
+		}
+		changes = append(changes, getStorageChanges(host, old, new)...)
 	}
 	// Profiles Global
 	{
@@ -169,9 +259,7 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 		if host.HasCHI() {
 			new = host.GetCHI().Spec.Configuration.Profiles
 		}
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionProfiles, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionProfiles, old, new)...)
 	}
 	// Quotas Global
 	{
@@ -182,9 +270,7 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 		if host.HasCHI() {
 			new = host.GetCHI().Spec.Configuration.Quotas
 		}
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionQuotas, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionQuotas, old, new)...)
 	}
 	// Settings Global
 	{
@@ -195,9 +281,7 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 		if host.HasCHI() {
 			new = host.GetCHI().Spec.Configuration.Settings
 		}
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionSettings, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionSettings, old, new)...)
 	}
 	// Settings Local
 	{
@@ -206,9 +290,7 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 			old = host.GetAncestor().Settings
 		}
 		new = host.Settings
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionSettings, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionSettings, old, new)...)
 	}
 	// Files Global
 	{
@@ -227,9 +309,7 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 				true,
 			)
 		}
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionFiles, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionFiles, old, new)...)
 	}
 	// Files Local
 	{
@@ -246,10 +326,8 @@ func IsConfigurationChangeRequiresReboot(host *api.ChiHost) bool {
 			[]api.SettingsSection{api.SectionUsers},
 			true,
 		)
-		if isSettingsChangeRequiresReboot(host, configurationRestartPolicyRulesSectionFiles, old, new) {
-			return true
-		}
+		changes = append(changes, getSettingsChanges(host, configurationRestartPolicyRulesSectionFiles, old, new)...)
 	}
 
-	return false
+	return changes
 }
@@ -0,0 +1,272 @@
+package chi
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/swversion"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+)
+
+var initTestCHOpOnce sync.Once
+
+// initTestCHOp initializes operator with the default config, which provides configuration restart policy rules
+func initTestCHOp() {
+	initTestCHOpOnce.Do(func() {
+		chop.New(nil, nil, "../../../config/config.yaml")
+	})
+}
+
+// newTestConfigurationChangesHost creates host of a single-host CHI with the specified configuration
+func newTestConfigurationChangesHost(version string, configuration *api.Configuration) *api.ChiHost {
+	host := &api.ChiHost{
+		Name:     "host",
+		Settings: configuration.Settings,
+		Files:    configuration.Files,
+	}
+	host.Runtime.Address.ClusterName = "cluster"
+	host.Runtime.Address.ShardName = "shard"
+	host.Runtime.Address.HostName = "host"
+	host.Runtime.Version = swversion.NewSoftWareVersion(version)
+
+	chi := &api.ClickHouseInstallation{}
+	chi.Spec.Configuration = &api.Configuration{
+		Settings: configuration.Settings,
+		Profiles: configuration.Profiles,
+		Files:    configuration.Files,
+		Clusters: []*api.Cluster{
+			{
+				Name:      "cluster",
+				Zookeeper: configuration.Zookeeper,
+				Layout: &api.ChiClusterLayout{
+					Shards: []api.ChiShard{
+						{
+							Name:  "shard",
+							Hosts: []*api.ChiHost{host},
+						},
+					},
+				},
+			},
+		},
+	}
+	host.Runtime.CHI = chi
+	return host
+}
+
+func newTestSettings(values map[string]string) *api.Settings {
+	settings := api.NewSettings()
+	for name, value := range values {
+		settings.Set(name, api.NewSettingScalar(value))
+	}
+	return settings
+}
+
+func TestGetConfigurationChanges(t *testing.T) {
+	initTestCHOp()
+
+	tests := []struct {
+		name    string
+		version string
+		old     *api.Configuration
+		new     *api.Configuration
+		// changes specifies expected changes, mapped to the rule each one is decided by
+		changes map[string]string
+		restart bool
+	}{
+		{
+			name:    "no changes",
+			old:     &api.Configuration{Settings: newTestSettings(map[string]string{"logger/level": "debug"})},
+			new:     &api.Configuration{Settings: newTestSettings(map[string]string{"logger/level": "debug"})},
+			changes: map[string]string{},
+		},
+		{
+			name: "reloadable setting",
+			old:  &api.Configuration{Settings: newTestSettings(map[string]string{"logger/level": "debug"})},
+			new:  &api.Configuration{Settings: newTestSettings(map[string]string{"logger/level": "trace"})},
+			changes: map[string]string{
+				"settings/logger/level": "settings/logger/*",
+			},
+		},
+		{
+			name: "setting requires restart",
+			old:  &api.Configuration{Settings: newTestSettings(map[string]string{"max_concurrent_queries": "100"})},
+			new: &api.Configuration{Settings: newTestSettings(map[string]string{
+				"max_concurrent_queries": "200",
+				"listen_host":            "0.0.0.0",
+			})},
+			changes: map[string]string{
+				"settings/max_concurrent_queries": "settings/max_concurrent_queries",
+				"settings/listen_host":            "settings/*",
+			},
+			restart: true,
+		},
+		{
+			name:    "rules of the host version",
+			version: "21.8.15.7",
+			old:     &api.Configuration{Settings: newTestSettings(map[string]string{"logger": "debug"})},
+			new:     &api.Configuration{Settings: newTestSettings(map[string]string{"logger": "trace"})},
+			changes: map[string]string{
+				"settings/logger": "settings/logger",
+			},
+			restart: true,
+		},
+		{
+			name: "profile with no rule",
+			old:  &api.Configuration{Profiles: newTestSettings(map[string]string{"default/max_memory_usage": "1"})},
+			new:  &api.Configuration{Profiles: newTestSettings(map[string]string{"default/max_memory_usage": "2"})},
+			changes: map[string]string{
+				"profiles/default/max_memory_usage": "",
+			},
+		},
+		{
+			name: "profile requires restart",
+			old:  &api.Configuration{Profiles: newTestSettings(map[string]string{"default/background_fetches_pool_size": "16"})},
+			new:  &api.Configuration{Profiles: newTestSettings(map[string]string{"default/background_fetches_pool_size": "32"})},
+			changes: map[string]string{
+				"profiles/default/background_fetches_pool_size": "profiles/default/background_*_pool_size",
+			},
+			restart: true,
+		},
+		{
+			name: "files",
+			old:  &api.Configuration{Files: newTestSettings(map[string]string{"config.d/dict.xml": "<a/>"})},
+			new: &api.Configuration{Files: newTestSettings(map[string]string{
+				"config.d/dict.xml":   "<b/>",
+				"config.d/macros.xml": "<c/>",
+			})},
+			changes: map[string]string{
+				"files/config.d/dict.xml":   "files/config.d/*dict*.xml",
+				"files/config.d/macros.xml": "files/config.d/*.xml",
+			},
+			restart: true,
+		},
+		{
+			name: "zookeeper",
+			old:  &api.Configuration{Zookeeper: &api.ChiZookeeperConfig{Root: "/a"}},
+			new:  &api.Configuration{Zookeeper: &api.ChiZookeeperConfig{Root: "/b"}},
+			changes: map[string]string{
+				"zookeeper": "",
+			},
+			restart: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := tt.version
+			if version == "" {
+				version = "24.3"
+			}
+			host := newTestConfigurationChangesHost(version, tt.new)
+			ancestor := newTestConfigurationChangesHost(version, tt.old)
+			host.GetCHI().SetAncestor(ancestor.GetCHI())
+			require.True(t, host.HasAncestor())
+
+			changes := GetConfigurationChanges(host)
+			rules := make(map[string]string)
+			for _, change := range changes {
+				rules[change.Path] = change.Rule
+			}
+			require.Equal(t, tt.changes, rules)
+			require.Equal(t, tt.restart, changes.RequiresRestart())
+			require.Equal(t, (len(tt.changes) > 0) && !tt.restart, changes.IsReloadable())
+			require.Equal(t, tt.restart, len(changes.RestartReasons()) > 0)
+		})
+	}
+}
+
+func TestGetSettingsChanges(t *testing.T) {
+	initTestCHOp()
+	host := newTestConfigurationChangesHost("24.3", &api.Configuration{})
+
+	// Nil and empty settings are the same
+	require.Empty(t, getSettingsChanges(host, configurationRestartPolicyRulesSectionSettings, nil, api.NewSettings()))
+
+	// Added and removed settings are changes as well
+	changes := getSettingsChanges(host, configurationRestartPolicyRulesSectionSettings,
+		newTestSettings(map[string]string{"macros/shard": "1"}),
+		newTestSettings(map[string]string{"dictionaries_config": "*.xml"}),
+	)
+	require.Equal(t, []string{"settings/dictionaries_config", "settings/macros/shard"}, changes.Paths())
+	require.False(t, changes.RequiresRestart())
+	for _, change := range changes {
+		require.Equal(t, "*", change.Version)
+	}
+}
+
+func TestIsWatchedOperatorClass(t *testing.T) {
+	tests := []struct {
+		name         string
+		class        string
+		classDefault bool
+		// chiClass and templateClass specify class of the CHI and class of the template applied to the CHI
+		chiClass      string
+		templateClass string
+		watched       bool
+	}{
+		{
+			name:    "no classes at all",
+			watched: true,
+		},
+		{
+			name:     "CHI of another class is ignored by the default operator",
+			chiClass: "dev",
+		},
+		{
+			name:     "CHI of the operator class",
+			class:    "dev",
+			chiClass: "dev",
+			watched:  true,
+		},
+		{
+			name:     "CHI of another class",
+			class:    "dev",
+			chiClass: "prod",
+		},
+		{
+			name:  "CHI without class is ignored by the operator of a class",
+			class: "dev",
+		},
+		{
+			name:         "CHI without class is handled by the default operator of a class",
+			class:        "dev",
+			classDefault: true,
+			watched:      true,
+		},
+		{
+			name:         "CHI of another class is ignored by the default operator of a class",
+			class:        "dev",
+			classDefault: true,
+			chiClass:     "prod",
+		},
+		{
+			name:          "class inherited from the template",
+			class:         "dev",
+			templateClass: "dev",
+			watched:       true,
+		},
+		{
+			name:          "class of the CHI takes precedence over class of the template",
+			class:         "dev",
+			chiClass:      "prod",
+			templateClass: "dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &api.OperatorConfig{}
+			config.Watch.OperatorClass = tt.class
+			config.Watch.OperatorClassDefault = tt.classDefault
+
+			chi := &api.ClickHouseInstallation{}
+			chi.Spec.OperatorClass = tt.chiClass
+			chi.Spec.MergeFrom(&api.ChiSpec{OperatorClass: tt.templateClass}, api.MergeTypeFillEmptyValues)
+
+			require.Equal(t, tt.watched, config.IsWatchedOperatorClass(chi.Spec.GetOperatorClass()))
+		})
+	}
+}
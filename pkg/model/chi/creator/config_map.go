@@ -75,3 +75,19 @@ func (c *Creator) CreateConfigMapHost(host *api.ChiHost) *core.ConfigMap {
 	model.MakeObjectVersion(&cm.ObjectMeta, cm)
 	return cm
 }
+
+// CreateConfigMapCHIRevision creates new core.ConfigMap with the revision of the CHI spec
+func (c *Creator) CreateConfigMapCHIRevision(revision int, spec string) *core.ConfigMap {
+	return &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            model.CreateConfigMapRevisionName(c.chi, revision),
+			Namespace:       c.chi.Namespace,
+			Labels:          model.Macro(c.chi).Map(c.labels.GetConfigMapCHIRevision()),
+			Annotations:     model.Macro(c.chi).Map(c.annotations.GetConfigMapCHIRevision()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		Data: map[string]string{
+			model.RevisionConfigMapKey: spec,
+		},
+	}
+}
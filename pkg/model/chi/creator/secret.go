@@ -18,6 +18,7 @@ import (
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
 
@@ -34,3 +35,17 @@ func (c *Creator) CreateClusterSecret(name string) *core.Secret {
 		Type: core.SecretTypeOpaque,
 	}
 }
+
+// CreateOperatorCredentialsSecret creates Secret with generated operator credentials
+func (c *Creator) CreateOperatorCredentialsSecret(creds *model.OperatorCredentials) *core.Secret {
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:       c.chi.Namespace,
+			Name:            model.CreateOperatorCredentialsSecretName(c.chi),
+			Labels:          model.Macro(c.chi).Map(c.labels.GetSecretOperatorCredentials()),
+			OwnerReferences: getOwnerReferences(c.chi),
+		},
+		StringData: creds.StringData(),
+		Type:       core.SecretTypeOpaque,
+	}
+}
@@ -73,6 +73,7 @@ func (c *Creator) setupStatefulSetPodTemplate(statefulSet *apps.StatefulSet, hos
 	// Post-process StatefulSet
 	ensureStatefulSetTemplateIntegrity(statefulSet, host)
 	setupEnvVars(statefulSet, host)
+	setupDataSourcesVersion(statefulSet, host)
 	c.personalizeStatefulSetTemplate(statefulSet, host)
 }
 
@@ -93,6 +94,19 @@ func setupEnvVars(statefulSet *apps.StatefulSet, host *api.ChiHost) {
 	container.Env = append(container.Env, host.GetCHI().EnsureRuntime().GetAttributes().AdditionalEnvVars...)
 }
 
+// setupDataSourcesVersion annotates pod template with version of the config maps data, settings and files are sourced from.
+// Pods are rolled out in case data of the config maps changes
+func setupDataSourcesVersion(statefulSet *apps.StatefulSet, host *api.ChiHost) {
+	version := host.GetCHI().EnsureRuntime().GetAttributes().DataSourcesVersion
+	if version == "" {
+		return
+	}
+	if statefulSet.Spec.Template.Annotations == nil {
+		statefulSet.Spec.Template.Annotations = make(map[string]string)
+	}
+	statefulSet.Spec.Template.Annotations[model.AnnotationDataSourcesVersion] = version
+}
+
 // ensureMainContainerSpecified is a unification wrapper
 func ensureMainContainerSpecified(statefulSet *apps.StatefulSet, host *api.ChiHost) {
 	ensureClickHouseContainerSpecified(statefulSet, host)
@@ -0,0 +1,103 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"sort"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// GetDataSourcesConfigMapKeys lists keys of config maps, which settings and files of the CHI are sourced from
+func GetDataSourcesConfigMapKeys(chi *api.ClickHouseInstallation) (keys []api.ObjectAddress) {
+	add := func(name, key string) {
+		address := api.ObjectAddress{
+			Namespace: chi.Namespace,
+			Name:      name,
+			Key:       key,
+		}
+		for _, k := range keys {
+			if k == address {
+				return
+			}
+		}
+		keys = append(keys, address)
+	}
+
+	for _, envVar := range chi.EnsureRuntime().GetAttributes().AdditionalEnvVars {
+		if (envVar.ValueFrom != nil) && (envVar.ValueFrom.ConfigMapKeyRef != nil) {
+			add(envVar.ValueFrom.ConfigMapKeyRef.Name, envVar.ValueFrom.ConfigMapKeyRef.Key)
+		}
+	}
+	for _, volume := range chi.EnsureRuntime().GetAttributes().AdditionalVolumes {
+		if volume.ConfigMap == nil {
+			continue
+		}
+		for _, item := range volume.ConfigMap.Items {
+			add(volume.ConfigMap.Name, item.Key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name == keys[j].Name {
+			return keys[i].Key < keys[j].Key
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+// BuildDataSourcesVersion builds version of the data of the config maps.
+// Data is specified as a map of values by their config map keys, missing key is expected to have empty value
+func BuildDataSourcesVersion(keys []api.ObjectAddress, data map[api.ObjectAddress]string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	var values []string
+	for _, key := range keys {
+		values = append(values, key.Name+"/"+key.Key+"="+data[key])
+	}
+	return util.Fingerprint(values)
+}
+
+// IsDataSourceConfigMap checks whether config map is a data source of the settings or files of the StatefulSet
+func IsDataSourceConfigMap(statefulSet *apps.StatefulSet, configMap *core.ConfigMap) bool {
+	if (statefulSet == nil) || (configMap == nil) || (statefulSet.Namespace != configMap.Namespace) {
+		return false
+	}
+	if _, ok := statefulSet.Spec.Template.Annotations[AnnotationDataSourcesVersion]; !ok {
+		// StatefulSet has no data sources
+		return false
+	}
+
+	podSpec := &statefulSet.Spec.Template.Spec
+	for i := range podSpec.Containers {
+		for _, envVar := range podSpec.Containers[i].Env {
+			if (envVar.ValueFrom != nil) && (envVar.ValueFrom.ConfigMapKeyRef != nil) && (envVar.ValueFrom.ConfigMapKeyRef.Name == configMap.Name) {
+				return true
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if (volume.ConfigMap != nil) && (volume.ConfigMap.Name == configMap.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// HasHibernation checks whether CHI is stopped and started by the hibernation schedule
+func HasHibernation(chi *api.ClickHouseInstallation) bool {
+	if chi == nil {
+		return false
+	}
+	return (chi.Spec.Hibernation.GetSleep() != "") && (chi.Spec.Hibernation.GetWake() != "")
+}
+
+// IsHibernationTime checks whether CHI has to be hibernated at the moment.
+// CHI is hibernated in case it is going to be woken up earlier than put to sleep.
+// Schedule, which can not be parsed, never hibernates CHI
+func IsHibernationTime(chi *api.ClickHouseInstallation, now time.Time) bool {
+	sleep, wake := GetNextHibernationTransitions(chi, now)
+	if wake.IsZero() {
+		return false
+	}
+	return sleep.IsZero() || wake.Before(sleep)
+}
+
+// GetNextHibernationTransitions finds when CHI is put to sleep and woken up next time after the moment.
+// Zero time is returned in case there is no such moment
+func GetNextHibernationTransitions(chi *api.ClickHouseInstallation, now time.Time) (sleep, wake time.Time) {
+	if !HasHibernation(chi) {
+		return
+	}
+	sleepSchedule, wakeSchedule, location, err := ParseHibernation(chi.Spec.Hibernation)
+	if err != nil {
+		return
+	}
+	return sleepSchedule.Next(now.In(location)), wakeSchedule.Next(now.In(location))
+}
+
+// ParseHibernation parses sleep and wake schedules and timezone of the hibernation.
+// Empty timezone stands for UTC
+func ParseHibernation(hibernation *api.ChiHibernation) (sleep, wake *util.CronSchedule, location *time.Location, err error) {
+	if sleep, err = util.ParseCronSchedule(hibernation.GetSleep()); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to parse sleep schedule of hibernation err: %v", err)
+	}
+	if wake, err = util.ParseCronSchedule(hibernation.GetWake()); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to parse wake schedule of hibernation err: %v", err)
+	}
+	if location, err = time.LoadLocation(hibernation.GetTimezone()); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to load timezone %s of hibernation err: %v", hibernation.GetTimezone(), err)
+	}
+	return sleep, wake, location, nil
+}
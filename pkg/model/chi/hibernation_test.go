@@ -0,0 +1,136 @@
+package chi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func newTestHibernatedCHI(sleep, wake, timezone string) *api.ClickHouseInstallation {
+	chi := &api.ClickHouseInstallation{}
+	chi.Spec.Hibernation = &api.ChiHibernation{
+		Sleep:    sleep,
+		Wake:     wake,
+		Timezone: timezone,
+	}
+	return chi
+}
+
+func TestIsHibernationTime(t *testing.T) {
+	// Wednesday
+	wednesday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 3, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		chi        *api.ClickHouseInstallation
+		now        time.Time
+		sleep      time.Time
+		wake       time.Time
+		hibernated bool
+	}{
+		{
+			name: "no CHI",
+			now:  wednesday(12, 0),
+		},
+		{
+			name: "no hibernation",
+			chi:  &api.ClickHouseInstallation{},
+			now:  wednesday(12, 0),
+		},
+		{
+			name: "sleep schedule only",
+			chi:  newTestHibernatedCHI("0 20 * * *", "", ""),
+			now:  wednesday(22, 0),
+		},
+		{
+			name: "working hours",
+			chi:  newTestHibernatedCHI("0 20 * * 1-5", "0 8 * * 1-5", ""),
+			now:  wednesday(12, 0),
+			// Wednesday evening and Thursday morning
+			sleep: wednesday(20, 0),
+			wake:  wednesday(8, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:       "night",
+			chi:        newTestHibernatedCHI("0 20 * * 1-5", "0 8 * * 1-5", ""),
+			now:        wednesday(22, 0),
+			sleep:      wednesday(20, 0).AddDate(0, 0, 1),
+			wake:       wednesday(8, 0).AddDate(0, 0, 1),
+			hibernated: true,
+		},
+		{
+			name:       "sleep moment",
+			chi:        newTestHibernatedCHI("0 20 * * 1-5", "0 8 * * 1-5", ""),
+			now:        wednesday(20, 0),
+			sleep:      wednesday(20, 0).AddDate(0, 0, 1),
+			wake:       wednesday(8, 0).AddDate(0, 0, 1),
+			hibernated: true,
+		},
+		{
+			name: "wake moment",
+			chi:  newTestHibernatedCHI("0 20 * * 1-5", "0 8 * * 1-5", ""),
+			now:  wednesday(8, 0),
+			// Wednesday evening and Thursday morning
+			sleep: wednesday(20, 0),
+			wake:  wednesday(8, 0).AddDate(0, 0, 1),
+		},
+		{
+			name: "weekend",
+			chi:  newTestHibernatedCHI("0 20 * * 1-5", "0 8 * * 1-5", ""),
+			// Saturday
+			now: wednesday(12, 0).AddDate(0, 0, 3),
+			// Monday evening and Monday morning
+			sleep:      wednesday(20, 0).AddDate(0, 0, 5),
+			wake:       wednesday(8, 0).AddDate(0, 0, 5),
+			hibernated: true,
+		},
+		{
+			name: "schedule in timezone",
+			chi:  newTestHibernatedCHI("0 20 * * *", "0 8 * * *", "Europe/Berlin"),
+			// 08:30 in Berlin
+			now:   wednesday(7, 30),
+			sleep: wednesday(19, 0),
+			wake:  wednesday(7, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:       "schedule in UTC",
+			chi:        newTestHibernatedCHI("0 20 * * *", "0 8 * * *", ""),
+			now:        wednesday(7, 30),
+			sleep:      wednesday(20, 0),
+			wake:       wednesday(8, 0),
+			hibernated: true,
+		},
+		{
+			name: "never sleeping",
+			chi:  newTestHibernatedCHI("0 20 30 2 *", "0 8 * * *", ""),
+			now:  wednesday(7, 30),
+			wake: wednesday(8, 0),
+			// Wake is the only transition ahead, thus CHI is asleep
+			hibernated: true,
+		},
+		{
+			name: "invalid schedule",
+			chi:  newTestHibernatedCHI("0 25 * * *", "0 8 * * *", ""),
+			now:  wednesday(7, 30),
+		},
+		{
+			name: "invalid timezone",
+			chi:  newTestHibernatedCHI("0 20 * * *", "0 8 * * *", "Mars/Olympus"),
+			now:  wednesday(7, 30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sleep, wake := GetNextHibernationTransitions(tt.chi, tt.now)
+			require.True(t, tt.sleep.Equal(sleep), "sleep %s", sleep)
+			require.True(t, tt.wake.Equal(wake), "wake %s", wake)
+			require.Equal(t, tt.hibernated, IsHibernationTime(tt.chi, tt.now))
+		})
+	}
+}
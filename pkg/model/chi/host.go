@@ -15,9 +15,13 @@
 package chi
 
 import (
+	"regexp"
+	"strings"
+
 	core "k8s.io/api/core/v1"
 
 	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/swversion"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
 
@@ -85,3 +89,34 @@ func HostWalkInvalidPorts(host *api.ChiHost, f func(name string, port *int32, pr
 		},
 	)
 }
+
+// imageTagVersion matches ClickHouse version at the beginning of the image tag. Ex.: 23.8.2.7-alpine
+var imageTagVersion = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+`)
+
+// GetHostImageVersion gets ClickHouse version of the host out of the image tag of the ClickHouse container.
+// Returns nil in case version is not specified by the tag, such as for 'latest' tag
+func GetHostImageVersion(host *api.ChiHost) *swversion.SoftWareVersion {
+	image := DefaultClickHouseDockerImage
+	if podTemplate, ok := host.GetPodTemplate(); ok && (len(podTemplate.Spec.Containers) > 0) {
+		image = podTemplate.Spec.Containers[0].Image
+		for i := range podTemplate.Spec.Containers {
+			if podTemplate.Spec.Containers[i].Name == ClickHouseContainerName {
+				image = podTemplate.Spec.Containers[i].Image
+			}
+		}
+	}
+
+	// Cut digest and repository off
+	image, _, _ = strings.Cut(image, "@")
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+
+	version := imageTagVersion.FindString(tag)
+	if strings.Count(version, ".") == 1 {
+		// Tag specifies major.minor only, such as 23.8
+		version += ".0"
+	}
+	return swversion.NewSoftWareVersion(version)
+}
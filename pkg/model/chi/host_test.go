@@ -0,0 +1,120 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// newTestImageVersionHost creates host of a CHI, which has pod template with the specified containers
+func newTestImageVersionHost(containers ...core.Container) *api.ChiHost {
+	chi := &api.ClickHouseInstallation{}
+	host := &api.ChiHost{Name: "host"}
+	host.Runtime.CHI = chi
+	if len(containers) == 0 {
+		return host
+	}
+
+	chi.Spec.Templates = api.NewChiTemplates()
+	chi.Spec.Templates.EnsurePodTemplatesIndex().Set("pod-template", &api.PodTemplate{
+		Name: "pod-template",
+		Spec: core.PodSpec{Containers: containers},
+	})
+	host.Templates = api.NewChiTemplateNames()
+	host.Templates.PodTemplate = "pod-template"
+	return host
+}
+
+func TestGetHostImageVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []core.Container
+		// version specifies expected version, empty means version is unknown
+		version string
+		// constraint specifies constraint the version is expected to match
+		constraint string
+	}{
+		{
+			name: "no pod template uses default image",
+		},
+		{
+			name:       "full version",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:23.8.2.7"}},
+			version:    "23.8.2.7",
+			constraint: ">= 23.8",
+		},
+		{
+			name:       "version with suffix",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "altinity/clickhouse-server:23.8.11.29.altinitystable-alpine"}},
+			version:    "23.8.11.29",
+			constraint: "< 24.1",
+		},
+		{
+			name:       "major and minor only",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:24.3"}},
+			version:    "24.3.0",
+			constraint: ">= 24.1",
+		},
+		{
+			name:       "major only",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:24"}},
+		},
+		{
+			name:       "latest",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:latest"}},
+		},
+		{
+			name:       "no tag",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server"}},
+		},
+		{
+			name:       "registry port is not a tag",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "registry.local:5000/clickhouse-server"}},
+		},
+		{
+			name:       "registry port and tag",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "registry.local:5000/clickhouse-server:22.3.20.29"}},
+			version:    "22.3.20.29",
+			constraint: "< 22.6",
+		},
+		{
+			name:       "digest is cut off",
+			containers: []core.Container{{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:24.1.5.6@sha256:0123456789abcdef"}},
+			version:    "24.1.5.6",
+			constraint: ">= 24.1",
+		},
+		{
+			name: "clickhouse container is preferred",
+			containers: []core.Container{
+				{Name: "sidecar", Image: "busybox:1.36.1"},
+				{Name: ClickHouseContainerName, Image: "clickhouse/clickhouse-server:23.3.1.2823"},
+			},
+			version:    "23.3.1.2823",
+			constraint: "< 23.8",
+		},
+		{
+			name: "first container is used in case there is no clickhouse container",
+			containers: []core.Container{
+				{Name: "server", Image: "clickhouse/clickhouse-server:23.10.1.1"},
+				{Name: "sidecar", Image: "busybox:1.36.1"},
+			},
+			version:    "23.10.1.1",
+			constraint: ">= 23.10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := GetHostImageVersion(newTestImageVersionHost(tt.containers...))
+			if tt.version == "" {
+				require.True(t, version.IsUnknown(), "version %s", version)
+				return
+			}
+			require.Equal(t, tt.version, version.String())
+			require.True(t, version.Matches(tt.constraint), "version %s constraint %s", version, tt.constraint)
+		})
+	}
+}
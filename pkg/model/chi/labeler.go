@@ -48,7 +48,10 @@ const (
 	labelConfigMapValueCHICommon      = "ChiCommon"
 	labelConfigMapValueCHICommonUsers = "ChiCommonUsers"
 	labelConfigMapValueHost           = "Host"
+	labelConfigMapValueCHIRevision    = "ChiRevision"
 	LabelService                      = clickhouse_altinity_com.APIGroupName + "/" + "Service"
+	LabelSecret                       = clickhouse_altinity_com.APIGroupName + "/" + "Secret"
+	labelSecretValueOperatorCreds     = "OperatorCredentials"
 	labelServiceValueCHI              = "chi"
 	labelServiceValueCluster          = "cluster"
 	labelServiceValueShard            = "shard"
@@ -105,6 +108,24 @@ func (l *Labeler) GetConfigMapCHICommonUsers() map[string]string {
 		})
 }
 
+// GetConfigMapCHIRevision
+func (l *Labeler) GetConfigMapCHIRevision() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelConfigMap: labelConfigMapValueCHIRevision,
+		})
+}
+
+// GetSelectorConfigMapCHIRevision gets labels to select revision ConfigMaps of the CHI
+func (l *Labeler) GetSelectorConfigMapCHIRevision() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.GetSelectorCHIScope(),
+		map[string]string{
+			LabelConfigMap: labelConfigMapValueCHIRevision,
+		})
+}
+
 // GetConfigMapHost
 func (l *Labeler) GetConfigMapHost(host *api.ChiHost) map[string]string {
 	return util.MergeStringMapsOverwrite(
@@ -114,6 +135,23 @@ func (l *Labeler) GetConfigMapHost(host *api.ChiHost) map[string]string {
 		})
 }
 
+// GetSecretOperatorCredentials
+func (l *Labeler) GetSecretOperatorCredentials() map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getCHIScope(),
+		map[string]string{
+			LabelSecret: labelSecretValueOperatorCreds,
+		})
+}
+
+// GetSelectorOperatorCredentials gets labels to select Secrets with generated operator credentials of all CHIs
+func GetSelectorOperatorCredentials() map[string]string {
+	return map[string]string{
+		LabelAppName: LabelAppValue,
+		LabelSecret:  labelSecretValueOperatorCreds,
+	}
+}
+
 // GetServiceCHI
 func (l *Labeler) GetServiceCHI(chi *api.ClickHouseInstallation) map[string]string {
 	return util.MergeStringMapsOverwrite(
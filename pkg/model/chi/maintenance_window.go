@@ -0,0 +1,93 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// HasMaintenanceWindows checks whether disruptive changes of the CHI are restricted to maintenance windows
+func HasMaintenanceWindows(chi *api.ClickHouseInstallation) bool {
+	if chi == nil {
+		return false
+	}
+	return len(chi.GetReconciling().GetMaintenanceWindows()) > 0
+}
+
+// IsInMaintenanceWindow checks whether disruptive changes of the CHI are allowed at the moment.
+// CHI without maintenance windows allows disruptive changes at any moment.
+// Windows, which can not be parsed, are never open. Such windows are reported by spec validation of the normalizer
+func IsInMaintenanceWindow(chi *api.ClickHouseInstallation, now time.Time) bool {
+	if !HasMaintenanceWindows(chi) {
+		return true
+	}
+	for i := range chi.GetReconciling().GetMaintenanceWindows() {
+		schedule, duration, location, err := ParseMaintenanceWindow(&chi.GetReconciling().GetMaintenanceWindows()[i])
+		if err != nil {
+			continue
+		}
+		// Window is open in case it started within the last duration
+		start := schedule.Next(now.In(location).Add(-duration))
+		if !start.IsZero() && !start.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNextMaintenanceWindow finds start of the nearest maintenance window of the CHI after the moment.
+// Zero time is returned in case there is no such window
+func GetNextMaintenanceWindow(chi *api.ClickHouseInstallation, now time.Time) time.Time {
+	var next time.Time
+	if !HasMaintenanceWindows(chi) {
+		return next
+	}
+	for i := range chi.GetReconciling().GetMaintenanceWindows() {
+		schedule, _, location, err := ParseMaintenanceWindow(&chi.GetReconciling().GetMaintenanceWindows()[i])
+		if err != nil {
+			continue
+		}
+		start := schedule.Next(now.In(location))
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// ParseMaintenanceWindow parses schedule, duration and timezone of the maintenance window.
+// Empty timezone stands for UTC
+func ParseMaintenanceWindow(window *api.ChiMaintenanceWindow) (*util.CronSchedule, time.Duration, *time.Location, error) {
+	schedule, err := util.ParseCronSchedule(window.Schedule)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("unable to parse duration %s of maintenance window err: %v", window.Duration, err)
+	}
+	if duration <= 0 {
+		return nil, 0, nil, fmt.Errorf("duration %s of maintenance window has to be positive", window.Duration)
+	}
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("unable to load timezone %s of maintenance window err: %v", window.Timezone, err)
+	}
+	return schedule, duration, location, nil
+}
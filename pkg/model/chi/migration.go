@@ -0,0 +1,261 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// schemaMigrationSuffix specifies suffix of ConfigMap keys, which are migrations
+const schemaMigrationSuffix = ".sql"
+
+// SchemaMigration specifies one migration
+type SchemaMigration struct {
+	// Version is the ConfigMap key of the migration
+	Version string
+	// Checksum is used to detect migrations edited after being applied
+	Checksum string
+	SQLs     []string
+	// Err specifies why migration can not be applied, such as unterminated string literal
+	Err error
+}
+
+// NewSchemaMigrationsFromConfigMap reads migrations from the ConfigMap.
+// Migrations are sorted in the order they are to be applied
+func NewSchemaMigrationsFromConfigMap(configMap *core.ConfigMap) (migrations []*SchemaMigration) {
+	if configMap == nil {
+		return nil
+	}
+	for key, value := range configMap.Data {
+		if !strings.HasSuffix(key, schemaMigrationSuffix) {
+			continue
+		}
+		sqls, err := splitSQLStatements(value)
+		migrations = append(migrations, &SchemaMigration{
+			Version:  key,
+			Checksum: getSchemaMigrationChecksum(value),
+			SQLs:     sqls,
+			Err:      err,
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return compareSchemaMigrationVersions(migrations[i].Version, migrations[j].Version) < 0
+	})
+	return migrations
+}
+
+// compareSchemaMigrationVersions compares versions in natural order, thus "2_b.sql" precedes "10_a.sql".
+// Runs of digits are compared by their numeric values, the rest of the version is compared lexicographically.
+// Versions, which differ in leading zeros only, are ordered lexicographically in order to keep the order stable
+func compareSchemaMigrationVersions(a, b string) int {
+	i, j := 0, 0
+	for (i < len(a)) && (j < len(b)) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			// Compare runs of digits by numeric values of any length
+			startA, startB := i, j
+			for (i < len(a)) && isDigit(a[i]) {
+				i++
+			}
+			for (j < len(b)) && isDigit(b[j]) {
+				j++
+			}
+			numA := strings.TrimLeft(a[startA:i], "0")
+			numB := strings.TrimLeft(b[startB:j], "0")
+			if len(numA) != len(numB) {
+				return len(numA) - len(numB)
+			}
+			if cmp := strings.Compare(numA, numB); cmp != 0 {
+				return cmp
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return int(a[i]) - int(b[j])
+		}
+		i++
+		j++
+	}
+	if rest := (len(a) - i) - (len(b) - j); rest != 0 {
+		return rest
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return ('0' <= c) && (c <= '9')
+}
+
+// getSchemaMigrationChecksum gets checksum of the migration script as it is specified in the ConfigMap
+func getSchemaMigrationChecksum(script string) string {
+	checksum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(checksum[:])
+}
+
+// splitSQLStatements splits SQL script into statements separated by ';'.
+// Separators within literals and comments are respected, comments preceding and trailing the statement are trimmed
+func splitSQLStatements(script string) (statements []string, err error) {
+	tokens, err := util.TokenizeSQL(script)
+	if err != nil {
+		return nil, err
+	}
+	first, last := -1, -1
+	flush := func() {
+		if first >= 0 {
+			statements = append(statements, script[tokens[first].Start:tokens[last].End])
+		}
+		first, last = -1, -1
+	}
+	for i := range tokens {
+		token := &tokens[i]
+		switch {
+		case token.IsPunct(";"):
+			flush()
+		case token.IsSignificant():
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	flush()
+	return statements, nil
+}
+
+// SetSQLOnCluster adds ON CLUSTER clause to DDL statement, thus the statement is applied to all hosts of the cluster.
+// Statements, which have ON CLUSTER clause already, as well as statements, which are not DDL, such as INSERT, are kept as-is.
+// DDL statement, which clause can not be added to, is reported as an error
+func SetSQLOnCluster(sql, cluster string) (string, error) {
+	tokens, err := util.TokenizeSQL(sql)
+	if err != nil {
+		return "", err
+	}
+	var significant []*util.SQLToken
+	for i := range tokens {
+		if tokens[i].IsSignificant() {
+			significant = append(significant, &tokens[i])
+		}
+	}
+	get := func(i int) *util.SQLToken {
+		if i < len(significant) {
+			return significant[i]
+		}
+		return nil
+	}
+	for i := range significant {
+		if significant[i].IsWord("ON") && get(i+1).IsWord("CLUSTER") {
+			return sql, nil
+		}
+	}
+	if len(significant) == 0 {
+		return sql, nil
+	}
+
+	clause := " ON CLUSTER " + util.QuoteSQLString(cluster)
+	insertAfter := func(token *util.SQLToken) string {
+		return sql[:token.End] + clause + sql[token.End:]
+	}
+	// objectName skips object name, which starts at specified token and is optionally qualified with database name.
+	// Returns index of the last token of the name or -1 in case there is no name
+	objectName := func(i int) int {
+		if !get(i).IsIdentifier() {
+			return -1
+		}
+		if get(i+1).IsPunct(".") && get(i+2).IsIdentifier() {
+			return i + 2
+		}
+		return i
+	}
+	// objectKind skips kind of object, such as TABLE or MATERIALIZED VIEW, along with IF [NOT] EXISTS.
+	// Returns index of the token next to skipped ones or -1 in case object kind is not known
+	objectKind := func(i int, kinds ...string) int {
+		found := false
+		for _, kind := range kinds {
+			words := strings.Fields(kind)
+			matched := true
+			for j, word := range words {
+				if !get(i + j).IsWord(word) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				i += len(words)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return -1
+		}
+		if get(i).IsWord("IF") && get(i+1).IsWord("NOT") && get(i+2).IsWord("EXISTS") {
+			return i + 3
+		}
+		if get(i).IsWord("IF") && get(i+1).IsWord("EXISTS") {
+			return i + 2
+		}
+		return i
+	}
+	notSupported := fmt.Errorf("unable to add ON CLUSTER clause to '%s', specify it explicitly", get(0).Value)
+
+	i := 0
+	switch first := get(0); {
+	case first.IsWord("CREATE"):
+		i = 1
+		if get(i).IsWord("OR") && get(i+1).IsWord("REPLACE") {
+			i += 2
+		}
+		i = objectKind(i, "DATABASE", "TABLE", "MATERIALIZED VIEW", "VIEW", "DICTIONARY", "FUNCTION", "USER", "ROLE")
+	case first.IsWord("ATTACH"), first.IsWord("DETACH"):
+		i = objectKind(1, "DATABASE", "TABLE", "MATERIALIZED VIEW", "VIEW", "DICTIONARY")
+	case first.IsWord("ALTER"):
+		i = objectKind(1, "TABLE", "USER", "ROLE")
+	case first.IsWord("DROP"):
+		i = objectKind(1, "DATABASE", "TABLE", "MATERIALIZED VIEW", "VIEW", "DICTIONARY", "FUNCTION", "USER", "ROLE")
+	case first.IsWord("TRUNCATE"):
+		// TABLE keyword is optional
+		if i = objectKind(1, "TABLE"); i < 0 {
+			i = 1
+			if get(i).IsWord("IF") && get(i+1).IsWord("EXISTS") {
+				i += 2
+			}
+		}
+	case first.IsWord("OPTIMIZE"):
+		i = objectKind(1, "TABLE")
+	case first.IsWord("RENAME"), first.IsWord("EXCHANGE"):
+		// ON CLUSTER follows the list of renamed objects
+		return insertAfter(significant[len(significant)-1]), nil
+	case first.IsWord("GRANT"), first.IsWord("REVOKE"):
+		return insertAfter(first), nil
+	default:
+		// Not a DDL statement, such as INSERT, is applied on one host
+		return sql, nil
+	}
+	if i < 0 {
+		return "", notSupported
+	}
+	name := objectName(i)
+	if name < 0 {
+		return "", notSupported
+	}
+	return insertAfter(significant[name]), nil
+}
@@ -0,0 +1,187 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "single without separator",
+			script:   "SELECT 1",
+			expected: []string{"SELECT 1"},
+		},
+		{
+			name:     "several on one line",
+			script:   "SELECT 1; SELECT 2;",
+			expected: []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:     "multi-line statement",
+			script:   "CREATE TABLE t\n(\n    a UInt8\n)\nENGINE = Memory;\nSELECT 1;\n",
+			expected: []string{"CREATE TABLE t\n(\n    a UInt8\n)\nENGINE = Memory", "SELECT 1"},
+		},
+		{
+			name:     "separator at the end of the line within multi-line literal",
+			script:   "INSERT INTO t VALUES ('a;\nb;\n-- c');\nSELECT 1;",
+			expected: []string{"INSERT INTO t VALUES ('a;\nb;\n-- c')", "SELECT 1"},
+		},
+		{
+			name:     "escaped and doubled quotes",
+			script:   `SELECT 'it\'s;', 'it''s;'; SELECT "a;""b", ` + "`c;``d`;",
+			expected: []string{`SELECT 'it\'s;', 'it''s;'`, `SELECT "a;""b", ` + "`c;``d`"},
+		},
+		{
+			name:     "comments",
+			script:   "-- header;\n/* block; */\nSELECT 1 -- trailing;\n;\n-- only comment;\n",
+			expected: []string{"SELECT 1"},
+		},
+		{
+			name:     "comment inside statement",
+			script:   "SELECT\n-- column;\n1;",
+			expected: []string{"SELECT\n-- column;\n1"},
+		},
+		{
+			name:     "empty statements",
+			script:   ";;\n  ;",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := splitSQLStatements(tt.script)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, statements)
+		})
+	}
+}
+
+func TestSplitSQLStatementsUnterminated(t *testing.T) {
+	for _, script := range []string{"SELECT 'a;", "SELECT \"a", "SELECT `a", "SELECT 1 /* comment"} {
+		_, err := splitSQLStatements(script)
+		require.Error(t, err, script)
+	}
+}
+
+func TestNewSchemaMigrationsFromConfigMap(t *testing.T) {
+	configMap := &core.ConfigMap{
+		Data: map[string]string{
+			"0002_b.sql": "SELECT 2;",
+			"0001_a.sql": "SELECT 1;\nSELECT 11;",
+			"README.md":  "not a migration",
+			"0003_c.sql": "SELECT 'unterminated",
+		},
+	}
+	migrations := NewSchemaMigrationsFromConfigMap(configMap)
+	require.Len(t, migrations, 3)
+
+	require.Equal(t, "0001_a.sql", migrations[0].Version)
+	require.Equal(t, []string{"SELECT 1", "SELECT 11"}, migrations[0].SQLs)
+	require.NoError(t, migrations[0].Err)
+	require.Equal(t, "0002_b.sql", migrations[1].Version)
+	require.Equal(t, "0003_c.sql", migrations[2].Version)
+	require.Error(t, migrations[2].Err)
+
+	// Checksum is taken over the script as-is, thus any edit, even within comments, is detected
+	require.Equal(t, "8e7003d62f9d8cbd28da2f243bb0d215bfd4622c716be09be89a8764d9f4c7cb", migrations[1].Checksum)
+	require.NotEqual(t, getSchemaMigrationChecksum("SELECT 2;"), getSchemaMigrationChecksum("SELECT 2; -- edited"))
+
+	require.Nil(t, NewSchemaMigrationsFromConfigMap(nil))
+
+	// Versions are not required to be zero-padded
+	migrations = NewSchemaMigrationsFromConfigMap(&core.ConfigMap{
+		Data: map[string]string{
+			"10_c.sql": "SELECT 10;",
+			"2_b.sql":  "SELECT 2;",
+			"1_a.sql":  "SELECT 1;",
+		},
+	})
+	require.Len(t, migrations, 3)
+	require.Equal(t, "1_a.sql", migrations[0].Version)
+	require.Equal(t, "2_b.sql", migrations[1].Version)
+	require.Equal(t, "10_c.sql", migrations[2].Version)
+}
+
+func TestCompareSchemaMigrationVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		less bool
+	}{
+		{a: "0001_a.sql", b: "0002_a.sql", less: true},
+		{a: "2_b.sql", b: "10_a.sql", less: true},
+		{a: "9.sql", b: "0010.sql", less: true},
+		{a: "v2_b.sql", b: "v10_a.sql", less: true},
+		{a: "1_a.sql", b: "1_b.sql", less: true},
+		{a: "1.sql", b: "1_a.sql", less: true},
+		{a: "001_a.sql", b: "1_a.sql", less: true},
+		{a: "20240102_a.sql", b: "20240101_b.sql", less: false},
+		{a: "a.sql", b: "a.sql", less: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"<"+tt.b, func(t *testing.T) {
+			require.Equal(t, tt.less, compareSchemaMigrationVersions(tt.a, tt.b) < 0)
+			if tt.a != tt.b {
+				require.Equal(t, !tt.less, compareSchemaMigrationVersions(tt.b, tt.a) < 0)
+			}
+		})
+	}
+}
+
+func TestSetSQLOnCluster(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"CREATE TABLE db.t (a UInt8) ENGINE = Memory", "CREATE TABLE db.t ON CLUSTER 'c' (a UInt8) ENGINE = Memory"},
+		{"create table if not exists `db`.`t` AS db.s", "create table if not exists `db`.`t` ON CLUSTER 'c' AS db.s"},
+		{"CREATE OR REPLACE VIEW v AS SELECT 1", "CREATE OR REPLACE VIEW v ON CLUSTER 'c' AS SELECT 1"},
+		{"CREATE MATERIALIZED VIEW IF NOT EXISTS db.mv TO db.t AS SELECT 1", "CREATE MATERIALIZED VIEW IF NOT EXISTS db.mv ON CLUSTER 'c' TO db.t AS SELECT 1"},
+		{"CREATE DATABASE IF NOT EXISTS db", "CREATE DATABASE IF NOT EXISTS db ON CLUSTER 'c'"},
+		{"CREATE FUNCTION f AS (x) -> x", "CREATE FUNCTION f ON CLUSTER 'c' AS (x) -> x"},
+		{"ALTER TABLE db.t ADD COLUMN b UInt8", "ALTER TABLE db.t ON CLUSTER 'c' ADD COLUMN b UInt8"},
+		{"DROP TABLE IF EXISTS db.t SYNC", "DROP TABLE IF EXISTS db.t ON CLUSTER 'c' SYNC"},
+		{"TRUNCATE db.t", "TRUNCATE db.t ON CLUSTER 'c'"},
+		{"TRUNCATE TABLE IF EXISTS t", "TRUNCATE TABLE IF EXISTS t ON CLUSTER 'c'"},
+		{"RENAME TABLE a TO b, c TO d", "RENAME TABLE a TO b, c TO d ON CLUSTER 'c'"},
+		{"GRANT SELECT ON db.* TO u", "GRANT ON CLUSTER 'c' SELECT ON db.* TO u"},
+		{"/* hint */ ALTER TABLE t -- comment\nDELETE WHERE 1", "/* hint */ ALTER TABLE t ON CLUSTER 'c' -- comment\nDELETE WHERE 1"},
+		// Kept as-is
+		{"CREATE TABLE t ON CLUSTER '{cluster}' (a UInt8) ENGINE = Memory", "CREATE TABLE t ON CLUSTER '{cluster}' (a UInt8) ENGINE = Memory"},
+		{"INSERT INTO t VALUES ('CREATE TABLE x')", "INSERT INTO t VALUES ('CREATE TABLE x')"},
+		{"SELECT 'ON CLUSTER'", "SELECT 'ON CLUSTER'"},
+		{"SYSTEM FLUSH LOGS", "SYSTEM FLUSH LOGS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			actual, err := SetSQLOnCluster(tt.sql, "c")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	// Cluster name is quoted
+	actual, err := SetSQLOnCluster("DROP TABLE t", "it's")
+	require.NoError(t, err)
+	require.Equal(t, `DROP TABLE t ON CLUSTER 'it\'s'`, actual)
+}
+
+func TestSetSQLOnClusterNotSupported(t *testing.T) {
+	for _, sql := range []string{
+		"CREATE TEMPORARY TABLE t (a UInt8)",
+		"CREATE ROW POLICY p ON db.t USING 1",
+		"ALTER QUOTA q",
+		"DROP TABLE",
+		"CREATE TABLE 'x'",
+	} {
+		_, err := SetSQLOnCluster(sql, "c")
+		require.Error(t, err, sql)
+	}
+}
@@ -68,6 +68,12 @@ const (
 	// configMapHostNamePattern is a template of macros ConfigMap. "chi-{chi}-deploy-confd-{cluster}-{shard}-{host}"
 	configMapHostNamePattern = "chi-" + macrosChiName + "-deploy-confd-" + macrosClusterName + "-" + macrosHostName
 
+	// configMapRevisionNamePattern is a template of CHI revision ConfigMap. "chi-{chi}-revision-{revision}"
+	configMapRevisionNamePattern = "chi-" + macrosChiName + "-revision-%d"
+
+	// operatorCredentialsSecretNamePattern is a template of generated operator credentials Secret. "chi-{chi}-operator-credentials"
+	operatorCredentialsSecretNamePattern = "chi-" + macrosChiName + "-operator-credentials"
+
 	// configMapHostMigrationNamePattern is a template of macros ConfigMap. "chi-{chi}-migration-{cluster}-{shard}-{host}"
 	//configMapHostMigrationNamePattern = "chi-" + macrosChiName + "-migration-" + macrosClusterName + "-" + macrosHostName
 
@@ -354,6 +360,16 @@ func CreateConfigMapCommonUsersName(chi *api.ClickHouseInstallation) string {
 	return Macro(chi).Line(configMapCommonUsersNamePattern)
 }
 
+// CreateConfigMapRevisionName returns a name for a ConfigMap with CHI revision
+func CreateConfigMapRevisionName(chi *api.ClickHouseInstallation, revision int) string {
+	return fmt.Sprintf(Macro(chi).Line(configMapRevisionNamePattern), revision)
+}
+
+// CreateOperatorCredentialsSecretName returns a name for a Secret with generated operator credentials
+func CreateOperatorCredentialsSecretName(chi *api.ClickHouseInstallation) string {
+	return Macro(chi).Line(operatorCredentialsSecretNamePattern)
+}
+
 // CreateCHIServiceName creates a name of a root ClickHouseInstallation Service resource
 func CreateCHIServiceName(chi *api.ClickHouseInstallation) string {
 	// Name can be generated either from default name pattern,
@@ -650,6 +666,27 @@ func CreateFQDNs(obj interface{}, scope interface{}, excludeSelf bool) []string
 	return nil
 }
 
+// CreateExternalFQDNs creates hostnames of the external hosts within the scope of the host.
+// scope specifies target scope - either shard or cluster of the host
+func CreateExternalFQDNs(host *api.ChiHost, scope interface{}) (fqdns []string) {
+	f := func(h *api.ChiHost) error {
+		if h.IsExternal() && (h.External.GetHostname() != "") {
+			fqdns = append(fqdns, h.External.GetHostname())
+		}
+		return nil
+	}
+	switch scope.(type) {
+	case api.ChiShard:
+		host.GetShard().WalkAllHosts(f)
+	case api.Cluster:
+		host.GetCluster().WalkShards(func(index int, shard *api.ChiShard) error {
+			shard.WalkAllHosts(f)
+			return nil
+		})
+	}
+	return fqdns
+}
+
 // CreatePodHostnameRegexp creates pod hostname regexp.
 // For example, `template` can be defined in operator config:
 // HostRegexpTemplate: chi-{chi}-[^.]+\\d+-\\d+\\.{namespace}.svc.cluster.local$"
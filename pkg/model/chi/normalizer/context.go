@@ -22,6 +22,8 @@ type Context struct {
 	chi *api.ClickHouseInstallation
 	// options specifies normalization options
 	options *Options
+	// specIssues specifies invalid sections of the spec, which are skipped by normalization
+	specIssues []string
 }
 
 // NewContext creates new Context
@@ -46,6 +48,22 @@ func (c *Context) SetTarget(chi *api.ClickHouseInstallation) *api.ClickHouseInst
 	return c.chi
 }
 
+// AddSpecIssue records invalid section of the spec
+func (c *Context) AddSpecIssue(issue string) {
+	if c == nil {
+		return
+	}
+	c.specIssues = append(c.specIssues, issue)
+}
+
+// GetSpecIssues gets invalid sections of the spec
+func (c *Context) GetSpecIssues() []string {
+	if c == nil {
+		return nil
+	}
+	return c.specIssues
+}
+
 func (c *Context) Options() *Options {
 	if c == nil {
 		return nil
@@ -109,6 +109,7 @@ func (n *Normalizer) normalize() (*api.ClickHouseInstallation, error) {
 	n.ctx.GetTarget().Spec.TaskID = n.normalizeTaskID(n.ctx.GetTarget().Spec.TaskID)
 	n.ctx.GetTarget().Spec.UseTemplates = n.normalizeUseTemplates(n.ctx.GetTarget().Spec.UseTemplates)
 	n.ctx.GetTarget().Spec.Stop = n.normalizeStop(n.ctx.GetTarget().Spec.Stop)
+	n.ctx.GetTarget().Spec.Hibernation = n.normalizeHibernation(n.ctx.GetTarget().Spec.Hibernation)
 	n.ctx.GetTarget().Spec.Restart = n.normalizeRestart(n.ctx.GetTarget().Spec.Restart)
 	n.ctx.GetTarget().Spec.Troubleshoot = n.normalizeTroubleshoot(n.ctx.GetTarget().Spec.Troubleshoot)
 	n.ctx.GetTarget().Spec.NamespaceDomainPattern = n.normalizeNamespaceDomainPattern(n.ctx.GetTarget().Spec.NamespaceDomainPattern)
@@ -122,6 +123,14 @@ func (n *Normalizer) normalize() (*api.ClickHouseInstallation, error) {
 	n.finalizeCHI()
 	n.fillStatus()
 
+	// Normalized CHI is returned along with validation error, thus caller decides whether to proceed
+	if err := n.validateSpec(); err != nil {
+		return n.ctx.GetTarget(), err
+	}
+	if err := n.validateSettings(); err != nil {
+		return n.ctx.GetTarget(), err
+	}
+
 	return n.ctx.GetTarget(), nil
 }
 
@@ -134,9 +143,27 @@ func (n *Normalizer) finalizeCHI() {
 		hostApplyHostTemplate(host, hostTemplate)
 		return nil
 	})
+	n.ctx.GetTarget().WalkExternalHosts(func(host *api.ChiHost) error {
+		normalizeExternalHost(host)
+		return nil
+	})
 	n.fillCHIAddressInfo()
 }
 
+// normalizeExternalHost normalizes host, which is not managed by the operator.
+// Host templates are not applied to external host, thus ports fall back to ClickHouse defaults
+func normalizeExternalHost(host *api.ChiHost) {
+	host.External.Hostname = strings.TrimSpace(host.External.Hostname)
+	if host.External.Hostname == "" {
+		log.V(1).M(host).F().Warning("external host: %s has no hostname specified and is skipped", host.Name)
+	}
+
+	host.TCPPort = api.EnsurePortValue(host.TCPPort, api.PortUnassigned(), model.ChDefaultTCPPortNumber)
+	host.TLSPort = api.EnsurePortValue(host.TLSPort, api.PortUnassigned(), model.ChDefaultTLSPortNumber)
+	host.HTTPPort = api.EnsurePortValue(host.HTTPPort, api.PortUnassigned(), model.ChDefaultHTTPPortNumber)
+	host.HTTPSPort = api.EnsurePortValue(host.HTTPSPort, api.PortUnassigned(), model.ChDefaultHTTPSPortNumber)
+}
+
 // fillCHIAddressInfo
 func (n *Normalizer) fillCHIAddressInfo() {
 	n.ctx.GetTarget().WalkHosts(func(host *api.ChiHost) error {
@@ -144,6 +171,11 @@ func (n *Normalizer) fillCHIAddressInfo() {
 		host.Runtime.Address.FQDN = model.CreateFQDN(host)
 		return nil
 	})
+	n.ctx.GetTarget().WalkExternalHosts(func(host *api.ChiHost) error {
+		// External host has no StatefulSet and is reachable by its hostname
+		host.Runtime.Address.FQDN = host.External.GetHostname()
+		return nil
+	})
 }
 
 // getHostTemplate gets Host Template to be used to normalize Host
@@ -329,6 +361,25 @@ func (n *Normalizer) normalizeStop(stop *api.StringBool) *api.StringBool {
 	return api.NewStringBool(false)
 }
 
+// defaultHibernationTimezone specifies timezone of the hibernation schedule, which has no timezone specified
+const defaultHibernationTimezone = "UTC"
+
+// normalizeHibernation normalizes .spec.hibernation
+func (n *Normalizer) normalizeHibernation(hibernation *api.ChiHibernation) *api.ChiHibernation {
+	if hibernation == nil {
+		return nil
+	}
+	// Hibernation may be shared with the template it came from, thus it is normalized as a copy
+	hibernation = hibernation.DeepCopy()
+	hibernation.Sleep = strings.Join(strings.Fields(hibernation.Sleep), " ")
+	hibernation.Wake = strings.Join(strings.Fields(hibernation.Wake), " ")
+	hibernation.Timezone = strings.TrimSpace(hibernation.Timezone)
+	if hibernation.Timezone == "" {
+		hibernation.Timezone = defaultHibernationTimezone
+	}
+	return hibernation
+}
+
 // normalizeRestart normalizes .spec.restart
 func (n *Normalizer) normalizeRestart(restart string) string {
 	switch strings.ToLower(restart) {
@@ -399,10 +450,27 @@ func (n *Normalizer) normalizeConfiguration(conf *api.Configuration) *api.Config
 	}
 	conf.Zookeeper = n.normalizeConfigurationZookeeper(conf.Zookeeper)
 	n.normalizeConfigurationAllSettingsBasedSections(conf)
+	conf.Storage = n.normalizeConfigurationStorage(conf.Storage)
 	conf.Clusters = n.normalizeClusters(conf.Clusters)
+	conf.VirtualClusters = n.normalizeConfigurationVirtualClusters(conf.VirtualClusters, conf.Clusters)
+	conf.Format = n.normalizeConfigurationFormat(conf.Format)
 	return conf
 }
 
+// normalizeConfigurationFormat normalizes .spec.configuration.format
+func (n *Normalizer) normalizeConfigurationFormat(format string) string {
+	for _, known := range []string{
+		api.ConfigFormatXML,
+		api.ConfigFormatYAML,
+	} {
+		if strings.EqualFold(format, known) {
+			return known
+		}
+	}
+	// Unknown or unspecified format falls back to XML
+	return api.ConfigFormatXML
+}
+
 // normalizeConfigurationAllSettingsBasedSections normalizes Settings-based configuration
 func (n *Normalizer) normalizeConfigurationAllSettingsBasedSections(conf *api.Configuration) {
 	conf.Users = n.normalizeConfigurationUsers(conf.Users)
@@ -462,9 +530,42 @@ func (n *Normalizer) normalizeReconciling(reconciling *api.ChiReconciling) *api.
 		reconciling.SetPolicy(api.ReconcilingPolicyUnspecified)
 	}
 	reconciling.Cleanup = n.normalizeReconcilingCleanup(reconciling.Cleanup)
+	reconciling.MaintenanceWindows = n.normalizeReconcilingMaintenanceWindows(reconciling.MaintenanceWindows)
 	return reconciling
 }
 
+// Defaults of the maintenance window, which has no duration or timezone specified
+const (
+	defaultMaintenanceWindowDuration = "1h"
+	defaultMaintenanceWindowTimezone = "UTC"
+)
+
+// normalizeReconcilingMaintenanceWindows normalizes .spec.reconciling.maintenanceWindows
+func (n *Normalizer) normalizeReconcilingMaintenanceWindows(windows []api.ChiMaintenanceWindow) []api.ChiMaintenanceWindow {
+	if len(windows) == 0 {
+		return nil
+	}
+	// Windows may be shared with the template they came from, thus they are normalized as a copy
+	windows = append([]api.ChiMaintenanceWindow{}, windows...)
+	for i := range windows {
+		window := &windows[i]
+		window.Schedule = strings.Join(strings.Fields(window.Schedule), " ")
+		window.Duration = strings.TrimSpace(window.Duration)
+		if window.Duration == "" {
+			window.Duration = defaultMaintenanceWindowDuration
+		}
+		window.Timezone = strings.TrimSpace(window.Timezone)
+		if window.Timezone == "" {
+			window.Timezone = defaultMaintenanceWindowTimezone
+		}
+		// Invalid window is never open, thus changes deferred till the window would never be applied
+		if _, _, _, err := model.ParseMaintenanceWindow(window); err != nil {
+			n.addSpecIssue("maintenance window with schedule: '%s' is invalid: %v", window.Schedule, err)
+		}
+	}
+	return windows
+}
+
 func (n *Normalizer) normalizeReconcilingCleanup(cleanup *api.ChiCleanup) *api.ChiCleanup {
 	if cleanup == nil {
 		cleanup = api.NewChiCleanup()
@@ -596,6 +697,71 @@ func (n *Normalizer) ensureClusters(clusters []*api.Cluster) []*api.Cluster {
 	return nil
 }
 
+// normalizeConfigurationVirtualClusters normalizes .spec.configuration.virtualClusters
+func (n *Normalizer) normalizeConfigurationVirtualClusters(
+	virtualClusters []*api.ChiVirtualCluster,
+	clusters []*api.Cluster,
+) (res []*api.ChiVirtualCluster) {
+	for _, virtualCluster := range virtualClusters {
+		if virtualCluster == nil {
+			continue
+		}
+		// Virtual cluster may be shared with the template it came from, thus it is normalized as a copy
+		virtualCluster = virtualCluster.DeepCopy()
+		virtualCluster.Name = strings.TrimSpace(virtualCluster.Name)
+		if virtualCluster.Name == "" {
+			// Virtual cluster has to be named
+			continue
+		}
+		// Virtual cluster is rendered into remote_servers along with the clusters, thus names must not collide
+		if isClusterNameUsed(clusters, virtualCluster.Name) {
+			n.addSpecIssue("virtual cluster: %s has the same name as the cluster of the CHI", virtualCluster.Name)
+			continue
+		}
+
+		// Referenced CHIs are looked up in the namespace of the CHI by default
+		for i := range virtualCluster.Clusters {
+			ref := &virtualCluster.Clusters[i]
+			if ref.Namespace == "" {
+				ref.Namespace = n.ctx.GetTarget().Namespace
+			}
+		}
+
+		n.appendVirtualClusterSecretEnvVar(virtualCluster)
+		res = append(res, virtualCluster)
+	}
+	return res
+}
+
+// isClusterNameUsed checks whether any of the clusters is named as specified
+func isClusterNameUsed(clusters []*api.Cluster, name string) bool {
+	for _, cluster := range clusters {
+		if (cluster != nil) && (cluster.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendVirtualClusterSecretEnvVar passes secret of the virtual cluster via ENV var, in case secret is sourced from a secret.
+// Auto-generated secret is specific to the CHI, thus it is not applicable to the virtual cluster
+func (n *Normalizer) appendVirtualClusterSecretEnvVar(virtualCluster *api.ChiVirtualCluster) {
+	switch virtualCluster.Secret.Source() {
+	case api.ClusterSecretSourceSecretRef:
+		n.appendAdditionalEnvVar(
+			core.EnvVar{
+				Name: model.CreateVirtualClusterSecretEnvVarName(virtualCluster),
+				ValueFrom: &core.EnvVarSource{
+					SecretKeyRef: virtualCluster.Secret.GetSecretKeyRef(),
+				},
+			},
+		)
+	case api.ClusterSecretSourceAuto:
+		log.V(1).F().Warning("virtual cluster: %s can not have auto-generated secret, secret is ignored", virtualCluster.Name)
+		virtualCluster.Secret = nil
+	}
+}
+
 // normalizeConfigurationZookeeper normalizes .spec.configuration.zookeeper
 func (n *Normalizer) normalizeConfigurationZookeeper(zk *api.ChiZookeeperConfig) *api.ChiZookeeperConfig {
 	if zk == nil {
@@ -619,6 +785,116 @@ func (n *Normalizer) normalizeConfigurationZookeeper(zk *api.ChiZookeeperConfig)
 	return zk
 }
 
+// normalizeConfigurationStorage normalizes .spec.configuration.storage
+func (n *Normalizer) normalizeConfigurationStorage(storage *api.StorageConfiguration) *api.StorageConfiguration {
+	if storage.IsEmpty() {
+		return nil
+	}
+
+	// Disks and policies have to be named, since names are used as tag names in storage config.
+	// Invalid ones are skipped and reported
+	disks := storage.Disks
+	storage.Disks = nil
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		if !util.IsXMLTagName(disk.Name) {
+			n.addSpecIssue("storage disk has invalid name: '%s'", disk.Name)
+			continue
+		}
+		if n.normalizeConfigurationStorageDisk(disk) {
+			storage.Disks = append(storage.Disks, disk)
+		}
+	}
+	policies := storage.Policies
+	storage.Policies = nil
+	for _, policy := range policies {
+		if policy == nil {
+			continue
+		}
+		if !util.IsXMLTagName(policy.Name) {
+			n.addSpecIssue("storage policy has invalid name: '%s'", policy.Name)
+			continue
+		}
+		if n.normalizeConfigurationStoragePolicy(policy) {
+			storage.Policies = append(storage.Policies, policy)
+		}
+	}
+
+	return storage
+}
+
+// normalizeConfigurationStorageDisk normalizes disk of .spec.configuration.storage.
+// Returns false in case disk is invalid
+func (n *Normalizer) normalizeConfigurationStorageDisk(disk *api.StorageDisk) bool {
+	switch strings.ToLower(disk.Type) {
+	case "", api.StorageDiskTypeLocal:
+		disk.Type = api.StorageDiskTypeLocal
+	case api.StorageDiskTypeS3:
+		disk.Type = api.StorageDiskTypeS3
+	case api.StorageDiskTypeAzureBlobStorage, "azure":
+		disk.Type = api.StorageDiskTypeAzureBlobStorage
+	case api.StorageDiskTypeCache:
+		disk.Type = api.StorageDiskTypeCache
+	default:
+		n.addSpecIssue("storage disk: %s has unknown type: '%s'", disk.Name, disk.Type)
+		return false
+	}
+
+	// In case no path specified for local or cache disk - assign '/var/lib/clickhouse/disks/{disk name}/'
+	if (disk.Path == "") && !disk.IsObjectStorage() {
+		disk.Path = model.DirPathClickHouseData + "/disks/" + disk.Name + "/"
+	}
+
+	// Credentials are not written into config files, but are passed via ENV vars
+	n.appendStorageDiskEnvVar(disk, model.StorageDiskFieldAccessKeyID, disk.AccessKeyID)
+	n.appendStorageDiskEnvVar(disk, model.StorageDiskFieldSecretAccessKey, disk.SecretAccessKey)
+	n.appendStorageDiskEnvVar(disk, model.StorageDiskFieldAccountName, disk.AccountName)
+	n.appendStorageDiskEnvVar(disk, model.StorageDiskFieldAccountKey, disk.AccountKey)
+	return true
+}
+
+// normalizeConfigurationStoragePolicy normalizes policy of .spec.configuration.storage.
+// Returns false in case policy is invalid
+func (n *Normalizer) normalizeConfigurationStoragePolicy(policy *api.StoragePolicy) bool {
+	volumes := policy.Volumes
+	policy.Volumes = nil
+	for i, volume := range volumes {
+		if volume == nil {
+			continue
+		}
+		// In case no volume name specified - assign 'volume{index}'
+		if volume.Name == "" {
+			volume.Name = fmt.Sprintf("volume%d", i)
+		}
+		if !util.IsXMLTagName(volume.Name) {
+			n.addSpecIssue("storage policy: %s has volume with invalid name: '%s'", policy.Name, volume.Name)
+			return false
+		}
+		policy.Volumes = append(policy.Volumes, volume)
+	}
+	return true
+}
+
+// appendStorageDiskEnvVar appends ENV var, which provides value of the disk's field from the secret, the config map
+// or the field of the pod
+func (n *Normalizer) appendStorageDiskEnvVar(disk *api.StorageDisk, field string, src *api.SettingSource) {
+	if src == nil {
+		return
+	}
+	if !src.HasValue() {
+		n.addSpecIssue("storage disk: %s has no source of '%s' specified", disk.Name, field)
+		return
+	}
+	n.appendAdditionalEnvVar(
+		core.EnvVar{
+			Name:      model.CreateStorageDiskEnvVarName(disk, field),
+			ValueFrom: src.GetEnvVarSource(),
+		},
+	)
+}
+
 type SettingsSubstitution interface {
 	Has(string) bool
 	Get(string) *api.Setting
@@ -685,7 +961,8 @@ func (n *Normalizer) substSettingsFieldWithSecretFieldValue(
 		})
 }
 
-// substSettingsFieldWithEnvRefToSecretField substitute users settings field with ref to ENV var where value from k8s secret is stored in
+// substSettingsFieldWithEnvRefToSecretField substitute users settings field with ref to ENV var where value from k8s secret is stored in.
+// Source setting may refer to config map or to pod's field as well, in this case ENV var is sourced from it.
 func (n *Normalizer) substSettingsFieldWithEnvRefToSecretField(
 	settings SettingsSubstitution,
 	dstField,
@@ -693,22 +970,31 @@ func (n *Normalizer) substSettingsFieldWithEnvRefToSecretField(
 	envVarNamePrefix string,
 	parseScalarString bool,
 ) bool {
+	// Source setting is replaced by the substitution, thus fetch its data source beforehand
+	var envVarSource *core.EnvVarSource
+	if settings.Has(srcSecretRefField) {
+		envVarSource = settings.Get(srcSecretRefField).GetEnvVarSource()
+	}
 	return n.substSettingsFieldWithDataFromDataSource(settings, dstField, srcSecretRefField, parseScalarString,
 		func(secretAddress api.ObjectAddress) (*api.Setting, error) {
+			if envVarSource == nil {
+				// Address of the secret is specified as a string
+				envVarSource = &core.EnvVarSource{
+					SecretKeyRef: &core.SecretKeySelector{
+						LocalObjectReference: core.LocalObjectReference{
+							Name: secretAddress.Name,
+						},
+						Key: secretAddress.Key,
+					},
+				}
+			}
 			// ENV VAR name and value
 			// In case not OK env var name will be empty and config will be incorrect. CH may not start
 			envVarName, _ := util.BuildShellEnvVarName(envVarNamePrefix + "_" + settings.Name2Key(dstField))
 			n.appendAdditionalEnvVar(
 				core.EnvVar{
-					Name: envVarName,
-					ValueFrom: &core.EnvVarSource{
-						SecretKeyRef: &core.SecretKeySelector{
-							LocalObjectReference: core.LocalObjectReference{
-								Name: secretAddress.Name,
-							},
-							Key: secretAddress.Key,
-						},
-					},
+					Name:      envVarName,
+					ValueFrom: envVarSource,
 				},
 			)
 			// Create new setting w/o value but with attribute to read from ENV var
@@ -716,37 +1002,30 @@ func (n *Normalizer) substSettingsFieldWithEnvRefToSecretField(
 		})
 }
 
+// substSettingsFieldWithMountedFile substitute files field with the file mounted from k8s secret, config map or pod's field
 func (n *Normalizer) substSettingsFieldWithMountedFile(settings *api.Settings, srcSecretRefField string) bool {
-	var defaultMode int32 = 0644
+	// Source setting is deleted by the substitution, thus fetch it beforehand
+	src := settings.Get(srcSecretRefField)
 	return n.substSettingsFieldWithDataFromDataSource(settings, "", srcSecretRefField, false,
 		func(secretAddress api.ObjectAddress) (*api.Setting, error) {
 			volumeName, ok1 := util.BuildRFC1035Label(srcSecretRefField)
 			volumeMountName, ok2 := util.BuildRFC1035Label(srcSecretRefField)
 			filenameInSettingsOrFiles := srcSecretRefField
-			filenameInMountedFS := secretAddress.Key
 
 			if !ok1 || !ok2 {
 				return nil, fmt.Errorf("unable to build k8s object name")
 			}
 
+			volumeSource, mountPath := n.buildMountedFileVolumeSource(src, secretAddress, filenameInSettingsOrFiles)
+			if volumeSource == nil {
+				return nil, fmt.Errorf("unable to build volume for the data source")
+			}
+
 			n.appendAdditionalVolume(core.Volume{
-				Name: volumeName,
-				VolumeSource: core.VolumeSource{
-					Secret: &core.SecretVolumeSource{
-						SecretName: secretAddress.Name,
-						Items: []core.KeyToPath{
-							{
-								Key:  secretAddress.Key,
-								Path: filenameInMountedFS,
-							},
-						},
-						DefaultMode: &defaultMode,
-					},
-				},
+				Name:         volumeName,
+				VolumeSource: *volumeSource,
 			})
 
-			// TODO setting may have specified mountPath explicitly
-			mountPath := filepath.Join(model.DirPathSecretFilesConfig, filenameInSettingsOrFiles, secretAddress.Name)
 			// TODO setting may have specified subPath explicitly
 			// Mount as file
 			//subPath := filename
@@ -764,6 +1043,87 @@ func (n *Normalizer) substSettingsFieldWithMountedFile(settings *api.Settings, s
 		})
 }
 
+// buildMountedFileVolumeSource builds volume source and mount path of the file provided by the data source.
+// Files are mounted as following:
+// 1. secret: secrets.d/<filename>/<secret name>/<key>
+// 2. config map: configmaps.d/<filename>/<config map name>/<key>
+// 3. pod's or container's field: podinfo.d/<filename>/<field path or resource>
+func (n *Normalizer) buildMountedFileVolumeSource(
+	src *api.Setting,
+	address api.ObjectAddress,
+	filename string,
+) (*core.VolumeSource, string) {
+	var defaultMode int32 = 0644
+
+	switch {
+	case src.HasSecretKeyRef():
+		// TODO setting may have specified mountPath explicitly
+		return &core.VolumeSource{
+			Secret: &core.SecretVolumeSource{
+				SecretName: address.Name,
+				Items: []core.KeyToPath{
+					{
+						Key:  address.Key,
+						Path: address.Key,
+					},
+				},
+				DefaultMode: &defaultMode,
+			},
+		}, filepath.Join(model.DirPathSecretFilesConfig, filename, address.Name)
+
+	case src.HasConfigMapKeyRef():
+		return &core.VolumeSource{
+			ConfigMap: &core.ConfigMapVolumeSource{
+				LocalObjectReference: core.LocalObjectReference{
+					Name: address.Name,
+				},
+				Items: []core.KeyToPath{
+					{
+						Key:  address.Key,
+						Path: address.Key,
+					},
+				},
+				DefaultMode: &defaultMode,
+				Optional:    src.GetConfigMapKeyRef().Optional,
+			},
+		}, filepath.Join(model.DirPathConfigMapFilesConfig, filename, address.Name)
+
+	case src.GetFieldRef() != nil:
+		ref := src.GetFieldRef()
+		return &core.VolumeSource{
+			DownwardAPI: &core.DownwardAPIVolumeSource{
+				Items: []core.DownwardAPIVolumeFile{
+					{
+						Path:     ref.FieldPath,
+						FieldRef: ref.DeepCopy(),
+					},
+				},
+				DefaultMode: &defaultMode,
+			},
+		}, filepath.Join(model.DirPathDownwardAPIFilesConfig, filename)
+
+	case src.GetResourceFieldRef() != nil:
+		ref := src.GetResourceFieldRef().DeepCopy()
+		if ref.ContainerName == "" {
+			// Unlike ENV var, volume has to specify container explicitly
+			ref.ContainerName = model.ClickHouseContainerName
+		}
+		return &core.VolumeSource{
+			DownwardAPI: &core.DownwardAPIVolumeSource{
+				Items: []core.DownwardAPIVolumeFile{
+					{
+						Path:             ref.Resource,
+						ResourceFieldRef: ref,
+					},
+				},
+				DefaultMode: &defaultMode,
+			},
+		}, filepath.Join(model.DirPathDownwardAPIFilesConfig, filename)
+	}
+
+	return nil, ""
+}
+
 func (n *Normalizer) appendClusterSecretEnvVar(cluster *api.Cluster) {
 	switch cluster.Secret.Source() {
 	case api.ClusterSecretSourcePlaintext:
@@ -887,6 +1247,9 @@ func (n *Normalizer) normalizeConfigurationUsers(users *api.Settings) *api.Setti
 	// Ensure and normalize user settings
 	users = users.Ensure().Normalize()
 
+	// Users specified by ClickHouseUser resources are normalized along with the explicitly specified ones
+	n.appendClickHouseUsers(users)
+
 	// Add special "default" user to the list of users, which is used/required for:
 	// 1. ClickHouse hosts to communicate with each other
 	// 2. Specify host_regexp for default user as "allowed hosts to visit from"
@@ -896,9 +1259,8 @@ func (n *Normalizer) normalizeConfigurationUsers(users *api.Settings) *api.Setti
 		// user-based settings contains non-explicit users list in it
 		users,
 		// Add default user which always exists
-		defaultUsername,
-		// Add CHOp user
-		chop.Config().ClickHouse.Access.Username,
+		// Add CHOp user(s)
+		append([]string{defaultUsername}, n.operatorUsernames()...)...,
 	)
 
 	// Normalize each user in the list of users
@@ -912,6 +1274,47 @@ func (n *Normalizer) normalizeConfigurationUsers(users *api.Settings) *api.Setti
 	return users
 }
 
+// appendClickHouseUsers appends users specified by ClickHouseUser resources in Config mode to the users settings.
+// Users explicitly specified in the CHI have priority and are not overwritten
+func (n *Normalizer) appendClickHouseUsers(users *api.Settings) {
+	for _, chu := range n.ctx.Options().Users {
+		if chu.GetMode() != api.ClickHouseUserModeConfig {
+			continue
+		}
+		username := chu.GetUsername()
+		if util.InArray(username, users.Groups()) {
+			log.V(1).M(chu).F().Warning("user %s is specified in CHI explicitly, skip ClickHouseUser %s/%s", username, chu.Namespace, chu.Name)
+			continue
+		}
+		// User without grants in users config has all privileges, thus user with invalid grants is skipped entirely
+		queries, err := model.CreateUserGrantQueries(chu)
+		if err != nil {
+			log.V(1).M(chu).F().Warning("invalid grants, skip ClickHouseUser %s/%s err: %v", chu.Namespace, chu.Name, err)
+			continue
+		}
+
+		user := api.NewSettingsUser(users, username)
+		if ref := chu.Spec.PasswordSecretRef; ref != nil {
+			user.Set("k8s_secret_password", api.NewSettingScalar(chu.Namespace+"/"+ref.Name+"/"+ref.Key))
+		}
+		if chu.Spec.Profile != "" {
+			user.Set("profile", api.NewSettingScalar(chu.Spec.Profile))
+		}
+		if chu.Spec.Quota != "" {
+			user.Set("quota", api.NewSettingScalar(chu.Spec.Quota))
+		}
+		if ips := chu.Spec.Networks.GetIP(); len(ips) > 0 {
+			user.Set("networks/ip", api.NewSettingVector(ips))
+		}
+		if hostRegexp := chu.Spec.Networks.GetHostRegexp(); len(hostRegexp) > 0 {
+			user.Set("networks/host_regexp", api.NewSettingVector(hostRegexp))
+		}
+		if len(queries) > 0 {
+			user.Set("grants/query", api.NewSettingVector(queries))
+		}
+	}
+}
+
 func (n *Normalizer) removePlainPassword(user *api.SettingsUser) {
 	// If user has any of encrypted password(s) specified, we need to delete existing plaintext password.
 	// Set `remove` flag for user's plaintext `password`, which is specified as empty in stock ClickHouse users.xml,
@@ -943,6 +1346,31 @@ func (n *Normalizer) normalizeConfigurationUserSecretRef(user *api.SettingsUser)
 	})
 }
 
+// operatorUsernames returns usernames used by CHOp to access ClickHouse instances
+func (n *Normalizer) operatorUsernames() []string {
+	if creds := n.ctx.Options().OperatorCredentials; creds != nil {
+		return creds.Usernames()
+	}
+	return []string{chop.Config().ClickHouse.Access.Username}
+}
+
+// isOperatorUsername checks whether specified username is used by CHOp to access ClickHouse instances
+func (n *Normalizer) isOperatorUsername(username string) bool {
+	if creds := n.ctx.Options().OperatorCredentials; creds != nil {
+		return creds.HasUsername(username)
+	}
+	return username == chop.Config().ClickHouse.Access.Username
+}
+
+// operatorPassword returns password of the specified username used by CHOp to access ClickHouse instances
+func (n *Normalizer) operatorPassword(username string) string {
+	if creds := n.ctx.Options().OperatorCredentials; creds != nil {
+		password, _ := creds.GetPassword(username)
+		return password
+	}
+	return chop.Config().ClickHouse.Access.Password
+}
+
 func (n *Normalizer) normalizeConfigurationUserEnsureMandatoryFields(user *api.SettingsUser) {
 	//
 	// Ensure each user has mandatory fields:
@@ -957,14 +1385,14 @@ func (n *Normalizer) normalizeConfigurationUserEnsureMandatoryFields(user *api.S
 	hostRegexp := model.CreatePodHostnameRegexp(n.ctx.GetTarget(), chop.Config().ClickHouse.Config.Network.HostRegexpTemplate)
 
 	// Some users may have special options for mandatory fields
-	switch user.Username() {
-	case defaultUsername:
+	switch {
+	case user.Username() == defaultUsername:
 		// "default" user
 		ips = append(ips, n.ctx.Options().DefaultUserAdditionalIPs...)
 		if !n.ctx.Options().DefaultUserInsertHostRegex {
 			hostRegexp = ""
 		}
-	case chop.Config().ClickHouse.Access.Username:
+	case n.isOperatorUsername(user.Username()):
 		// User used by CHOp to access ClickHouse instances.
 		ip, _ := chop.Get().ConfigManager.GetRuntimeParam(deployment.OPERATOR_POD_IP)
 
@@ -1053,13 +1481,13 @@ func (n *Normalizer) normalizeConfigurationUserPassword(user *api.SettingsUser)
 	// 2. ClickHouse user gets password from his section of CHOp configuration
 	// 3. All the rest users get default password
 	if passwordPlaintext == "" {
-		switch user.Username() {
-		case defaultUsername:
+		switch {
+		case user.Username() == defaultUsername:
 			// NB "default" user keeps empty password in here.
-		case chop.Config().ClickHouse.Access.Username:
+		case n.isOperatorUsername(user.Username()):
 			// User used by CHOp to access ClickHouse instances.
-			// Gets ClickHouse access password from "ClickHouse.Access.Password"
-			passwordPlaintext = chop.Config().ClickHouse.Access.Password
+			// Gets ClickHouse access password either from generated credentials or from "ClickHouse.Access.Password"
+			passwordPlaintext = n.operatorPassword(user.Username())
 		default:
 			// All the rest users get default password from "ClickHouse.Config.User.Default.Password"
 			passwordPlaintext = chop.Config().ClickHouse.Config.User.Default.Password
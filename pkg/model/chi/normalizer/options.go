@@ -14,6 +14,11 @@
 
 package normalizer
 
+import (
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+)
+
 // Options specifies normalization options
 type Options struct {
 	// WithDefaultCluster specifies whether to insert default cluster in case no cluster specified
@@ -21,6 +26,15 @@ type Options struct {
 	// DefaultUserAdditionalIPs specifies set of additional IPs applied to default user
 	DefaultUserAdditionalIPs   []string
 	DefaultUserInsertHostRegex bool
+	// OperatorCredentials specifies generated operator credentials, which replace credentials from CHOp config
+	OperatorCredentials *model.OperatorCredentials
+	// Users specifies ClickHouseUser resources to be provisioned into users config of the CHI
+	Users []*api.ClickHouseUser
+	// ValidateSettings specifies whether settings and profiles are validated against the catalog of ClickHouse settings
+	ValidateSettings bool
+	// ValidateSpec specifies whether invalid sections of the spec are reported as an error.
+	// Invalid sections are skipped regardless of the option
+	ValidateSpec bool
 }
 
 // NewOptions creates new Options
@@ -0,0 +1,103 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// ErrSettingsValidation is returned by the normalizer in case CHI has settings of mismatching types
+// and settings validation strictness is Error
+var ErrSettingsValidation = errors.New("invalid settings")
+
+// validateSettings validates settings and profiles of the normalized CHI against the catalog of ClickHouse settings.
+// Each host is validated against the catalog of the ClickHouse version of the host.
+// Settings unknown to the catalog are reported only, since the catalog is not exhaustive
+func (n *Normalizer) validateSettings() error {
+	if !n.ctx.Options().ValidateSettings {
+		return nil
+	}
+	strictness := chop.Config().ClickHouse.SettingsValidation.Strictness
+	if strictness == api.SettingsValidationStrictnessNone {
+		return nil
+	}
+
+	invalid, unknown := n.listSettingsIssues()
+
+	chi := n.ctx.GetTarget()
+	for _, issue := range unknown {
+		log.V(1).M(chi).F().Warning("CHI: %s/%s has unknown setting %s", chi.Namespace, chi.Name, issue)
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	if strictness == api.SettingsValidationStrictnessError {
+		return fmt.Errorf("%w: %s", ErrSettingsValidation, strings.Join(invalid, "; "))
+	}
+
+	for _, issue := range invalid {
+		log.V(1).M(chi).F().Warning("CHI: %s/%s has invalid setting %s", chi.Namespace, chi.Name, issue)
+	}
+	return nil
+}
+
+// listSettingsIssues lists unique issues of settings and profiles of all hosts.
+// Issues of known settings are listed as invalid, issues of settings unknown to the catalog are listed separately
+func (n *Normalizer) listSettingsIssues() (invalid, unknown []string) {
+	catalog := model.GetSettingsCatalog()
+	chi := n.ctx.GetTarget()
+	customPrefixes := model.GetCustomSettingsPrefixes(chi.Spec.Configuration.Settings)
+
+	add := func(list []model.SettingsIssue) {
+		for _, issue := range list {
+			issues := &invalid
+			if issue.Unknown {
+				issues = &unknown
+			}
+			if str := issue.String(); !util.InArray(str, *issues) {
+				*issues = append(*issues, str)
+			}
+		}
+	}
+
+	// Global settings are validated once per each ClickHouse version in use
+	var versions []string
+	chi.WalkHosts(func(host *api.ChiHost) error {
+		version := model.GetHostImageVersion(host)
+		add(catalog.ValidateServerSettings(host.Settings, version))
+		if util.InArray(version.String(), versions) {
+			return nil
+		}
+		versions = append(versions, version.String())
+		add(catalog.ValidateServerSettings(chi.Spec.Configuration.Settings, version))
+		add(catalog.ValidateProfiles(chi.Spec.Configuration.Profiles, version, customPrefixes))
+		return nil
+	})
+
+	sort.Strings(invalid)
+	sort.Strings(unknown)
+	return invalid, unknown
+}
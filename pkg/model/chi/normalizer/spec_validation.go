@@ -0,0 +1,47 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package normalizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+)
+
+// ErrSpecValidation is returned by the normalizer in case CHI spec has invalid sections, such as storage disk of unknown type.
+// Invalid sections are skipped by normalization, thus CHI is not expected to be reconciled till they are fixed
+var ErrSpecValidation = errors.New("invalid spec")
+
+// addSpecIssue records invalid section of the spec
+func (n *Normalizer) addSpecIssue(format string, args ...interface{}) {
+	issue := fmt.Sprintf(format, args...)
+	chi := n.ctx.GetTarget()
+	log.V(1).M(chi).F().Warning("CHI: %s/%s has invalid spec: %s", chi.Namespace, chi.Name, issue)
+	n.ctx.AddSpecIssue(issue)
+}
+
+// validateSpec reports invalid sections of the spec found during normalization
+func (n *Normalizer) validateSpec() error {
+	if !n.ctx.Options().ValidateSpec {
+		return nil
+	}
+	issues := n.ctx.GetSpecIssues()
+	if len(issues) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrSpecValidation, strings.Join(issues, "; "))
+}
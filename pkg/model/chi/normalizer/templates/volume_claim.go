@@ -26,6 +26,52 @@ func NormalizeVolumeClaimTemplate(template *api.VolumeClaimTemplate) {
 
 	// Check Spec
 	// Skip for now
+
+	// Autoscaling
+	normalizeAutoscaling(template.Autoscaling)
+
+	// StorageClassMigration
+	normalizeStorageClassMigration(template.StorageClassMigration)
+}
+
+const (
+	// defaultAutoscalingThreshold specifies default disk utilization PVC is expanded at. In percents
+	defaultAutoscalingThreshold = 80
+	// defaultAutoscalingStep specifies default size PVC is expanded by
+	defaultAutoscalingStep = "20%"
+)
+
+// normalizeAutoscaling normalizes Autoscaling
+func normalizeAutoscaling(autoscaling *api.VolumeClaimTemplateAutoscaling) {
+	if autoscaling == nil {
+		return
+	}
+
+	// Check Threshold
+	if (autoscaling.Threshold <= 0) || (autoscaling.Threshold >= 100) {
+		autoscaling.Threshold = defaultAutoscalingThreshold
+	}
+
+	// Check Step
+	if autoscaling.Step == "" {
+		autoscaling.Step = defaultAutoscalingStep
+	}
+}
+
+// defaultStorageClassMigrationVerifyTimeout specifies default time host is waited to catch up with other replicas.
+// In seconds
+const defaultStorageClassMigrationVerifyTimeout = 3600
+
+// normalizeStorageClassMigration normalizes StorageClassMigration
+func normalizeStorageClassMigration(migration *api.VolumeClaimTemplateStorageClassMigration) {
+	if migration == nil {
+		return
+	}
+
+	// Check VerifyTimeout
+	if migration.VerifyTimeout <= 0 {
+		migration.VerifyTimeout = defaultStorageClassMigrationVerifyTimeout
+	}
 }
 
 // normalizeStorageManagement normalizes StorageManagement
@@ -0,0 +1,269 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// AnnotationRotateOperatorPassword specifies CHI annotation used to request rotation of the generated operator password.
+// Each new value of the annotation triggers exactly one rotation
+const AnnotationRotateOperatorPassword = clickhouse_altinity_com.APIGroupName + "/" + "rotate-operator-password"
+
+// Keys of the generated operator credentials Secret
+const (
+	operatorCredentialsKeyUsername         = "username"
+	operatorCredentialsKeyPassword         = "password"
+	operatorCredentialsKeyPreviousUsername = "previousUsername"
+	operatorCredentialsKeyPreviousPassword = "previousPassword"
+	operatorCredentialsKeyPhase            = "phase"
+	operatorCredentialsKeyRotatedAt        = "rotatedAt"
+	operatorCredentialsKeyRotationRequest  = "rotationRequest"
+)
+
+// operatorUsernameAlternateSuffix is appended to the operator username to get an alternate username.
+// ClickHouse user can have one password only, so rotation alternates between two usernames,
+// which allows both old and new passwords to be valid at the same time.
+const operatorUsernameAlternateSuffix = "_alt"
+
+// OperatorCredentialsPhase specifies phase of the operator credentials rotation
+type OperatorCredentialsPhase string
+
+// Possible phases of the operator credentials rotation
+const (
+	// OperatorCredentialsPhaseStable means no rotation is in progress, only current credentials exist in ClickHouse
+	OperatorCredentialsPhaseStable OperatorCredentialsPhase = ""
+	// OperatorCredentialsPhaseAdded means new credentials are added to ClickHouse along with previous ones,
+	// connections still use previous credentials
+	OperatorCredentialsPhaseAdded OperatorCredentialsPhase = "Added"
+	// OperatorCredentialsPhaseSwitched means connections use new credentials,
+	// previous ones are still present in ClickHouse and are to be removed
+	OperatorCredentialsPhaseSwitched OperatorCredentialsPhase = "Switched"
+)
+
+// OperatorCredentials specifies generated credentials of the operator user for one CHI
+type OperatorCredentials struct {
+	Username string
+	Password string
+
+	// Previous credentials are kept in ClickHouse while rotation is in progress
+	PreviousUsername string
+	PreviousPassword string
+
+	Phase           OperatorCredentialsPhase
+	RotatedAt       time.Time
+	RotationRequest string
+}
+
+// NewOperatorCredentials creates new operator credentials with generated password
+func NewOperatorCredentials() *OperatorCredentials {
+	return &OperatorCredentials{
+		Username:  chop.Config().ClickHouse.Access.Username,
+		Password:  generateOperatorPassword(),
+		Phase:     OperatorCredentialsPhaseStable,
+		RotatedAt: time.Now(),
+	}
+}
+
+// NewOperatorCredentialsFromSecret reads operator credentials from the Secret.
+// Returns nil in case Secret has no credentials
+func NewOperatorCredentialsFromSecret(secret *core.Secret) *OperatorCredentials {
+	if secret == nil {
+		return nil
+	}
+	get := func(key string) string {
+		if value, ok := secret.StringData[key]; ok {
+			return value
+		}
+		return string(secret.Data[key])
+	}
+
+	creds := &OperatorCredentials{
+		Username:         get(operatorCredentialsKeyUsername),
+		Password:         get(operatorCredentialsKeyPassword),
+		PreviousUsername: get(operatorCredentialsKeyPreviousUsername),
+		PreviousPassword: get(operatorCredentialsKeyPreviousPassword),
+		Phase:            OperatorCredentialsPhase(get(operatorCredentialsKeyPhase)),
+		RotationRequest:  get(operatorCredentialsKeyRotationRequest),
+	}
+	if (creds.Username == "") || (creds.Password == "") {
+		return nil
+	}
+	if rotatedAt, err := time.Parse(time.RFC3339, get(operatorCredentialsKeyRotatedAt)); err == nil {
+		creds.RotatedAt = rotatedAt
+	}
+	if creds.PreviousUsername == "" {
+		// Nothing to rotate from
+		creds.Phase = OperatorCredentialsPhaseStable
+	}
+
+	return creds
+}
+
+// GetOperatorCredentials gets generated operator credentials of the CHI with the specified Secret getter.
+// Returns nil in case password generation is disabled or credentials are not generated yet.
+// Any other failure is returned as an error, since falling back to credentials from CHOp config
+// would render users config without generated users and would lock the operator out of ClickHouse
+func GetOperatorCredentials(chi *api.ClickHouseInstallation, get func(namespace, name string) (*core.Secret, error)) (*OperatorCredentials, error) {
+	if (chi == nil) || !chop.Config().ClickHouse.Access.Generate.Enabled {
+		return nil, nil
+	}
+	secret, err := get(chi.Namespace, CreateOperatorCredentialsSecretName(chi))
+	switch {
+	case apiErrors.IsNotFound(err):
+		// Not generated yet
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return NewOperatorCredentialsFromSecret(secret), nil
+}
+
+// StringData returns credentials in the form to be kept in a Secret
+func (c *OperatorCredentials) StringData() map[string]string {
+	if c == nil {
+		return nil
+	}
+	return map[string]string{
+		operatorCredentialsKeyUsername:         c.Username,
+		operatorCredentialsKeyPassword:         c.Password,
+		operatorCredentialsKeyPreviousUsername: c.PreviousUsername,
+		operatorCredentialsKeyPreviousPassword: c.PreviousPassword,
+		operatorCredentialsKeyPhase:            string(c.Phase),
+		operatorCredentialsKeyRotatedAt:        c.RotatedAt.Format(time.RFC3339),
+		operatorCredentialsKeyRotationRequest:  c.RotationRequest,
+	}
+}
+
+// Usernames returns all usernames which has to be present in ClickHouse
+func (c *OperatorCredentials) Usernames() []string {
+	if c == nil {
+		return nil
+	}
+	if c.IsRotationInProgress() {
+		return []string{c.Username, c.PreviousUsername}
+	}
+	return []string{c.Username}
+}
+
+// HasUsername checks whether specified username is one of operator's usernames
+func (c *OperatorCredentials) HasUsername(username string) bool {
+	_, ok := c.GetPassword(username)
+	return ok
+}
+
+// GetPassword gets password of the specified username
+func (c *OperatorCredentials) GetPassword(username string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	switch {
+	case username == c.Username:
+		return c.Password, true
+	case c.IsRotationInProgress() && (username == c.PreviousUsername):
+		return c.PreviousPassword, true
+	}
+	return "", false
+}
+
+// GetConnectionCredentials gets username and password to be used to connect to ClickHouse
+func (c *OperatorCredentials) GetConnectionCredentials() (username, password string) {
+	if c == nil {
+		return "", ""
+	}
+	if c.Phase == OperatorCredentialsPhaseAdded {
+		// New credentials may not be propagated to ClickHouse yet
+		return c.PreviousUsername, c.PreviousPassword
+	}
+	return c.Username, c.Password
+}
+
+// IsRotationInProgress checks whether rotation is in progress
+func (c *OperatorCredentials) IsRotationInProgress() bool {
+	if c == nil {
+		return false
+	}
+	return c.Phase != OperatorCredentialsPhaseStable
+}
+
+// IsRotationRequested checks whether rotation is requested either by schedule or by the request value
+func (c *OperatorCredentials) IsRotationRequested(period time.Duration, request string) bool {
+	if c == nil {
+		return false
+	}
+	if (request != "") && (request != c.RotationRequest) {
+		// New request
+		return true
+	}
+	if (period > 0) && (time.Since(c.RotatedAt) > period) {
+		// Rotation by schedule
+		return true
+	}
+	return false
+}
+
+// StartRotation generates new credentials and keeps current ones as previous
+func (c *OperatorCredentials) StartRotation(request string) {
+	if c == nil {
+		return
+	}
+	c.PreviousUsername = c.Username
+	c.PreviousPassword = c.Password
+	c.Username = alternateOperatorUsername(c.Username)
+	c.Password = generateOperatorPassword()
+	c.Phase = OperatorCredentialsPhaseAdded
+	c.RotatedAt = time.Now()
+	c.RotationRequest = request
+}
+
+// SwitchRotation switches connections to new credentials
+func (c *OperatorCredentials) SwitchRotation() {
+	if c == nil {
+		return
+	}
+	c.Phase = OperatorCredentialsPhaseSwitched
+}
+
+// CompleteRotation drops previous credentials
+func (c *OperatorCredentials) CompleteRotation() {
+	if c == nil {
+		return
+	}
+	c.PreviousUsername = ""
+	c.PreviousPassword = ""
+	c.Phase = OperatorCredentialsPhaseStable
+}
+
+// alternateOperatorUsername returns username to be used for the next credentials
+func alternateOperatorUsername(username string) string {
+	base := chop.Config().ClickHouse.Access.Username
+	if username == base {
+		return base + operatorUsernameAlternateSuffix
+	}
+	return base
+}
+
+// generateOperatorPassword generates new operator password
+func generateOperatorPassword() string {
+	return util.RandStringSecure(chop.Config().ClickHouse.Access.Generate.Length)
+}
@@ -0,0 +1,259 @@
+package chi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/chop"
+)
+
+func TestNewOperatorCredentialsFromSecret(t *testing.T) {
+	rotatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		secret *core.Secret
+		creds  *OperatorCredentials
+	}{
+		{
+			name: "no secret",
+		},
+		{
+			name:   "empty secret",
+			secret: &core.Secret{},
+		},
+		{
+			name: "no password",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username": []byte("operator"),
+				},
+			},
+		},
+		{
+			name: "stable credentials",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username":        []byte("operator"),
+					"password":        []byte("secret"),
+					"rotatedAt":       []byte(rotatedAt.Format(time.RFC3339)),
+					"rotationRequest": []byte("1"),
+				},
+			},
+			creds: &OperatorCredentials{
+				Username:        "operator",
+				Password:        "secret",
+				RotatedAt:       rotatedAt,
+				RotationRequest: "1",
+			},
+		},
+		{
+			name: "rotation in progress",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username":         []byte("operator_alt"),
+					"password":         []byte("new"),
+					"previousUsername": []byte("operator"),
+					"previousPassword": []byte("old"),
+					"phase":            []byte("Added"),
+					"rotatedAt":        []byte(rotatedAt.Format(time.RFC3339)),
+				},
+			},
+			creds: &OperatorCredentials{
+				Username:         "operator_alt",
+				Password:         "new",
+				PreviousUsername: "operator",
+				PreviousPassword: "old",
+				Phase:            OperatorCredentialsPhaseAdded,
+				RotatedAt:        rotatedAt,
+			},
+		},
+		{
+			name: "string data has priority over data",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username": []byte("operator"),
+					"password": []byte("old"),
+				},
+				StringData: map[string]string{
+					"password": "new",
+				},
+			},
+			creds: &OperatorCredentials{
+				Username: "operator",
+				Password: "new",
+			},
+		},
+		{
+			name: "phase without previous credentials",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username": []byte("operator"),
+					"password": []byte("secret"),
+					"phase":    []byte("Switched"),
+				},
+			},
+			creds: &OperatorCredentials{
+				Username: "operator",
+				Password: "secret",
+				Phase:    OperatorCredentialsPhaseStable,
+			},
+		},
+		{
+			name: "malformed rotation time",
+			secret: &core.Secret{
+				Data: map[string][]byte{
+					"username":  []byte("operator"),
+					"password":  []byte("secret"),
+					"rotatedAt": []byte("yesterday"),
+				},
+			},
+			creds: &OperatorCredentials{
+				Username: "operator",
+				Password: "secret",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.creds, NewOperatorCredentialsFromSecret(tt.secret))
+		})
+	}
+}
+
+// reloadTestOperatorCredentials writes credentials into a Secret and reads them back, the way interrupted rotation is resumed
+func reloadTestOperatorCredentials(t *testing.T, creds *OperatorCredentials) *OperatorCredentials {
+	reloaded := NewOperatorCredentialsFromSecret(&core.Secret{StringData: creds.StringData()})
+	require.NotNil(t, reloaded)
+	require.Equal(t, creds.StringData(), reloaded.StringData())
+	return reloaded
+}
+
+func TestOperatorCredentialsRotation(t *testing.T) {
+	initTestCHOp()
+	base := chop.Config().ClickHouse.Access.Username
+	alt := base + operatorUsernameAlternateSuffix
+
+	creds := NewOperatorCredentials()
+	require.Equal(t, base, creds.Username)
+	require.NotEmpty(t, creds.Password)
+
+	// Rotation alternates between the base and the alternate usernames
+	for _, tt := range []struct {
+		from string
+		to   string
+	}{
+		{from: base, to: alt},
+		{from: alt, to: base},
+	} {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			password := creds.Password
+
+			// Stable: current credentials only
+			require.Equal(t, OperatorCredentialsPhaseStable, creds.Phase)
+			require.False(t, creds.IsRotationInProgress())
+			require.Equal(t, []string{tt.from}, creds.Usernames())
+			username, connPassword := creds.GetConnectionCredentials()
+			require.Equal(t, tt.from, username)
+			require.Equal(t, password, connPassword)
+
+			// Added: new credentials are added, connections still use previous ones
+			creds.StartRotation("request-" + tt.to)
+			creds = reloadTestOperatorCredentials(t, creds)
+			require.Equal(t, OperatorCredentialsPhaseAdded, creds.Phase)
+			require.True(t, creds.IsRotationInProgress())
+			require.Equal(t, tt.to, creds.Username)
+			require.NotEqual(t, password, creds.Password)
+			require.Equal(t, tt.from, creds.PreviousUsername)
+			require.Equal(t, password, creds.PreviousPassword)
+			require.Equal(t, []string{tt.to, tt.from}, creds.Usernames())
+			require.True(t, creds.HasUsername(tt.from))
+			require.True(t, creds.HasUsername(tt.to))
+			username, connPassword = creds.GetConnectionCredentials()
+			require.Equal(t, tt.from, username)
+			require.Equal(t, password, connPassword)
+
+			// Switched: connections use new credentials, previous ones are still accepted
+			creds.SwitchRotation()
+			creds = reloadTestOperatorCredentials(t, creds)
+			require.Equal(t, OperatorCredentialsPhaseSwitched, creds.Phase)
+			require.Equal(t, []string{tt.to, tt.from}, creds.Usernames())
+			username, connPassword = creds.GetConnectionCredentials()
+			require.Equal(t, tt.to, username)
+			require.Equal(t, creds.Password, connPassword)
+
+			// Completed: previous credentials are dropped
+			creds.CompleteRotation()
+			creds = reloadTestOperatorCredentials(t, creds)
+			require.Equal(t, OperatorCredentialsPhaseStable, creds.Phase)
+			require.Equal(t, []string{tt.to}, creds.Usernames())
+			require.False(t, creds.HasUsername(tt.from))
+			require.Empty(t, creds.PreviousUsername)
+			require.Empty(t, creds.PreviousPassword)
+			require.Equal(t, "request-"+tt.to, creds.RotationRequest)
+		})
+	}
+}
+
+func TestOperatorCredentialsIsRotationRequested(t *testing.T) {
+	tests := []struct {
+		name      string
+		rotatedAt time.Time
+		handled   string
+		period    time.Duration
+		request   string
+		requested bool
+	}{
+		{
+			name:      "nothing requested",
+			rotatedAt: time.Now(),
+		},
+		{
+			name:      "new request",
+			rotatedAt: time.Now(),
+			handled:   "1",
+			request:   "2",
+			requested: true,
+		},
+		{
+			name:      "handled request",
+			rotatedAt: time.Now(),
+			handled:   "1",
+			request:   "1",
+		},
+		{
+			name:      "period elapsed",
+			rotatedAt: time.Now().Add(-2 * time.Hour),
+			period:    time.Hour,
+			requested: true,
+		},
+		{
+			name:      "period not elapsed",
+			rotatedAt: time.Now().Add(-time.Minute),
+			period:    time.Hour,
+		},
+		{
+			name:      "no period",
+			rotatedAt: time.Now().Add(-2 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := &OperatorCredentials{
+				Username:        "operator",
+				Password:        "secret",
+				RotatedAt:       tt.rotatedAt,
+				RotationRequest: tt.handled,
+			}
+			require.Equal(t, tt.requested, creds.IsRotationRequested(tt.period, tt.request))
+		})
+	}
+
+	var nilCreds *OperatorCredentials
+	require.False(t, nilCreds.IsRotationRequested(time.Hour, "1"))
+}
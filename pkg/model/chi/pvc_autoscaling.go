@@ -0,0 +1,135 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// DiskUsage specifies usage of the disk as it is seen by ClickHouse
+type DiskUsage struct {
+	Name       string
+	Path       string
+	FreeSpace  uint64
+	TotalSpace uint64
+}
+
+// pvcExpansionAlignment specifies alignment of expanded PVC size
+const pvcExpansionAlignment = 1024 * 1024
+
+// HasPVCAutoscaling checks whether CHI has any VolumeClaimTemplate with autoscaling enabled
+func HasPVCAutoscaling(chi *api.ClickHouseInstallation) bool {
+	if (chi == nil) || (chi.Spec.Templates == nil) {
+		return false
+	}
+	for i := range chi.Spec.Templates.VolumeClaimTemplates {
+		if chi.Spec.Templates.VolumeClaimTemplates[i].Autoscaling.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMountsUtilization calculates utilization, in percents, of the filesystems mounted at specified paths.
+// Each disk is attributed to the mount with the longest path the disk's path starts with.
+// Mounts, which have no disks on them, are not included into the result
+func GetMountsUtilization(disks []*DiskUsage, mountPaths []string) map[string]int {
+	utilization := make(map[string]int)
+	for _, disk := range disks {
+		if disk.TotalSpace == 0 {
+			continue
+		}
+		mount := ""
+		for _, mountPath := range mountPaths {
+			if isPathWithin(disk.Path, mountPath) && (len(mountPath) > len(mount)) {
+				mount = mountPath
+			}
+		}
+		if mount == "" {
+			continue
+		}
+		used := int((disk.TotalSpace - disk.FreeSpace) * 100 / disk.TotalSpace)
+		if used > utilization[mount] {
+			utilization[mount] = used
+		}
+	}
+	return utilization
+}
+
+// isPathWithin checks whether path is the dir or is located within the dir
+func isPathWithin(path, dir string) bool {
+	path = filepath.Clean(path)
+	dir = filepath.Clean(dir)
+	return (path == dir) || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// ComputePVCExpansion computes size PVC has to be expanded to according to the autoscaling policy.
+// Returns false in case PVC is not to be expanded. Error is returned in case PVC has to be expanded, but it can not be
+func ComputePVCExpansion(
+	autoscaling *api.VolumeClaimTemplateAutoscaling,
+	utilization int,
+	current resource.Quantity,
+) (resource.Quantity, bool, error) {
+	if !autoscaling.IsEnabled() || (utilization < autoscaling.Threshold) {
+		return resource.Quantity{}, false, nil
+	}
+
+	step, err := parsePVCExpansionStep(autoscaling.Step, current)
+	if err != nil {
+		return resource.Quantity{}, false, err
+	}
+	size := current.Value() + step
+	// Align to make size human-readable
+	size = (size + pvcExpansionAlignment - 1) / pvcExpansionAlignment * pvcExpansionAlignment
+
+	if autoscaling.MaxSize != "" {
+		maxSize, err := resource.ParseQuantity(autoscaling.MaxSize)
+		if err != nil {
+			return resource.Quantity{}, false, fmt.Errorf("unable to parse max size %s err: %v", autoscaling.MaxSize, err)
+		}
+		if current.Cmp(maxSize) >= 0 {
+			return resource.Quantity{}, false, fmt.Errorf("max size %s reached", autoscaling.MaxSize)
+		}
+		if size > maxSize.Value() {
+			size = maxSize.Value()
+		}
+	}
+
+	return *resource.NewQuantity(size, resource.BinarySI), true, nil
+}
+
+// parsePVCExpansionStep parses step into bytes. Step is either a quantity or a percentage of the current size
+func parsePVCExpansionStep(step string, current resource.Quantity) (int64, error) {
+	if percents, ok := strings.CutSuffix(step, "%"); ok {
+		p, err := strconv.Atoi(strings.TrimSpace(percents))
+		if (err != nil) || (p <= 0) {
+			return 0, fmt.Errorf("unable to parse step %s", step)
+		}
+		return current.Value() * int64(p) / 100, nil
+	}
+
+	q, err := resource.ParseQuantity(step)
+	if (err != nil) || (q.Sign() <= 0) {
+		return 0, fmt.Errorf("unable to parse step %s", step)
+	}
+	return q.Value(), nil
+}
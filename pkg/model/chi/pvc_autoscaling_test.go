@@ -0,0 +1,211 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestComputePVCExpansion(t *testing.T) {
+	const gi = 1024 * 1024 * 1024
+	const mi = 1024 * 1024
+
+	tests := []struct {
+		name        string
+		autoscaling *api.VolumeClaimTemplateAutoscaling
+		utilization int
+		current     string
+		expected    int64
+		expand      bool
+		err         bool
+	}{
+		{
+			name:        "disabled",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(false), Threshold: 80, Step: "10Gi"},
+			utilization: 99,
+			current:     "100Gi",
+		},
+		{
+			name:        "not specified",
+			utilization: 99,
+			current:     "100Gi",
+		},
+		{
+			name:        "below threshold",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10Gi"},
+			utilization: 79,
+			current:     "100Gi",
+		},
+		{
+			name:        "at threshold",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10Gi"},
+			utilization: 80,
+			current:     "100Gi",
+			expected:    110 * gi,
+			expand:      true,
+		},
+		{
+			name:        "percentage step",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "20%"},
+			utilization: 90,
+			current:     "100Gi",
+			expected:    120 * gi,
+			expand:      true,
+		},
+		{
+			name:        "percentage step is rounded up to MiB",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10%"},
+			utilization: 90,
+			current:     "1G",
+			// 1G + 100M = 1100000000 bytes, rounded up to 1050 MiB
+			expected: 1050 * mi,
+			expand:   true,
+		},
+		{
+			name:        "quantity step is rounded up to MiB",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "1k"},
+			utilization: 90,
+			current:     "1Gi",
+			expected:    gi + mi,
+			expand:      true,
+		},
+		{
+			name:        "capped by max size",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "50Gi", MaxSize: "120Gi"},
+			utilization: 90,
+			current:     "100Gi",
+			expected:    120 * gi,
+			expand:      true,
+		},
+		{
+			name:        "below max size",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10Gi", MaxSize: "1Ti"},
+			utilization: 90,
+			current:     "100Gi",
+			expected:    110 * gi,
+			expand:      true,
+		},
+		{
+			name:        "max size reached",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10Gi", MaxSize: "100Gi"},
+			utilization: 90,
+			current:     "100Gi",
+			err:         true,
+		},
+		{
+			name:        "invalid max size",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "10Gi", MaxSize: "lots"},
+			utilization: 90,
+			current:     "100Gi",
+			err:         true,
+		},
+		{
+			name:        "invalid step",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "some"},
+			utilization: 90,
+			current:     "100Gi",
+			err:         true,
+		},
+		{
+			name:        "zero percentage step",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "0%"},
+			utilization: 90,
+			current:     "100Gi",
+			err:         true,
+		},
+		{
+			name:        "negative step",
+			autoscaling: &api.VolumeClaimTemplateAutoscaling{Enabled: api.NewStringBool(true), Threshold: 80, Step: "-10Gi"},
+			utilization: 90,
+			current:     "100Gi",
+			err:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, expand, err := ComputePVCExpansion(tt.autoscaling, tt.utilization, resource.MustParse(tt.current))
+			if tt.err {
+				require.Error(t, err)
+				require.False(t, expand)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expand, expand)
+			if tt.expand {
+				require.Equal(t, tt.expected, size.Value())
+			}
+		})
+	}
+}
+
+func TestGetMountsUtilization(t *testing.T) {
+	tests := []struct {
+		name     string
+		disks    []*DiskUsage
+		mounts   []string
+		expected map[string]int
+	}{
+		{
+			name: "disk at mount path",
+			disks: []*DiskUsage{
+				{Name: "default", Path: "/var/lib/clickhouse/", FreeSpace: 25, TotalSpace: 100},
+			},
+			mounts:   []string{"/var/lib/clickhouse"},
+			expected: map[string]int{"/var/lib/clickhouse": 75},
+		},
+		{
+			name: "utilization is rounded down",
+			disks: []*DiskUsage{
+				{Name: "default", Path: "/var/lib/clickhouse/", FreeSpace: 1, TotalSpace: 3},
+			},
+			mounts:   []string{"/var/lib/clickhouse"},
+			expected: map[string]int{"/var/lib/clickhouse": 66},
+		},
+		{
+			name: "disk is attributed to the longest mount path",
+			disks: []*DiskUsage{
+				{Name: "default", Path: "/var/lib/clickhouse/", FreeSpace: 50, TotalSpace: 100},
+				{Name: "cold", Path: "/var/lib/clickhouse/disks/cold/", FreeSpace: 10, TotalSpace: 100},
+			},
+			mounts: []string{"/var/lib/clickhouse", "/var/lib/clickhouse/disks/cold"},
+			expected: map[string]int{
+				"/var/lib/clickhouse":            50,
+				"/var/lib/clickhouse/disks/cold": 90,
+			},
+		},
+		{
+			name: "mount has the highest utilization of its disks",
+			disks: []*DiskUsage{
+				{Name: "a", Path: "/data/a/", FreeSpace: 80, TotalSpace: 100},
+				{Name: "b", Path: "/data/b/", FreeSpace: 5, TotalSpace: 100},
+			},
+			mounts:   []string{"/data/"},
+			expected: map[string]int{"/data/": 95},
+		},
+		{
+			name: "path prefix is not a parent dir",
+			disks: []*DiskUsage{
+				{Name: "default", Path: "/var/lib/clickhouse2/", FreeSpace: 0, TotalSpace: 100},
+			},
+			mounts:   []string{"/var/lib/clickhouse"},
+			expected: map[string]int{},
+		},
+		{
+			name: "disks without total space and unmounted disks are skipped",
+			disks: []*DiskUsage{
+				{Name: "s3", Path: "/var/lib/clickhouse/disks/s3/", FreeSpace: 0, TotalSpace: 0},
+				{Name: "other", Path: "/mnt/other/", FreeSpace: 0, TotalSpace: 100},
+			},
+			mounts:   []string{"/var/lib/clickhouse"},
+			expected: map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, GetMountsUtilization(tt.disks, tt.mounts))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/yaml"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// RevisionConfigMapKey specifies key of the revision ConfigMap, spec of the revision is persisted under
+const RevisionConfigMapKey = "spec.yaml"
+
+// NewRevisionSpec builds YAML of the spec to be persisted as a revision out of the spec supplied by the user.
+// Spec is persisted as is, before normalization, thus neither defaults nor runtime data, such as IPs of pods, end up in the revision.
+// Task ID and rollback request are not persisted, thus spec re-applied on rollback is reconciled as a new task.
+// Returns YAML along with its hash
+func NewRevisionSpec(spec *api.ChiSpec) (yamlSpec string, hash string, err error) {
+	if spec == nil {
+		return "", "", fmt.Errorf("no spec to build revision of")
+	}
+	s := spec.DeepCopy()
+	s.TaskID = nil
+	s.RollbackTo = nil
+
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return "", "", err
+	}
+	return string(b), util.HashIntoString(b), nil
+}
+
+// ParseRevisionSpec parses spec persisted in the revision ConfigMap
+func ParseRevisionSpec(configMap *core.ConfigMap) (*api.ChiSpec, error) {
+	data, ok := configMap.Data[RevisionConfigMapKey]
+	if !ok || (data == "") {
+		return nil, fmt.Errorf("no spec found in ConfigMap %s/%s", configMap.Namespace, configMap.Name)
+	}
+	spec := &api.ChiSpec{}
+	if err := yaml.Unmarshal([]byte(data), spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
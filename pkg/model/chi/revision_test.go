@@ -0,0 +1,81 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func newTestRevisionSpec() *api.ChiSpec {
+	return &api.ChiSpec{
+		Configuration: &api.Configuration{
+			Clusters: []*api.Cluster{
+				{
+					Name: "cluster",
+					Layout: &api.ChiClusterLayout{
+						ShardsCount:   2,
+						ReplicasCount: 2,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewRevisionSpecHash(t *testing.T) {
+	_, hash, err := NewRevisionSpec(newTestRevisionSpec())
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+
+	// Task ID and rollback request do not make a new revision
+	spec := newTestRevisionSpec()
+	taskID := "task"
+	rollbackTo := 1
+	spec.TaskID = &taskID
+	spec.RollbackTo = &rollbackTo
+	yamlSpec, same, err := NewRevisionSpec(spec)
+	require.NoError(t, err)
+	require.Equal(t, hash, same)
+	require.NotContains(t, yamlSpec, "taskID")
+	require.NotContains(t, yamlSpec, "rollbackTo")
+	// Spec supplied is not modified
+	require.Equal(t, "task", *spec.TaskID)
+	require.Equal(t, 1, *spec.RollbackTo)
+
+	// Changes of the spec make a new revision
+	spec = newTestRevisionSpec()
+	spec.Stop = api.NewStringBool(true)
+	_, changed, err := NewRevisionSpec(spec)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, changed)
+
+	_, _, err = NewRevisionSpec(nil)
+	require.Error(t, err)
+}
+
+func TestRevisionSpecRoundTrip(t *testing.T) {
+	spec := newTestRevisionSpec()
+	spec.Stop = api.NewStringBool(true)
+	yamlSpec, hash, err := NewRevisionSpec(spec)
+	require.NoError(t, err)
+
+	configMap := &core.ConfigMap{
+		Data: map[string]string{
+			RevisionConfigMapKey: yamlSpec,
+		},
+	}
+	restored, err := ParseRevisionSpec(configMap)
+	require.NoError(t, err)
+	require.Equal(t, spec, restored)
+
+	// Spec restored on rollback matches the revision it is restored from
+	_, restoredHash, err := NewRevisionSpec(restored)
+	require.NoError(t, err)
+	require.Equal(t, hash, restoredHash)
+
+	_, err = ParseRevisionSpec(&core.ConfigMap{})
+	require.Error(t, err)
+}
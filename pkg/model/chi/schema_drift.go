@@ -0,0 +1,100 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// SchemaTable specifies table as it is seen on the host
+type SchemaTable struct {
+	// Host is the replica name of the host, as specified by the {replica} macro
+	Host string
+	// Table is the full table name in the 'database.table' form
+	Table string
+	// Query is the create table query
+	Query string
+}
+
+var (
+	createTableQueryUUID        = regexp.MustCompile(`(?i)\s+(INNER\s+)?UUID\s+'[^']*'`)
+	createTableQueryIfNotExists = regexp.MustCompile(`(?i)\s+IF\s+NOT\s+EXISTS`)
+	createTableQuerySpaces      = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeCreateTableQuery normalizes create table query, so queries of the same table on different hosts are comparable.
+// UUIDs are host-specific (unless explicitly specified) and 'IF NOT EXISTS' depends on how table was created,
+// thus both are excluded
+func NormalizeCreateTableQuery(query string) string {
+	query = createTableQueryUUID.ReplaceAllString(query, "")
+	query = createTableQueryIfNotExists.ReplaceAllString(query, "")
+	query = createTableQuerySpaces.ReplaceAllString(query, " ")
+	return strings.TrimSpace(query)
+}
+
+// DetectSchemaDrift compares tables of all hosts of the cluster.
+// Table is considered to be missing on the host, in case it exists on any other host of the cluster.
+// Table is considered to be divergent on the host, in case its schema differs from the schema most hosts have
+func DetectSchemaDrift(cluster string, hosts []string, tables []*SchemaTable) (drift []api.ChiSchemaDriftTable) {
+	// table name -> host -> normalized query
+	queries := make(map[string]map[string]string)
+	for _, table := range tables {
+		if _, ok := queries[table.Table]; !ok {
+			queries[table.Table] = make(map[string]string)
+		}
+		queries[table.Table][table.Host] = NormalizeCreateTableQuery(table.Query)
+	}
+
+	for table, hostQueries := range queries {
+		reference := mostCommonQuery(hostQueries)
+		for _, host := range hosts {
+			query, ok := hostQueries[host]
+			switch {
+			case !ok:
+				drift = append(drift, api.ChiSchemaDriftTable{Cluster: cluster, Host: host, Table: table, Kind: api.SchemaDriftMissing})
+			case query != reference:
+				drift = append(drift, api.ChiSchemaDriftTable{Cluster: cluster, Host: host, Table: table, Kind: api.SchemaDriftDivergent})
+			}
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Table != drift[j].Table {
+			return drift[i].Table < drift[j].Table
+		}
+		return drift[i].Host < drift[j].Host
+	})
+	return drift
+}
+
+// mostCommonQuery finds query most hosts have. Ties are resolved in favor of the lexicographically smaller query
+func mostCommonQuery(hostQueries map[string]string) (reference string) {
+	counts := make(map[string]int)
+	for _, query := range hostQueries {
+		counts[query]++
+	}
+	max := 0
+	for query, count := range counts {
+		if (count > max) || ((count == max) && (query < reference)) {
+			reference = query
+			max = count
+		}
+	}
+	return reference
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+)
+
+// HostDisks fetches usage of local disks of the host
+func (s *ClusterSchemer) HostDisks(ctx context.Context, host *api.ChiHost) (disks []*model.DiskUsage, err error) {
+	query, err := s.QueryHost(ctx, host, s.sqlDisks())
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	var names, paths, freeSpaces, totalSpaces []string
+	if err := query.UnzipColumnsAsStrings(&names, &paths, &freeSpaces, &totalSpaces); err != nil {
+		return nil, err
+	}
+	for i := range names {
+		freeSpace, _ := strconv.ParseUint(freeSpaces[i], 10, 64)
+		totalSpace, _ := strconv.ParseUint(totalSpaces[i], 10, 64)
+		disks = append(disks, &model.DiskUsage{
+			Name:       names[i],
+			Path:       paths[i],
+			FreeSpace:  freeSpace,
+			TotalSpace: totalSpace,
+		})
+	}
+	return disks, nil
+}
+
+func (s *ClusterSchemer) sqlDisks() string {
+	return heredoc.Doc(`
+		SELECT
+			name,
+			path,
+			toString(free_space),
+			toString(total_space)
+		FROM
+			system.disks
+		WHERE
+			lower(type) = 'local'
+		`,
+	)
+}
@@ -25,7 +25,8 @@ import (
 
 // shouldCreateDistributedObjects determines whether distributed objects should be created
 func shouldCreateDistributedObjects(host *api.ChiHost) bool {
-	hosts := model.CreateFQDNs(host, api.Cluster{}, false)
+	// External hosts are reachable via cluster as well, thus they can be used as a source of the schema
+	hosts := append(model.CreateFQDNs(host, api.Cluster{}, false), model.CreateExternalFQDNs(host, api.Cluster{})...)
 
 	if host.GetCluster().SchemaPolicy.Shard == model.SchemaPolicyShardNone {
 		log.V(1).M(host).F().Info("SchemaPolicy.Shard says there is no need to distribute objects")
@@ -44,12 +45,12 @@ func shouldCreateDistributedObjects(host *api.ChiHost) bool {
 // That includes all distributed tables, corresponding local tables and databases, if necessary
 func (s *ClusterSchemer) getDistributedObjectsSQLs(ctx context.Context, host *api.ChiHost) ([]string, []string, error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("ctx is done")
+		log.Ctx(ctx).V(2).Info("ctx is done")
 		return nil, nil, nil
 	}
 
 	if !shouldCreateDistributedObjects(host) {
-		log.V(1).M(host).F().Info("Should not create distributed objects")
+		log.Ctx(ctx).V(1).M(host).F().Info("Should not create distributed objects")
 		return nil, nil, nil
 	}
 
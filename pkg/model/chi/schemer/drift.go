@@ -0,0 +1,92 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// HostClusterTables fetches tables of all hosts of the cluster the host belongs to.
+// Hosts, which are not available at the moment, are not included into the result
+func (s *ClusterSchemer) HostClusterTables(ctx context.Context, host *api.ChiHost) (hosts []string, tables []*model.SchemaTable, err error) {
+	cluster := host.Runtime.Address.ClusterName
+
+	query, err := s.QueryHost(ctx, host, s.sqlClusterHosts(cluster))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer query.Close()
+	if err := query.UnzipColumnsAsStrings(&hosts); err != nil {
+		return nil, nil, err
+	}
+
+	query, err = s.QueryHost(ctx, host, s.sqlClusterTables(cluster))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer query.Close()
+	var replicas, names, queries []string
+	if err := query.UnzipColumnsAsStrings(&replicas, &names, &queries); err != nil {
+		return nil, nil, err
+	}
+	for i := range names {
+		tables = append(tables, &model.SchemaTable{
+			Host:  replicas[i],
+			Table: names[i],
+			Query: queries[i],
+		})
+	}
+
+	return hosts, tables, nil
+}
+
+func (s *ClusterSchemer) sqlClusterHosts(cluster string) string {
+	return heredoc.Docf(`
+		SELECT
+			DISTINCT getMacro('replica')
+		FROM
+			clusterAllReplicas(%s, system.one)
+		SETTINGS skip_unavailable_shards=1
+		`,
+		util.QuoteSQLString(cluster),
+	)
+}
+
+func (s *ClusterSchemer) sqlClusterTables(cluster string) string {
+	return heredoc.Docf(`
+		SELECT
+			getMacro('replica'),
+			concat(database, '.', name),
+			create_table_query
+		FROM
+			clusterAllReplicas(%s, system.tables)
+		WHERE
+			database NOT IN (%s) AND
+			NOT is_temporary AND
+			create_table_query != '' AND
+			name NOT LIKE '.inner.%%' AND
+			name NOT LIKE '.inner_id.%%'
+		SETTINGS skip_unavailable_shards=1
+		`,
+		util.QuoteSQLString(cluster),
+		ignoredDBs,
+	)
+}
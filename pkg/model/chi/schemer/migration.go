@@ -0,0 +1,200 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	model "github.com/minorhacks/clickhouse-operator/pkg/model/chi"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/clickhouse"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// MigrationRecord specifies migration recorded in the history table
+type MigrationRecord struct {
+	Version   string
+	Checksum  string
+	AppliedAt string
+	// Statements specifies how many statements of the migration are applied
+	Statements int
+	// Completed specifies whether all statements of the migration are applied
+	Completed bool
+}
+
+// HostMigrationsEnsureHistoryTable creates table, which keeps history of applied migrations, on all hosts of the cluster.
+// Replicated table is used in case cluster has ZooKeeper, thus history is available on any host of the cluster
+func (s *ClusterSchemer) HostMigrationsEnsureHistoryTable(ctx context.Context, host *api.ChiHost, table string) error {
+	cluster := host.GetCluster()
+	sqls := []string{
+		s.sqlCreateMigrationsHistoryDatabase(table, cluster.Name),
+		s.sqlCreateMigrationsHistoryTable(table, cluster.Name, !cluster.Zookeeper.IsEmpty()),
+		// Table may be created by previous version of the operator, which did not record progress of the migration
+		s.sqlAlterMigrationsHistoryTable(table, cluster.Name),
+	}
+	return s.ExecHost(ctx, host, sqls, clickhouse.NewQueryOptions().SetRetry(false))
+}
+
+// HostMigrationsHistory fetches migrations recorded in the history table
+func (s *ClusterSchemer) HostMigrationsHistory(ctx context.Context, host *api.ChiHost, table string) (map[string]*MigrationRecord, error) {
+	query, err := s.QueryHost(ctx, host, s.sqlSelectMigrationsHistory(table))
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	var versions, checksums, appliedAts, statements, completeds []string
+	if err := query.UnzipColumnsAsStrings(&versions, &checksums, &appliedAts, &statements, &completeds); err != nil {
+		return nil, err
+	}
+
+	history := make(map[string]*MigrationRecord)
+	for i := range versions {
+		applied, _ := strconv.Atoi(statements[i])
+		history[versions[i]] = &MigrationRecord{
+			Version:    versions[i],
+			Checksum:   checksums[i],
+			AppliedAt:  appliedAts[i],
+			Statements: applied,
+			Completed:  completeds[i] != "0",
+		}
+	}
+	return history, nil
+}
+
+// HostMigrationApply applies statements of the migration one by one, starting from the specified one.
+// DDL statements are applied ON CLUSTER the host belongs to. Progress is recorded in the history table,
+// thus partially applied migration is resumed from the failed statement. Returns number of statements applied
+func (s *ClusterSchemer) HostMigrationApply(
+	ctx context.Context,
+	host *api.ChiHost,
+	table string,
+	migration *model.SchemaMigration,
+	from int,
+) (applied int, err error) {
+	log.Ctx(ctx).V(1).M(host).F().Info("Apply migration %s at %s starting from statement %d", migration.Version, host.Runtime.Address.HostName, from)
+	cluster := host.GetCluster().Name
+	applied = from
+	for _, sql := range migration.SQLs[from:] {
+		if sql, err = model.SetSQLOnCluster(sql, cluster); err != nil {
+			break
+		}
+		log.Ctx(ctx).V(2).M(host).F().Info("\n%s", sql)
+		if err = s.ExecHost(ctx, host, []string{sql}, clickhouse.NewQueryOptions().SetRetry(false)); err != nil {
+			break
+		}
+		applied++
+	}
+	if err != nil {
+		err = fmt.Errorf("statement %d of %d: %w", applied+1, len(migration.SQLs), err)
+		if applied == from {
+			// No progress to be recorded
+			return applied, err
+		}
+	}
+
+	completed := err == nil
+	sql := s.sqlInsertMigrationsHistory(table, migration.Version, migration.Checksum, applied, completed)
+	if e := s.ExecHost(ctx, host, []string{sql}, clickhouse.NewQueryOptions().SetRetry(false)); e != nil {
+		log.Ctx(ctx).V(1).M(host).F().Error("unable to record migration %s progress err: %v", migration.Version, e)
+		if err == nil {
+			err = e
+		}
+	}
+	return applied, err
+}
+
+// splitMigrationsHistoryTable splits 'database.table' into database and table. Database is 'default' in case omitted
+func splitMigrationsHistoryTable(table string) (string, string) {
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return "default", table
+}
+
+func (s *ClusterSchemer) sqlCreateMigrationsHistoryDatabase(table, cluster string) string {
+	database, _ := splitMigrationsHistoryTable(table)
+	return fmt.Sprintf("CREATE DATABASE IF NOT EXISTS \"%s\" ON CLUSTER %s", database, util.QuoteSQLString(cluster))
+}
+
+func (s *ClusterSchemer) sqlCreateMigrationsHistoryTable(table, cluster string, replicated bool) string {
+	database, name := splitMigrationsHistoryTable(table)
+	engine := "ReplacingMergeTree(applied_at)"
+	if replicated {
+		engine = "ReplicatedReplacingMergeTree(applied_at)"
+	}
+	return heredoc.Docf(`
+		CREATE TABLE IF NOT EXISTS "%s"."%s" ON CLUSTER %s
+		(
+			version String,
+			checksum String,
+			applied_at DateTime DEFAULT now(),
+			statements UInt32 DEFAULT 0,
+			completed UInt8 DEFAULT 1
+		)
+		ENGINE = %s
+		ORDER BY version
+		`,
+		database,
+		name,
+		util.QuoteSQLString(cluster),
+		engine,
+	)
+}
+
+func (s *ClusterSchemer) sqlAlterMigrationsHistoryTable(table, cluster string) string {
+	database, name := splitMigrationsHistoryTable(table)
+	return heredoc.Docf(`
+		ALTER TABLE "%s"."%s" ON CLUSTER %s
+			ADD COLUMN IF NOT EXISTS statements UInt32 DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS completed UInt8 DEFAULT 1
+		`,
+		database,
+		name,
+		util.QuoteSQLString(cluster),
+	)
+}
+
+func (s *ClusterSchemer) sqlSelectMigrationsHistory(table string) string {
+	database, name := splitMigrationsHistoryTable(table)
+	return fmt.Sprintf(
+		`SELECT version, checksum, toString(applied_at), toString(statements), toString(completed) FROM "%s"."%s" FINAL ORDER BY version`,
+		database,
+		name,
+	)
+}
+
+func (s *ClusterSchemer) sqlInsertMigrationsHistory(table, version, checksum string, statements int, completed bool) string {
+	database, name := splitMigrationsHistoryTable(table)
+	completedFlag := 0
+	if completed {
+		completedFlag = 1
+	}
+	return fmt.Sprintf(
+		`INSERT INTO "%s"."%s" (version, checksum, statements, completed) VALUES ('%s', '%s', %d, %d)`,
+		database,
+		name,
+		version,
+		checksum,
+		statements,
+		completedFlag,
+	)
+}
@@ -25,8 +25,9 @@ import (
 
 // shouldCreateReplicatedObjects determines whether replicated objects should be created
 func shouldCreateReplicatedObjects(host *api.ChiHost) bool {
-	shard := model.CreateFQDNs(host, api.ChiShard{}, false)
-	cluster := model.CreateFQDNs(host, api.Cluster{}, false)
+	// External hosts are reachable via cluster as well, thus they can be used as a source of the schema
+	shard := append(model.CreateFQDNs(host, api.ChiShard{}, false), model.CreateExternalFQDNs(host, api.ChiShard{})...)
+	cluster := append(model.CreateFQDNs(host, api.Cluster{}, false), model.CreateExternalFQDNs(host, api.Cluster{})...)
 
 	if host.GetCluster().SchemaPolicy.Shard == model.SchemaPolicyShardAll {
 		// We have explicit request to create replicated objects on each shard
@@ -54,12 +55,12 @@ func shouldCreateReplicatedObjects(host *api.ChiHost) bool {
 // getReplicatedObjectsSQLs returns a list of objects that needs to be created on a host in a cluster
 func (s *ClusterSchemer) getReplicatedObjectsSQLs(ctx context.Context, host *api.ChiHost) ([]string, []string, error) {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("ctx is done")
+		log.Ctx(ctx).V(2).Info("ctx is done")
 		return nil, nil, nil
 	}
 
 	if !shouldCreateReplicatedObjects(host) {
-		log.V(1).M(host).F().Info("Should not create replicated objects")
+		log.Ctx(ctx).V(1).M(host).F().Info("Should not create replicated objects")
 		return nil, nil, nil
 	}
 
@@ -0,0 +1,161 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/model/clickhouse"
+)
+
+// ReplicaState specifies replication state of the replicated table on the host
+type ReplicaState struct {
+	Database      string
+	Table         string
+	IsReadOnly    bool
+	QueueSize     int
+	AbsoluteDelay time.Duration
+	ReplicaPath   string
+	// TotalRows specifies number of rows in the table on the host
+	TotalRows uint64
+}
+
+// GetName gets full name of the table
+func (r *ReplicaState) GetName() string {
+	return r.Database + "." + r.Table
+}
+
+// HostReplicas fetches replication state of all replicated tables of the host
+func (s *ClusterSchemer) HostReplicas(ctx context.Context, host *api.ChiHost) (replicas []*ReplicaState, err error) {
+	query, err := s.QueryHost(ctx, host, s.sqlReplicas())
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	var databases, tables, readOnlys, queueSizes, absoluteDelays, replicaPaths, totalRows []string
+	if err := query.UnzipColumnsAsStrings(&databases, &tables, &readOnlys, &queueSizes, &absoluteDelays, &replicaPaths, &totalRows); err != nil {
+		return nil, err
+	}
+	for i := range tables {
+		queueSize, _ := strconv.Atoi(queueSizes[i])
+		absoluteDelay, _ := strconv.Atoi(absoluteDelays[i])
+		rows, _ := strconv.ParseUint(totalRows[i], 10, 64)
+		replicas = append(replicas, &ReplicaState{
+			Database:      databases[i],
+			Table:         tables[i],
+			IsReadOnly:    readOnlys[i] == "1",
+			QueueSize:     queueSize,
+			AbsoluteDelay: time.Duration(absoluteDelay) * time.Second,
+			ReplicaPath:   replicaPaths[i],
+			TotalRows:     rows,
+		})
+	}
+	return replicas, nil
+}
+
+// HostReplicaMetadataExists checks whether metadata of the replica exists in ZooKeeper
+func (s *ClusterSchemer) HostReplicaMetadataExists(ctx context.Context, host *api.ChiHost, replica *ReplicaState) (bool, error) {
+	query, err := s.QueryHost(ctx, host, s.sqlReplicaMetadataExists(replica.ReplicaPath))
+	if err != nil {
+		return false, err
+	}
+	defer query.Close()
+	count, err := query.Int()
+	return count > 0, err
+}
+
+// HostRestartReplica re-initializes ZooKeeper session of the replicated table
+func (s *ClusterSchemer) HostRestartReplica(ctx context.Context, host *api.ChiHost, replica *ReplicaState) error {
+	log.Ctx(ctx).V(1).M(host).F().Info("Restart replica %s at %s", replica.GetName(), host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlRestartReplica(replica)}, clickhouse.NewQueryOptions().SetRetry(false))
+}
+
+// HostRestoreReplica restores metadata of the replicated table in ZooKeeper
+func (s *ClusterSchemer) HostRestoreReplica(ctx context.Context, host *api.ChiHost, replica *ReplicaState) error {
+	log.Ctx(ctx).V(1).M(host).F().Info("Restore replica %s at %s", replica.GetName(), host.Runtime.Address.HostName)
+	return s.ExecHost(ctx, host, []string{s.sqlRestoreReplica(replica)}, clickhouse.NewQueryOptions().SetRetry(false))
+}
+
+// HostNonReplicatedTables fetches names of the tables, which keep data on the host and do not replicate it
+func (s *ClusterSchemer) HostNonReplicatedTables(ctx context.Context, host *api.ChiHost) (tables []string, err error) {
+	query, err := s.QueryHost(ctx, host, s.sqlNonReplicatedTables())
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	if err := query.UnzipColumnsAsStrings(&tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func (s *ClusterSchemer) sqlReplicas() string {
+	return heredoc.Doc(`
+		SELECT
+			replicas.database,
+			replicas.table,
+			toString(replicas.is_readonly),
+			toString(replicas.queue_size),
+			toString(replicas.absolute_delay),
+			replicas.replica_path,
+			toString(ifNull(tables.total_rows, 0))
+		FROM
+			system.replicas AS replicas
+			LEFT JOIN system.tables AS tables ON (replicas.database = tables.database) AND (replicas.table = tables.name)
+		`,
+	)
+}
+
+func (s *ClusterSchemer) sqlNonReplicatedTables() string {
+	return heredoc.Doc(`
+		SELECT
+			concat(database, '.', name)
+		FROM
+			system.tables
+		WHERE
+			database NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')
+			AND (
+				(engine LIKE '%MergeTree' AND engine NOT LIKE 'Replicated%' AND total_rows > 0)
+				OR (engine LIKE '%Log')
+			)
+		`,
+	)
+}
+
+func (s *ClusterSchemer) sqlReplicaMetadataExists(replicaPath string) string {
+	return fmt.Sprintf(
+		"SELECT count() FROM system.zookeeper WHERE path = '%s' AND name = '%s'",
+		path.Dir(replicaPath),
+		path.Base(replicaPath),
+	)
+}
+
+func (s *ClusterSchemer) sqlRestartReplica(replica *ReplicaState) string {
+	return fmt.Sprintf("SYSTEM RESTART REPLICA \"%s\".\"%s\"", replica.Database, replica.Table)
+}
+
+func (s *ClusterSchemer) sqlRestoreReplica(replica *ReplicaState) string {
+	return fmt.Sprintf("SYSTEM RESTORE REPLICA \"%s\".\"%s\"", replica.Database, replica.Table)
+}
@@ -40,10 +40,32 @@ func NewClusterSchemer(clusterConnectionParams *clickhouse.ClusterConnectionPara
 	}
 }
 
+// NewClusterSchemerForHost creates new Schemer object with base cluster connection params adjusted to the host
+func NewClusterSchemerForHost(clusterConnectionParams *clickhouse.ClusterConnectionParams, host *api.ChiHost) *ClusterSchemer {
+	// Adjust base cluster connection params with per-host props
+	switch clusterConnectionParams.Scheme {
+	case api.ChSchemeAuto:
+		switch {
+		case api.IsPortAssigned(host.HTTPPort):
+			clusterConnectionParams.Scheme = "http"
+			clusterConnectionParams.Port = int(host.HTTPPort)
+		case api.IsPortAssigned(host.HTTPSPort):
+			clusterConnectionParams.Scheme = "https"
+			clusterConnectionParams.Port = int(host.HTTPSPort)
+		}
+	case api.ChSchemeHTTP:
+		clusterConnectionParams.Port = int(host.HTTPPort)
+	case api.ChSchemeHTTPS:
+		clusterConnectionParams.Port = int(host.HTTPSPort)
+	}
+
+	return NewClusterSchemer(clusterConnectionParams, host.Runtime.Version)
+}
+
 // HostSyncTables calls SYSTEM SYNC REPLICA for replicated tables
 func (s *ClusterSchemer) HostSyncTables(ctx context.Context, host *api.ChiHost) error {
 	tableNames, syncTableSQLs, _ := s.sqlSyncTable(ctx, host)
-	log.V(1).M(host).F().Info("Sync tables: %v as %v", tableNames, syncTableSQLs)
+	log.Ctx(ctx).V(1).M(host).F().Info("Sync tables: %v as %v", tableNames, syncTableSQLs)
 	opts := clickhouse.NewQueryOptions()
 	opts.SetQueryTimeout(120 * time.Second)
 	return s.ExecHost(ctx, host, syncTableSQLs, opts)
@@ -53,7 +75,7 @@ func (s *ClusterSchemer) HostSyncTables(ctx context.Context, host *api.ChiHost)
 func (s *ClusterSchemer) HostDropReplica(ctx context.Context, hostToRunOn, hostToDrop *api.ChiHost) error {
 	replica := model.CreateInstanceHostname(hostToDrop)
 	shard := hostToRunOn.Runtime.Address.ShardIndex
-	log.V(1).M(hostToRunOn).F().Info("Drop replica: %v at %v", replica, hostToRunOn.Runtime.Address.HostName)
+	log.Ctx(ctx).V(1).M(hostToRunOn).F().Info("Drop replica: %v at %v", replica, hostToRunOn.Runtime.Address.HostName)
 	return s.ExecHost(ctx, hostToRunOn, s.sqlDropReplica(shard, replica), clickhouse.NewQueryOptions().SetRetry(false))
 }
 
@@ -81,12 +103,12 @@ func (s *ClusterSchemer) createTablesSQLs(
 // HostCreateTables creates tables on a new host
 func (s *ClusterSchemer) HostCreateTables(ctx context.Context, host *api.ChiHost) error {
 	if util.IsContextDone(ctx) {
-		log.V(2).Info("ctx is done")
+		log.Ctx(ctx).V(2).Info("ctx is done")
 		return nil
 	}
 
-	log.V(1).M(host).F().S().Info("Migrating schema objects to host %s", host.Runtime.Address.HostName)
-	defer log.V(1).M(host).F().E().Info("Migrating schema objects to host %s", host.Runtime.Address.HostName)
+	log.Ctx(ctx).V(1).M(host).F().S().Info("Migrating schema objects to host %s", host.Runtime.Address.HostName)
+	defer log.Ctx(ctx).V(1).M(host).F().E().Info("Migrating schema objects to host %s", host.Runtime.Address.HostName)
 
 	replicatedObjectNames,
 		replicatedCreateSQLs,
@@ -95,15 +117,15 @@ func (s *ClusterSchemer) HostCreateTables(ctx context.Context, host *api.ChiHost
 
 	var err1 error
 	if len(replicatedCreateSQLs) > 0 {
-		log.V(1).M(host).F().Info("Creating replicated objects at %s: %v", host.Runtime.Address.HostName, replicatedObjectNames)
-		log.V(2).M(host).F().Info("\n%v", replicatedCreateSQLs)
+		log.Ctx(ctx).V(1).M(host).F().Info("Creating replicated objects at %s: %v", host.Runtime.Address.HostName, replicatedObjectNames)
+		log.Ctx(ctx).V(2).M(host).F().Info("\n%v", replicatedCreateSQLs)
 		err1 = s.ExecHost(ctx, host, replicatedCreateSQLs, clickhouse.NewQueryOptions().SetRetry(true))
 	}
 
 	var err2 error
 	if len(distributedCreateSQLs) > 0 {
-		log.V(1).M(host).F().Info("Creating distributed objects at %s: %v", host.Runtime.Address.HostName, distributedObjectNames)
-		log.V(2).M(host).F().Info("\n%v", distributedCreateSQLs)
+		log.Ctx(ctx).V(1).M(host).F().Info("Creating distributed objects at %s: %v", host.Runtime.Address.HostName, distributedObjectNames)
+		log.Ctx(ctx).V(2).M(host).F().Info("\n%v", distributedCreateSQLs)
 		err2 = s.ExecHost(ctx, host, distributedCreateSQLs, clickhouse.NewQueryOptions().SetRetry(true))
 	}
 
@@ -120,7 +142,7 @@ func (s *ClusterSchemer) HostCreateTables(ctx context.Context, host *api.ChiHost
 // HostDropTables drops tables on a host
 func (s *ClusterSchemer) HostDropTables(ctx context.Context, host *api.ChiHost) error {
 	tableNames, dropTableSQLs, _ := s.sqlDropTable(ctx, host)
-	log.V(1).M(host).F().Info("Drop tables: %v as %v", tableNames, dropTableSQLs)
+	log.Ctx(ctx).V(1).M(host).F().Info("Drop tables: %v as %v", tableNames, dropTableSQLs)
 	return s.ExecHost(ctx, host, dropTableSQLs, clickhouse.NewQueryOptions().SetRetry(false))
 }
 
@@ -131,10 +153,10 @@ func (s *ClusterSchemer) IsHostInCluster(ctx context.Context, host *api.ChiHost)
 	opts := clickhouse.NewQueryOptions().SetSilent(true)
 	err := s.ExecHost(ctx, host, SQLs, opts)
 	if err == nil {
-		log.V(1).M(host).F().Info("The host %s is inside the cluster", host.GetName())
+		log.Ctx(ctx).V(1).M(host).F().Info("The host %s is inside the cluster", host.GetName())
 		inside = true
 	} else {
-		log.V(1).M(host).F().Info("The host %s is outside of the cluster", host.GetName())
+		log.Ctx(ctx).V(1).M(host).F().Info("The host %s is outside of the cluster", host.GetName())
 		inside = false
 	}
 	return inside
@@ -148,6 +170,12 @@ func (s *ClusterSchemer) CHIDropDnsCache(ctx context.Context, chi *api.ClickHous
 	return nil
 }
 
+// HostReloadConfig runs 'SYSTEM RELOAD CONFIG' on the host
+func (s *ClusterSchemer) HostReloadConfig(ctx context.Context, host *api.ChiHost) error {
+	log.Ctx(ctx).V(1).M(host).F().Info("Reload config on host: %s", host.GetName())
+	return s.ExecHost(ctx, host, []string{s.sqlReloadConfig()}, clickhouse.NewQueryOptions().SetRetry(false))
+}
+
 // HostActiveQueriesNum returns how many active queries are on the host
 func (s *ClusterSchemer) HostActiveQueriesNum(ctx context.Context, host *api.ChiHost) (int, error) {
 	return s.QueryHostInt(ctx, host, s.sqlActiveQueriesNum())
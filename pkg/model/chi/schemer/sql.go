@@ -241,6 +241,10 @@ func (s *ClusterSchemer) sqlDropDNSCache() string {
 	return `SYSTEM DROP DNS CACHE`
 }
 
+func (s *ClusterSchemer) sqlReloadConfig() string {
+	return `SYSTEM RELOAD CONFIG`
+}
+
 func (s *ClusterSchemer) sqlActiveQueriesNum() string {
 	return `SELECT count() FROM system.processes`
 }
@@ -0,0 +1,263 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/yaml"
+
+	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/swversion"
+)
+
+//go:embed settings_catalog.yaml
+var settingsCatalogYAML []byte
+
+// SettingValueType specifies type of the setting value in the settings catalog
+type SettingValueType string
+
+// Possible types of the setting value
+const (
+	SettingValueTypeBool       SettingValueType = "Bool"
+	SettingValueTypeUInt64     SettingValueType = "UInt64"
+	SettingValueTypeInt64      SettingValueType = "Int64"
+	SettingValueTypeFloat      SettingValueType = "Float"
+	SettingValueTypeSeconds    SettingValueType = "Seconds"
+	SettingValueTypeMaxThreads SettingValueType = "MaxThreads"
+	SettingValueTypeString     SettingValueType = "String"
+	SettingValueTypeSection    SettingValueType = "Section"
+)
+
+var (
+	settingValueBools      = []string{"0", "1", "true", "false", "yes", "no", "on", "off"}
+	settingValueUInt64     = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?\s*([KkMGTPE]i?[Bb]?)?$`)
+	settingValueInt64      = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	settingValueMaxThreads = regexp.MustCompile(`^(?i:auto(\([0-9]+\))?|[0-9]+)$`)
+)
+
+// SettingsCatalogEntry describes setting known to ClickHouse
+type SettingsCatalogEntry struct {
+	Name string           `json:"name"`
+	Type SettingValueType `json:"type"`
+	// Versions specifies semver constraint of ClickHouse versions the setting is known to.
+	// Empty means setting is known to all versions
+	Versions string `json:"versions,omitempty"`
+}
+
+// isKnownTo checks whether setting is known to the specified ClickHouse version.
+// Any setting is considered to be known to unknown version
+func (e *SettingsCatalogEntry) isKnownTo(version *swversion.SoftWareVersion) bool {
+	if (e.Versions == "") || version.IsUnknown() {
+		return true
+	}
+	return version.Matches(e.Versions)
+}
+
+// validateValue checks whether value of the setting matches type of the entry
+func (e *SettingsCatalogEntry) validateValue(setting *api.Setting) error {
+	if setting.IsSource() || setting.HasAttributes() {
+		// Value is substituted in runtime
+		return nil
+	}
+	for _, value := range setting.AsVectorOfStrings() {
+		if err := e.Type.validate(strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks whether value matches the type
+func (t SettingValueType) validate(value string) error {
+	if value == "" {
+		return nil
+	}
+	valid := true
+	switch t {
+	case SettingValueTypeBool:
+		valid = false
+		for _, b := range settingValueBools {
+			if strings.EqualFold(value, b) {
+				valid = true
+			}
+		}
+	case SettingValueTypeUInt64:
+		valid = settingValueUInt64.MatchString(value)
+	case SettingValueTypeInt64:
+		valid = settingValueInt64.MatchString(value)
+	case SettingValueTypeFloat, SettingValueTypeSeconds:
+		_, err := strconv.ParseFloat(value, 64)
+		valid = err == nil
+	case SettingValueTypeMaxThreads:
+		valid = settingValueMaxThreads.MatchString(value)
+	}
+	if !valid {
+		return fmt.Errorf("value %q is not a valid %s", value, t)
+	}
+	return nil
+}
+
+// SettingsCatalog specifies settings known to ClickHouse
+type SettingsCatalog struct {
+	// Server specifies server settings - top-level elements of config.xml
+	Server []SettingsCatalogEntry `json:"server"`
+	// Profile specifies settings of the settings profile
+	Profile []SettingsCatalogEntry `json:"profile"`
+
+	server  map[string]*SettingsCatalogEntry
+	profile map[string]*SettingsCatalogEntry
+}
+
+var (
+	settingsCatalog     *SettingsCatalog
+	settingsCatalogOnce sync.Once
+)
+
+// GetSettingsCatalog gets catalog of ClickHouse settings embedded into the operator.
+// Returns nil in case catalog is not available
+func GetSettingsCatalog() *SettingsCatalog {
+	settingsCatalogOnce.Do(func() {
+		catalog := &SettingsCatalog{}
+		if err := yaml.Unmarshal(settingsCatalogYAML, catalog); err != nil {
+			log.V(1).F().Error("unable to parse settings catalog. err: %v", err)
+			return
+		}
+		catalog.server = indexSettingsCatalogEntries(catalog.Server)
+		catalog.profile = indexSettingsCatalogEntries(catalog.Profile)
+		settingsCatalog = catalog
+	})
+	return settingsCatalog
+}
+
+func indexSettingsCatalogEntries(entries []SettingsCatalogEntry) map[string]*SettingsCatalogEntry {
+	index := make(map[string]*SettingsCatalogEntry, len(entries))
+	for i := range entries {
+		index[entries[i].Name] = &entries[i]
+	}
+	return index
+}
+
+// SettingsIssue describes invalid setting
+type SettingsIssue struct {
+	// Path specifies path of the setting, prefixed with the section. Ex.: settings/logger/level
+	Path    string
+	Message string
+	// Unknown specifies setting is not known to the catalog or to the ClickHouse version,
+	// which is not necessarily a mistake, since the catalog is not exhaustive
+	Unknown bool
+}
+
+// String returns human-readable description of the issue
+func (i SettingsIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+// ValidateServerSettings validates server settings against the catalog for the specified ClickHouse version
+func (c *SettingsCatalog) ValidateServerSettings(settings *api.Settings, version *swversion.SoftWareVersion) (issues []SettingsIssue) {
+	if c == nil {
+		return nil
+	}
+	settings.Walk(func(name string, setting *api.Setting) {
+		path := strings.Split(name, "/")
+		if issue, unknown := c.validate(c.server, path, 0, setting, version); issue != "" {
+			issues = append(issues, SettingsIssue{
+				Path:    configurationRestartPolicyRulesSectionSettings + "/" + name,
+				Message: issue,
+				Unknown: unknown,
+			})
+		}
+	})
+	return issues
+}
+
+// ValidateProfiles validates settings of the profiles against the catalog for the specified ClickHouse version.
+// Settings having custom prefixes are not validated
+func (c *SettingsCatalog) ValidateProfiles(profiles *api.Settings, version *swversion.SoftWareVersion, customPrefixes []string) (issues []SettingsIssue) {
+	if c == nil {
+		return nil
+	}
+	profiles.Walk(func(name string, setting *api.Setting) {
+		// Path is expected to be in form of profile/setting
+		path := strings.Split(name, "/")
+		if len(path) < 2 {
+			return
+		}
+		for _, prefix := range customPrefixes {
+			if strings.HasPrefix(path[1], prefix) {
+				return
+			}
+		}
+		if issue, unknown := c.validate(c.profile, path, 1, setting, version); issue != "" {
+			issues = append(issues, SettingsIssue{
+				Path:    configurationRestartPolicyRulesSectionProfiles + "/" + name,
+				Message: issue,
+				Unknown: unknown,
+			})
+		}
+	})
+	return issues
+}
+
+// validate validates setting, which is named by path[index], against the index of the catalog.
+// Returns description of the issue or empty string in case setting is valid,
+// along with whether the issue is the setting being unknown
+func (c *SettingsCatalog) validate(
+	index map[string]*SettingsCatalogEntry,
+	path []string,
+	depth int,
+	setting *api.Setting,
+	version *swversion.SoftWareVersion,
+) (string, bool) {
+	entry, ok := index[path[depth]]
+	switch {
+	case !ok:
+		return "unknown setting", true
+	case !entry.isKnownTo(version):
+		return fmt.Sprintf("setting is not known to ClickHouse %s, requires %s", version, entry.Versions), true
+	case len(path) > depth+1:
+		// Nested setting
+		if entry.Type != SettingValueTypeSection {
+			return fmt.Sprintf("setting %s has no nested settings", entry.Name), false
+		}
+		return "", false
+	case entry.Type == SettingValueTypeSection:
+		// Section specified as a whole, nested settings are not known to the catalog
+		return "", false
+	}
+	if err := entry.validateValue(setting); err != nil {
+		return err.Error(), false
+	}
+	return "", false
+}
+
+// GetCustomSettingsPrefixes gets prefixes of custom settings allowed by server settings
+func GetCustomSettingsPrefixes(settings *api.Settings) (prefixes []string) {
+	if !settings.Has("custom_settings_prefixes") {
+		return nil
+	}
+	for _, prefix := range strings.Split(settings.Get("custom_settings_prefixes").String(), ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
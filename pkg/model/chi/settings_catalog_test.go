@@ -0,0 +1,196 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/apis/swversion"
+)
+
+func TestSettingValueTypeValidate(t *testing.T) {
+	tests := []struct {
+		typ     SettingValueType
+		valid   []string
+		invalid []string
+	}{
+		{
+			typ:     SettingValueTypeBool,
+			valid:   []string{"", "0", "1", "true", "False", "yes", "NO", "on", "off"},
+			invalid: []string{"2", "-1", "enabled", "t"},
+		},
+		{
+			typ:     SettingValueTypeUInt64,
+			valid:   []string{"", "0", "100", "1.5", "10G", "10Gi", "10GiB", "512 MB", "1kb"},
+			invalid: []string{"-1", "+1", "1e3", "10X", "ten", "1.", "1GiBs"},
+		},
+		{
+			typ:     SettingValueTypeInt64,
+			valid:   []string{"", "0", "-100", "+100", "9223372036854775807"},
+			invalid: []string{"1.5", "10G", "--1", "one"},
+		},
+		{
+			typ:     SettingValueTypeFloat,
+			valid:   []string{"", "0", "0.5", "-1.5", "1e3"},
+			invalid: []string{"0,5", "half", "1.5s"},
+		},
+		{
+			typ:     SettingValueTypeSeconds,
+			valid:   []string{"", "300", "0.1"},
+			invalid: []string{"5m", "300s"},
+		},
+		{
+			typ:     SettingValueTypeMaxThreads,
+			valid:   []string{"", "8", "auto", "AUTO", "auto(16)", "Auto(4)"},
+			invalid: []string{"-1", "auto()", "auto(x)", "1.5", "all"},
+		},
+		{
+			typ:   SettingValueTypeString,
+			valid: []string{"", "anything", "/var/lib/clickhouse/", "0.0.0.0"},
+		},
+		{
+			typ:   SettingValueTypeSection,
+			valid: []string{"", "<level>debug</level>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			for _, value := range tt.valid {
+				require.NoError(t, tt.typ.validate(value), "value %q", value)
+			}
+			for _, value := range tt.invalid {
+				require.Error(t, tt.typ.validate(value), "value %q", value)
+			}
+		})
+	}
+}
+
+func TestSettingsCatalogValidateServerSettings(t *testing.T) {
+	catalog := GetSettingsCatalog()
+	require.NotNil(t, catalog)
+
+	tests := []struct {
+		name     string
+		settings *api.Settings
+		version  string
+		// issues specifies paths of the settings expected to be reported
+		issues []string
+		// unknown specifies paths of the settings expected to be reported as unknown ones
+		unknown []string
+	}{
+		{
+			name: "valid settings",
+			settings: newTestSettings(map[string]string{
+				"listen_host":            "0.0.0.0",
+				"max_concurrent_queries": "100",
+				"logger/level":           "debug",
+				"macros/shard":           "1",
+			}),
+			version: "24.3.1.1",
+		},
+		{
+			name:     "unknown setting",
+			settings: newTestSettings(map[string]string{"no_such_setting": "1"}),
+			version:  "24.3.1.1",
+			issues:   []string{"settings/no_such_setting"},
+			unknown:  []string{"settings/no_such_setting"},
+		},
+		{
+			name:     "invalid value",
+			settings: newTestSettings(map[string]string{"max_concurrent_queries": "many"}),
+			version:  "24.3.1.1",
+			issues:   []string{"settings/max_concurrent_queries"},
+		},
+		{
+			name:     "nested setting of the scalar one",
+			settings: newTestSettings(map[string]string{"max_concurrent_queries/value": "100"}),
+			version:  "24.3.1.1",
+			issues:   []string{"settings/max_concurrent_queries/value"},
+		},
+		{
+			name:     "setting of the newer version",
+			settings: newTestSettings(map[string]string{"max_waiting_queries": "10"}),
+			version:  "23.8.2.7",
+			issues:   []string{"settings/max_waiting_queries"},
+			unknown:  []string{"settings/max_waiting_queries"},
+		},
+		{
+			name:     "setting of the matching version",
+			settings: newTestSettings(map[string]string{"max_waiting_queries": "10"}),
+			version:  "24.1.1.1",
+		},
+		{
+			name:     "setting of unknown version",
+			settings: newTestSettings(map[string]string{"max_waiting_queries": "10"}),
+		},
+		{
+			name: "vector values are validated one by one",
+			settings: func() *api.Settings {
+				settings := api.NewSettings()
+				settings.Set("max_concurrent_queries", api.NewSettingVector([]string{"1", "two"}))
+				return settings
+			}(),
+			issues: []string{"settings/max_concurrent_queries"},
+		},
+		{
+			name: "source values are not validated",
+			settings: func() *api.Settings {
+				settings := api.NewSettings()
+				settings.Set("max_concurrent_queries", api.NewSettingSource(&api.SettingSource{}))
+				return settings
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths, unknown []string
+			for _, issue := range catalog.ValidateServerSettings(tt.settings, swversion.NewSoftWareVersion(tt.version)) {
+				paths = append(paths, issue.Path)
+				if issue.Unknown {
+					unknown = append(unknown, issue.Path)
+				}
+			}
+			require.Equal(t, tt.issues, paths)
+			require.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestSettingsCatalogValidateProfiles(t *testing.T) {
+	catalog := GetSettingsCatalog()
+	require.NotNil(t, catalog)
+
+	profiles := newTestSettings(map[string]string{
+		"default/max_threads":         "auto",
+		"default/max_memory_usage":    "10Gi",
+		"default/max_execution_time":  "five",
+		"readonly/readonly":           "1",
+		"readonly/no_such_setting":    "1",
+		"readonly/custom_tenant_name": "tenant",
+	})
+	var paths, unknown []string
+	for _, issue := range catalog.ValidateProfiles(profiles, nil, []string{"custom_"}) {
+		paths = append(paths, issue.Path)
+		if issue.Unknown {
+			unknown = append(unknown, issue.Path)
+		}
+	}
+	require.ElementsMatch(t, []string{
+		"profiles/default/max_execution_time",
+		"profiles/readonly/no_such_setting",
+	}, paths)
+	require.Equal(t, []string{"profiles/readonly/no_such_setting"}, unknown)
+
+	// Nil catalog validates nothing
+	var nilCatalog *SettingsCatalog
+	require.Empty(t, nilCatalog.ValidateProfiles(profiles, nil, nil))
+}
+
+func TestGetCustomSettingsPrefixes(t *testing.T) {
+	require.Nil(t, GetCustomSettingsPrefixes(api.NewSettings()))
+	settings := newTestSettings(map[string]string{"custom_settings_prefixes": " custom_, tenant_ ,"})
+	require.Equal(t, []string{"custom_", "tenant_"}, GetCustomSettingsPrefixes(settings))
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// CreateUserGrantQueries creates list of GRANT queries of the user, to be used in users config.
+// Queries have no grantee specified, as required by 'grants/query' section of users config
+func CreateUserGrantQueries(user *api.ClickHouseUser) (queries []string, err error) {
+	if user == nil {
+		return nil, nil
+	}
+	for _, grant := range user.Spec.Grants {
+		parsed, err := ParseUserGrant(grant)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, "GRANT "+parsed.String())
+	}
+	for _, role := range user.Spec.Roles {
+		queries = append(queries, "GRANT "+util.QuoteSQLIdentifier(role))
+	}
+	return queries, nil
+}
+
+// ValidateUser validates spec of the user against its provisioning mode
+func ValidateUser(user *api.ClickHouseUser) error {
+	if (user.GetMode() == api.ClickHouseUserModeSQL) && (user.Spec.Quota != "") {
+		// Quotas of users config are not applicable to SQL-provisioned users, while assignment of SQL quota
+		// with ALTER QUOTA ... TO would replace all other users and roles of the quota
+		return fmt.Errorf("quota %s is not supported in %s mode, assign the quota to the user with SQL instead", user.Spec.Quota, api.ClickHouseUserModeSQL)
+	}
+	return nil
+}
+
+// GetUserCluster gets cluster to be used in ON CLUSTER clause of the user SQLs
+func GetUserCluster(user *api.ClickHouseUser) string {
+	if (user == nil) || (user.Spec.Target.Cluster == "") {
+		return OneShardAllReplicasClusterName
+	}
+	return user.Spec.Target.Cluster
+}
+
+// CreateUserSQLs creates SQLs to create the user and to bring its authentication, hosts and profile up to the spec.
+// Empty passwordSHA256 means user has no password
+func CreateUserSQLs(user *api.ClickHouseUser, passwordSHA256 string) []string {
+	name := util.QuoteSQLIdentifier(user.GetUsername())
+	cluster := util.QuoteSQLString(GetUserCluster(user))
+
+	identified := "NOT IDENTIFIED"
+	if passwordSHA256 != "" {
+		identified = fmt.Sprintf("IDENTIFIED WITH sha256_hash BY %s", util.QuoteSQLString(passwordSHA256))
+	}
+
+	var hosts []string
+	for _, ip := range user.Spec.Networks.GetIP() {
+		hosts = append(hosts, "IP "+util.QuoteSQLString(ip))
+	}
+	for _, regexp := range user.Spec.Networks.GetHostRegexp() {
+		hosts = append(hosts, "REGEXP "+util.QuoteSQLString(regexp))
+	}
+	host := "HOST ANY"
+	if len(hosts) > 0 {
+		host = "HOST " + strings.Join(hosts, ", ")
+	}
+
+	alter := fmt.Sprintf("ALTER USER %s ON CLUSTER %s %s %s", name, cluster, identified, host)
+	if user.Spec.Profile != "" {
+		alter += " SETTINGS PROFILE " + util.QuoteSQLString(user.Spec.Profile)
+	}
+
+	return []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s ON CLUSTER %s", name, cluster),
+		alter,
+	}
+}
+
+// CreateUserGrantSQLs creates SQLs to grant specified privileges and roles to the user.
+// Grants are validated and rendered by the operator, invalid grant fails the whole list
+func CreateUserGrantSQLs(user *api.ClickHouseUser, grants, roles []string) (sqls []string, err error) {
+	name := util.QuoteSQLIdentifier(user.GetUsername())
+	cluster := util.QuoteSQLString(GetUserCluster(user))
+	for _, grant := range grants {
+		parsed, err := ParseUserGrant(grant)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, fmt.Sprintf("GRANT ON CLUSTER %s %s TO %s", cluster, parsed, name))
+	}
+	for _, role := range roles {
+		sqls = append(sqls, fmt.Sprintf("GRANT ON CLUSTER %s %s TO %s", cluster, util.QuoteSQLIdentifier(role), name))
+	}
+	return sqls, nil
+}
+
+// CreateUserRevokeSQLs creates SQLs to revoke specified privileges and roles from the user.
+// Grants are validated and rendered by the operator, invalid grant fails the whole list
+func CreateUserRevokeSQLs(user *api.ClickHouseUser, grants, roles []string) (sqls []string, err error) {
+	name := util.QuoteSQLIdentifier(user.GetUsername())
+	cluster := util.QuoteSQLString(GetUserCluster(user))
+	for _, grant := range grants {
+		parsed, err := ParseUserGrant(grant)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, fmt.Sprintf("REVOKE ON CLUSTER %s %s FROM %s", cluster, parsed, name))
+	}
+	for _, role := range roles {
+		sqls = append(sqls, fmt.Sprintf("REVOKE ON CLUSTER %s %s FROM %s", cluster, util.QuoteSQLIdentifier(role), name))
+	}
+	return sqls, nil
+}
+
+// CreateUserDropSQLs creates SQLs to drop the user
+func CreateUserDropSQLs(user *api.ClickHouseUser) []string {
+	return []string{
+		fmt.Sprintf("DROP USER IF EXISTS %s ON CLUSTER %s", util.QuoteSQLIdentifier(user.GetUsername()), util.QuoteSQLString(GetUserCluster(user))),
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minorhacks/clickhouse-operator/pkg/util"
+)
+
+// userGrantAny specifies any database or any table in the grant target
+const userGrantAny = "*"
+
+// UserPrivilege specifies one privilege of the grant along with optional columns it is limited to
+type UserPrivilege struct {
+	// Name is the privilege name, such as SELECT or ALTER UPDATE
+	Name string
+	// Columns the privilege is limited to. Empty means all columns
+	Columns []string
+}
+
+// UserGrant specifies privileges granted on a database object, such as `SELECT, INSERT ON db.table`.
+// Grants are parsed and rendered back by the operator, so arbitrary SQL can not be passed with them
+type UserGrant struct {
+	Privileges []UserPrivilege
+	// Database name, "*" means any database
+	Database string
+	// Table name, "*" means any table
+	Table string
+}
+
+// ParseUserGrant parses grant in the form of `<privilege>[(<column>, ...)][, ...] ON <database>.<table>`.
+// Database and table are either identifiers or "*". Anything else is rejected
+func ParseUserGrant(grant string) (*UserGrant, error) {
+	tokens, err := tokenizeUserGrant(grant)
+	if err != nil {
+		return nil, fmt.Errorf("grant '%s': %w", grant, err)
+	}
+	p := &userGrantParser{
+		tokens: tokens,
+	}
+	res, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("grant '%s': %w", grant, err)
+	}
+	return res, nil
+}
+
+// String renders the grant with all identifiers quoted
+func (g *UserGrant) String() string {
+	var privileges []string
+	for _, privilege := range g.Privileges {
+		str := privilege.Name
+		if len(privilege.Columns) > 0 {
+			var columns []string
+			for _, column := range privilege.Columns {
+				columns = append(columns, util.QuoteSQLIdentifier(column))
+			}
+			str += "(" + strings.Join(columns, ", ") + ")"
+		}
+		privileges = append(privileges, str)
+	}
+	return strings.Join(privileges, ", ") + " ON " + quoteGrantTarget(g.Database) + "." + quoteGrantTarget(g.Table)
+}
+
+// quoteGrantTarget quotes database or table name of the grant target
+func quoteGrantTarget(name string) string {
+	if name == userGrantAny {
+		return userGrantAny
+	}
+	return util.QuoteSQLIdentifier(name)
+}
+
+// userGrantTokenKind specifies kind of the grant token
+type userGrantTokenKind int
+
+const (
+	userGrantTokenWord userGrantTokenKind = iota
+	userGrantTokenQuoted
+	userGrantTokenPunct
+)
+
+// userGrantToken specifies one token of the grant
+type userGrantToken struct {
+	kind  userGrantTokenKind
+	value string
+}
+
+// tokenizeUserGrant splits grant into words, backquoted identifiers and punctuation.
+// Any other character, such as quote or semicolon, makes the grant invalid
+func tokenizeUserGrant(grant string) (tokens []userGrantToken, err error) {
+	runes := []rune(grant)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case isUserGrantWordStart(r):
+			j := i
+			for j < len(runes) && isUserGrantWordPart(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, userGrantToken{kind: userGrantTokenWord, value: string(runes[i:j])})
+			i = j
+		case r == '`':
+			value := strings.Builder{}
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '`'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				value.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated quoted identifier")
+			}
+			if value.Len() == 0 {
+				return nil, fmt.Errorf("empty quoted identifier")
+			}
+			tokens = append(tokens, userGrantToken{kind: userGrantTokenQuoted, value: value.String()})
+			i = j + 1
+		case strings.ContainsRune("*.,()", r):
+			tokens = append(tokens, userGrantToken{kind: userGrantTokenPunct, value: string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character '%c'", r)
+		}
+	}
+	return tokens, nil
+}
+
+func isUserGrantWordStart(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r == '_')
+}
+
+func isUserGrantWordPart(r rune) bool {
+	return isUserGrantWordStart(r) || (r >= '0' && r <= '9')
+}
+
+// userGrantParser parses tokens of the grant
+type userGrantParser struct {
+	tokens []userGrantToken
+	pos    int
+}
+
+// peek returns current token, nil in case all tokens are consumed
+func (p *userGrantParser) peek() *userGrantToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+// isPunct checks whether current token is the specified punctuation
+func (p *userGrantParser) isPunct(punct string) bool {
+	token := p.peek()
+	return (token != nil) && (token.kind == userGrantTokenPunct) && (token.value == punct)
+}
+
+// isKeywordOn checks whether current token is the ON keyword
+func (p *userGrantParser) isKeywordOn() bool {
+	token := p.peek()
+	return (token != nil) && (token.kind == userGrantTokenWord) && strings.EqualFold(token.value, "ON")
+}
+
+// expectPunct consumes specified punctuation
+func (p *userGrantParser) expectPunct(punct string) error {
+	if !p.isPunct(punct) {
+		return fmt.Errorf("'%s' expected", punct)
+	}
+	p.pos++
+	return nil
+}
+
+// identifier consumes identifier, either bare word or backquoted one
+func (p *userGrantParser) identifier() (string, error) {
+	token := p.peek()
+	if (token == nil) || (token.kind == userGrantTokenPunct) {
+		return "", fmt.Errorf("identifier expected")
+	}
+	p.pos++
+	return token.value, nil
+}
+
+// target consumes database or table name of the grant target
+func (p *userGrantParser) target() (string, error) {
+	if p.isPunct(userGrantAny) {
+		p.pos++
+		return userGrantAny, nil
+	}
+	return p.identifier()
+}
+
+func (p *userGrantParser) parse() (*UserGrant, error) {
+	grant := &UserGrant{}
+	for {
+		privilege, err := p.privilege()
+		if err != nil {
+			return nil, err
+		}
+		grant.Privileges = append(grant.Privileges, *privilege)
+		if !p.isPunct(",") {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.isKeywordOn() {
+		return nil, fmt.Errorf("ON <database>.<table> expected")
+	}
+	p.pos++
+
+	var err error
+	if grant.Database, err = p.target(); err != nil {
+		return nil, err
+	}
+	if err = p.expectPunct("."); err != nil {
+		return nil, err
+	}
+	if grant.Table, err = p.target(); err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token != nil {
+		return nil, fmt.Errorf("unexpected '%s' after grant target", token.value)
+	}
+	return grant, nil
+}
+
+// privilege consumes one privilege, which consists of one or more words optionally followed by columns
+func (p *userGrantParser) privilege() (*UserPrivilege, error) {
+	var words []string
+	for token := p.peek(); (token != nil) && (token.kind == userGrantTokenWord) && !p.isKeywordOn(); token = p.peek() {
+		words = append(words, strings.ToUpper(token.value))
+		p.pos++
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("privilege expected")
+	}
+	privilege := &UserPrivilege{
+		Name: strings.Join(words, " "),
+	}
+
+	if !p.isPunct("(") {
+		return privilege, nil
+	}
+	p.pos++
+	for {
+		column, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		privilege.Columns = append(privilege.Columns, column)
+		if !p.isPunct(",") {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return privilege, nil
+}
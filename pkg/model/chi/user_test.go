@@ -0,0 +1,178 @@
+package chi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+func TestParseUserGrant(t *testing.T) {
+	tests := []struct {
+		grant    string
+		expected string
+	}{
+		{"SELECT ON db.*", "SELECT ON `db`.*"},
+		{"select on *.*", "SELECT ON *.*"},
+		{"SELECT, INSERT ON db.table", "SELECT, INSERT ON `db`.`table`"},
+		{"ALTER UPDATE, alter delete ON db.t", "ALTER UPDATE, ALTER DELETE ON `db`.`t`"},
+		{"SELECT(a, b), INSERT(c) ON db.t", "SELECT(`a`, `b`), INSERT(`c`) ON `db`.`t`"},
+		{"SELECT ON `my db`.`my-table`", "SELECT ON `my db`.`my-table`"},
+		{"SELECT ON `a\\`b`.t", "SELECT ON `a\\`b`.`t`"},
+		{"  SELECT\n ON\tdb . t  ", "SELECT ON `db`.`t`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.grant, func(t *testing.T) {
+			grant, err := ParseUserGrant(tt.grant)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, grant.String())
+		})
+	}
+}
+
+func TestParseUserGrantInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"SELECT",
+		"SELECT ON",
+		"SELECT ON db",
+		"SELECT ON db.",
+		"SELECT ON .t",
+		"ON db.t",
+		"SELECT, ON db.t",
+		"SELECT() ON db.t",
+		"SELECT(a ON db.t",
+		"SELECT ON db.t.c",
+		"SELECT ON db.t, INSERT ON db.t",
+		"SELECT ON db.t TO admin",
+		"SELECT ON db.t WITH GRANT OPTION",
+		"SELECT ON db.t; DROP USER admin",
+		"SELECT ON db.t -- comment",
+		"SELECT ON 'db'.t",
+		"SELECT ON \"db\".t",
+		"SELECT ON `db.t",
+		"SELECT ON ``.t",
+	}
+	for _, grant := range tests {
+		t.Run(grant, func(t *testing.T) {
+			_, err := ParseUserGrant(grant)
+			require.Error(t, err)
+		})
+	}
+}
+
+func newTestUser() *api.ClickHouseUser {
+	user := &api.ClickHouseUser{}
+	user.Name = "alice"
+	user.Spec.Target.CHI = "chi"
+	user.Spec.Target.Cluster = "main"
+	return user
+}
+
+func TestCreateUserSQLs(t *testing.T) {
+	user := newTestUser()
+	require.Equal(t, []string{
+		"CREATE USER IF NOT EXISTS `alice` ON CLUSTER 'main'",
+		"ALTER USER `alice` ON CLUSTER 'main' NOT IDENTIFIED HOST ANY",
+	}, CreateUserSQLs(user, ""))
+
+	user.Spec.Username = "bo`b"
+	user.Spec.Target.Cluster = ""
+	user.Spec.Profile = "read'only"
+	user.Spec.Networks = &api.ClickHouseUserNetworks{
+		IP:         []string{"10.0.0.0/8"},
+		HostRegexp: []string{`^chi-.*\.svc$`},
+	}
+	require.Equal(t, []string{
+		"CREATE USER IF NOT EXISTS `bo\\`b` ON CLUSTER '" + OneShardAllReplicasClusterName + "'",
+		"ALTER USER `bo\\`b` ON CLUSTER '" + OneShardAllReplicasClusterName + "' IDENTIFIED WITH sha256_hash BY 'abc' " +
+			`HOST IP '10.0.0.0/8', REGEXP '^chi-.*\\.svc$' SETTINGS PROFILE 'read\'only'`,
+	}, CreateUserSQLs(user, "abc"))
+}
+
+func TestCreateUserGrantAndRevokeSQLs(t *testing.T) {
+	user := newTestUser()
+	grants := []string{"select on db.*", "INSERT(a) ON db.t"}
+	roles := []string{"reader"}
+
+	sqls, err := CreateUserGrantSQLs(user, grants, roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"GRANT ON CLUSTER 'main' SELECT ON `db`.* TO `alice`",
+		"GRANT ON CLUSTER 'main' INSERT(`a`) ON `db`.`t` TO `alice`",
+		"GRANT ON CLUSTER 'main' `reader` TO `alice`",
+	}, sqls)
+
+	sqls, err = CreateUserRevokeSQLs(user, grants, roles)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"REVOKE ON CLUSTER 'main' SELECT ON `db`.* FROM `alice`",
+		"REVOKE ON CLUSTER 'main' INSERT(`a`) ON `db`.`t` FROM `alice`",
+		"REVOKE ON CLUSTER 'main' `reader` FROM `alice`",
+	}, sqls)
+
+	_, err = CreateUserGrantSQLs(user, []string{"SELECT ON db.t TO admin"}, nil)
+	require.Error(t, err)
+	_, err = CreateUserRevokeSQLs(user, []string{"ALL ON *.*; DROP USER admin"}, nil)
+	require.Error(t, err)
+}
+
+func TestCreateUserGrantQueries(t *testing.T) {
+	user := newTestUser()
+	user.Spec.Grants = []string{"SELECT ON db.*"}
+	user.Spec.Roles = []string{"reader"}
+	queries, err := CreateUserGrantQueries(user)
+	require.NoError(t, err)
+	require.Equal(t, []string{"GRANT SELECT ON `db`.*", "GRANT `reader`"}, queries)
+
+	user.Spec.Grants = []string{"SELECT ON db.* TO admin"}
+	_, err = CreateUserGrantQueries(user)
+	require.Error(t, err)
+}
+
+func TestCreateUserDropSQLs(t *testing.T) {
+	require.Equal(t, []string{"DROP USER IF EXISTS `alice` ON CLUSTER 'main'"}, CreateUserDropSQLs(newTestUser()))
+}
+
+func TestValidateUser(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  api.ClickHouseUserMode
+		quota string
+		valid bool
+	}{
+		{name: "config mode", mode: api.ClickHouseUserModeConfig, valid: true},
+		{name: "config mode with quota", mode: api.ClickHouseUserModeConfig, quota: "default", valid: true},
+		{name: "sql mode", mode: api.ClickHouseUserModeSQL, valid: true},
+		{name: "sql mode with quota", mode: api.ClickHouseUserModeSQL, quota: "default", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser()
+			user.Spec.Mode = tt.mode
+			user.Spec.Quota = tt.quota
+			if tt.valid {
+				require.NoError(t, ValidateUser(user))
+			} else {
+				require.Error(t, ValidateUser(user))
+			}
+		})
+	}
+}
+
+func TestGetProvisionedTarget(t *testing.T) {
+	user := newTestUser()
+	// Nothing is recorded yet
+	require.Equal(t, user.Spec.Target, user.GetProvisionedTarget())
+
+	user.EnsureStatus().Target = &api.ClickHouseUserTarget{CHI: "old", Cluster: "old"}
+	require.Equal(t, api.ClickHouseUserTarget{CHI: "old", Cluster: "old"}, user.GetProvisionedTarget())
+	require.NotEqual(t, user.Spec.Target, user.GetProvisionedTarget())
+
+	// Provisioned user is dropped from the recorded target
+	provisioned := user.DeepCopy()
+	provisioned.Spec.Target = user.GetProvisionedTarget()
+	require.Equal(t, []string{"DROP USER IF EXISTS `alice` ON CLUSTER 'old'"}, CreateUserDropSQLs(provisioned))
+}
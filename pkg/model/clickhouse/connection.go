@@ -20,12 +20,17 @@ import (
 	"crypto/x509"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	// go-clickhouse is explicitly required in order to setup connection to clickhouse db
 	//goch "github.com/mailru/go-clickhouse"
 	goch "github.com/mailru/go-clickhouse/v2"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 
 	log "github.com/minorhacks/clickhouse-operator/pkg/announcer"
+	"github.com/minorhacks/clickhouse-operator/pkg/metrics"
 	"github.com/minorhacks/clickhouse-operator/pkg/util"
 )
 
@@ -119,12 +124,46 @@ func (c *Connection) ensureConnected(ctx context.Context) bool {
 	return c.db != nil
 }
 
+// startSpan starts span of the SQL statement.
+// Statement is exported on demand only and has string literals redacted, since they may carry passwords and other secrets
+func (c *Connection) startSpan(ctx context.Context, name, sql string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemClickhouse,
+		semconv.ServerAddress(c.params.hostname),
+		semconv.ServerPort(c.params.port),
+	}
+	if metrics.IsStatementTracingEnabled() {
+		attrs = append(attrs, semconv.DBStatement(redactSQL(sql)))
+	}
+	return metrics.StartSpan(c.ensureCtx(ctx), name, attrs...)
+}
+
+// redactSQL replaces string literals of the SQL statement with '?'
+func redactSQL(sql string) string {
+	// Unterminated literal is returned as the last token, thus it is redacted as well
+	tokens, _ := util.TokenizeSQL(sql)
+	var b strings.Builder
+	for i := range tokens {
+		if tokens[i].Kind == util.SQLTokenString {
+			b.WriteString("'?'")
+		} else {
+			b.WriteString(tokens[i].Value)
+		}
+	}
+	return b.String()
+}
+
 // QueryContext runs given sql query on behalf of specified context
-func (c *Connection) QueryContext(ctx context.Context, sql string) (*QueryResult, error) {
+func (c *Connection) QueryContext(ctx context.Context, sql string) (_ *QueryResult, err error) {
 	if len(sql) == 0 {
 		return nil, nil
 	}
 
+	ctx, span := c.startSpan(ctx, "clickhouse.Query", sql)
+	defer func() {
+		metrics.EndSpan(span, err)
+	}()
+
 	if !c.ensureConnected(ctx) {
 		s := fmt.Sprintf("FAILED connect(%s) for SQL: %s", c.params.GetDSNWithHiddenCredentials(), sql)
 		c.l.V(1).F().Error(s)
@@ -172,11 +211,16 @@ func (c *Connection) ctx(ctx context.Context, opts *QueryOptions) (context.Conte
 }
 
 // Exec runs given sql query
-func (c *Connection) Exec(_ctx context.Context, sql string, opts *QueryOptions) error {
+func (c *Connection) Exec(_ctx context.Context, sql string, opts *QueryOptions) (err error) {
 	if len(sql) == 0 {
 		return nil
 	}
 
+	_ctx, span := c.startSpan(_ctx, "clickhouse.Exec", sql)
+	defer func() {
+		metrics.EndSpan(span, err)
+	}()
+
 	ctx, cancel := c.ctx(_ctx, opts)
 	defer cancel()
 
@@ -187,7 +231,7 @@ func (c *Connection) Exec(_ctx context.Context, sql string, opts *QueryOptions)
 		return fmt.Errorf(s)
 	}
 
-	_, err := c.db.ExecContext(ctx, sql)
+	_, err = c.db.ExecContext(ctx, sql)
 
 	if err != nil {
 		cancel()
@@ -57,6 +57,19 @@ func (p *ClusterConnectionParams) SetTimeouts(timeouts *Timeouts) *ClusterConnec
 	return p
 }
 
+// SetCredentials sets username and password. Empty username means keep current credentials
+func (p *ClusterConnectionParams) SetCredentials(username, password string) *ClusterConnectionParams {
+	if p == nil {
+		return nil
+	}
+	if username == "" {
+		return p
+	}
+	p.Username = username
+	p.Password = password
+	return p
+}
+
 // NewEndpointConnectionParams creates endpoint connection params for a specified host in the cluster
 func (p *ClusterConnectionParams) NewEndpointConnectionParams(host string) *EndpointConnectionParams {
 	if p == nil {
@@ -0,0 +1,28 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactSQL(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"ALTER USER `u` IDENTIFIED WITH sha256_hash BY 'abc' HOST ANY", "ALTER USER `u` IDENTIFIED WITH sha256_hash BY '?' HOST ANY"},
+		{`SELECT 'it\'s', 'it''s', "col"`, `SELECT '?', '?', "col"`},
+		{`SELECT 'a\\', 'b'`, `SELECT '?', '?'`},
+		{"SELECT ''", "SELECT '?'"},
+		{"SELECT 'unterminated", "SELECT '?'"},
+		{"SELECT 1 -- it's a comment", "SELECT 1 -- it's a comment"},
+		{"SELECT /* 'a' */ 'b'", "SELECT /* 'a' */ '?'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			require.Equal(t, tt.expected, redactSQL(tt.sql))
+		})
+	}
+}
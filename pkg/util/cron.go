@@ -0,0 +1,196 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Timezones of schedules have to be resolvable in minimal images as well
+	_ "time/tzdata"
+)
+
+// CronSchedule specifies parsed schedule in standard 5-fields cron format:
+// minute hour day-of-month month day-of-week
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domStar and dowStar specify whether day-of-month and day-of-week are not restricted,
+	// since in case both are restricted a day matches either of them
+	domStar bool
+	dowStar bool
+}
+
+// cronField specifies bounds of a cron field
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	cronMinute = cronField{"minute", 0, 59}
+	cronHour   = cronField{"hour", 0, 23}
+	cronDom    = cronField{"day-of-month", 1, 31}
+	cronMonth  = cronField{"month", 1, 12}
+	cronDow    = cronField{"day-of-week", 0, 7}
+)
+
+// cronSearchLimit specifies how far in the future the next matching time is searched for
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// ParseCronSchedule parses schedule in standard 5-fields cron format.
+// Each field is either "*" or a comma-separated list of values, ranges "a-b" and steps "*/n" or "a-b/n"
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule '%s' has to have 5 fields, has %d", spec, len(fields))
+	}
+
+	var err error
+	s := &CronSchedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	if s.minute, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseCronField(fields[2], cronDom); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseCronField(fields[4], cronDow); err != nil {
+		return nil, err
+	}
+	// Both 0 and 7 stand for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses cron field into bit set of allowed values
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if before, after, found := strings.Cut(item, "/"); found {
+			rng = before
+			n, err := strconv.Atoi(after)
+			if (err != nil) || (n <= 0) {
+				return 0, fmt.Errorf("bad step '%s' in %s field '%s'", after, bounds.name, field)
+			}
+			step = n
+		}
+
+		from, to := bounds.min, bounds.max
+		if rng != "*" {
+			before, after, found := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(before); err != nil {
+				return 0, fmt.Errorf("bad value '%s' in %s field '%s'", before, bounds.name, field)
+			}
+			to = from
+			if found {
+				if to, err = strconv.Atoi(after); err != nil {
+					return 0, fmt.Errorf("bad value '%s' in %s field '%s'", after, bounds.name, field)
+				}
+			} else if step > 1 {
+				// "a/n" means "a-max/n"
+				to = bounds.max
+			}
+		}
+		if (from < bounds.min) || (to > bounds.max) || (from > to) {
+			return 0, fmt.Errorf("%s field '%s' is out of range %d-%d", bounds.name, field, bounds.min, bounds.max)
+		}
+
+		for i := from; i <= to; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+// Next finds the earliest time strictly after t, which matches the schedule, in location of t.
+// Wall clock time skipped by the transition to daylight saving time is matched by the first minute after the transition.
+// Wall clock time repeated by the transition from daylight saving time is matched once, unless the search starts within it.
+// Returns zero time in case schedule never matches
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.matchSkippedHour(t) {
+			return t
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay checks whether day of t matches day-of-month and day-of-week fields
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// matchSkippedHour checks whether t is the first minute after the transition to daylight saving time,
+// which has skipped an hour matching the schedule
+func (s *CronSchedule) matchSkippedHour(t time.Time) bool {
+	if t.Minute() != 0 {
+		return false
+	}
+	from := 0
+	if prev := t.Add(-time.Minute); prev.Day() == t.Day() {
+		from = prev.Hour() + 1
+	}
+	for hour := from; hour < t.Hour(); hour++ {
+		if s.hour&(1<<uint(hour)) != 0 {
+			return true
+		}
+	}
+	return false
+}
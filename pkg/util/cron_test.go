@@ -0,0 +1,315 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		err    bool
+		minute []int
+		hour   []int
+		dom    []int
+		dow    []int
+		// domStar and dowStar specify whether day fields are expected to be unrestricted
+		domStar bool
+		dowStar bool
+	}{
+		{
+			name:    "all stars",
+			spec:    "* * * * *",
+			dom:     []int{1, 15, 31},
+			dow:     []int{0, 3, 6},
+			domStar: true,
+			dowStar: true,
+		},
+		{
+			name:   "values and lists",
+			spec:   "0,30 2 1,15 * 1",
+			minute: []int{0, 30},
+			hour:   []int{2},
+			dom:    []int{1, 15},
+			dow:    []int{1},
+		},
+		{
+			name:    "ranges",
+			spec:    "10-12 22-23 * * 1-5",
+			minute:  []int{10, 11, 12},
+			hour:    []int{22, 23},
+			dow:     []int{1, 2, 3, 4, 5},
+			domStar: true,
+		},
+		{
+			name:    "steps",
+			spec:    "*/15 1-7/3 10/10 * *",
+			minute:  []int{0, 15, 30, 45},
+			hour:    []int{1, 4, 7},
+			dom:     []int{10, 20, 30},
+			dowStar: true,
+		},
+		{
+			name:    "sunday as 7",
+			spec:    "0 0 * * 7",
+			dow:     []int{0, 7},
+			domStar: true,
+		},
+		{
+			name:    "extra whitespace",
+			spec:    "  0   0 * *  0 ",
+			dow:     []int{0},
+			domStar: true,
+		},
+		{name: "too few fields", spec: "0 0 * *", err: true},
+		{name: "too many fields", spec: "0 0 * * * *", err: true},
+		{name: "minute out of range", spec: "60 0 * * *", err: true},
+		{name: "hour out of range", spec: "0 24 * * *", err: true},
+		{name: "day-of-month zero", spec: "0 0 0 * *", err: true},
+		{name: "month out of range", spec: "0 0 * 13 *", err: true},
+		{name: "day-of-week out of range", spec: "0 0 * * 8", err: true},
+		{name: "reversed range", spec: "0 5-1 * * *", err: true},
+		{name: "zero step", spec: "*/0 * * * *", err: true},
+		{name: "bad step", spec: "*/x * * * *", err: true},
+		{name: "names are not supported", spec: "0 0 * JAN MON", err: true},
+		{name: "empty list item", spec: "0, 0 * * *", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseCronSchedule(tt.spec)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.domStar, s.domStar)
+			require.Equal(t, tt.dowStar, s.dowStar)
+			for _, v := range tt.minute {
+				require.NotZero(t, s.minute&(1<<uint(v)), "minute %d", v)
+			}
+			for _, v := range tt.hour {
+				require.NotZero(t, s.hour&(1<<uint(v)), "hour %d", v)
+			}
+			for _, v := range tt.dom {
+				require.NotZero(t, s.dom&(1<<uint(v)), "day-of-month %d", v)
+			}
+			for _, v := range tt.dow {
+				require.NotZero(t, s.dow&(1<<uint(v)), "day-of-week %d", v)
+			}
+			// Listed minutes and hours are the only ones allowed
+			if tt.minute != nil {
+				require.Equal(t, len(tt.minute), bitsCount(s.minute))
+			}
+			if tt.hour != nil {
+				require.Equal(t, len(tt.hour), bitsCount(s.hour))
+			}
+		})
+	}
+}
+
+func bitsCount(bits uint64) int {
+	n := 0
+	for ; bits != 0; bits &= bits - 1 {
+		n++
+	}
+	return n
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	utc := time.UTC
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		next []time.Time
+	}{
+		{
+			name: "every minute is strictly after",
+			spec: "* * * * *",
+			from: time.Date(2024, 1, 1, 10, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 1, 10, 1, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 2, 0, 0, utc),
+			},
+		},
+		{
+			name: "seconds are truncated",
+			spec: "* * * * *",
+			from: time.Date(2024, 1, 1, 10, 0, 59, 999, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 1, 10, 1, 0, 0, utc),
+			},
+		},
+		{
+			name: "daily rolls over to the next day",
+			spec: "30 2 * * *",
+			from: time.Date(2024, 1, 1, 3, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 2, 2, 30, 0, 0, utc),
+				time.Date(2024, 1, 3, 2, 30, 0, 0, utc),
+			},
+		},
+		{
+			name: "steps of hours",
+			spec: "0 */8 * * *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 1, 8, 0, 0, 0, utc),
+				time.Date(2024, 1, 1, 16, 0, 0, 0, utc),
+				time.Date(2024, 1, 2, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "working days only",
+			spec: "0 22 * * 1-5",
+			// Friday
+			from: time.Date(2024, 1, 5, 23, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 8, 22, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "day-of-month restricted only",
+			spec: "0 0 13 * *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 13, 0, 0, 0, 0, utc),
+				time.Date(2024, 2, 13, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "day-of-week restricted only",
+			spec: "0 0 * * 5",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "both days restricted match either of them",
+			spec: "0 0 13 * 5",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				// Friday
+				time.Date(2024, 1, 5, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, utc),
+				// 13th, Saturday
+				time.Date(2024, 1, 13, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 19, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "months and leap day",
+			spec: "0 0 29 2 *",
+			from: time.Date(2023, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, utc),
+				time.Date(2028, 2, 29, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "never matching",
+			spec: "0 0 30 2 *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, utc),
+			next: []time.Time{
+				{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseCronSchedule(tt.spec)
+			require.NoError(t, err)
+			cur := tt.from
+			for _, expected := range tt.next {
+				cur = s.Next(cur)
+				require.True(t, expected.Equal(cur), "expected %s got %s", expected, cur)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	s, err := ParseCronSchedule("0 9 * * *")
+	require.NoError(t, err)
+
+	// Schedule is evaluated in location of the time, not in UTC
+	next := s.Next(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC).Equal(next))
+	next = s.Next(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).In(loc))
+	require.True(t, time.Date(2024, 1, 1, 9, 0, 0, 0, loc).Equal(next))
+	require.Equal(t, loc, next.Location())
+}
+
+func TestCronScheduleNextDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	cet := time.FixedZone("CET", 3600)
+	cest := time.FixedZone("CEST", 2*3600)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		next []time.Time
+	}{
+		{
+			name: "hour skipped by the transition to daylight saving time matches the first minute after it",
+			spec: "30 2 * * *",
+			from: time.Date(2024, 3, 31, 0, 0, 0, 0, loc),
+			next: []time.Time{
+				time.Date(2024, 3, 31, 3, 0, 0, 0, cest),
+				time.Date(2024, 4, 1, 2, 30, 0, 0, cest),
+			},
+		},
+		{
+			name: "hours around the skipped one are not affected",
+			spec: "0 * * * *",
+			from: time.Date(2024, 3, 31, 0, 30, 0, 0, loc),
+			next: []time.Time{
+				time.Date(2024, 3, 31, 1, 0, 0, 0, cet),
+				time.Date(2024, 3, 31, 3, 0, 0, 0, cest),
+				time.Date(2024, 3, 31, 4, 0, 0, 0, cest),
+			},
+		},
+		{
+			name: "hour repeated by the transition from daylight saving time matches once",
+			spec: "30 2 * * *",
+			from: time.Date(2024, 10, 27, 0, 0, 0, 0, loc),
+			next: []time.Time{
+				time.Date(2024, 10, 27, 2, 30, 0, 0, cet),
+				time.Date(2024, 10, 28, 2, 30, 0, 0, cet),
+			},
+		},
+		{
+			name: "every hour matches both instances of the repeated hour",
+			spec: "0 * * * *",
+			from: time.Date(2024, 10, 27, 1, 30, 0, 0, cest),
+			next: []time.Time{
+				time.Date(2024, 10, 27, 2, 0, 0, 0, cest),
+				time.Date(2024, 10, 27, 2, 0, 0, 0, cet),
+				time.Date(2024, 10, 27, 3, 0, 0, 0, cet),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseCronSchedule(tt.spec)
+			require.NoError(t, err)
+			cur := tt.from.In(loc)
+			for _, expected := range tt.next {
+				cur = s.Next(cur)
+				require.True(t, expected.Equal(cur), "expected %s got %s", expected, cur)
+			}
+		})
+	}
+}
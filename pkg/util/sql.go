@@ -0,0 +1,149 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QuoteSQLIdentifier quotes identifier, such as user or table name, to be used in SQL
+func QuoteSQLIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(strings.ReplaceAll(identifier, `\`, `\\`), "`", "\\`") + "`"
+}
+
+// QuoteSQLString quotes string literal to be used in SQL
+func QuoteSQLString(str string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(str, `\`, `\\`), "'", `\'`) + "'"
+}
+
+// SQLTokenKind specifies kind of the SQL token
+type SQLTokenKind int
+
+const (
+	// SQLTokenWord is a keyword, bare identifier or number
+	SQLTokenWord SQLTokenKind = iota
+	// SQLTokenQuoted is an identifier quoted with double quotes or backquotes
+	SQLTokenQuoted
+	// SQLTokenString is a string literal
+	SQLTokenString
+	// SQLTokenPunct is any other character
+	SQLTokenPunct
+	// SQLTokenComment is either '--' or '/* */' comment
+	SQLTokenComment
+	// SQLTokenSpace is a whitespace
+	SQLTokenSpace
+)
+
+// SQLToken specifies one token of SQL script along with its position in the script
+type SQLToken struct {
+	Kind  SQLTokenKind
+	Value string
+	Start int
+	End   int
+}
+
+// IsWord checks whether token is the specified keyword
+func (t *SQLToken) IsWord(word string) bool {
+	return (t != nil) && (t.Kind == SQLTokenWord) && strings.EqualFold(t.Value, word)
+}
+
+// IsPunct checks whether token is the specified punctuation
+func (t *SQLToken) IsPunct(punct string) bool {
+	return (t != nil) && (t.Kind == SQLTokenPunct) && (t.Value == punct)
+}
+
+// IsIdentifier checks whether token is either bare or quoted identifier
+func (t *SQLToken) IsIdentifier() bool {
+	return (t != nil) && ((t.Kind == SQLTokenWord) || (t.Kind == SQLTokenQuoted))
+}
+
+// IsSignificant checks whether token matters for statement structure
+func (t *SQLToken) IsSignificant() bool {
+	return (t.Kind != SQLTokenComment) && (t.Kind != SQLTokenSpace)
+}
+
+// TokenizeSQL splits SQL script into tokens. Quotes within literals are escaped either with backslash or by doubling
+// In case of unterminated literal or comment, tokens preceding it are returned along with the rest of the script as the last token
+func TokenizeSQL(script string) (tokens []SQLToken, err error) {
+	for i := 0; i < len(script); {
+		start := i
+		kind := SQLTokenPunct
+		c := script[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			kind = SQLTokenSpace
+			for i < len(script) && strings.IndexByte(" \t\n\r\f\v", script[i]) >= 0 {
+				i++
+			}
+		case strings.HasPrefix(script[i:], "--"):
+			kind = SQLTokenComment
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case strings.HasPrefix(script[i:], "/*"):
+			kind = SQLTokenComment
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				tokens = append(tokens, SQLToken{Kind: kind, Value: script[start:], Start: start, End: len(script)})
+				return tokens, fmt.Errorf("unterminated comment at offset %d", start)
+			}
+			i += 2 + end + 2
+		case c == '\'' || c == '"' || c == '`':
+			kind = SQLTokenQuoted
+			if c == '\'' {
+				kind = SQLTokenString
+			}
+			if i, err = skipSQLQuoted(script, i); err != nil {
+				tokens = append(tokens, SQLToken{Kind: kind, Value: script[start:], Start: start, End: len(script)})
+				return tokens, err
+			}
+		case isSQLWordPart(c):
+			kind = SQLTokenWord
+			for i < len(script) && isSQLWordPart(script[i]) {
+				i++
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, SQLToken{Kind: kind, Value: script[start:i], Start: start, End: i})
+	}
+	return tokens, nil
+}
+
+// skipSQLQuoted skips quoted literal starting at the specified position. Returns position right after the literal
+func skipSQLQuoted(script string, start int) (int, error) {
+	quote := script[start]
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			i++
+		case quote:
+			if (i+1 < len(script)) && (script[i+1] == quote) {
+				// Doubled quote
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated literal %c at offset %d", quote, start)
+}
+
+func isSQLWordPart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || (c == '_') || (c == '$') || (c >= 0x80)
+}
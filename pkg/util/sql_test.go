@@ -0,0 +1,44 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteSQL(t *testing.T) {
+	require.Equal(t, "`user`", QuoteSQLIdentifier("user"))
+	require.Equal(t, "`us\\`er`", QuoteSQLIdentifier("us`er"))
+	require.Equal(t, "`us\\\\er`", QuoteSQLIdentifier(`us\er`))
+	require.Equal(t, "'value'", QuoteSQLString("value"))
+	require.Equal(t, `'it\'s'`, QuoteSQLString("it's"))
+	require.Equal(t, `'a\\b'`, QuoteSQLString(`a\b`))
+	require.Equal(t, `'a\\\''`, QuoteSQLString(`a\'`))
+}
+
+func TestTokenizeSQL(t *testing.T) {
+	tokens, err := TokenizeSQL("SELECT `a b`, 'it''s' -- c\n/* d */;")
+	require.NoError(t, err)
+	var kinds []SQLTokenKind
+	var values []string
+	for _, token := range tokens {
+		kinds = append(kinds, token.Kind)
+		values = append(values, token.Value)
+	}
+	require.Equal(t, []SQLTokenKind{
+		SQLTokenWord, SQLTokenSpace, SQLTokenQuoted, SQLTokenPunct, SQLTokenSpace, SQLTokenString,
+		SQLTokenSpace, SQLTokenComment, SQLTokenSpace, SQLTokenComment, SQLTokenPunct,
+	}, kinds)
+	require.Equal(t, []string{"SELECT", " ", "`a b`", ",", " ", "'it''s'", " ", "-- c", "\n", "/* d */", ";"}, values)
+	require.True(t, tokens[0].IsWord("select"))
+	require.True(t, tokens[2].IsIdentifier())
+	require.False(t, tokens[7].IsSignificant())
+
+	// Unterminated literal is returned as the last token along with the error
+	for _, script := range []string{"SELECT 'a", "SELECT /* a"} {
+		tokens, err = TokenizeSQL(script)
+		require.Error(t, err)
+		require.Equal(t, 3, len(tokens))
+		require.Equal(t, script[7:], tokens[2].Value)
+	}
+}
@@ -15,6 +15,8 @@
 package util
 
 import (
+	cryptoRand "crypto/rand"
+	"math/big"
 	// #nosec
 	// G505 (CWE-327): Blocklisted import crypto/sha1: weak cryptographic primitive
 	// It is good enough for string ID
@@ -45,6 +47,25 @@ func RandStringRange(minLength, maxLength int) string {
 	return RandString(rand.Intn(maxLength-minLength+1) + minLength)
 }
 
+// randStringSecureBytes specifies bytes that could be used by RandStringSecure generator
+const randStringSecureBytes = randStringBytes + "0123456789"
+
+// RandStringSecure generates cryptographically strong random string of specified length.
+// Suitable for passwords and other secrets
+func RandStringSecure(length int) string {
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(randStringSecureBytes)))
+	for i := range b {
+		n, err := cryptoRand.Int(cryptoRand.Reader, max)
+		if err != nil {
+			// Entropy source is not available, nothing reasonable can be done
+			panic(err)
+		}
+		b[i] = randStringSecureBytes[n.Int64()]
+	}
+	return string(b)
+}
+
 // CreateStringID creates HEX hash ID out of a string.
 // In case maxHashLen == 0 the whole hash is returned
 func CreateStringID(str string, maxHashLen int) string {
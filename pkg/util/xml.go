@@ -0,0 +1,36 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+)
+
+// xmlTagName specifies names, which are accepted as XML tag names in generated configs
+var xmlTagName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+
+// IsXMLTagName checks whether name can be used as a name of the XML tag as-is
+func IsXMLTagName(name string) bool {
+	return xmlTagName.MatchString(name)
+}
+
+// EscapeXML escapes text to be used as content or attribute value of the XML tag
+func EscapeXML(text string) string {
+	b := &bytes.Buffer{}
+	_ = xml.EscapeText(b, []byte(text))
+	return b.String()
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	stdxml "encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node is an element of the parsed XML document
+type Node struct {
+	Name       string
+	Attributes []Attribute
+	Text       string
+	Children   []*Node
+}
+
+// Attribute is an attribute of the XML element
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Parse parses XML document into the tree of elements and returns root element of the document.
+// Comments and processing instructions are skipped
+func Parse(r io.Reader) (*Node, error) {
+	decoder := stdxml.NewDecoder(r)
+
+	var root *Node
+	var stack []*Node
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case stdxml.StartElement:
+			node := &Node{
+				Name: t.Name.Local,
+			}
+			for _, attr := range t.Attr {
+				node.Attributes = append(node.Attributes, Attribute{
+					Name:  attr.Name.Local,
+					Value: attr.Value,
+				})
+			}
+			switch {
+			case len(stack) > 0:
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			case root == nil:
+				root = node
+			default:
+				return nil, fmt.Errorf("multiple root elements: %s and %s", root.Name, node.Name)
+			}
+			stack = append(stack, node)
+		case stdxml.EndElement:
+			stack = stack[:len(stack)-1]
+		case stdxml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	root.trimText()
+	return root, nil
+}
+
+// trimText drops whitespaces, which indent nested elements
+func (n *Node) trimText() {
+	if (len(n.Children) > 0) || (strings.TrimSpace(n.Text) == "") {
+		n.Text = strings.TrimSpace(n.Text)
+	}
+	for _, child := range n.Children {
+		child.trimText()
+	}
+}
+
+// GetAttribute gets value of the attribute and whether the attribute is specified
+func (n *Node) GetAttribute(name string) (string, bool) {
+	for _, attr := range n.Attributes {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// GroupChildren groups children by names in order of the first appearance of the name.
+// Children having the same name keep their order
+func (n *Node) GroupChildren() (names []string, groups map[string][]*Node) {
+	groups = make(map[string][]*Node)
+	for _, child := range n.Children {
+		if _, ok := groups[child.Name]; !ok {
+			names = append(names, child.Name)
+		}
+		groups[child.Name] = append(groups[child.Name], child)
+	}
+	return names, groups
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// yamlAttributePrefix prefixes names of the keys, which are attributes of the element in ClickHouse YAML config
+	yamlAttributePrefix = "@"
+	// yamlTextKey specifies key of the text of the element, which has attributes, in ClickHouse YAML config
+	yamlTextKey = "#text"
+)
+
+// ConvertToYAML converts XML config into semantically equivalent ClickHouse YAML config.
+// Root element (<yandex> or <clickhouse>) is implicit in YAML config, thus it is skipped.
+// Attributes are represented as keys prefixed with '@', text of the element having attributes is represented as '#text' key,
+// repeated elements are represented as a sequence
+func ConvertToYAML(xmlConfig string) (string, error) {
+	root, err := Parse(strings.NewReader(xmlConfig))
+	if err != nil {
+		return "", err
+	}
+
+	doc := root.buildYAML()
+	if doc.Kind != yaml.MappingNode {
+		// Root has no nested elements
+		doc = &yaml.Node{
+			Kind: yaml.MappingNode,
+		}
+	}
+
+	b := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// buildYAML builds YAML node out of the XML element
+func (n *Node) buildYAML() *yaml.Node {
+	if (len(n.Attributes) == 0) && (len(n.Children) == 0) {
+		return newYAMLScalar(n.Text)
+	}
+
+	mapping := &yaml.Node{
+		Kind: yaml.MappingNode,
+	}
+	for _, attr := range n.Attributes {
+		mapping.Content = append(mapping.Content, newYAMLScalar(yamlAttributePrefix+attr.Name), newYAMLScalar(attr.Value))
+	}
+	if n.Text != "" {
+		mapping.Content = append(mapping.Content, newYAMLScalar(yamlTextKey), newYAMLScalar(n.Text))
+	}
+
+	names, groups := n.GroupChildren()
+	for _, name := range names {
+		group := groups[name]
+		if len(group) == 1 {
+			mapping.Content = append(mapping.Content, newYAMLScalar(name), group[0].buildYAML())
+			continue
+		}
+		sequence := &yaml.Node{
+			Kind: yaml.SequenceNode,
+		}
+		for _, child := range group {
+			sequence.Content = append(sequence.Content, child.buildYAML())
+		}
+		mapping.Content = append(mapping.Content, newYAMLScalar(name), sequence)
+	}
+	return mapping
+}
+
+// newYAMLScalar creates YAML string scalar
+func newYAMLScalar(value string) *yaml.Node {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: value,
+	}
+}
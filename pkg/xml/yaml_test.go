@@ -0,0 +1,188 @@
+package xml
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	api "github.com/minorhacks/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+)
+
+// parseYAML parses ClickHouse YAML config into the tree of elements, the same way ClickHouse does.
+// Root element is implicit in YAML config, thus root is named after the provided name
+func parseYAML(t *testing.T, yamlConfig, rootName string) *Node {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), doc); err != nil {
+		t.Fatalf("unable to parse YAML: %v\n%s", err, yamlConfig)
+	}
+	root := &Node{
+		Name: rootName,
+	}
+	if len(doc.Content) > 0 {
+		fillFromYAML(t, root, doc.Content[0])
+	}
+	return root
+}
+
+// fillFromYAML fills element with the content of the YAML node
+func fillFromYAML(t *testing.T, node *Node, value *yaml.Node) {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		node.Text = value.Value
+	case yaml.MappingNode:
+		for i := 0; i < len(value.Content); i += 2 {
+			key, val := value.Content[i].Value, value.Content[i+1]
+			switch {
+			case strings.HasPrefix(key, yamlAttributePrefix):
+				node.Attributes = append(node.Attributes, Attribute{
+					Name:  strings.TrimPrefix(key, yamlAttributePrefix),
+					Value: val.Value,
+				})
+			case key == yamlTextKey:
+				node.Text = val.Value
+			case val.Kind == yaml.SequenceNode:
+				for _, item := range val.Content {
+					child := &Node{Name: key}
+					fillFromYAML(t, child, item)
+					node.Children = append(node.Children, child)
+				}
+			default:
+				child := &Node{Name: key}
+				fillFromYAML(t, child, val)
+				node.Children = append(node.Children, child)
+			}
+		}
+	default:
+		t.Fatalf("unexpected YAML node kind: %v", value.Kind)
+	}
+}
+
+// canonical builds canonical representation of the tree of elements.
+// Order of elements having different names does not matter, while order of elements having the same name does
+func canonical(n *Node) string {
+	b := &bytes.Buffer{}
+	n.writeCanonical(b, 0)
+	return b.String()
+}
+
+func (n *Node) writeCanonical(b *bytes.Buffer, indent int) {
+	attributes := make([]string, 0, len(n.Attributes))
+	for _, attr := range n.Attributes {
+		attributes = append(attributes, fmt.Sprintf("%s=%q", attr.Name, attr.Value))
+	}
+	sort.Strings(attributes)
+	_, _ = fmt.Fprintf(b, "%s%s %v %q\n", strings.Repeat(" ", indent), n.Name, attributes, n.Text)
+
+	names, groups := n.GroupChildren()
+	sort.Strings(names)
+	for _, name := range names {
+		for _, child := range groups[name] {
+			child.writeCanonical(b, indent+2)
+		}
+	}
+}
+
+// requireEquivalent checks XML config and its YAML rendering are semantically equivalent
+func requireEquivalent(t *testing.T, xmlConfig string) {
+	xmlTree, err := Parse(strings.NewReader(xmlConfig))
+	if err != nil {
+		t.Fatalf("unable to parse XML: %v\n%s", err, xmlConfig)
+	}
+	yamlConfig, err := ConvertToYAML(xmlConfig)
+	if err != nil {
+		t.Fatalf("unable to convert XML: %v\n%s", err, xmlConfig)
+	}
+	yamlTree := parseYAML(t, yamlConfig, xmlTree.Name)
+
+	if expected, actual := canonical(xmlTree), canonical(yamlTree); expected != actual {
+		t.Fatalf("XML and YAML configs differ\nXML:\n%s\nYAML:\n%s\nXML tree:\n%s\nYAML tree:\n%s", xmlConfig, yamlConfig, expected, actual)
+	}
+}
+
+func TestConvertToYAMLFromSettings(t *testing.T) {
+	settings := api.NewSettings()
+	settings.Set("compression/case/method", api.NewSettingScalar("zstd"))
+	settings.Set("compression/case/min_part_size", api.NewSettingScalar("10000000000"))
+	settings.Set("logger/level", api.NewSettingScalar("debug"))
+	settings.Set("listen_host", api.NewSettingVector([]string{"::", "0.0.0.0"}))
+	settings.Set("s3/bucket/access_key_id", api.NewSettingScalar("").SetAttribute("from_env", "ACCESS_KEY_ID"))
+	settings.Set("macros/replica", api.NewSettingScalar("_removed_"))
+	settings.Set("display_name", api.NewSettingScalar("true"))
+	settings.Set("query_log/partition_by", api.NewSettingScalar("event_date: toYYYYMM(event_date)"))
+
+	for _, prefix := range []string{"", "profiles"} {
+		b := &bytes.Buffer{}
+		b.WriteString("<yandex>\n")
+		GenerateFromSettings(b, settings, prefix)
+		b.WriteString("</yandex>\n")
+		requireEquivalent(t, b.String())
+	}
+}
+
+func TestConvertToYAMLRemoteServers(t *testing.T) {
+	requireEquivalent(t, `<yandex>
+    <remote_servers>
+        <!-- User-specified clusters -->
+        <cluster>
+            <secret from_env="CLICKHOUSE_INTERNODE_CLUSTER_SECRET" />
+            <shard>
+                <internal_replication>True</internal_replication>
+                <replica>
+                    <host>chi-a-cluster-0-0</host>
+                    <port>9000</port>
+                    <secure>0</secure>
+                </replica>
+                <replica>
+                    <host>chi-a-cluster-0-1</host>
+                    <port>9000</port>
+                    <secure>0</secure>
+                </replica>
+            </shard>
+            <shard>
+                <internal_replication>True</internal_replication>
+                <replica>
+                    <host>chi-a-cluster-1-0</host>
+                    <port>9000</port>
+                    <secure>0</secure>
+                </replica>
+            </shard>
+        </cluster>
+    </remote_servers>
+    <profiles replace="replace">
+        <default>
+            <readonly>1</readonly>
+        </default>
+    </profiles>
+    <users>
+        <admin>
+            <networks>
+                <ip>::1</ip>
+                <ip>127.0.0.1</ip>
+                <host_regexp>^chi-a-.*$</host_regexp>
+            </networks>
+            <password_sha256_hex>716b36073a90c6fe1d445ac1af85f4777c5b7a155cea359961826a030513e448</password_sha256_hex>
+        </admin>
+    </users>
+    <macros>
+        <installation>a</installation>
+        <multi>line
+value</multi>
+        <empty></empty>
+        <escaped>&lt;tag&gt; &amp; "quotes"</escaped>
+    </macros>
+    <tagged remove="1">text with attributes</tagged>
+</yandex>
+`)
+}
+
+func TestConvertToYAMLEmpty(t *testing.T) {
+	requireEquivalent(t, "<yandex>\n</yandex>\n")
+
+	if _, err := ConvertToYAML("<yandex><unclosed></yandex>"); err == nil {
+		t.Fatalf("malformed XML is expected to fail to be converted")
+	}
+}